@@ -0,0 +1,172 @@
+package explain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/traefik/paerser/cli"
+	tcli "github.com/traefik/traefik/v3/pkg/cli"
+)
+
+// Configuration holds the synthetic request to explain, and the API to explain it with.
+type Configuration struct {
+	API        string            `description:"URL of the Traefik API." json:"api,omitempty" toml:"api,omitempty" yaml:"api,omitempty"`
+	EntryPoint string            `description:"Entry point receiving the request." json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty"`
+	Method     string            `description:"Method of the request." json:"method,omitempty" toml:"method,omitempty" yaml:"method,omitempty"`
+	Host       string            `description:"Host of the request." json:"host,omitempty" toml:"host,omitempty" yaml:"host,omitempty"`
+	Path       string            `description:"Path, and query, of the request." json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty"`
+	Headers    map[string]string `description:"Headers of the request." json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty"`
+	ClientIP   string            `description:"IP address of the client." json:"clientIP,omitempty" toml:"clientIP,omitempty" yaml:"clientIP,omitempty"`
+	SNI        string            `description:"Server name sent by the client, which makes the request a TLS one." json:"sni,omitempty" toml:"sni,omitempty" yaml:"sni,omitempty"`
+	TLS        bool              `description:"Whether the request is received over TLS." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// NewCmd builds a new Explain command.
+func NewCmd() *cli.Command {
+	config := &Configuration{
+		API:    "http://127.0.0.1:8080",
+		Method: http.MethodGet,
+		Path:   "/",
+	}
+
+	return &cli.Command{
+		Name: "explain",
+		Description: `Explains how a synthetic request is matched by the routers of an entry point, using the Traefik API /api/explain endpoint.
+It has no side effect on the traffic.`,
+		Configuration: config,
+		Resources:     []cli.ResourceLoader{&tcli.FlagLoader{}},
+		Run: func(_ []string) error {
+			explanation, err := Do(*config)
+			if err != nil {
+				return err
+			}
+
+			Print(os.Stdout, explanation)
+			return nil
+		},
+	}
+}
+
+// Explanation is the explanation of a synthetic request returned by the API.
+type Explanation struct {
+	EntryPoint string            `json:"entryPoint"`
+	TLS        bool              `json:"tls"`
+	HTTP       *MuxerExplanation `json:"http,omitempty"`
+	TCP        *MuxerExplanation `json:"tcp,omitempty"`
+}
+
+// MuxerExplanation explains how the routers of a muxer match the request.
+type MuxerExplanation struct {
+	Router  string              `json:"router,omitempty"`
+	Routers []RouterExplanation `json:"routers"`
+}
+
+// RouterExplanation explains whether a router matches the request.
+type RouterExplanation struct {
+	Name     string `json:"name"`
+	Rule     string `json:"rule"`
+	Priority int    `json:"priority"`
+	Matched  bool   `json:"matched"`
+	Matchers []struct {
+		Matcher string `json:"matcher"`
+		Matched bool   `json:"matched"`
+	} `json:"matchers"`
+}
+
+// Do calls the API explain endpoint with the given synthetic request.
+func Do(config Configuration) (*Explanation, error) {
+	if config.EntryPoint == "" {
+		return nil, errors.New("the entry point is missing")
+	}
+
+	query := url.Values{}
+	query.Set("entryPoint", config.EntryPoint)
+	query.Set("method", config.Method)
+	query.Set("host", config.Host)
+	query.Set("path", config.Path)
+	query.Set("clientIP", config.ClientIP)
+	query.Set("sni", config.SNI)
+	query.Set("tls", strconv.FormatBool(config.TLS))
+
+	names := make([]string, 0, len(config.Headers))
+	for name := range config.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		query.Add("header", name+": "+config.Headers[name])
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	resp, err := client.Get(strings.TrimSuffix(config.API, "/") + "/api/explain?" + query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("calling the API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading the API response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected API response status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var explanation Explanation
+	if err := json.Unmarshal(body, &explanation); err != nil {
+		return nil, fmt.Errorf("decoding the API response: %w", err)
+	}
+
+	return &explanation, nil
+}
+
+// Print writes the given explanation, with the routers in priority order.
+func Print(w io.Writer, explanation *Explanation) {
+	fmt.Fprintf(w, "Entry point: %s (TLS: %t)\n", explanation.EntryPoint, explanation.TLS)
+
+	printMuxer(w, "TCP", explanation.TCP)
+	printMuxer(w, "HTTP", explanation.HTTP)
+}
+
+func printMuxer(w io.Writer, kind string, explanation *MuxerExplanation) {
+	if explanation == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "\n%s routers:\n", kind)
+
+	for _, router := range explanation.Routers {
+		fmt.Fprintf(w, "  %s %s (priority %d): %s\n", status(router.Matched), router.Name, router.Priority, router.Rule)
+
+		for _, matcher := range router.Matchers {
+			fmt.Fprintf(w, "      %s %s\n", status(matcher.Matched), matcher.Matcher)
+		}
+	}
+
+	if explanation.Router == "" {
+		fmt.Fprintf(w, "No %s router matches the request.\n", kind)
+		return
+	}
+
+	fmt.Fprintf(w, "Selected %s router: %s\n", kind, explanation.Router)
+}
+
+func status(matched bool) string {
+	if matched {
+		return "[pass]"
+	}
+
+	return "[fail]"
+}
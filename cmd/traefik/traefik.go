@@ -22,6 +22,7 @@ import (
 	"github.com/spiffe/go-spiffe/v2/workloadapi"
 	"github.com/traefik/paerser/cli"
 	"github.com/traefik/traefik/v3/cmd"
+	"github.com/traefik/traefik/v3/cmd/explain"
 	"github.com/traefik/traefik/v3/cmd/healthcheck"
 	cmdVersion "github.com/traefik/traefik/v3/cmd/version"
 	tcli "github.com/traefik/traefik/v3/pkg/cli"
@@ -35,6 +36,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/provider/acme"
 	"github.com/traefik/traefik/v3/pkg/provider/aggregator"
 	"github.com/traefik/traefik/v3/pkg/provider/hub"
+	"github.com/traefik/traefik/v3/pkg/provider/internalca"
 	"github.com/traefik/traefik/v3/pkg/provider/tailscale"
 	"github.com/traefik/traefik/v3/pkg/provider/traefik"
 	"github.com/traefik/traefik/v3/pkg/safe"
@@ -78,6 +80,12 @@ Complete documentation is available at https://traefik.io`,
 		os.Exit(1)
 	}
 
+	err = cmdTraefik.AddCommand(explain.NewCmd())
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
 	err = cli.Execute(cmdTraefik)
 	if err != nil {
 		log.Error().Err(err).Msg("Command error")
@@ -178,7 +186,16 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 
 	// ACME
 
-	tlsManager := traefiktls.NewManager()
+	tlsManager := traefiktls.NewManager(staticConfiguration.OCSP)
+	if staticConfiguration.SessionTickets != nil && staticConfiguration.SessionTickets.KVStorage != nil {
+		client, rootKey, err := staticConfiguration.SessionTickets.KVStorage.Client()
+		if err != nil {
+			return nil, fmt.Errorf("connecting to the session tickets KV store: %w", err)
+		}
+
+		tlsManager.SetSessionTicketsStore(&traefiktls.SessionTicketsStore{Client: client, RootKey: rootKey})
+	}
+	routinesPool.GoCtx(tlsManager.Run)
 	httpChallengeProvider := acme.NewChallengeHTTP()
 
 	tlsChallengeProvider := acme.NewChallengeTLSALPN()
@@ -193,10 +210,16 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 
 	tsProviders := initTailscaleProviders(staticConfiguration, &providerAggregator)
 
+	// Internal CA
+
+	internalCAProviders := initInternalCAProviders(staticConfiguration, &providerAggregator)
+
 	// Metrics
 
 	metricRegistries := registerMetricClients(staticConfiguration.Metrics)
 	metricsRegistry := metrics.NewMultiRegistry(metricRegistries)
+	tlsManager.SetClientCertRejectsCounter(metricsRegistry.TLSClientCertRejectsCounter())
+	tlsManager.SetHandshakesCounter(metricsRegistry.TLSHandshakesCounter())
 
 	// Entrypoints
 
@@ -270,7 +293,18 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 	}
 
 	roundTripperManager := service.NewRoundTripperManager(spiffeX509Source)
+	routinesPool.GoCtx(roundTripperManager.Run)
 	dialerManager := tcp.NewDialerManager(spiffeX509Source)
+	routinesPool.GoCtx(dialerManager.Run)
+
+	roundTripperCertResolvers := make(map[string]service.ClientCertificateResolver)
+	dialerCertResolvers := make(map[string]tcp.ClientCertificateResolver)
+	for _, p := range internalCAProviders {
+		roundTripperCertResolvers[p.ResolverName] = p
+		dialerCertResolvers[p.ResolverName] = p
+	}
+	roundTripperManager.SetClientCertificateResolvers(roundTripperCertResolvers)
+	dialerManager.SetClientCertificateResolvers(dialerCertResolvers)
 	acmeHTTPHandler := getHTTPChallengeHandler(acmeProviders, httpChallengeProvider)
 	managerFactory := service.NewManagerFactory(*staticConfiguration, routinesPool, metricsRegistry, roundTripperManager, acmeHTTPHandler)
 
@@ -347,6 +381,12 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		watcher.AddListener(p.HandleConfigUpdate)
 	}
 
+	// Internal CA
+	for _, p := range internalCAProviders {
+		resolverNames[p.ResolverName] = struct{}{}
+		watcher.AddListener(p.HandleConfigUpdate)
+	}
+
 	// Certificate resolver logs
 	watcher.AddListener(func(config dynamic.Configuration) {
 		for rtName, rt := range config.HTTP.Routers {
@@ -438,13 +478,24 @@ func initACMEProvider(c *static.Configuration, providerAggregator *aggregator.Pr
 			continue
 		}
 
-		if localStores[resolver.ACME.Storage] == nil {
-			localStores[resolver.ACME.Storage] = acme.NewLocalStore(resolver.ACME.Storage)
+		var store acme.Store
+		if resolver.ACME.KVStorage != nil {
+			kvStore, err := acme.NewKVStore(resolver.ACME.KVStorage)
+			if err != nil {
+				log.Error().Err(err).Str("resolver", name).Msg("The ACME resolve is skipped from the resolvers list")
+				continue
+			}
+			store = kvStore
+		} else {
+			if localStores[resolver.ACME.Storage] == nil {
+				localStores[resolver.ACME.Storage] = acme.NewLocalStore(resolver.ACME.Storage)
+			}
+			store = localStores[resolver.ACME.Storage]
 		}
 
 		p := &acme.Provider{
 			Configuration:         resolver.ACME,
-			Store:                 localStores[resolver.ACME.Storage],
+			Store:                 store,
 			ResolverName:          name,
 			HTTPChallengeProvider: httpChallengeProvider,
 			TLSChallengeProvider:  tlsChallengeProvider,
@@ -486,6 +537,27 @@ func initTailscaleProviders(cfg *static.Configuration, providerAggregator *aggre
 	return providers
 }
 
+// initInternalCAProviders creates and registers internalca.Provider instances corresponding to the configured internal CA certificate resolvers.
+func initInternalCAProviders(cfg *static.Configuration, providerAggregator *aggregator.ProviderAggregator) []*internalca.Provider {
+	var providers []*internalca.Provider
+	for name, resolver := range cfg.CertificatesResolvers {
+		if resolver.InternalCA == nil {
+			continue
+		}
+
+		caProvider := &internalca.Provider{Configuration: resolver.InternalCA, ResolverName: name}
+
+		if err := providerAggregator.AddProvider(caProvider); err != nil {
+			log.Error().Err(err).Str(logs.ProviderName, name).Msg("Unable to create internal CA provider")
+			continue
+		}
+
+		providers = append(providers, caProvider)
+	}
+
+	return providers
+}
+
 func registerMetricClients(metricsConfig *types.Metrics) []metrics.Registry {
 	if metricsConfig == nil {
 		return nil
@@ -30,7 +30,8 @@ func writeError(rw http.ResponseWriter, msg string, code int) {
 
 type serviceInfoRepresentation struct {
 	*runtime.ServiceInfo
-	ServerStatus map[string]string `json:"serverStatus,omitempty"`
+	ServerStatus map[string]string    `json:"serverStatus,omitempty"`
+	TierStatus   []runtime.TierStatus `json:"tierStatus,omitempty"`
 }
 
 // RunTimeRepresentation is the configuration information exposed by the API handler.
@@ -43,6 +44,7 @@ type RunTimeRepresentation struct {
 	TCPServices    map[string]*runtime.TCPServiceInfo    `json:"tcpServices,omitempty"`
 	UDPRouters     map[string]*runtime.UDPRouterInfo     `json:"udpRouters,omitempty"`
 	UDPServices    map[string]*runtime.UDPServiceInfo    `json:"udpServices,omitempty"`
+	UDPMiddlewares map[string]*runtime.UDPMiddlewareInfo `json:"udpMiddlewares,omitempty"`
 }
 
 // Handler serves the configuration and status of Traefik on API endpoints.
@@ -51,12 +53,19 @@ type Handler struct {
 
 	// runtimeConfiguration is the data set used to create all the data representations exposed by the API.
 	runtimeConfiguration *runtime.Configuration
+
+	// routeExplainer holds the live muxers used by the explain endpoint, which is disabled when nil.
+	routeExplainer *RouteExplainer
 }
 
 // NewBuilder returns a http.Handler builder based on runtime.Configuration.
-func NewBuilder(staticConfig static.Configuration) func(*runtime.Configuration) http.Handler {
+// The explain endpoint matches the synthetic requests against the muxers held by the given routeExplainer, if any.
+func NewBuilder(staticConfig static.Configuration, routeExplainer *RouteExplainer) func(*runtime.Configuration) http.Handler {
 	return func(configuration *runtime.Configuration) http.Handler {
-		return New(staticConfig, configuration).createRouter()
+		handler := New(staticConfig, configuration)
+		handler.routeExplainer = routeExplainer
+
+		return handler.createRouter()
 	}
 }
 
@@ -108,6 +117,12 @@ func (h Handler) createRouter() *mux.Router {
 	router.Methods(http.MethodGet).Path("/api/udp/routers/{routerID}").HandlerFunc(h.getUDPRouter)
 	router.Methods(http.MethodGet).Path("/api/udp/services").HandlerFunc(h.getUDPServices)
 	router.Methods(http.MethodGet).Path("/api/udp/services/{serviceID}").HandlerFunc(h.getUDPService)
+	router.Methods(http.MethodGet).Path("/api/udp/middlewares").HandlerFunc(h.getUDPMiddlewares)
+	router.Methods(http.MethodGet).Path("/api/udp/middlewares/{middlewareID}").HandlerFunc(h.getUDPMiddleware)
+
+	if h.routeExplainer != nil {
+		router.Methods(http.MethodGet).Path("/api/explain").HandlerFunc(h.getExplanation)
+	}
 
 	version.Handler{}.Append(router)
 
@@ -120,6 +135,7 @@ func (h Handler) getRuntimeConfiguration(rw http.ResponseWriter, request *http.R
 		siRepr[k] = &serviceInfoRepresentation{
 			ServiceInfo:  v,
 			ServerStatus: v.GetAllStatus(),
+			TierStatus:   v.GetTierStatus(),
 		}
 	}
 
@@ -132,6 +148,7 @@ func (h Handler) getRuntimeConfiguration(rw http.ResponseWriter, request *http.R
 		TCPServices:    h.runtimeConfiguration.TCPServices,
 		UDPRouters:     h.runtimeConfiguration.UDPRouters,
 		UDPServices:    h.runtimeConfiguration.UDPServices,
+		UDPMiddlewares: h.runtimeConfiguration.UDPMiddlewares,
 	}
 
 	rw.Header().Set("Content-Type", "application/json")
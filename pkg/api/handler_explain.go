@@ -0,0 +1,220 @@
+package api
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/middlewares/requestdecorator"
+	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
+	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// EntryPointMuxers holds the live muxers of an entry point.
+type EntryPointMuxers struct {
+	// HTTP is the muxer of the HTTP routers without TLS.
+	HTTP *httpmuxer.Muxer
+	// HTTPS is the muxer of the HTTP routers with TLS.
+	HTTPS *httpmuxer.Muxer
+	// TCP is the muxer of the TCP routers without TLS.
+	TCP *tcpmuxer.Muxer
+	// TCPTLS is the muxer of the TCP routers with TLS.
+	TCPTLS *tcpmuxer.Muxer
+}
+
+// RouteExplainer holds the live muxers of the entry points,
+// against which the synthetic requests of the explain endpoint are matched.
+type RouteExplainer struct {
+	mu               sync.RWMutex
+	muxers           map[string]EntryPointMuxers
+	requestDecorator *requestdecorator.RequestDecorator
+}
+
+// NewRouteExplainer creates a new RouteExplainer.
+func NewRouteExplainer() *RouteExplainer {
+	return &RouteExplainer{
+		muxers:           make(map[string]EntryPointMuxers),
+		requestDecorator: requestdecorator.New(nil),
+	}
+}
+
+// Update replaces the muxers of the entry points.
+func (e *RouteExplainer) Update(muxers map[string]EntryPointMuxers) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.muxers = muxers
+}
+
+func (e *RouteExplainer) get(entryPoint string) (EntryPointMuxers, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	muxers, ok := e.muxers[entryPoint]
+	return muxers, ok
+}
+
+type explanationRepresentation struct {
+	EntryPoint string                         `json:"entryPoint"`
+	TLS        bool                           `json:"tls"`
+	HTTP       *httpExplanationRepresentation `json:"http,omitempty"`
+	TCP        *tcpExplanationRepresentation  `json:"tcp,omitempty"`
+}
+
+type httpExplanationRepresentation struct {
+	Router  string                       `json:"router,omitempty"`
+	Routers []httpmuxer.RouteExplanation `json:"routers"`
+}
+
+type tcpExplanationRepresentation struct {
+	Router  string                      `json:"router,omitempty"`
+	Routers []tcpmuxer.RouteExplanation `json:"routers"`
+}
+
+func (h Handler) getExplanation(rw http.ResponseWriter, request *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	query := request.URL.Query()
+
+	entryPoint := query.Get("entryPoint")
+	muxers, ok := h.routeExplainer.get(entryPoint)
+	if !ok {
+		writeError(rw, fmt.Sprintf("entry point not found: %s", entryPoint), http.StatusNotFound)
+		return
+	}
+
+	var tlsEnabled bool
+	if value := query.Get("tls"); value != "" {
+		var err error
+		tlsEnabled, err = strconv.ParseBool(value)
+		if err != nil {
+			writeError(rw, fmt.Sprintf("invalid tls value: %s", value), http.StatusBadRequest)
+			return
+		}
+	}
+
+	sni := query.Get("sni")
+	if sni != "" {
+		tlsEnabled = true
+	}
+
+	clientIP := query.Get("clientIP")
+	if clientIP == "" {
+		clientIP = "127.0.0.1"
+	}
+
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		writeError(rw, fmt.Sprintf("invalid client IP: %s", clientIP), http.StatusBadRequest)
+		return
+	}
+
+	remoteAddr := &net.TCPAddr{IP: ip}
+
+	result := explanationRepresentation{
+		EntryPoint: entryPoint,
+		TLS:        tlsEnabled,
+	}
+
+	tcpMuxer := muxers.TCP
+	if tlsEnabled {
+		tcpMuxer = muxers.TCPTLS
+	}
+
+	if tcpMuxer != nil && tcpMuxer.HasRoutes() {
+		connData, err := tcpmuxer.NewConnData(sni, explainConn{remoteAddr: remoteAddr}, nil, nil, nil)
+		if err != nil {
+			writeError(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		router, routers := tcpMuxer.Explain(connData)
+		result.TCP = &tcpExplanationRepresentation{Router: router, Routers: routers}
+	}
+
+	httpMuxer := muxers.HTTP
+	if tlsEnabled {
+		httpMuxer = muxers.HTTPS
+	}
+
+	if httpMuxer != nil {
+		req, err := newExplainRequest(query, remoteAddr, tlsEnabled, sni)
+		if err != nil {
+			writeError(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		h.routeExplainer.requestDecorator.ServeHTTP(httptest.NewRecorder(), req, func(_ http.ResponseWriter, req *http.Request) {
+			router, routers := httpMuxer.Explain(req)
+			result.HTTP = &httpExplanationRepresentation{Router: router, Routers: routers}
+		})
+	}
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// newExplainRequest builds the synthetic request described by the given query parameters.
+func newExplainRequest(query map[string][]string, remoteAddr net.Addr, tlsEnabled bool, sni string) (*http.Request, error) {
+	get := func(key, defaultValue string) string {
+		if values := query[key]; len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+		return defaultValue
+	}
+
+	path := get("path", "/")
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("path %q does not start with a '/'", path)
+	}
+
+	scheme := "http"
+	if tlsEnabled {
+		scheme = "https"
+	}
+
+	req, err := http.NewRequest(strings.ToUpper(get("method", http.MethodGet)), scheme+"://localhost"+path, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	req.Host = get("host", "")
+	req.RemoteAddr = remoteAddr.String()
+
+	for _, header := range query["header"] {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q, expected 'Name: value'", header)
+		}
+
+		req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	if tlsEnabled {
+		req.TLS = &tls.ConnectionState{ServerName: sni}
+	}
+
+	return req, nil
+}
+
+// explainConn is the connection of the synthetic requests, which only has a remote address.
+type explainConn struct {
+	tcp.WriteCloser
+
+	remoteAddr net.Addr
+}
+
+func (c explainConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
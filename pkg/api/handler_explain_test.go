@@ -0,0 +1,164 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
+	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestHandler_Explain(t *testing.T) {
+	type expected struct {
+		statusCode int
+		jsonFile   string
+	}
+
+	testCases := []struct {
+		desc     string
+		path     string
+		expected expected
+	}{
+		{
+			desc: "HTTP request",
+			path: "/api/explain?entryPoint=web&host=example.com&path=/api/foo%3Fbar=baz&header=X-Version:%202&clientIP=10.0.0.1",
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/explain-http.json",
+			},
+		},
+		{
+			desc: "TLS request",
+			path: "/api/explain?entryPoint=web&method=post&host=example.com&sni=example.com",
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/explain-tls.json",
+			},
+		},
+		{
+			desc: "entry point without muxers",
+			path: "/api/explain?entryPoint=empty",
+			expected: expected{
+				statusCode: http.StatusOK,
+				jsonFile:   "testdata/explain-empty.json",
+			},
+		},
+		{
+			desc: "unknown entry point",
+			path: "/api/explain?entryPoint=foo",
+			expected: expected{
+				statusCode: http.StatusNotFound,
+			},
+		},
+		{
+			desc: "invalid client IP",
+			path: "/api/explain?entryPoint=web&clientIP=foo",
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			desc: "invalid header",
+			path: "/api/explain?entryPoint=web&header=foo",
+			expected: expected{
+				statusCode: http.StatusBadRequest,
+			},
+		},
+	}
+
+	httpMuxer, err := httpmuxer.NewMuxer()
+	require.NoError(t, err)
+
+	httpsMuxer, err := httpmuxer.NewMuxer()
+	require.NoError(t, err)
+
+	tcpMuxer, err := tcpmuxer.NewMuxer()
+	require.NoError(t, err)
+
+	httpHandler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("the HTTP handler must not be called")
+	})
+
+	tcpHandler := tcp.HandlerFunc(func(tcp.WriteCloser) {
+		t.Error("the TCP handler must not be called")
+	})
+
+	require.NoError(t, httpMuxer.AddRoute("api@myprovider", "Host(`example.com`) && PathPrefix(`/api`) && Header(`X-Version`, `2`)", 100, httpHandler))
+	require.NoError(t, httpMuxer.AddRoute("web@myprovider", "Host(`example.com`) && ClientIP(`192.168.0.0/16`)", 10, httpHandler))
+	require.NoError(t, httpMuxer.AddRoute("query@myprovider", "Query(`bar`, `baz`)", 1, httpHandler))
+	require.NoError(t, httpsMuxer.AddRoute("secure@myprovider", "Host(`example.com`) && Method(`POST`)", 10, httpHandler))
+	require.NoError(t, tcpMuxer.AddRoute("db@myprovider", "HostSNI(`db.example.com`)", 0, tcpHandler))
+
+	routeExplainer := NewRouteExplainer()
+	routeExplainer.Update(map[string]EntryPointMuxers{
+		"web": {
+			HTTP:   httpMuxer,
+			HTTPS:  httpsMuxer,
+			TCPTLS: tcpMuxer,
+		},
+		"empty": {},
+	})
+
+	conf := static.Configuration{API: &static.API{}, Global: &static.Global{}}
+	server := httptest.NewServer(NewBuilder(conf, routeExplainer)(&runtime.Configuration{}))
+	t.Cleanup(server.Close)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := http.DefaultClient.Get(server.URL + test.path)
+			require.NoError(t, err)
+
+			require.Equal(t, test.expected.statusCode, resp.StatusCode)
+
+			if test.expected.jsonFile == "" {
+				return
+			}
+
+			assert.Equal(t, resp.Header.Get("Content-Type"), "application/json")
+			contents, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			err = resp.Body.Close()
+			require.NoError(t, err)
+
+			if *updateExpected {
+				var results interface{}
+				err := json.Unmarshal(contents, &results)
+				require.NoError(t, err)
+
+				newJSON, err := json.MarshalIndent(results, "", "\t")
+				require.NoError(t, err)
+
+				err = os.WriteFile(test.expected.jsonFile, newJSON, 0o644)
+				require.NoError(t, err)
+			}
+
+			data, err := os.ReadFile(test.expected.jsonFile)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(data), string(contents))
+		})
+	}
+}
+
+func TestHandler_Explain_disabled(t *testing.T) {
+	conf := static.Configuration{API: &static.API{}, Global: &static.Global{}}
+	server := httptest.NewServer(NewBuilder(conf, nil)(&runtime.Configuration{}))
+	t.Cleanup(server.Close)
+
+	resp, err := http.DefaultClient.Get(server.URL + "/api/explain?entryPoint=web")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -33,10 +33,11 @@ func newRouterRepresentation(name string, rt *runtime.RouterInfo) routerRepresen
 
 type serviceRepresentation struct {
 	*runtime.ServiceInfo
-	ServerStatus map[string]string `json:"serverStatus,omitempty"`
-	Name         string            `json:"name,omitempty"`
-	Provider     string            `json:"provider,omitempty"`
-	Type         string            `json:"type,omitempty"`
+	ServerStatus map[string]string    `json:"serverStatus,omitempty"`
+	TierStatus   []runtime.TierStatus `json:"tierStatus,omitempty"`
+	Name         string               `json:"name,omitempty"`
+	Provider     string               `json:"provider,omitempty"`
+	Type         string               `json:"type,omitempty"`
 }
 
 func newServiceRepresentation(name string, si *runtime.ServiceInfo) serviceRepresentation {
@@ -45,6 +46,7 @@ func newServiceRepresentation(name string, si *runtime.ServiceInfo) serviceRepre
 		Name:         name,
 		Provider:     getProviderName(name),
 		ServerStatus: si.GetAllStatus(),
+		TierStatus:   si.GetTierStatus(),
 		Type:         strings.ToLower(extractType(si.Service)),
 	}
 }
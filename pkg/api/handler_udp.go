@@ -42,6 +42,22 @@ func newUDPServiceRepresentation(name string, si *runtime.UDPServiceInfo) udpSer
 	}
 }
 
+type udpMiddlewareRepresentation struct {
+	*runtime.UDPMiddlewareInfo
+	Name     string `json:"name,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
+
+func newUDPMiddlewareRepresentation(name string, mi *runtime.UDPMiddlewareInfo) udpMiddlewareRepresentation {
+	return udpMiddlewareRepresentation{
+		UDPMiddlewareInfo: mi,
+		Name:              name,
+		Provider:          getProviderName(name),
+		Type:              strings.ToLower(extractType(mi.UDPMiddleware)),
+	}
+}
+
 func (h Handler) getUDPRouters(rw http.ResponseWriter, request *http.Request) {
 	results := make([]udpRouterRepresentation, 0, len(h.runtimeConfiguration.UDPRouters))
 
@@ -144,14 +160,66 @@ func (h Handler) getUDPService(rw http.ResponseWriter, request *http.Request) {
 	}
 }
 
+func (h Handler) getUDPMiddlewares(rw http.ResponseWriter, request *http.Request) {
+	results := make([]udpMiddlewareRepresentation, 0, len(h.runtimeConfiguration.UDPMiddlewares))
+
+	query := request.URL.Query()
+	criterion := newSearchCriterion(query)
+
+	for name, mi := range h.runtimeConfiguration.UDPMiddlewares {
+		if keepUDPMiddleware(name, mi, criterion) {
+			results = append(results, newUDPMiddlewareRepresentation(name, mi))
+		}
+	}
+
+	sortMiddlewares(query, results)
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	pageInfo, err := pagination(request, len(results))
+	if err != nil {
+		writeError(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set(nextPageHeader, strconv.Itoa(pageInfo.nextPage))
+
+	err = json.NewEncoder(rw).Encode(results[pageInfo.startIndex:pageInfo.endIndex])
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h Handler) getUDPMiddleware(rw http.ResponseWriter, request *http.Request) {
+	middlewareID := mux.Vars(request)["middlewareID"]
+
+	rw.Header().Set("Content-Type", "application/json")
+
+	middleware, ok := h.runtimeConfiguration.UDPMiddlewares[middlewareID]
+	if !ok {
+		writeError(rw, fmt.Sprintf("middleware not found: %s", middlewareID), http.StatusNotFound)
+		return
+	}
+
+	result := newUDPMiddlewareRepresentation(middlewareID, middleware)
+
+	err := json.NewEncoder(rw).Encode(result)
+	if err != nil {
+		log.Ctx(request.Context()).Error().Err(err).Send()
+		writeError(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func keepUDPRouter(name string, item *runtime.UDPRouterInfo, criterion *searchCriterion) bool {
 	if criterion == nil {
 		return true
 	}
 
 	return criterion.withStatus(item.Status) &&
-		criterion.searchIn(name) &&
-		criterion.filterService(item.Service)
+		criterion.searchIn(item.Rule, name) &&
+		criterion.filterService(item.Service) &&
+		criterion.filterMiddleware(item.Middlewares)
 }
 
 func keepUDPService(name string, item *runtime.UDPServiceInfo, criterion *searchCriterion) bool {
@@ -161,3 +229,11 @@ func keepUDPService(name string, item *runtime.UDPServiceInfo, criterion *search
 
 	return criterion.withStatus(item.Status) && criterion.searchIn(name)
 }
+
+func keepUDPMiddleware(name string, item *runtime.UDPMiddlewareInfo, criterion *searchCriterion) bool {
+	if criterion == nil {
+		return true
+	}
+
+	return criterion.withStatus(item.Status) && criterion.searchIn(name)
+}
@@ -132,8 +132,7 @@ func (r udpRouterRepresentation) provider() string {
 }
 
 func (r udpRouterRepresentation) priority() int {
-	// noop
-	return 0
+	return r.Priority
 }
 
 func (r udpRouterRepresentation) status() string {
@@ -141,8 +140,7 @@ func (r udpRouterRepresentation) status() string {
 }
 
 func (r udpRouterRepresentation) rule() string {
-	// noop
-	return ""
+	return r.Rule
 }
 
 func (r udpRouterRepresentation) service() string {
@@ -341,6 +339,22 @@ func (m tcpMiddlewareRepresentation) status() string {
 	return m.Status
 }
 
+func (m udpMiddlewareRepresentation) name() string {
+	return m.Name
+}
+
+func (m udpMiddlewareRepresentation) resourceType() string {
+	return m.Type
+}
+
+func (m udpMiddlewareRepresentation) provider() string {
+	return m.Provider
+}
+
+func (m udpMiddlewareRepresentation) status() string {
+	return m.Status
+}
+
 type orderedByName interface {
 	orderedWithName
 }
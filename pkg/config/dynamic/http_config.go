@@ -61,6 +61,19 @@ type Router struct {
 	Rule        string           `json:"rule,omitempty" toml:"rule,omitempty" yaml:"rule,omitempty"`
 	Priority    int              `json:"priority,omitempty" toml:"priority,omitempty,omitzero" yaml:"priority,omitempty" export:"true"`
 	TLS         *RouterTLSConfig `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	Timeouts    *RequestTimeouts `json:"timeouts,omitempty" toml:"timeouts,omitempty" yaml:"timeouts,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RequestTimeouts holds the timeouts applied to the requests handled by a router or a service.
+type RequestTimeouts struct {
+	// Request defines the overall amount of time allowed to handle a request, retries included.
+	// If zero, no timeout exists.
+	Request ptypes.Duration `json:"request,omitempty" toml:"request,omitempty" yaml:"request,omitempty" export:"true"`
+	// PerTry defines the amount of time allowed for each attempt to reach a server.
+	// If zero, no timeout exists.
+	PerTry ptypes.Duration `json:"perTry,omitempty" toml:"perTry,omitempty" yaml:"perTry,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -80,6 +93,8 @@ type Mirroring struct {
 	MaxBodySize *int64          `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
 	Mirrors     []MirrorService `json:"mirrors,omitempty" toml:"mirrors,omitempty" yaml:"mirrors,omitempty" export:"true"`
 	HealthCheck *HealthCheck    `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// Compare enables the comparison of the mirrors responses with the response of the service.
+	Compare *MirrorCompare `json:"compare,omitempty" toml:"compare,omitempty" yaml:"compare,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 }
 
 // SetDefaults Default values for a WRRService.
@@ -88,6 +103,35 @@ func (m *Mirroring) SetDefaults() {
 	m.MaxBodySize = &defaultMaxBodySize
 }
 
+// Body comparison modes of the mirrors responses.
+const (
+	MirrorCompareBodyNone = "none"
+	MirrorCompareBodyHash = "hash"
+	MirrorCompareBodyJSON = "json"
+)
+
+// +k8s:deepcopy-gen=true
+
+// MirrorCompare holds the configuration of the comparison of the mirrors responses with the response of the service.
+// A mismatch is reported by the mirror mismatches metric, and logged according to the log sample rate.
+type MirrorCompare struct {
+	// Headers defines the response headers which have to match.
+	Headers []string `json:"headers,omitempty" toml:"headers,omitempty" yaml:"headers,omitempty" export:"true"`
+	// Body defines how the response bodies are compared: none, hash (byte to byte), or json (JSON-normalized).
+	Body string `json:"body,omitempty" toml:"body,omitempty" yaml:"body,omitempty" export:"true"`
+	// MaxBodySize defines the maximum size of a JSON body to compare, larger bodies are compared with their hash.
+	MaxBodySize int64 `json:"maxBodySize,omitempty" toml:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty" export:"true"`
+	// LogSampleRate defines the ratio, between 0 and 1, of the mismatches which are logged.
+	LogSampleRate float64 `json:"logSampleRate,omitempty" toml:"logSampleRate,omitempty" yaml:"logSampleRate,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (m *MirrorCompare) SetDefaults() {
+	m.Body = MirrorCompareBodyHash
+	m.MaxBodySize = 1024 * 1024
+	m.LogSampleRate = 1
+}
+
 // +k8s:deepcopy-gen=true
 
 // Failover holds the Failover configuration.
@@ -95,6 +139,24 @@ type Failover struct {
 	Service     string       `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
 	Fallback    string       `json:"fallback,omitempty" toml:"fallback,omitempty" yaml:"fallback,omitempty" export:"true"`
 	HealthCheck *HealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	// Tiers defines an ordered list of services, by decreasing priority, as an alternative to Service and Fallback.
+	// The requests are forwarded to the first tier having enough healthy servers.
+	Tiers []FailoverTier `json:"tiers,omitempty" toml:"tiers,omitempty" yaml:"tiers,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// FailoverTier holds the configuration of a tier of a Failover service.
+type FailoverTier struct {
+	Service string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	// MinHealthy defines the minimum number of healthy servers (or children services) of the tier
+	// under which the requests spill over to the next tier.
+	MinHealthy int `json:"minHealthy,omitempty" toml:"minHealthy,omitempty" yaml:"minHealthy,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (f *FailoverTier) SetDefaults() {
+	f.MinHealthy = 1
 }
 
 // +k8s:deepcopy-gen=true
@@ -170,6 +232,8 @@ type ServersLoadBalancer struct {
 	PassHostHeader     *bool               `json:"passHostHeader" toml:"passHostHeader" yaml:"passHostHeader" export:"true"`
 	ResponseForwarding *ResponseForwarding `json:"responseForwarding,omitempty" toml:"responseForwarding,omitempty" yaml:"responseForwarding,omitempty" export:"true"`
 	ServersTransport   string              `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	Timeouts           *RequestTimeouts    `json:"timeouts,omitempty" toml:"timeouts,omitempty" yaml:"timeouts,omitempty" export:"true"`
+	Hedging            *Hedging            `json:"hedging,omitempty" toml:"hedging,omitempty" yaml:"hedging,omitempty" export:"true"`
 }
 
 // Mergeable tells if the given service is mergeable.
@@ -217,6 +281,21 @@ func (r *ResponseForwarding) SetDefaults() {
 
 // +k8s:deepcopy-gen=true
 
+// Hedging holds the request hedging configuration.
+// When the first attempt to reach a server has not received the response headers in time,
+// a second attempt is sent to another server, and the first response wins.
+// Only the requests with a safe method and without body are hedged.
+type Hedging struct {
+	// Delay defines how long to wait for the response headers of the first attempt before sending the hedged request.
+	Delay ptypes.Duration `json:"delay,omitempty" toml:"delay,omitempty" yaml:"delay,omitempty" export:"true"`
+	// Percentile defines the percentile of the observed response headers latencies
+	// after which the hedged request is sent (e.g. 95).
+	// Once enough latencies have been observed, it takes precedence over the delay.
+	Percentile float64 `json:"percentile,omitempty" toml:"percentile,omitempty" yaml:"percentile,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // Server holds the server configuration.
 type Server struct {
 	URL    string `json:"url,omitempty" toml:"url,omitempty" yaml:"url,omitempty" label:"-"`
@@ -268,6 +347,7 @@ type ServersTransport struct {
 	InsecureSkipVerify  bool                       `description:"Disables SSL certificate verification." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
 	RootCAs             []traefiktls.FileOrContent `description:"Defines a list of CA secret used to validate self-signed certificate" json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
 	Certificates        traefiktls.Certificates    `description:"Defines a list of secret storing client certificates for mTLS." json:"certificates,omitempty" toml:"certificates,omitempty" yaml:"certificates,omitempty" export:"true"`
+	CertResolver        string                     `description:"Defines the internal CA certificates resolver issuing the client certificate presented to the servers." json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty" export:"true"`
 	MaxIdleConnsPerHost int                        `description:"If non-zero, controls the maximum idle (keep-alive) to keep per-host. If zero, DefaultMaxIdleConnsPerHost is used" json:"maxIdleConnsPerHost,omitempty" toml:"maxIdleConnsPerHost,omitempty" yaml:"maxIdleConnsPerHost,omitempty" export:"true"`
 	ForwardingTimeouts  *ForwardingTimeouts        `description:"Defines the timeouts for requests forwarded to the backend servers." json:"forwardingTimeouts,omitempty" toml:"forwardingTimeouts,omitempty" yaml:"forwardingTimeouts,omitempty" export:"true"`
 	DisableHTTP2        bool                       `description:"Disables HTTP/2 for connections with backend servers." json:"disableHTTP2,omitempty" toml:"disableHTTP2,omitempty" yaml:"disableHTTP2,omitempty" export:"true"`
@@ -30,7 +30,9 @@ type Middleware struct {
 	InFlightReq       *InFlightReq       `json:"inFlightReq,omitempty" toml:"inFlightReq,omitempty" yaml:"inFlightReq,omitempty" export:"true"`
 	Buffering         *Buffering         `json:"buffering,omitempty" toml:"buffering,omitempty" yaml:"buffering,omitempty" export:"true"`
 	CircuitBreaker    *CircuitBreaker    `json:"circuitBreaker,omitempty" toml:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty" export:"true"`
+	Coalesce          *Coalesce          `json:"coalesce,omitempty" toml:"coalesce,omitempty" yaml:"coalesce,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	Compress          *Compress          `json:"compress,omitempty" toml:"compress,omitempty" yaml:"compress,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	MTLSAuthz         *MTLSAuthz         `json:"mTLSAuthz,omitempty" toml:"mTLSAuthz,omitempty" yaml:"mTLSAuthz,omitempty" export:"true"`
 	PassTLSClientCert *PassTLSClientCert `json:"passTLSClientCert,omitempty" toml:"passTLSClientCert,omitempty" yaml:"passTLSClientCert,omitempty" export:"true"`
 	Retry             *Retry             `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
 	ContentType       *ContentType       `json:"contentType,omitempty" toml:"contentType,omitempty" yaml:"contentType,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
@@ -92,6 +94,31 @@ type BasicAuth struct {
 
 // +k8s:deepcopy-gen=true
 
+// Coalesce holds the coalesce middleware configuration.
+// This middleware collapses the concurrent identical requests into a single request to the service,
+// and shares its response with all of them.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/coalesce/
+type Coalesce struct {
+	// VaryHeaders defines the request headers which, along with the method and the URL, have to be identical for requests to be coalesced.
+	// The requests with an Authorization or a Cookie header are only coalesced if this header is listed.
+	VaryHeaders []string `json:"varyHeaders,omitempty" toml:"varyHeaders,omitempty" yaml:"varyHeaders,omitempty" export:"true"`
+	// MaxResponseBodyBytes defines the maximum size of a shared response (in bytes).
+	// The requests waiting for a larger response are forwarded to the service.
+	// Default: 1048576 (1Mi).
+	MaxResponseBodyBytes int64 `json:"maxResponseBodyBytes,omitempty" toml:"maxResponseBodyBytes,omitempty" yaml:"maxResponseBodyBytes,omitempty" export:"true"`
+	// Timeout defines how long a request waits for a shared response.
+	// When it expires, the client gets a 504 (Gateway Timeout) response.
+	// Default: 0 (no timeout).
+	Timeout ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a Coalesce.
+func (c *Coalesce) SetDefaults() {
+	c.MaxResponseBodyBytes = 1024 * 1024
+}
+
+// +k8s:deepcopy-gen=true
+
 // Buffering holds the buffering middleware configuration.
 // This middleware retries or limits the size of requests that can be forwarded to backends.
 // More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/buffering/#maxrequestbodybytes
@@ -403,6 +430,41 @@ type InFlightReq struct {
 
 // +k8s:deepcopy-gen=true
 
+// MTLSAuthz holds the mTLS authorization middleware configuration.
+// This middleware accepts/refuses requests based on the verified client certificate.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/mtlsauthz/
+type MTLSAuthz struct {
+	// Allow defines the rules of which one must be matched by the client certificate.
+	// If empty, all the client certificates which are not denied are allowed.
+	Allow []CertificateRule `json:"allow,omitempty" toml:"allow,omitempty" yaml:"allow,omitempty" export:"true"`
+	// Deny defines the rules refusing the client certificate, taking precedence over the Allow rules.
+	Deny []CertificateRule `json:"deny,omitempty" toml:"deny,omitempty" yaml:"deny,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// CertificateRule defines the fields a client certificate must match.
+// All the set fields must be matched, and a field is matched if one of its values matches one of the certificate values.
+// A value is either an exact value, or a regular expression prefixed by "regex:".
+type CertificateRule struct {
+	// CommonName defines the values of the subject common name.
+	CommonName []string `json:"commonName,omitempty" toml:"commonName,omitempty" yaml:"commonName,omitempty" export:"true"`
+	// Organization defines the values of the subject organizations.
+	Organization []string `json:"organization,omitempty" toml:"organization,omitempty" yaml:"organization,omitempty" export:"true"`
+	// OrganizationalUnit defines the values of the subject organizational units.
+	OrganizationalUnit []string `json:"organizationalUnit,omitempty" toml:"organizationalUnit,omitempty" yaml:"organizationalUnit,omitempty" export:"true"`
+	// DNSNames defines the values of the DNS subject alternative names.
+	DNSNames []string `json:"dnsNames,omitempty" toml:"dnsNames,omitempty" yaml:"dnsNames,omitempty" export:"true"`
+	// URIs defines the values of the URI subject alternative names, such as SPIFFE IDs.
+	URIs []string `json:"uris,omitempty" toml:"uris,omitempty" yaml:"uris,omitempty" export:"true"`
+	// EmailAddresses defines the values of the email subject alternative names.
+	EmailAddresses []string `json:"emailAddresses,omitempty" toml:"emailAddresses,omitempty" yaml:"emailAddresses,omitempty" export:"true"`
+	// Fingerprints defines the SHA-256 fingerprints (hexadecimal) of the certificate.
+	Fingerprints []string `json:"fingerprints,omitempty" toml:"fingerprints,omitempty" yaml:"fingerprints,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // PassTLSClientCert holds the pass TLS client cert middleware configuration.
 // This middleware adds the selected data from the passed client TLS certificate to a header.
 // More info: https://doc.traefik.io/traefik/v3.0/middlewares/http/passtlsclientcert/
@@ -522,6 +584,37 @@ type Retry struct {
 	// The value of initialInterval should be provided in seconds or as a valid duration format,
 	// see https://pkg.go.dev/time#ParseDuration.
 	InitialInterval ptypes.Duration `json:"initialInterval,omitempty" toml:"initialInterval,omitempty" yaml:"initialInterval,omitempty" export:"true"`
+	// StatusCodes defines which status codes returned by the backend trigger a retry.
+	// It can be a list of codes or ranges of codes (e.g. "500-599").
+	// Retries on status codes require the request body to be buffered in memory.
+	StatusCodes []string `json:"statusCodes,omitempty" toml:"statusCodes,omitempty" yaml:"statusCodes,omitempty" export:"true"`
+	// MaxRequestBodyBytes defines the maximum size (in bytes) of the request body buffered to retry on status codes.
+	// Larger requests are forwarded once, without retries on status codes.
+	// A negative value means no maximum.
+	// Default: 1048576 (1 MiB).
+	MaxRequestBodyBytes int64 `json:"maxRequestBodyBytes,omitempty" toml:"maxRequestBodyBytes,omitempty" yaml:"maxRequestBodyBytes,omitempty" export:"true"`
+	// AllowNonIdempotent allows the retries on status codes of the requests with a non-idempotent method, such as POST or PATCH.
+	// By default, only the requests with an idempotent method are retried on status codes.
+	AllowNonIdempotent bool `json:"allowNonIdempotent,omitempty" toml:"allowNonIdempotent,omitempty" yaml:"allowNonIdempotent,omitempty" export:"true"`
+	// Budget limits the number of concurrent retries sent to the targeted service.
+	Budget *RetryBudget `json:"budget,omitempty" toml:"budget,omitempty" yaml:"budget,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// RetryBudget holds the retry budget configuration.
+// The budget is shared by all the retry middlewares targeting the same service.
+type RetryBudget struct {
+	// Percent defines the maximum ratio, in percent, of in-flight retries to in-flight requests for the service.
+	Percent float64 `json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
+	// MinRetries defines the number of concurrent retries which are always allowed, regardless of the percent.
+	MinRetries int `json:"minRetries,omitempty" toml:"minRetries,omitempty" yaml:"minRetries,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a RetryBudget.
+func (r *RetryBudget) SetDefaults() {
+	r.Percent = 20
+	r.MinRetries = 3
 }
 
 // +k8s:deepcopy-gen=true
@@ -25,6 +25,35 @@ type TCPConfiguration struct {
 type TCPService struct {
 	LoadBalancer *TCPServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty" export:"true"`
 	Weighted     *TCPWeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
+	Failover     *TCPFailover            `json:"failover,omitempty" toml:"failover,omitempty" yaml:"failover,omitempty" label:"-" export:"true"`
+	Mirroring    *TCPMirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPFailover holds the TCP failover service configuration.
+// The connections are forwarded to the fallback service when dialing a server of the main service fails.
+type TCPFailover struct {
+	Service  string `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Fallback string `json:"fallback,omitempty" toml:"fallback,omitempty" yaml:"fallback,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPMirroring holds the TCP mirroring service configuration.
+// The bytes sent by the clients to the main service are copied to the mirrors, whose responses are discarded.
+type TCPMirroring struct {
+	Service string             `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Mirrors []TCPMirrorService `json:"mirrors,omitempty" toml:"mirrors,omitempty" yaml:"mirrors,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPMirrorService holds the TCP mirror configuration.
+type TCPMirrorService struct {
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	// Percent defines the percentage of the connections mirrored to the service.
+	Percent int `json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -70,6 +99,18 @@ type RouterTCPTLSConfig struct {
 	Domains      []types.Domain `json:"domains,omitempty" toml:"domains,omitempty" yaml:"domains,omitempty" export:"true"`
 }
 
+// Load-balancing strategies of the TCP and UDP servers load-balancers.
+const (
+	// BalancingStrategyWRR forwards the connections to the servers in a weighted round-robin fashion.
+	BalancingStrategyWRR = "wrr"
+	// BalancingStrategyLeastConn forwards each connection to the server having the fewest ongoing connections,
+	// relatively to its weight.
+	BalancingStrategyLeastConn = "leastConn"
+	// BalancingStrategyConsistentHash forwards the connections of a client IP to the same server,
+	// as long as the servers do not change.
+	BalancingStrategyConsistentHash = "consistentHash"
+)
+
 // +k8s:deepcopy-gen=true
 
 // TCPServersLoadBalancer holds the LoadBalancerService configuration.
@@ -77,6 +118,8 @@ type TCPServersLoadBalancer struct {
 	ProxyProtocol    *ProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	Servers          []TCPServer    `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
 	ServersTransport string         `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	// Strategy defines how the connections are balanced between the servers: wrr (default), leastConn or consistentHash.
+	Strategy string `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty" export:"true"`
 }
 
 // Mergeable tells if the given service is mergeable.
@@ -103,6 +146,7 @@ type TCPServer struct {
 	Address string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty" label:"-"`
 	Port    string `toml:"-" json:"-" yaml:"-"`
 	TLS     bool   `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
+	Weight  *int   `json:"weight,omitempty" toml:"weight,omitempty" yaml:"weight,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -142,6 +186,7 @@ type TLSClientConfig struct {
 	InsecureSkipVerify bool                       `description:"Disables SSL certificate verification." json:"insecureSkipVerify,omitempty" toml:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty" export:"true"`
 	RootCAs            []traefiktls.FileOrContent `description:"Defines a list of CA secret used to validate self-signed certificate" json:"rootCAs,omitempty" toml:"rootCAs,omitempty" yaml:"rootCAs,omitempty"`
 	Certificates       traefiktls.Certificates    `description:"Defines a list of secret storing client certificates for mTLS." json:"certificates,omitempty" toml:"certificates,omitempty" yaml:"certificates,omitempty" export:"true"`
+	CertResolver       string                     `description:"Defines the internal CA certificates resolver issuing the client certificate presented to the servers." json:"certResolver,omitempty" toml:"certResolver,omitempty" yaml:"certResolver,omitempty" export:"true"`
 	PeerCertURI        string                     `description:"Defines the URI used to match against SAN URI during the peer certificate verification." json:"peerCertURI,omitempty" toml:"peerCertURI,omitempty" yaml:"peerCertURI,omitempty" export:"true"`
 	Spiffe             *Spiffe                    `description:"Defines the SPIFFE TLS configuration." json:"spiffe,omitempty" toml:"spiffe,omitempty" yaml:"spiffe,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 }
@@ -1,11 +1,21 @@
 package dynamic
 
+import (
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+)
+
 // +k8s:deepcopy-gen=true
 
 // TCPMiddleware holds the TCPMiddleware configuration.
 type TCPMiddleware struct {
 	InFlightConn *TCPInFlightConn `json:"inFlightConn,omitempty" toml:"inFlightConn,omitempty" yaml:"inFlightConn,omitempty" export:"true"`
 	IPAllowList  *TCPIPAllowList  `json:"ipAllowList,omitempty" toml:"ipAllowList,omitempty" yaml:"ipAllowList,omitempty" export:"true"`
+	IPDenyList   *TCPIPDenyList   `json:"ipDenyList,omitempty" toml:"ipDenyList,omitempty" yaml:"ipDenyList,omitempty" export:"true"`
+	MTLSAuthz    *TCPMTLSAuthz    `json:"mTLSAuthz,omitempty" toml:"mTLSAuthz,omitempty" yaml:"mTLSAuthz,omitempty" export:"true"`
+	RateLimit    *TCPRateLimit    `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
+	Timeout      *TCPTimeout      `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -28,3 +38,59 @@ type TCPIPAllowList struct {
 	// SourceRange defines the allowed IPs (or ranges of allowed IPs by using CIDR notation).
 	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
 }
+
+// +k8s:deepcopy-gen=true
+
+// TCPIPDenyList holds the TCP IPDenyList middleware configuration.
+// This middleware refuses connections based on the client IP.
+type TCPIPDenyList struct {
+	// SourceRange defines the denied IPs (or ranges of denied IPs by using CIDR notation).
+	SourceRange []string `json:"sourceRange,omitempty" toml:"sourceRange,omitempty" yaml:"sourceRange,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPRateLimit holds the TCP RateLimit middleware configuration.
+// This middleware refuses the new connections of a client IP above the given rate.
+type TCPRateLimit struct {
+	// Average is the maximum rate, by default in connections/s, allowed for a client IP.
+	// The rate is actually defined by dividing Average by Period. So for a rate below 1 connection/s,
+	// one needs to define a Period larger than a second.
+	Average int64 `json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	// Period, in combination with Average, defines the actual maximum rate, such as:
+	// r = Average / Period. It defaults to a second.
+	Period ptypes.Duration `json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty" export:"true"`
+	// Burst is the maximum number of connections allowed to be opened in the same arbitrarily small period of time.
+	// It defaults to 1.
+	Burst int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a TCPRateLimit.
+func (r *TCPRateLimit) SetDefaults() {
+	r.Burst = 1
+	r.Period = ptypes.Duration(time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPTimeout holds the TCP Timeout middleware configuration.
+// This middleware closes the connections which are idle, or opened, for too long.
+type TCPTimeout struct {
+	// IdleTimeout defines the maximum duration a connection can stay without any data exchanged in either direction.
+	IdleTimeout ptypes.Duration `json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
+	// MaxDuration defines the maximum duration a connection can stay opened.
+	MaxDuration ptypes.Duration `json:"maxDuration,omitempty" toml:"maxDuration,omitempty" yaml:"maxDuration,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPMTLSAuthz holds the TCP mTLS authorization middleware configuration.
+// This middleware accepts/refuses connections based on the verified client certificate.
+// More info: https://doc.traefik.io/traefik/v3.0/middlewares/tcp/mtlsauthz/
+type TCPMTLSAuthz struct {
+	// Allow defines the rules of which one must be matched by the client certificate.
+	// If empty, all the client certificates which are not denied are allowed.
+	Allow []CertificateRule `json:"allow,omitempty" toml:"allow,omitempty" yaml:"allow,omitempty" export:"true"`
+	// Deny defines the rules refusing the client certificate, taking precedence over the Allow rules.
+	Deny []CertificateRule `json:"deny,omitempty" toml:"deny,omitempty" yaml:"deny,omitempty" export:"true"`
+}
@@ -180,6 +180,9 @@ func TestDecodeConfiguration(t *testing.T) {
 
 		"traefik.tcp.middlewares.Middleware0.ipallowlist.sourcerange":      "foobar, fiibar",
 		"traefik.tcp.middlewares.Middleware2.inflightconn.amount":          "42",
+		"traefik.tcp.middlewares.Middleware3.ipdenylist.sourcerange":       "foobar, fiibar",
+		"traefik.tcp.middlewares.Middleware4.timeout.idletimeout":          "1s",
+		"traefik.tcp.middlewares.Middleware4.timeout.maxduration":          "1h",
 		"traefik.tcp.routers.Router0.rule":                                 "foobar",
 		"traefik.tcp.routers.Router0.priority":                             "42",
 		"traefik.tcp.routers.Router0.entrypoints":                          "foobar, fiibar",
@@ -195,16 +198,23 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.tcp.services.Service0.loadbalancer.server.Port":           "42",
 		"traefik.tcp.services.Service0.loadbalancer.proxyProtocol.version": "42",
 		"traefik.tcp.services.Service0.loadbalancer.serversTransport":      "foo",
+		"traefik.tcp.services.Service0.loadbalancer.strategy":              "consistentHash",
+		"traefik.tcp.services.Service0.loadbalancer.server.weight":         "42",
 		"traefik.tcp.services.Service1.loadbalancer.server.Port":           "42",
 		"traefik.tcp.services.Service1.loadbalancer.proxyProtocol":         "true",
 		"traefik.tcp.services.Service1.loadbalancer.serversTransport":      "foo",
 
-		"traefik.udp.routers.Router0.entrypoints":                "foobar, fiibar",
-		"traefik.udp.routers.Router0.service":                    "foobar",
-		"traefik.udp.routers.Router1.entrypoints":                "foobar, fiibar",
-		"traefik.udp.routers.Router1.service":                    "foobar",
-		"traefik.udp.services.Service0.loadbalancer.server.Port": "42",
-		"traefik.udp.services.Service1.loadbalancer.server.Port": "42",
+		"traefik.udp.middlewares.Middleware0.ipallowlist.sourcerange": "foobar, fiibar",
+		"traefik.udp.middlewares.Middleware1.maxsessions.amount":      "42",
+		"traefik.udp.routers.Router0.rule":                            "foobar",
+		"traefik.udp.routers.Router0.priority":                        "42",
+		"traefik.udp.routers.Router0.entrypoints":                     "foobar, fiibar",
+		"traefik.udp.routers.Router0.middlewares":                     "Middleware0",
+		"traefik.udp.routers.Router0.service":                         "foobar",
+		"traefik.udp.routers.Router1.entrypoints":                     "foobar, fiibar",
+		"traefik.udp.routers.Router1.service":                         "foobar",
+		"traefik.udp.services.Service0.loadbalancer.server.Port":      "42",
+		"traefik.udp.services.Service1.loadbalancer.server.Port":      "42",
 	}
 
 	configuration, err := DecodeConfiguration(labels)
@@ -251,17 +261,30 @@ func TestDecodeConfiguration(t *testing.T) {
 						Amount: 42,
 					},
 				},
+				"Middleware3": {
+					IPDenyList: &dynamic.TCPIPDenyList{
+						SourceRange: []string{"foobar", "fiibar"},
+					},
+				},
+				"Middleware4": {
+					Timeout: &dynamic.TCPTimeout{
+						IdleTimeout: ptypes.Duration(time.Second),
+						MaxDuration: ptypes.Duration(time.Hour),
+					},
+				},
 			},
 			Services: map[string]*dynamic.TCPService{
 				"Service0": {
 					LoadBalancer: &dynamic.TCPServersLoadBalancer{
 						Servers: []dynamic.TCPServer{
 							{
-								Port: "42",
+								Port:   "42",
+								Weight: func(v int) *int { return &v }(42),
 							},
 						},
 						ProxyProtocol:    &dynamic.ProxyProtocol{Version: 42},
 						ServersTransport: "foo",
+						Strategy:         "consistentHash",
 					},
 				},
 				"Service1": {
@@ -284,7 +307,10 @@ func TestDecodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
+					Middlewares: []string{"Middleware0"},
+					Service:     "foobar",
+					Rule:        "foobar",
+					Priority:    42,
 				},
 				"Router1": {
 					EntryPoints: []string{
@@ -294,6 +320,18 @@ func TestDecodeConfiguration(t *testing.T) {
 					Service: "foobar",
 				},
 			},
+			Middlewares: map[string]*dynamic.UDPMiddleware{
+				"Middleware0": {
+					IPAllowList: &dynamic.UDPIPAllowList{
+						SourceRange: []string{"foobar", "fiibar"},
+					},
+				},
+				"Middleware1": {
+					MaxSessions: &dynamic.UDPMaxSessions{
+						Amount: 42,
+					},
+				},
+			},
 			Services: map[string]*dynamic.UDPService{
 				"Service0": {
 					LoadBalancer: &dynamic.UDPServersLoadBalancer{
@@ -746,16 +784,29 @@ func TestEncodeConfiguration(t *testing.T) {
 						Amount: 42,
 					},
 				},
+				"Middleware3": {
+					IPDenyList: &dynamic.TCPIPDenyList{
+						SourceRange: []string{"foobar", "fiibar"},
+					},
+				},
+				"Middleware4": {
+					Timeout: &dynamic.TCPTimeout{
+						IdleTimeout: ptypes.Duration(time.Second),
+						MaxDuration: ptypes.Duration(time.Hour),
+					},
+				},
 			},
 			Services: map[string]*dynamic.TCPService{
 				"Service0": {
 					LoadBalancer: &dynamic.TCPServersLoadBalancer{
 						Servers: []dynamic.TCPServer{
 							{
-								Port: "42",
+								Port:   "42",
+								Weight: func(v int) *int { return &v }(42),
 							},
 						},
 						ServersTransport: "foo",
+						Strategy:         "consistentHash",
 					},
 				},
 				"Service1": {
@@ -777,7 +828,10 @@ func TestEncodeConfiguration(t *testing.T) {
 						"foobar",
 						"fiibar",
 					},
-					Service: "foobar",
+					Middlewares: []string{"Middleware0"},
+					Service:     "foobar",
+					Rule:        "foobar",
+					Priority:    42,
 				},
 				"Router1": {
 					EntryPoints: []string{
@@ -787,6 +841,18 @@ func TestEncodeConfiguration(t *testing.T) {
 					Service: "foobar",
 				},
 			},
+			Middlewares: map[string]*dynamic.UDPMiddleware{
+				"Middleware0": {
+					IPAllowList: &dynamic.UDPIPAllowList{
+						SourceRange: []string{"foobar", "fiibar"},
+					},
+				},
+				"Middleware1": {
+					MaxSessions: &dynamic.UDPMaxSessions{
+						Amount: 42,
+					},
+				},
+			},
 			Services: map[string]*dynamic.UDPService{
 				"Service0": {
 					LoadBalancer: &dynamic.UDPServersLoadBalancer{
@@ -1296,8 +1362,10 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Middlewares.Middleware14.ReplacePath.Path":                                   "foobar",
 		"traefik.HTTP.Middlewares.Middleware15.ReplacePathRegex.Regex":                             "foobar",
 		"traefik.HTTP.Middlewares.Middleware15.ReplacePathRegex.Replacement":                       "foobar",
+		"traefik.HTTP.Middlewares.Middleware16.Retry.AllowNonIdempotent":                           "false",
 		"traefik.HTTP.Middlewares.Middleware16.Retry.Attempts":                                     "42",
 		"traefik.HTTP.Middlewares.Middleware16.Retry.InitialInterval":                              "1000000000",
+		"traefik.HTTP.Middlewares.Middleware16.Retry.MaxRequestBodyBytes":                          "0",
 		"traefik.HTTP.Middlewares.Middleware17.StripPrefix.Prefixes":                               "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware18.StripPrefixRegex.Regex":                             "foobar, fiibar",
 		"traefik.HTTP.Middlewares.Middleware19.Compress.MinResponseBodyBytes":                      "42",
@@ -1352,6 +1420,9 @@ func TestEncodeConfiguration(t *testing.T) {
 
 		"traefik.TCP.Middlewares.Middleware0.IPAllowList.SourceRange": "foobar, fiibar",
 		"traefik.TCP.Middlewares.Middleware2.InFlightConn.Amount":     "42",
+		"traefik.TCP.Middlewares.Middleware3.IPDenyList.SourceRange":  "foobar, fiibar",
+		"traefik.TCP.Middlewares.Middleware4.Timeout.IdleTimeout":     "1000000000",
+		"traefik.TCP.Middlewares.Middleware4.Timeout.MaxDuration":     "3600000000000",
 		"traefik.TCP.Routers.Router0.Rule":                            "foobar",
 		"traefik.TCP.Routers.Router0.Priority":                        "42",
 		"traefik.TCP.Routers.Router0.EntryPoints":                     "foobar, fiibar",
@@ -1367,16 +1438,25 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":      "42",
 		"traefik.TCP.Services.Service0.LoadBalancer.server.TLS":       "false",
 		"traefik.TCP.Services.Service0.LoadBalancer.ServersTransport": "foo",
+		"traefik.TCP.Services.Service0.LoadBalancer.Strategy":         "consistentHash",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.Weight":    "42",
 		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":      "42",
 		"traefik.TCP.Services.Service1.LoadBalancer.server.TLS":       "false",
 		"traefik.TCP.Services.Service1.LoadBalancer.ServersTransport": "foo",
 
-		"traefik.UDP.Routers.Router0.EntryPoints":                "foobar, fiibar",
-		"traefik.UDP.Routers.Router0.Service":                    "foobar",
-		"traefik.UDP.Routers.Router1.EntryPoints":                "foobar, fiibar",
-		"traefik.UDP.Routers.Router1.Service":                    "foobar",
-		"traefik.UDP.Services.Service0.LoadBalancer.server.Port": "42",
-		"traefik.UDP.Services.Service1.LoadBalancer.server.Port": "42",
+		"traefik.UDP.Middlewares.Middleware0.IPAllowList.SourceRange": "foobar, fiibar",
+		"traefik.UDP.Middlewares.Middleware1.MaxSessions.Amount":      "42",
+		"traefik.UDP.Middlewares.Middleware1.MaxSessions.PerClientIP": "0",
+		"traefik.UDP.Routers.Router0.Rule":                            "foobar",
+		"traefik.UDP.Routers.Router0.Priority":                        "42",
+		"traefik.UDP.Routers.Router0.EntryPoints":                     "foobar, fiibar",
+		"traefik.UDP.Routers.Router0.Middlewares":                     "Middleware0",
+		"traefik.UDP.Routers.Router0.Service":                         "foobar",
+		"traefik.UDP.Routers.Router1.Priority":                        "0",
+		"traefik.UDP.Routers.Router1.EntryPoints":                     "foobar, fiibar",
+		"traefik.UDP.Routers.Router1.Service":                         "foobar",
+		"traefik.UDP.Services.Service0.LoadBalancer.server.Port":      "42",
+		"traefik.UDP.Services.Service1.LoadBalancer.server.Port":      "42",
 	}
 
 	for key, val := range expected {
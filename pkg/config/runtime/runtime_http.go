@@ -144,6 +144,27 @@ type ServiceInfo struct {
 
 	serverStatusMu sync.RWMutex
 	serverStatus   map[string]string // keyed by server URL
+
+	tierStatusMu sync.RWMutex
+	tierStatus   []TierStatus // ordered as the tiers of a failover service
+}
+
+// Status of a tier of a failover service.
+const (
+	// TierStatusActive is the status of the tier receiving the requests.
+	TierStatusActive = "ACTIVE"
+	// TierStatusStandby is the status of a tier having enough healthy servers, but not receiving the requests.
+	TierStatusStandby = "STANDBY"
+	// TierStatusDown is the status of a tier without enough healthy servers.
+	TierStatusDown = "DOWN"
+)
+
+// TierStatus holds the status of a tier of a failover service.
+type TierStatus struct {
+	Service    string `json:"service,omitempty"`
+	Status     string `json:"status,omitempty"`
+	Healthy    int    `json:"healthy"`
+	MinHealthy int    `json:"minHealthy"`
 }
 
 // AddError adds err to s.Err, if it does not already exist.
@@ -195,3 +216,25 @@ func (s *ServiceInfo) GetAllStatus() map[string]string {
 	}
 	return allStatus
 }
+
+// UpdateTierStatus sets the status of the tiers of the failover service in the ServiceInfo.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) UpdateTierStatus(tiers []TierStatus) {
+	s.tierStatusMu.Lock()
+	defer s.tierStatusMu.Unlock()
+
+	s.tierStatus = append(s.tierStatus[:0:0], tiers...)
+}
+
+// GetTierStatus returns the statuses of the tiers of the failover service in ServiceInfo.
+// It is the responsibility of the caller to check that s is not nil.
+func (s *ServiceInfo) GetTierStatus() []TierStatus {
+	s.tierStatusMu.RLock()
+	defer s.tierStatusMu.RUnlock()
+
+	if len(s.tierStatus) == 0 {
+		return nil
+	}
+
+	return append([]TierStatus(nil), s.tierStatus...)
+}
@@ -20,6 +20,7 @@ type EntryPoint struct {
 	HTTP2            *HTTP2Config          `description:"HTTP/2 configuration." json:"http2,omitempty" toml:"http2,omitempty" yaml:"http2,omitempty" export:"true"`
 	HTTP3            *HTTP3Config          `description:"HTTP/3 configuration." json:"http3,omitempty" toml:"http3,omitempty" yaml:"http3,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	UDP              *UDPConfig            `description:"UDP configuration." json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
+	StartTLS         *StartTLSConfig       `description:"STARTTLS configuration." json:"startTLS,omitempty" toml:"startTLS,omitempty" yaml:"startTLS,omitempty" export:"true"`
 }
 
 // GetAddress strips any potential protocol part of the address field of the
@@ -78,6 +79,19 @@ type HTTP3Config struct {
 	AdvertisedPort int `description:"UDP port to advertise, on which HTTP/3 is available." json:"advertisedPort,omitempty" toml:"advertisedPort,omitempty" yaml:"advertisedPort,omitempty" export:"true"`
 }
 
+// StartTLS protocols in which the server speaks first, and which must therefore be configured on the entry point.
+const (
+	StartTLSMySQL = "mysql"
+	StartTLSSMTP  = "smtp"
+	StartTLSIMAP  = "imap"
+	StartTLSPOP3  = "pop3"
+)
+
+// StartTLSConfig is the STARTTLS configuration of an entry point.
+type StartTLSConfig struct {
+	Protocol string `description:"Protocol in which the server speaks first, negotiating STARTTLS with the clients before the TLS routing (mysql, smtp, imap or pop3)." json:"protocol,omitempty" toml:"protocol,omitempty" yaml:"protocol,omitempty" export:"true"`
+}
+
 // Redirections is a set of redirection for an entry point.
 type Redirections struct {
 	EntryPoint *RedirectEntryPoint `description:"Set of redirection for an entry point." json:"entryPoint,omitempty" toml:"entryPoint,omitempty" yaml:"entryPoint,omitempty" export:"true"`
@@ -113,8 +127,9 @@ type ForwardedHeaders struct {
 
 // ProxyProtocol contains Proxy-Protocol configuration.
 type ProxyProtocol struct {
-	Insecure   bool     `description:"Trust all." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
-	TrustedIPs []string `description:"Trust only selected IPs." json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+	Insecure   bool              `description:"Trust all." json:"insecure,omitempty" toml:"insecure,omitempty" yaml:"insecure,omitempty" export:"true"`
+	TrustedIPs []string          `description:"Trust only selected IPs." json:"trustedIPs,omitempty" toml:"trustedIPs,omitempty" yaml:"trustedIPs,omitempty"`
+	TLVHeaders map[string]string `description:"Forwards the values of the PROXY protocol v2 TLVs as request headers, by TLV type." json:"tlvHeaders,omitempty" toml:"tlvHeaders,omitempty" yaml:"tlvHeaders,omitempty" export:"true"`
 }
 
 // EntryPoints holds the HTTP entry point list.
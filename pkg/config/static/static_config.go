@@ -18,6 +18,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/provider/file"
 	"github.com/traefik/traefik/v3/pkg/provider/http"
 	"github.com/traefik/traefik/v3/pkg/provider/hub"
+	"github.com/traefik/traefik/v3/pkg/provider/internalca"
 	"github.com/traefik/traefik/v3/pkg/provider/kubernetes/crd"
 	"github.com/traefik/traefik/v3/pkg/provider/kubernetes/gateway"
 	"github.com/traefik/traefik/v3/pkg/provider/kubernetes/ingress"
@@ -78,6 +79,10 @@ type Configuration struct {
 
 	CertificatesResolvers map[string]CertificateResolver `description:"Certificates resolvers configuration." json:"certificatesResolvers,omitempty" toml:"certificatesResolvers,omitempty" yaml:"certificatesResolvers,omitempty" export:"true"`
 
+	OCSP *tls.OCSPConfig `description:"OCSP configuration." json:"ocsp,omitempty" toml:"ocsp,omitempty" yaml:"ocsp,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+
+	SessionTickets *SessionTickets `description:"TLS session tickets configuration." json:"sessionTickets,omitempty" toml:"sessionTickets,omitempty" yaml:"sessionTickets,omitempty" export:"true"`
+
 	Hub *hub.Provider `description:"Traefik Hub configuration." json:"hub,omitempty" toml:"hub,omitempty" yaml:"hub,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 
 	Experimental *Experimental `description:"experimental features." json:"experimental,omitempty" toml:"experimental,omitempty" yaml:"experimental,omitempty" export:"true"`
@@ -90,10 +95,16 @@ type SpiffeClientConfig struct {
 	WorkloadAPIAddr string `description:"Defines the workload API address." json:"workloadAPIAddr,omitempty" toml:"workloadAPIAddr,omitempty" yaml:"workloadAPIAddr,omitempty"`
 }
 
+// SessionTickets contains the configuration of the TLS session ticket keys shared between Traefik instances.
+type SessionTickets struct {
+	KVStorage *acmeprovider.KVStorage `description:"KV store sharing the TLS session ticket keys between Traefik instances." json:"kvStorage,omitempty" toml:"kvStorage,omitempty" yaml:"kvStorage,omitempty" export:"true"`
+}
+
 // CertificateResolver contains the configuration for the different types of certificates resolver.
 type CertificateResolver struct {
-	ACME      *acmeprovider.Configuration `description:"Enables ACME (Let's Encrypt) automatic SSL." json:"acme,omitempty" toml:"acme,omitempty" yaml:"acme,omitempty" export:"true"`
-	Tailscale *struct{}                   `description:"Enables Tailscale certificate resolution." json:"tailscale,omitempty" toml:"tailscale,omitempty" yaml:"tailscale,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	ACME       *acmeprovider.Configuration `description:"Enables ACME (Let's Encrypt) automatic SSL." json:"acme,omitempty" toml:"acme,omitempty" yaml:"acme,omitempty" export:"true"`
+	Tailscale  *struct{}                   `description:"Enables Tailscale certificate resolution." json:"tailscale,omitempty" toml:"tailscale,omitempty" yaml:"tailscale,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	InternalCA *internalca.Configuration   `description:"Enables certificates issued by an internal CA." json:"internalCA,omitempty" toml:"internalCA,omitempty" yaml:"internalCA,omitempty" export:"true"`
 }
 
 // Global holds the global configuration.
@@ -321,12 +332,16 @@ func (c *Configuration) initACMEProvider() {
 
 // ValidateConfiguration validate that configuration is coherent.
 func (c *Configuration) ValidateConfiguration() error {
-	var acmeEmail string
+	var acmeEmail, onDemandResolver string
 	for name, resolver := range c.CertificatesResolvers {
 		if resolver.ACME != nil && resolver.Tailscale != nil {
 			return fmt.Errorf("unable to initialize certificates resolver %q, as ACME and Tailscale providers are mutually exclusive", name)
 		}
 
+		if resolver.InternalCA != nil && (resolver.ACME != nil || resolver.Tailscale != nil) {
+			return fmt.Errorf("unable to initialize certificates resolver %q, as the internal CA provider is mutually exclusive with the ACME and Tailscale providers", name)
+		}
+
 		if resolver.ACME == nil {
 			continue
 		}
@@ -339,6 +354,25 @@ func (c *Configuration) ValidateConfiguration() error {
 			return fmt.Errorf("unable to initialize certificates resolver %q, as all ACME resolvers must use the same email", name)
 		}
 		acmeEmail = resolver.ACME.Email
+
+		if resolver.ACME.OnDemand != nil {
+			if onDemandResolver != "" {
+				return fmt.Errorf("unable to initialize certificates resolver %q, as on-demand certificates are already enabled by the resolver %q", name, onDemandResolver)
+			}
+			onDemandResolver = name
+		}
+	}
+
+	for name, entryPoint := range c.EntryPoints {
+		if entryPoint.StartTLS == nil {
+			continue
+		}
+
+		switch entryPoint.StartTLS.Protocol {
+		case StartTLSMySQL, StartTLSSMTP, StartTLSIMAP, StartTLSPOP3:
+		default:
+			return fmt.Errorf("invalid STARTTLS protocol %q on entry point %q, expected one of mysql, smtp, imap or pop3", entryPoint.StartTLS.Protocol, name)
+		}
 	}
 
 	return nil
@@ -36,6 +36,14 @@ type StatusUpdater interface {
 	RegisterStatusUpdater(fn func(up bool)) error
 }
 
+// HealthyCountUpdater should be implemented by a service that, when the number
+// of its healthy children changes, needs to propagate upwards (to their
+// parent(s)) that number.
+type HealthyCountUpdater interface {
+	HealthyCount() int
+	RegisterHealthyCountUpdater(fn func(healthy int))
+}
+
 type metricsHealthCheck interface {
 	ServiceServerUpGauge() gokitmetrics.Gauge
 }
@@ -14,5 +14,6 @@ const (
 	ServerName           = "serverName"
 	ServerIndex          = "serverIndex"
 	TLSStoreName         = "tlsStoreName"
+	TLSOptionsName       = "tlsOptionsName"
 	ServersTransportName = "serversTransport"
 )
@@ -23,6 +23,8 @@ const (
 	ddOpenConnsName               = "open.connections"
 
 	ddTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
+	ddTLSClientCertRejectsName      = "tls.client.cert.rejects.total"
+	ddTLSHandshakesName             = "tls.handshakes.total"
 
 	ddEntryPointReqsName        = "entrypoint.request.total"
 	ddEntryPointReqsTLSName     = "entrypoint.request.tls.total"
@@ -36,13 +38,15 @@ const (
 	ddRouterReqsBytesName    = "router.requests.bytes.total"
 	ddRouterRespsBytesName   = "router.responses.bytes.total"
 
-	ddServiceReqsName         = "service.request.total"
-	ddServiceReqsTLSName      = "service.request.tls.total"
-	ddServiceReqsDurationName = "service.request.duration"
-	ddServiceRetriesName      = "service.retries.total"
-	ddServiceServerUpName     = "service.server.up"
-	ddServiceReqsBytesName    = "service.requests.bytes.total"
-	ddServiceRespsBytesName   = "service.responses.bytes.total"
+	ddServiceReqsName             = "service.request.total"
+	ddServiceReqsTLSName          = "service.request.tls.total"
+	ddServiceReqsDurationName     = "service.request.duration"
+	ddServiceRetriesName          = "service.retries.total"
+	ddServiceHedgesName           = "service.hedges.total"
+	ddServiceMirrorMismatchesName = "service.mirror.mismatches.total"
+	ddServiceServerUpName         = "service.server.up"
+	ddServiceReqsBytesName        = "service.requests.bytes.total"
+	ddServiceRespsBytesName       = "service.responses.bytes.total"
 )
 
 // RegisterDatadog registers the metrics pusher if this didn't happen yet and creates a datadog Registry instance.
@@ -64,6 +68,8 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		lastConfigReloadSuccessGauge:   datadogClient.NewGauge(ddLastConfigReloadSuccessName),
 		openConnectionsGauge:           datadogClient.NewGauge(ddOpenConnsName),
 		tlsCertsNotAfterTimestampGauge: datadogClient.NewGauge(ddTLSCertsNotAfterTimestampName),
+		tlsClientCertRejectsCounter:    datadogClient.NewCounter(ddTLSClientCertRejectsName, 1.0),
+		tlsHandshakesCounter:           datadogClient.NewCounter(ddTLSHandshakesName, 1.0),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -90,6 +96,8 @@ func RegisterDatadog(ctx context.Context, config *types.Datadog) Registry {
 		registry.serviceReqsTLSCounter = datadogClient.NewCounter(ddServiceReqsTLSName, 1.0)
 		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(datadogClient.NewHistogram(ddServiceReqsDurationName, 1.0), time.Second)
 		registry.serviceRetriesCounter = datadogClient.NewCounter(ddServiceRetriesName, 1.0)
+		registry.serviceHedgesCounter = datadogClient.NewCounter(ddServiceHedgesName, 1.0)
+		registry.serviceMirrorMismatchesCounter = datadogClient.NewCounter(ddServiceMirrorMismatchesName, 1.0)
 		registry.serviceServerUpGauge = datadogClient.NewGauge(ddServiceServerUpName)
 		registry.serviceReqsBytesCounter = datadogClient.NewCounter(ddServiceReqsBytesName, 1.0)
 		registry.serviceRespsBytesCounter = datadogClient.NewCounter(ddServiceRespsBytesName, 1.0)
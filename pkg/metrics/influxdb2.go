@@ -29,6 +29,8 @@ const (
 	influxDBOpenConnsName               = "traefik.open.connections"
 
 	influxDBTLSCertsNotAfterTimestampName = "traefik.tls.certs.notAfterTimestamp"
+	influxDBTLSClientCertRejectsName      = "traefik.tls.client.cert.rejects.total"
+	influxDBTLSHandshakesName             = "traefik.tls.handshakes.total"
 
 	influxDBEntryPointReqsName        = "traefik.entrypoint.requests.total"
 	influxDBEntryPointReqsTLSName     = "traefik.entrypoint.requests.tls.total"
@@ -42,13 +44,15 @@ const (
 	influxDBRouterReqsBytesName    = "traefik.router.requests.bytes.total"
 	influxDBRouterRespsBytesName   = "traefik.router.responses.bytes.total"
 
-	influxDBServiceReqsName         = "traefik.service.requests.total"
-	influxDBServiceReqsTLSName      = "traefik.service.requests.tls.total"
-	influxDBServiceReqsDurationName = "traefik.service.request.duration"
-	influxDBServiceRetriesTotalName = "traefik.service.retries.total"
-	influxDBServiceServerUpName     = "traefik.service.server.up"
-	influxDBServiceReqsBytesName    = "traefik.service.requests.bytes.total"
-	influxDBServiceRespsBytesName   = "traefik.service.responses.bytes.total"
+	influxDBServiceReqsName                  = "traefik.service.requests.total"
+	influxDBServiceReqsTLSName               = "traefik.service.requests.tls.total"
+	influxDBServiceReqsDurationName          = "traefik.service.request.duration"
+	influxDBServiceRetriesTotalName          = "traefik.service.retries.total"
+	influxDBServiceHedgesTotalName           = "traefik.service.hedges.total"
+	influxDBServiceMirrorMismatchesTotalName = "traefik.service.mirror.mismatches.total"
+	influxDBServiceServerUpName              = "traefik.service.server.up"
+	influxDBServiceReqsBytesName             = "traefik.service.requests.bytes.total"
+	influxDBServiceRespsBytesName            = "traefik.service.responses.bytes.total"
 )
 
 // RegisterInfluxDB2 creates metrics exporter for InfluxDB2.
@@ -83,6 +87,8 @@ func RegisterInfluxDB2(ctx context.Context, config *types.InfluxDB2) Registry {
 		lastConfigReloadSuccessGauge:   influxDB2Store.NewGauge(influxDBLastConfigReloadSuccessName),
 		openConnectionsGauge:           influxDB2Store.NewGauge(influxDBOpenConnsName),
 		tlsCertsNotAfterTimestampGauge: influxDB2Store.NewGauge(influxDBTLSCertsNotAfterTimestampName),
+		tlsClientCertRejectsCounter:    influxDB2Store.NewCounter(influxDBTLSClientCertRejectsName),
+		tlsHandshakesCounter:           influxDB2Store.NewCounter(influxDBTLSHandshakesName),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -109,6 +115,8 @@ func RegisterInfluxDB2(ctx context.Context, config *types.InfluxDB2) Registry {
 		registry.serviceReqsTLSCounter = influxDB2Store.NewCounter(influxDBServiceReqsTLSName)
 		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(influxDB2Store.NewHistogram(influxDBServiceReqsDurationName), time.Second)
 		registry.serviceRetriesCounter = influxDB2Store.NewCounter(influxDBServiceRetriesTotalName)
+		registry.serviceHedgesCounter = influxDB2Store.NewCounter(influxDBServiceHedgesTotalName)
+		registry.serviceMirrorMismatchesCounter = influxDB2Store.NewCounter(influxDBServiceMirrorMismatchesTotalName)
 		registry.serviceServerUpGauge = influxDB2Store.NewGauge(influxDBServiceServerUpName)
 		registry.serviceReqsBytesCounter = influxDB2Store.NewCounter(influxDBServiceReqsBytesName)
 		registry.serviceRespsBytesCounter = influxDB2Store.NewCounter(influxDBServiceRespsBytesName)
@@ -28,6 +28,8 @@ type Registry interface {
 	// TLS
 
 	TLSCertsNotAfterTimestampGauge() metrics.Gauge
+	TLSClientCertRejectsCounter() metrics.Counter
+	TLSHandshakesCounter() metrics.Counter
 
 	// entry point metrics
 
@@ -51,6 +53,8 @@ type Registry interface {
 	ServiceReqsTLSCounter() metrics.Counter
 	ServiceReqDurationHistogram() ScalableHistogram
 	ServiceRetriesCounter() metrics.Counter
+	ServiceHedgesCounter() metrics.Counter
+	ServiceMirrorMismatchesCounter() metrics.Counter
 	ServiceServerUpGauge() metrics.Gauge
 	ServiceReqsBytesCounter() metrics.Counter
 	ServiceRespsBytesCounter() metrics.Counter
@@ -70,6 +74,8 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var lastConfigReloadSuccessGauge []metrics.Gauge
 	var openConnectionsGauge []metrics.Gauge
 	var tlsCertsNotAfterTimestampGauge []metrics.Gauge
+	var tlsClientCertRejectsCounter []metrics.Counter
+	var tlsHandshakesCounter []metrics.Counter
 	var entryPointReqsCounter []CounterWithHeaders
 	var entryPointReqsTLSCounter []metrics.Counter
 	var entryPointReqDurationHistogram []ScalableHistogram
@@ -84,6 +90,8 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var serviceReqsTLSCounter []metrics.Counter
 	var serviceReqDurationHistogram []ScalableHistogram
 	var serviceRetriesCounter []metrics.Counter
+	var serviceHedgesCounter []metrics.Counter
+	var serviceMirrorMismatchesCounter []metrics.Counter
 	var serviceServerUpGauge []metrics.Gauge
 	var serviceReqsBytesCounter []metrics.Counter
 	var serviceRespsBytesCounter []metrics.Counter
@@ -101,6 +109,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.TLSCertsNotAfterTimestampGauge() != nil {
 			tlsCertsNotAfterTimestampGauge = append(tlsCertsNotAfterTimestampGauge, r.TLSCertsNotAfterTimestampGauge())
 		}
+		if r.TLSClientCertRejectsCounter() != nil {
+			tlsClientCertRejectsCounter = append(tlsClientCertRejectsCounter, r.TLSClientCertRejectsCounter())
+		}
+		if r.TLSHandshakesCounter() != nil {
+			tlsHandshakesCounter = append(tlsHandshakesCounter, r.TLSHandshakesCounter())
+		}
 		if r.EntryPointReqsCounter() != nil {
 			entryPointReqsCounter = append(entryPointReqsCounter, r.EntryPointReqsCounter())
 		}
@@ -143,6 +157,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceRetriesCounter() != nil {
 			serviceRetriesCounter = append(serviceRetriesCounter, r.ServiceRetriesCounter())
 		}
+		if r.ServiceHedgesCounter() != nil {
+			serviceHedgesCounter = append(serviceHedgesCounter, r.ServiceHedgesCounter())
+		}
+		if r.ServiceMirrorMismatchesCounter() != nil {
+			serviceMirrorMismatchesCounter = append(serviceMirrorMismatchesCounter, r.ServiceMirrorMismatchesCounter())
+		}
 		if r.ServiceServerUpGauge() != nil {
 			serviceServerUpGauge = append(serviceServerUpGauge, r.ServiceServerUpGauge())
 		}
@@ -162,6 +182,8 @@ func NewMultiRegistry(registries []Registry) Registry {
 		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
 		openConnectionsGauge:           multi.NewGauge(openConnectionsGauge...),
 		tlsCertsNotAfterTimestampGauge: multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
+		tlsClientCertRejectsCounter:    multi.NewCounter(tlsClientCertRejectsCounter...),
+		tlsHandshakesCounter:           multi.NewCounter(tlsHandshakesCounter...),
 		entryPointReqsCounter:          NewMultiCounterWithHeaders(entryPointReqsCounter...),
 		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
 		entryPointReqDurationHistogram: MultiHistogram(entryPointReqDurationHistogram),
@@ -176,6 +198,8 @@ func NewMultiRegistry(registries []Registry) Registry {
 		serviceReqsTLSCounter:          multi.NewCounter(serviceReqsTLSCounter...),
 		serviceReqDurationHistogram:    MultiHistogram(serviceReqDurationHistogram),
 		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
+		serviceHedgesCounter:           multi.NewCounter(serviceHedgesCounter...),
+		serviceMirrorMismatchesCounter: multi.NewCounter(serviceMirrorMismatchesCounter...),
 		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
 		serviceReqsBytesCounter:        multi.NewCounter(serviceReqsBytesCounter...),
 		serviceRespsBytesCounter:       multi.NewCounter(serviceRespsBytesCounter...),
@@ -190,6 +214,8 @@ type standardRegistry struct {
 	lastConfigReloadSuccessGauge   metrics.Gauge
 	openConnectionsGauge           metrics.Gauge
 	tlsCertsNotAfterTimestampGauge metrics.Gauge
+	tlsClientCertRejectsCounter    metrics.Counter
+	tlsHandshakesCounter           metrics.Counter
 	entryPointReqsCounter          CounterWithHeaders
 	entryPointReqsTLSCounter       metrics.Counter
 	entryPointReqDurationHistogram ScalableHistogram
@@ -204,6 +230,8 @@ type standardRegistry struct {
 	serviceReqsTLSCounter          metrics.Counter
 	serviceReqDurationHistogram    ScalableHistogram
 	serviceRetriesCounter          metrics.Counter
+	serviceHedgesCounter           metrics.Counter
+	serviceMirrorMismatchesCounter metrics.Counter
 	serviceServerUpGauge           metrics.Gauge
 	serviceReqsBytesCounter        metrics.Counter
 	serviceRespsBytesCounter       metrics.Counter
@@ -237,6 +265,14 @@ func (r *standardRegistry) TLSCertsNotAfterTimestampGauge() metrics.Gauge {
 	return r.tlsCertsNotAfterTimestampGauge
 }
 
+func (r *standardRegistry) TLSClientCertRejectsCounter() metrics.Counter {
+	return r.tlsClientCertRejectsCounter
+}
+
+func (r *standardRegistry) TLSHandshakesCounter() metrics.Counter {
+	return r.tlsHandshakesCounter
+}
+
 func (r *standardRegistry) EntryPointReqsCounter() CounterWithHeaders {
 	return r.entryPointReqsCounter
 }
@@ -293,6 +329,14 @@ func (r *standardRegistry) ServiceRetriesCounter() metrics.Counter {
 	return r.serviceRetriesCounter
 }
 
+func (r *standardRegistry) ServiceHedgesCounter() metrics.Counter {
+	return r.serviceHedgesCounter
+}
+
+func (r *standardRegistry) ServiceMirrorMismatchesCounter() metrics.Counter {
+	return r.serviceMirrorMismatchesCounter
+}
+
 func (r *standardRegistry) ServiceServerUpGauge() metrics.Gauge {
 	return r.serviceServerUpGauge
 }
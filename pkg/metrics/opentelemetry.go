@@ -60,6 +60,10 @@ func RegisterOpenTelemetry(ctx context.Context, config *types.OpenTelemetry) Reg
 		lastConfigReloadSuccessGauge:   newOTLPGaugeFrom(meter, configLastReloadSuccessName, "Last config reload success", unit.Milliseconds),
 		openConnectionsGauge:           newOTLPGaugeFrom(meter, openConnectionsName, "How many open connections exist, by entryPoint and protocol", unit.Dimensionless),
 		tlsCertsNotAfterTimestampGauge: newOTLPGaugeFrom(meter, tlsCertsNotAfterTimestampName, "Certificate expiration timestamp", unit.Milliseconds),
+		tlsClientCertRejectsCounter: newOTLPCounterFrom(meter, tlsClientCertRejectsTotalName,
+			"How many client certificates have been rejected by the revocation checks, partitioned by TLS options and reason."),
+		tlsHandshakesCounter: newOTLPCounterFrom(meter, tlsHandshakesTotalName,
+			"How many TLS handshakes have been completed, partitioned by TLS options and session resumption."),
 	}
 
 	if config.AddEntryPointsLabels {
@@ -92,6 +96,10 @@ func RegisterOpenTelemetry(ctx context.Context, config *types.OpenTelemetry) Reg
 			unit.Milliseconds), time.Second)
 		reg.serviceRetriesCounter = newOTLPCounterFrom(meter, serviceRetriesTotalName,
 			"How many request retries happened on a service.")
+		reg.serviceHedgesCounter = newOTLPCounterFrom(meter, serviceHedgesTotalName,
+			"How many hedged requests were sent to a service.")
+		reg.serviceMirrorMismatchesCounter = newOTLPCounterFrom(meter, serviceMirrorMismatchesTotalName,
+			"How many mirrored responses did not match the response of a service, partitioned by mirror and reason.")
 		reg.serviceServerUpGauge = newOTLPGaugeFrom(meter, serviceServerUpName,
 			"service server is up, described by gauge value of 0 or 1.",
 			unit.Dimensionless)
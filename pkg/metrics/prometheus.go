@@ -29,6 +29,8 @@ const (
 	// TLS.
 	metricsTLSPrefix              = MetricNamePrefix + "tls_"
 	tlsCertsNotAfterTimestampName = metricsTLSPrefix + "certs_not_after"
+	tlsClientCertRejectsTotalName = metricsTLSPrefix + "client_cert_rejects_total"
+	tlsHandshakesTotalName        = metricsTLSPrefix + "handshakes_total"
 
 	// entry point.
 	metricEntryPointPrefix        = MetricNamePrefix + "entrypoint_"
@@ -47,14 +49,16 @@ const (
 	routerRespsBytesTotalName = metricRouterPrefix + "responses_bytes_total"
 
 	// service level.
-	metricServicePrefix        = MetricNamePrefix + "service_"
-	serviceReqsTotalName       = metricServicePrefix + "requests_total"
-	serviceReqsTLSTotalName    = metricServicePrefix + "requests_tls_total"
-	serviceReqDurationName     = metricServicePrefix + "request_duration_seconds"
-	serviceRetriesTotalName    = metricServicePrefix + "retries_total"
-	serviceServerUpName        = metricServicePrefix + "server_up"
-	serviceReqsBytesTotalName  = metricServicePrefix + "requests_bytes_total"
-	serviceRespsBytesTotalName = metricServicePrefix + "responses_bytes_total"
+	metricServicePrefix              = MetricNamePrefix + "service_"
+	serviceReqsTotalName             = metricServicePrefix + "requests_total"
+	serviceReqsTLSTotalName          = metricServicePrefix + "requests_tls_total"
+	serviceReqDurationName           = metricServicePrefix + "request_duration_seconds"
+	serviceRetriesTotalName          = metricServicePrefix + "retries_total"
+	serviceHedgesTotalName           = metricServicePrefix + "hedges_total"
+	serviceMirrorMismatchesTotalName = metricServicePrefix + "mirror_mismatches_total"
+	serviceServerUpName              = metricServicePrefix + "server_up"
+	serviceReqsBytesTotalName        = metricServicePrefix + "requests_bytes_total"
+	serviceRespsBytesTotalName       = metricServicePrefix + "responses_bytes_total"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -122,6 +126,14 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Name: tlsCertsNotAfterTimestampName,
 		Help: "Certificate expiration timestamp",
 	}, []string{"cn", "serial", "sans"})
+	tlsClientCertRejects := newCounterFrom(stdprometheus.CounterOpts{
+		Name: tlsClientCertRejectsTotalName,
+		Help: "How many client certificates have been rejected by the revocation checks, partitioned by TLS options and reason.",
+	}, []string{"tls_options", "reason"})
+	tlsHandshakes := newCounterFrom(stdprometheus.CounterOpts{
+		Name: tlsHandshakesTotalName,
+		Help: "How many TLS handshakes have been completed, partitioned by TLS options and session resumption.",
+	}, []string{"tls_options", "resumed"})
 	openConnections := newGaugeFrom(stdprometheus.GaugeOpts{
 		Name: openConnectionsName,
 		Help: "How many open connections exist, by entryPoint and protocol",
@@ -131,6 +143,8 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		configReloads.cv,
 		lastConfigReloadSuccess.gv,
 		tlsCertsNotAfterTimestamp.gv,
+		tlsClientCertRejects.cv,
+		tlsHandshakes.cv,
 		openConnections.gv,
 	}
 
@@ -141,6 +155,8 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		configReloadsCounter:           configReloads,
 		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
 		tlsCertsNotAfterTimestampGauge: tlsCertsNotAfterTimestamp,
+		tlsClientCertRejectsCounter:    tlsClientCertRejects,
+		tlsHandshakesCounter:           tlsHandshakes,
 		openConnectionsGauge:           openConnections,
 	}
 
@@ -237,6 +253,14 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			Name: serviceRetriesTotalName,
 			Help: "How many request retries happened on a service.",
 		}, []string{"service"})
+		serviceHedges := newCounterFrom(stdprometheus.CounterOpts{
+			Name: serviceHedgesTotalName,
+			Help: "How many hedged requests were sent to a service.",
+		}, []string{"service"})
+		serviceMirrorMismatches := newCounterFrom(stdprometheus.CounterOpts{
+			Name: serviceMirrorMismatchesTotalName,
+			Help: "How many mirrored responses did not match the response of a service, partitioned by mirror and reason.",
+		}, []string{"service", "mirror", "reason"})
 		serviceServerUp := newGaugeFrom(stdprometheus.GaugeOpts{
 			Name: serviceServerUpName,
 			Help: "service server is up, described by gauge value of 0 or 1.",
@@ -255,6 +279,8 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 			serviceReqsTLS.cv,
 			serviceReqDurations.hv,
 			serviceRetries.cv,
+			serviceHedges.cv,
+			serviceMirrorMismatches.cv,
 			serviceServerUp.gv,
 			serviceReqsBytesTotal.cv,
 			serviceRespsBytesTotal.cv,
@@ -264,6 +290,8 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		reg.serviceReqsTLSCounter = serviceReqsTLS
 		reg.serviceReqDurationHistogram, _ = NewHistogramWithScale(serviceReqDurations, time.Second)
 		reg.serviceRetriesCounter = serviceRetries
+		reg.serviceHedgesCounter = serviceHedges
+		reg.serviceMirrorMismatchesCounter = serviceMirrorMismatches
 		reg.serviceServerUpGauge = serviceServerUp
 		reg.serviceReqsBytesCounter = serviceReqsBytesTotal
 		reg.serviceRespsBytesCounter = serviceRespsBytesTotal
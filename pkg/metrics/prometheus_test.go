@@ -115,6 +115,14 @@ func TestPrometheus(t *testing.T) {
 		TLSCertsNotAfterTimestampGauge().
 		With("cn", "value", "serial", "value", "sans", "value").
 		Set(float64(time.Now().Unix()))
+	prometheusRegistry.
+		TLSClientCertRejectsCounter().
+		With("tls_options", "default", "reason", "crl_revoked").
+		Add(1)
+	prometheusRegistry.
+		TLSHandshakesCounter().
+		With("tls_options", "default", "resumed", "true").
+		Add(1)
 
 	prometheusRegistry.
 		EntryPointReqsCounter().
@@ -170,6 +178,14 @@ func TestPrometheus(t *testing.T) {
 		ServiceRetriesCounter().
 		With("service", "service1").
 		Add(1)
+	prometheusRegistry.
+		ServiceHedgesCounter().
+		With("service", "service1").
+		Add(1)
+	prometheusRegistry.
+		ServiceMirrorMismatchesCounter().
+		With("service", "service1", "mirror", "mirror1", "reason", "status").
+		Add(1)
 	prometheusRegistry.
 		ServiceServerUpGauge().
 		With("service", "service1", "url", "http://127.0.0.10:80").
@@ -217,6 +233,22 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildTimestampAssert(t, tlsCertsNotAfterTimestampName),
 		},
+		{
+			name: tlsClientCertRejectsTotalName,
+			labels: map[string]string{
+				"tls_options": "default",
+				"reason":      "crl_revoked",
+			},
+			assert: buildCounterAssert(t, tlsClientCertRejectsTotalName, 1),
+		},
+		{
+			name: tlsHandshakesTotalName,
+			labels: map[string]string{
+				"tls_options": "default",
+				"resumed":     "true",
+			},
+			assert: buildCounterAssert(t, tlsHandshakesTotalName, 1),
+		},
 		{
 			name: entryPointReqsTotalName,
 			labels: map[string]string{
@@ -350,6 +382,22 @@ func TestPrometheus(t *testing.T) {
 			},
 			assert: buildGreaterThanCounterAssert(t, serviceRetriesTotalName, 1),
 		},
+		{
+			name: serviceHedgesTotalName,
+			labels: map[string]string{
+				"service": "service1",
+			},
+			assert: buildGreaterThanCounterAssert(t, serviceHedgesTotalName, 1),
+		},
+		{
+			name: serviceMirrorMismatchesTotalName,
+			labels: map[string]string{
+				"service": "service1",
+				"mirror":  "mirror1",
+				"reason":  "status",
+			},
+			assert: buildGreaterThanCounterAssert(t, serviceMirrorMismatchesTotalName, 1),
+		},
 		{
 			name: serviceServerUpName,
 			labels: map[string]string{
@@ -22,6 +22,8 @@ const (
 	statsdOpenConnectionsName         = "open.connections"
 
 	statsdTLSCertsNotAfterTimestampName = "tls.certs.notAfterTimestamp"
+	statsdTLSClientCertRejectsName      = "tls.client.cert.rejects.total"
+	statsdTLSHandshakesName             = "tls.handshakes.total"
 
 	statsdEntryPointReqsName        = "entrypoint.request.total"
 	statsdEntryPointReqsTLSName     = "entrypoint.request.tls.total"
@@ -35,13 +37,15 @@ const (
 	statsdRouterReqsBytesName    = "router.requests.bytes.total"
 	statsdRouterRespsBytesName   = "router.responses.bytes.total"
 
-	statsdServiceReqsName         = "service.request.total"
-	statsdServiceReqsTLSName      = "service.request.tls.total"
-	statsdServiceReqsDurationName = "service.request.duration"
-	statsdServiceRetriesTotalName = "service.retries.total"
-	statsdServiceServerUpName     = "service.server.up"
-	statsdServiceReqsBytesName    = "service.requests.bytes.total"
-	statsdServiceRespsBytesName   = "service.responses.bytes.total"
+	statsdServiceReqsName                  = "service.request.total"
+	statsdServiceReqsTLSName               = "service.request.tls.total"
+	statsdServiceReqsDurationName          = "service.request.duration"
+	statsdServiceRetriesTotalName          = "service.retries.total"
+	statsdServiceHedgesTotalName           = "service.hedges.total"
+	statsdServiceMirrorMismatchesTotalName = "service.mirror.mismatches.total"
+	statsdServiceServerUpName              = "service.server.up"
+	statsdServiceReqsBytesName             = "service.requests.bytes.total"
+	statsdServiceRespsBytesName            = "service.responses.bytes.total"
 )
 
 // RegisterStatsd registers the metrics pusher if this didn't happen yet and creates a statsd Registry instance.
@@ -61,6 +65,8 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		configReloadsCounter:           statsdClient.NewCounter(statsdConfigReloadsName, 1.0),
 		lastConfigReloadSuccessGauge:   statsdClient.NewGauge(statsdLastConfigReloadSuccessName),
 		tlsCertsNotAfterTimestampGauge: statsdClient.NewGauge(statsdTLSCertsNotAfterTimestampName),
+		tlsClientCertRejectsCounter:    statsdClient.NewCounter(statsdTLSClientCertRejectsName, 1.0),
+		tlsHandshakesCounter:           statsdClient.NewCounter(statsdTLSHandshakesName, 1.0),
 		openConnectionsGauge:           statsdClient.NewGauge(statsdOpenConnectionsName),
 	}
 
@@ -88,6 +94,8 @@ func RegisterStatsd(ctx context.Context, config *types.Statsd) Registry {
 		registry.serviceReqsTLSCounter = statsdClient.NewCounter(statsdServiceReqsTLSName, 1.0)
 		registry.serviceReqDurationHistogram, _ = NewHistogramWithScale(statsdClient.NewTiming(statsdServiceReqsDurationName, 1.0), time.Millisecond)
 		registry.serviceRetriesCounter = statsdClient.NewCounter(statsdServiceRetriesTotalName, 1.0)
+		registry.serviceHedgesCounter = statsdClient.NewCounter(statsdServiceHedgesTotalName, 1.0)
+		registry.serviceMirrorMismatchesCounter = statsdClient.NewCounter(statsdServiceMirrorMismatchesTotalName, 1.0)
 		registry.serviceServerUpGauge = statsdClient.NewGauge(statsdServiceServerUpName)
 		registry.serviceReqsBytesCounter = statsdClient.NewCounter(statsdServiceReqsBytesName, 1.0)
 		registry.serviceRespsBytesCounter = statsdClient.NewCounter(statsdServiceRespsBytesName, 1.0)
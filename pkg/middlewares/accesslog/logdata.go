@@ -70,6 +70,8 @@ const (
 	Overhead = "Overhead"
 	// RetryAttempts is the map key used for the amount of attempts the request was retried.
 	RetryAttempts = "RetryAttempts"
+	// Hedged is the map key used to tell whether a hedged request was sent to another server.
+	Hedged = "Hedged"
 
 	// TLSVersion is the version of TLS used in the request.
 	TLSVersion = "TLSVersion"
@@ -77,6 +79,10 @@ const (
 	TLSCipher = "TLSCipher"
 	// TLSClientSubject is the string representation of the TLS client certificate's Subject.
 	TLSClientSubject = "TLSClientSubject"
+	// TLSClientJA3 is the JA3 fingerprint of the TLS ClientHello.
+	TLSClientJA3 = "TLSClientJA3"
+	// TLSClientJA4 is the JA4 fingerprint of the TLS ClientHello.
+	TLSClientJA4 = "TLSClientJA4"
 )
 
 // These are written out in the default case when no config is provided to specify keys of interest.
@@ -118,9 +124,12 @@ func init() {
 	allCoreKeys[StartLocal] = struct{}{}
 	allCoreKeys[Overhead] = struct{}{}
 	allCoreKeys[RetryAttempts] = struct{}{}
+	allCoreKeys[Hedged] = struct{}{}
 	allCoreKeys[TLSVersion] = struct{}{}
 	allCoreKeys[TLSCipher] = struct{}{}
 	allCoreKeys[TLSClientSubject] = struct{}{}
+	allCoreKeys[TLSClientJA3] = struct{}{}
+	allCoreKeys[TLSClientJA4] = struct{}{}
 }
 
 // CoreLogData holds the fields computed from the request/response.
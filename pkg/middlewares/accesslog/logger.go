@@ -22,6 +22,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/middlewares/capture"
 	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/tls/fingerprint"
 	"github.com/traefik/traefik/v3/pkg/types"
 )
 
@@ -222,6 +223,10 @@ func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request, next http
 		if len(req.TLS.PeerCertificates) > 0 && req.TLS.PeerCertificates[0] != nil {
 			core[TLSClientSubject] = req.TLS.PeerCertificates[0].Subject.String()
 		}
+		if fingerprints := fingerprint.FromContext(req.Context()); fingerprints != nil {
+			core[TLSClientJA3] = fingerprints.JA3
+			core[TLSClientJA4] = fingerprints.JA4
+		}
 	}
 
 	core[ClientAddr] = req.RemoteAddr
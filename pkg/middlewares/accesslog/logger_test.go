@@ -2,6 +2,7 @@ package accesslog
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -23,6 +24,7 @@ import (
 	"github.com/stretchr/testify/require"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/middlewares/capture"
+	"github.com/traefik/traefik/v3/pkg/tls/fingerprint"
 	"github.com/traefik/traefik/v3/pkg/types"
 )
 
@@ -375,6 +377,8 @@ func TestLoggerJSON(t *testing.T) {
 				TLSClientSubject:          assertString("CN=foobar"),
 				TLSVersion:                assertString("1.3"),
 				TLSCipher:                 assertString("TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"),
+				TLSClientJA3:              assertString("ada70206e40642a3e4461f35503241d5"),
+				TLSClientJA4:              assertString("t13d1516h2_8daaf6152771_e5627efa2ab1"),
 				"time":                    assertNotEmpty(),
 				StartLocal:                assertNotEmpty(),
 				StartUTC:                  assertNotEmpty(),
@@ -787,6 +791,10 @@ func doLoggingTLSOpt(t *testing.T, config *types.AccessLog, enableTLS bool) {
 				Subject: pkix.Name{CommonName: "foobar"},
 			}},
 		}
+		req = req.WithContext(fingerprint.WithFingerprints(context.Background(), &fingerprint.Fingerprints{
+			JA3: "ada70206e40642a3e4461f35503241d5",
+			JA4: "t13d1516h2_8daaf6152771_e5627efa2ab1",
+		}))
 	}
 
 	chain := alice.New()
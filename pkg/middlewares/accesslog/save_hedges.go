@@ -0,0 +1,46 @@
+package accesslog
+
+import (
+	"context"
+	"net/http"
+)
+
+// SaveHedged stores in the LogDataTable that a hedged request was sent.
+// It must not be called while another handler may write to the LogDataTable.
+func SaveHedged(req *http.Request) {
+	table := GetLogData(req)
+	if table != nil {
+		table.Core[Hedged] = true
+	}
+}
+
+// WithLogDataCopy returns a copy of the request holding a copy of its LogDataTable,
+// so that the request and its copy can be served concurrently.
+// The returned function merges the fields written in the copied LogDataTable back into the original one,
+// and must not be called while the requests are still being served.
+func WithLogDataCopy(req *http.Request) (*http.Request, func()) {
+	table := GetLogData(req)
+	if table == nil {
+		return req.Clone(req.Context()), func() {}
+	}
+
+	tableCopy := &LogData{
+		Core:               make(CoreLogData, len(table.Core)),
+		Request:            table.Request,
+		DownstreamResponse: table.DownstreamResponse,
+	}
+	for k, v := range table.Core {
+		tableCopy.Core[k] = v
+	}
+
+	reqCopy := req.Clone(context.WithValue(req.Context(), DataTableKey, tableCopy))
+
+	return reqCopy, func() {
+		for k, v := range tableCopy.Core {
+			table.Core[k] = v
+		}
+		if tableCopy.OriginResponse != nil {
+			table.OriginResponse = tableCopy.OriginResponse
+		}
+	}
+}
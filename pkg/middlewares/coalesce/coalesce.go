@@ -0,0 +1,305 @@
+package coalesce
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/tracing"
+)
+
+const (
+	typeName = "Coalesce"
+
+	defaultMaxResponseBodyBytes = 1024 * 1024
+)
+
+// call is an in-flight request to the service, whose response is shared with the identical requests.
+type call struct {
+	done chan struct{}
+	// response is nil when the response cannot be shared.
+	response *sharedResponse
+}
+
+type sharedResponse struct {
+	code   int
+	header http.Header
+	body   []byte
+}
+
+// coalesce is a middleware collapsing the concurrent identical requests into a single request to the service.
+type coalesce struct {
+	next        http.Handler
+	name        string
+	varyHeaders []string
+	maxBodySize int64
+	timeout     time.Duration
+
+	callsMu sync.Mutex
+	calls   map[string]*call
+}
+
+// New creates a coalesce middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Coalesce, name string) (http.Handler, error) {
+	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
+
+	maxBodySize := config.MaxResponseBodyBytes
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxResponseBodyBytes
+	}
+
+	varyHeaders := make([]string, 0, len(config.VaryHeaders))
+	for _, header := range config.VaryHeaders {
+		varyHeaders = append(varyHeaders, http.CanonicalHeaderKey(header))
+	}
+
+	return &coalesce{
+		next:        next,
+		name:        name,
+		varyHeaders: varyHeaders,
+		maxBodySize: maxBodySize,
+		timeout:     time.Duration(config.Timeout),
+		calls:       make(map[string]*call),
+	}, nil
+}
+
+func (c *coalesce) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return c.name, tracing.SpanKindNoneEnum
+}
+
+func (c *coalesce) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !c.canCoalesce(req) {
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	key := c.key(req)
+
+	c.callsMu.Lock()
+	if cl, ok := c.calls[key]; ok {
+		c.callsMu.Unlock()
+		c.wait(rw, req, cl)
+		return
+	}
+
+	cl := &call{done: make(chan struct{})}
+	c.calls[key] = cl
+	c.callsMu.Unlock()
+
+	c.lead(rw, req, key, cl)
+}
+
+// lead forwards the request to the service, and shares its response with the identical requests.
+func (c *coalesce) lead(rw http.ResponseWriter, req *http.Request, key string, cl *call) {
+	recorder := &responseRecorder{rw: rw, maxBodySize: c.maxBodySize}
+
+	defer func() {
+		c.callsMu.Lock()
+		delete(c.calls, key)
+		c.callsMu.Unlock()
+
+		// If the handler panicked, or if the request has been canceled, the response is most likely incomplete,
+		// and the waiting requests are forwarded to the service.
+		if err := recover(); err != nil {
+			close(cl.done)
+			panic(err)
+		}
+
+		if req.Context().Err() == nil {
+			if response := recorder.sharedResponse(); response != nil && c.sharable(response.header) {
+				cl.response = response
+			}
+		}
+		close(cl.done)
+	}()
+
+	c.next.ServeHTTP(recorder, req)
+}
+
+// wait writes the response of the given call, once available.
+func (c *coalesce) wait(rw http.ResponseWriter, req *http.Request, cl *call) {
+	var timeout <-chan time.Time
+	if c.timeout > 0 {
+		timer := time.NewTimer(c.timeout)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	select {
+	case <-cl.done:
+	case <-timeout:
+		middlewares.GetLogger(req.Context(), c.name, typeName).Debug().Msg("Timeout while waiting for the shared response")
+		http.Error(rw, http.StatusText(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+		return
+	case <-req.Context().Done():
+		return
+	}
+
+	if cl.response == nil {
+		c.next.ServeHTTP(rw, req)
+		return
+	}
+
+	for k, v := range cl.response.header {
+		rw.Header()[k] = append([]string(nil), v...)
+	}
+	rw.WriteHeader(cl.response.code)
+	_, _ = rw.Write(cl.response.body)
+}
+
+// canCoalesce tells whether the response to the given request can be shared with identical requests.
+func (c *coalesce) canCoalesce(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+
+	if req.ContentLength != 0 || req.Body != nil && req.Body != http.NoBody || req.Header.Get("Upgrade") != "" {
+		return false
+	}
+
+	// The responses to authenticated requests are only shared between the requests with the same credentials.
+	for _, header := range []string{"Authorization", "Cookie"} {
+		if req.Header.Get(header) != "" && !c.varies(header) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *coalesce) varies(header string) bool {
+	for _, h := range c.varyHeaders {
+		if h == header {
+			return true
+		}
+	}
+	return false
+}
+
+// sharable tells whether a response with the given header only varies on request headers which are part of the key.
+func (c *coalesce) sharable(header http.Header) bool {
+	for _, value := range header.Values("Vary") {
+		for _, h := range strings.Split(value, ",") {
+			h = http.CanonicalHeaderKey(strings.TrimSpace(h))
+			if h == "" {
+				continue
+			}
+
+			if h == "*" || h != "Accept-Encoding" && !c.varies(h) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (c *coalesce) key(req *http.Request) string {
+	var key strings.Builder
+	key.WriteString(req.Method)
+	key.WriteByte(0)
+	key.WriteString(req.Host)
+	key.WriteString(req.URL.RequestURI())
+
+	// The response encoding depends on the Accept-Encoding header, whether or not the service advertises it with Vary.
+	key.WriteByte(0)
+	key.WriteString(strings.Join(req.Header.Values("Accept-Encoding"), ","))
+
+	for _, header := range c.varyHeaders {
+		key.WriteByte(0)
+		key.WriteString(strings.Join(req.Header.Values(header), ","))
+	}
+
+	return key.String()
+}
+
+var _ middlewares.Stateful = &responseRecorder{}
+
+// responseRecorder is a wrapper of type http.ResponseWriter
+// that records the response it writes, up to a maximum body size.
+type responseRecorder struct {
+	rw          http.ResponseWriter
+	maxBodySize int64
+
+	code     int
+	header   http.Header
+	body     bytes.Buffer
+	tooLarge bool
+	hijacked bool
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.rw.Header()
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	// Informational responses are forwarded, but only the final response is shared.
+	if r.code == 0 && (code < 100 || code > 199) {
+		r.code = code
+		r.header = r.rw.Header().Clone()
+	}
+
+	r.rw.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.code == 0 {
+		r.WriteHeader(http.StatusOK)
+	}
+
+	if !r.tooLarge {
+		if int64(r.body.Len()+len(b)) > r.maxBodySize {
+			r.tooLarge = true
+			r.body = bytes.Buffer{}
+		} else {
+			r.body.Write(b)
+		}
+	}
+
+	return r.rw.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if f, ok := r.rw.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("not a hijacker: %T", r.rw)
+	}
+
+	r.hijacked = true
+	return h.Hijack()
+}
+
+// sharedResponse returns the recorded response, or nil if it cannot be shared.
+func (r *responseRecorder) sharedResponse() *sharedResponse {
+	if r.tooLarge || r.hijacked {
+		return nil
+	}
+
+	code, header := r.code, r.header
+	if code == 0 {
+		code, header = http.StatusOK, r.rw.Header().Clone()
+	}
+
+	// A response setting cookies is specific to its client.
+	if header.Get("Set-Cookie") != "" {
+		return nil
+	}
+
+	return &sharedResponse{code: code, header: header, body: r.body.Bytes()}
+}
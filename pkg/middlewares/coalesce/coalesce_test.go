@@ -0,0 +1,261 @@
+package coalesce
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestCoalesce(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.Coalesce
+		requests      func() []*http.Request
+		response      string
+		vary          string
+		setCookie     bool
+		expectedCalls int64
+	}{
+		{
+			desc: "identical requests",
+			requests: func() []*http.Request {
+				return repeat(3, func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://foo/bar?a=b", nil) })
+			},
+			response:      "foo",
+			expectedCalls: 1,
+		},
+		{
+			desc: "different URLs",
+			requests: func() []*http.Request {
+				return []*http.Request{
+					httptest.NewRequest(http.MethodGet, "http://foo/bar", nil),
+					httptest.NewRequest(http.MethodGet, "http://foo/bar?a=b", nil),
+					httptest.NewRequest(http.MethodGet, "http://bar/bar", nil),
+				}
+			},
+			response:      "foo",
+			expectedCalls: 3,
+		},
+		{
+			desc:   "different vary header",
+			config: dynamic.Coalesce{VaryHeaders: []string{"accept-language"}},
+			requests: func() []*http.Request {
+				reqs := repeat(3, func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://foo/bar", nil) })
+				reqs[0].Header.Set("Accept-Language", "fr")
+				reqs[1].Header.Set("Accept-Language", "fr")
+				reqs[2].Header.Set("Accept-Language", "en")
+				return reqs
+			},
+			response:      "foo",
+			expectedCalls: 2,
+		},
+		{
+			desc: "different accept-encoding header",
+			requests: func() []*http.Request {
+				reqs := repeat(3, func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://foo/bar", nil) })
+				reqs[0].Header.Set("Accept-Encoding", "gzip")
+				reqs[1].Header.Set("Accept-Encoding", "gzip")
+				reqs[2].Header.Set("Accept-Encoding", "br")
+				return reqs
+			},
+			response:      "foo",
+			expectedCalls: 2,
+		},
+		{
+			desc: "response varying on a header outside of the key",
+			requests: func() []*http.Request {
+				return repeat(3, func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://foo/bar", nil) })
+			},
+			response:      "foo",
+			vary:          "Accept-Encoding, Accept-Language",
+			expectedCalls: 3,
+		},
+		{
+			desc: "response varying on everything",
+			requests: func() []*http.Request {
+				return repeat(3, func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://foo/bar", nil) })
+			},
+			response:      "foo",
+			vary:          "*",
+			expectedCalls: 3,
+		},
+		{
+			desc:   "response varying on the key headers",
+			config: dynamic.Coalesce{VaryHeaders: []string{"accept-language"}},
+			requests: func() []*http.Request {
+				return repeat(3, func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://foo/bar", nil) })
+			},
+			response:      "foo",
+			vary:          "accept-language, Accept-Encoding",
+			expectedCalls: 1,
+		},
+		{
+			desc: "unsafe method",
+			requests: func() []*http.Request {
+				return repeat(3, func() *http.Request {
+					return httptest.NewRequest(http.MethodPost, "http://foo/bar", strings.NewReader("foo"))
+				})
+			},
+			response:      "foo",
+			expectedCalls: 3,
+		},
+		{
+			desc: "authenticated requests",
+			requests: func() []*http.Request {
+				reqs := repeat(2, func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://foo/bar", nil) })
+				for _, req := range reqs {
+					req.Header.Set("Authorization", "Bearer foo")
+				}
+				return reqs
+			},
+			response:      "foo",
+			expectedCalls: 2,
+		},
+		{
+			desc:   "authenticated requests varying on authorization",
+			config: dynamic.Coalesce{VaryHeaders: []string{"Authorization"}},
+			requests: func() []*http.Request {
+				reqs := repeat(2, func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://foo/bar", nil) })
+				for _, req := range reqs {
+					req.Header.Set("Authorization", "Bearer foo")
+				}
+				return reqs
+			},
+			response:      "foo",
+			expectedCalls: 1,
+		},
+		{
+			desc:   "response too large",
+			config: dynamic.Coalesce{MaxResponseBodyBytes: 2},
+			requests: func() []*http.Request {
+				return repeat(3, func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://foo/bar", nil) })
+			},
+			response:      "foo",
+			expectedCalls: 3,
+		},
+		{
+			desc: "response setting a cookie",
+			requests: func() []*http.Request {
+				return repeat(3, func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://foo/bar", nil) })
+			},
+			response:      "foo",
+			setCookie:     true,
+			expectedCalls: 3,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int64
+			release := make(chan struct{})
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				// Only the first call blocks, so that the identical requests arrive while it is in flight.
+				if calls.Add(1) == 1 {
+					<-release
+				}
+
+				rw.Header().Set("X-Foo", "bar")
+				if test.vary != "" {
+					rw.Header().Set("Vary", test.vary)
+				}
+				if test.setCookie {
+					rw.Header().Set("Set-Cookie", "foo=bar")
+				}
+				rw.WriteHeader(http.StatusTeapot)
+				_, _ = rw.Write([]byte(test.response))
+			})
+
+			handler, err := New(context.Background(), next, test.config, "coalesce")
+			require.NoError(t, err)
+
+			reqs := test.requests()
+			recorders := make([]*httptest.ResponseRecorder, len(reqs))
+
+			var wg sync.WaitGroup
+			for i, req := range reqs {
+				recorders[i] = httptest.NewRecorder()
+
+				wg.Add(1)
+				go func(rw http.ResponseWriter, req *http.Request) {
+					defer wg.Done()
+					handler.ServeHTTP(rw, req)
+				}(recorders[i], req)
+
+				if i == 0 {
+					// Lets the first request become the in-flight one.
+					waitFor(t, func() bool { return calls.Load() == 1 })
+				}
+			}
+
+			// Lets the other requests either wait for the first one, or reach the next handler.
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			assert.Equal(t, test.expectedCalls, calls.Load())
+			for _, recorder := range recorders {
+				assert.Equal(t, http.StatusTeapot, recorder.Code)
+				assert.Equal(t, "bar", recorder.Header().Get("X-Foo"))
+				assert.Equal(t, test.response, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestCoalesce_timeout(t *testing.T) {
+	release := make(chan struct{})
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-release
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	handler, err := New(context.Background(), next, dynamic.Coalesce{Timeout: ptypes.Duration(10 * time.Millisecond)}, "coalesce")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://foo/bar", nil))
+	}()
+
+	waitFor(t, func() bool {
+		c := handler.(*coalesce)
+		c.callsMu.Lock()
+		defer c.callsMu.Unlock()
+		return len(c.calls) == 1
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://foo/bar", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+
+	close(release)
+	<-done
+}
+
+func repeat(n int, newRequest func() *http.Request) []*http.Request {
+	reqs := make([]*http.Request, n)
+	for i := range reqs {
+		reqs[i] = newRequest()
+	}
+	return reqs
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	assert.Eventually(t, condition, time.Second, time.Millisecond)
+}
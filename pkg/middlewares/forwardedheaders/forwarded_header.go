@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/traefik/traefik/v3/pkg/ip"
+	"github.com/traefik/traefik/v3/pkg/tls/fingerprint"
 )
 
 const (
@@ -19,6 +20,8 @@ const (
 	xForwardedMethod            = "X-Forwarded-Method"
 	xForwardedTLSClientCert     = "X-Forwarded-Tls-Client-Cert"
 	xForwardedTLSClientCertInfo = "X-Forwarded-Tls-Client-Cert-Info"
+	xForwardedTLSClientJA3      = "X-Forwarded-Tls-Client-Ja3"
+	xForwardedTLSClientJA4      = "X-Forwarded-Tls-Client-Ja4"
 	xRealIP                     = "X-Real-Ip"
 	connection                  = "Connection"
 	upgrade                     = "Upgrade"
@@ -34,6 +37,8 @@ var xHeaders = []string{
 	xForwardedMethod,
 	xForwardedTLSClientCert,
 	xForwardedTLSClientCertInfo,
+	xForwardedTLSClientJA3,
+	xForwardedTLSClientJA4,
 	xRealIP,
 }
 
@@ -174,6 +179,16 @@ func (x *XForwarded) rewrite(outreq *http.Request) {
 		unsafeHeader(outreq.Header).Set(xForwardedFor, strings.Join(xffs, ", "))
 	}
 
+	if fingerprints := fingerprint.FromContext(outreq.Context()); fingerprints != nil {
+		if unsafeHeader(outreq.Header).Get(xForwardedTLSClientJA3) == "" {
+			unsafeHeader(outreq.Header).Set(xForwardedTLSClientJA3, fingerprints.JA3)
+		}
+
+		if unsafeHeader(outreq.Header).Get(xForwardedTLSClientJA4) == "" {
+			unsafeHeader(outreq.Header).Set(xForwardedTLSClientJA4, fingerprints.JA4)
+		}
+	}
+
 	if x.hostname != "" {
 		unsafeHeader(outreq.Header).Set(xForwardedServer, x.hostname)
 	}
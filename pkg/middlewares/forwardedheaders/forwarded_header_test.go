@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/tls/fingerprint"
 )
 
 func TestServeHTTP(t *testing.T) {
@@ -19,6 +20,7 @@ func TestServeHTTP(t *testing.T) {
 		remoteAddr      string
 		expectedHeaders map[string]string
 		tls             bool
+		fingerprints    *fingerprint.Fingerprints
 		websocket       bool
 		host            string
 	}{
@@ -269,6 +271,41 @@ func TestServeHTTP(t *testing.T) {
 				xForwardedServer: "foo.com:8080",
 			},
 		},
+		{
+			desc:         "TLS ClientHello fingerprints",
+			tls:          true,
+			fingerprints: &fingerprint.Fingerprints{JA3: "ja3", JA4: "ja4"},
+			expectedHeaders: map[string]string{
+				xForwardedTLSClientJA3: "ja3",
+				xForwardedTLSClientJA4: "ja4",
+			},
+		},
+		{
+			desc: "untrusted incoming TLS ClientHello fingerprints",
+			incomingHeaders: map[string][]string{
+				xForwardedTLSClientJA3: {"ja3"},
+				xForwardedTLSClientJA4: {"ja4"},
+			},
+			expectedHeaders: map[string]string{
+				xForwardedTLSClientJA3: "",
+				xForwardedTLSClientJA4: "",
+			},
+		},
+		{
+			desc:         "trusted incoming TLS ClientHello fingerprints",
+			trustedIps:   []string{"10.0.1.100"},
+			remoteAddr:   "10.0.1.100:80",
+			tls:          true,
+			fingerprints: &fingerprint.Fingerprints{JA3: "ja3", JA4: "ja4"},
+			incomingHeaders: map[string][]string{
+				xForwardedTLSClientJA3: {"client-ja3"},
+				xForwardedTLSClientJA4: {"client-ja4"},
+			},
+			expectedHeaders: map[string]string{
+				xForwardedTLSClientJA3: "client-ja3",
+				xForwardedTLSClientJA4: "client-ja4",
+			},
+		},
 	}
 
 	for _, test := range testCases {
@@ -279,6 +316,10 @@ func TestServeHTTP(t *testing.T) {
 			req, err := http.NewRequest(http.MethodGet, "", nil)
 			require.NoError(t, err)
 
+			if test.fingerprints != nil {
+				req = req.WithContext(fingerprint.WithFingerprints(req.Context(), test.fingerprints))
+			}
+
 			req.RemoteAddr = test.remoteAddr
 
 			if test.tls {
@@ -0,0 +1,78 @@
+package mtlsauthz
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/tls/certauthz"
+	"github.com/traefik/traefik/v3/pkg/tracing"
+)
+
+const (
+	typeName = "MTLSAuthz"
+)
+
+// mTLSAuthz is a middleware that checks the verified client certificate against a set of rules.
+type mTLSAuthz struct {
+	next    http.Handler
+	checker *certauthz.Checker
+	name    string
+}
+
+// New builds a new mTLSAuthz given the rules allowing and denying the client certificates.
+func New(ctx context.Context, next http.Handler, config dynamic.MTLSAuthz, name string) (http.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	checker, err := certauthz.NewChecker(config.Allow, config.Deny)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse certificate rules: %w", err)
+	}
+
+	return &mTLSAuthz{
+		next:    next,
+		checker: checker,
+		name:    name,
+	}, nil
+}
+
+func (m *mTLSAuthz) GetTracingInformation() (string, ext.SpanKindEnum) {
+	return m.name, tracing.SpanKindNoneEnum
+}
+
+func (m *mTLSAuthz) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := middlewares.GetLogger(req.Context(), m.name, typeName)
+	ctx := logger.WithContext(req.Context())
+
+	var cert *x509.Certificate
+	if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 && len(req.TLS.VerifiedChains[0]) > 0 {
+		cert = req.TLS.VerifiedChains[0][0]
+	}
+
+	if err := m.checker.IsAuthorized(cert); err != nil {
+		msg := fmt.Sprintf("Rejecting request: %v", err)
+		logger.Debug().Msg(msg)
+		tracing.SetErrorWithEvent(req, msg)
+		reject(ctx, rw)
+		return
+	}
+	logger.Debug().Msgf("Accepting client certificate %q", cert.Subject)
+
+	m.next.ServeHTTP(rw, req)
+}
+
+func reject(ctx context.Context, rw http.ResponseWriter) {
+	statusCode := http.StatusForbidden
+
+	rw.WriteHeader(statusCode)
+	_, err := rw.Write([]byte(http.StatusText(statusCode)))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Send()
+	}
+}
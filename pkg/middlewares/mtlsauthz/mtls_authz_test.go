@@ -0,0 +1,131 @@
+package mtlsauthz
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNewMTLSAuthz(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.MTLSAuthz
+		expectedError bool
+	}{
+		{
+			desc:          "empty config",
+			config:        dynamic.MTLSAuthz{},
+			expectedError: true,
+		},
+		{
+			desc: "invalid regex",
+			config: dynamic.MTLSAuthz{
+				Allow: []dynamic.CertificateRule{{CommonName: []string{"regex:("}}},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "valid rules",
+			config: dynamic.MTLSAuthz{
+				Allow: []dynamic.CertificateRule{{CommonName: []string{"foo"}}},
+				Deny:  []dynamic.CertificateRule{{Organization: []string{"bar"}}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			handler, err := New(context.Background(), next, test.config, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, handler)
+			}
+		})
+	}
+}
+
+func TestMTLSAuthz_ServeHTTP(t *testing.T) {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "foo", Organization: []string{"Acme"}}}
+
+	testCases := []struct {
+		desc     string
+		config   dynamic.MTLSAuthz
+		tls      *tls.ConnectionState
+		expected int
+	}{
+		{
+			desc: "allowed certificate",
+			config: dynamic.MTLSAuthz{
+				Allow: []dynamic.CertificateRule{{CommonName: []string{"foo"}}},
+			},
+			tls:      &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+			expected: http.StatusOK,
+		},
+		{
+			desc: "not allowed certificate",
+			config: dynamic.MTLSAuthz{
+				Allow: []dynamic.CertificateRule{{CommonName: []string{"bar"}}},
+			},
+			tls:      &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+			expected: http.StatusForbidden,
+		},
+		{
+			desc: "denied certificate",
+			config: dynamic.MTLSAuthz{
+				Deny: []dynamic.CertificateRule{{Organization: []string{"regex:Ac.*"}}},
+			},
+			tls:      &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+			expected: http.StatusForbidden,
+		},
+		{
+			desc: "unverified certificate",
+			config: dynamic.MTLSAuthz{
+				Deny: []dynamic.CertificateRule{{CommonName: []string{"bar"}}},
+			},
+			tls:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}},
+			expected: http.StatusForbidden,
+		},
+		{
+			desc: "no TLS",
+			config: dynamic.MTLSAuthz{
+				Deny: []dynamic.CertificateRule{{CommonName: []string{"bar"}}},
+			},
+			expected: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			handler, err := New(context.Background(), next, test.config, "traefikTest")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+
+			req := httptest.NewRequest(http.MethodGet, "https://localhost", nil)
+			req.TLS = test.tls
+
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expected, recorder.Code)
+		})
+	}
+}
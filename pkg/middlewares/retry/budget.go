@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Budget tracks the in-flight requests and retries sent to a service,
+// in order to limit the retries to a ratio of the live traffic.
+type Budget struct {
+	requests atomic.Int64
+	retries  atomic.Int64
+}
+
+// allow reports whether one more retry can be sent,
+// i.e. whether the in-flight retries are under the given percent of the in-flight requests,
+// or under the minimum number of retries which are always allowed.
+func (b *Budget) allow(percent float64, minRetries int) bool {
+	retries := b.retries.Load()
+	if retries < int64(minRetries) {
+		return true
+	}
+
+	return float64(retries) < percent*float64(b.requests.Load())/100
+}
+
+// Budgets holds the retry budgets by service name,
+// so that all the retry middlewares targeting the same service share the same budget.
+type Budgets struct {
+	mu      sync.Mutex
+	budgets map[string]*Budget
+}
+
+// NewBudgets creates a new Budgets.
+func NewBudgets() *Budgets {
+	return &Budgets{budgets: make(map[string]*Budget)}
+}
+
+// Get returns the budget of the given service, creating it if needed.
+func (b *Budgets) Get(serviceName string) *Budget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	budget, ok := b.budgets[serviceName]
+	if !ok {
+		budget = &Budget{}
+		b.budgets[serviceName] = budget
+	}
+
+	return budget
+}
+
+// Prune drops the budgets of the services for which keep returns false,
+// so that the budgets of the services removed by configuration reloads are not kept forever.
+func (b *Budgets) Prune(keep func(serviceName string) bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for serviceName := range b.budgets {
+		if !keep(serviceName) {
+			delete(b.budgets, serviceName)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package retry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgets_Prune(t *testing.T) {
+	budgets := NewBudgets()
+
+	foo := budgets.Get("foo@file")
+	budgets.Get("bar@file")
+
+	budgets.Prune(func(serviceName string) bool {
+		return serviceName == "foo@file"
+	})
+
+	assert.Len(t, budgets.budgets, 1)
+	assert.Same(t, foo, budgets.Get("foo@file"))
+	assert.NotContains(t, budgets.budgets, "bar@file")
+}
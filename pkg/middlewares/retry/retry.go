@@ -2,6 +2,7 @@ package retry
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -16,6 +17,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/middlewares"
 	"github.com/traefik/traefik/v3/pkg/tracing"
+	"github.com/traefik/traefik/v3/pkg/types"
 )
 
 // Compile time validation that the response writer implements http interfaces correctly.
@@ -23,6 +25,9 @@ var _ middlewares.Stateful = &responseWriter{}
 
 const typeName = "Retry"
 
+// defaultMaxRequestBodyBytes is the default maximum size of the request body buffered to retry on status codes.
+const defaultMaxRequestBodyBytes = 1 << 20
+
 // Listener is used to inform about retry attempts.
 type Listener interface {
 	// Retried will be called when a retry happens, with the request attempt passed to it.
@@ -38,26 +43,56 @@ type Listeners []Listener
 type retry struct {
 	attempts        int
 	initialInterval time.Duration
-	next            http.Handler
-	listener        Listener
-	name            string
+	statusCodes     types.HTTPCodeRanges
+	// maxRequestBodyBytes is the maximum size of the buffered request body, negative for no maximum.
+	maxRequestBodyBytes int64
+	allowNonIdempotent  bool
+	budget              *Budget
+	budgetConfig        *dynamic.RetryBudget
+	next                http.Handler
+	listener            Listener
+	name                string
 }
 
 // New returns a new retry middleware.
-func New(ctx context.Context, next http.Handler, config dynamic.Retry, listener Listener, name string) (http.Handler, error) {
+// The given budget, which can be nil, is the one of the service targeted by the middleware.
+func New(ctx context.Context, next http.Handler, config dynamic.Retry, listener Listener, budget *Budget, name string) (http.Handler, error) {
 	middlewares.GetLogger(ctx, name, typeName).Debug().Msg("Creating middleware")
 
 	if config.Attempts <= 0 {
 		return nil, fmt.Errorf("incorrect (or empty) value for attempt (%d)", config.Attempts)
 	}
 
-	return &retry{
-		attempts:        config.Attempts,
-		initialInterval: time.Duration(config.InitialInterval),
-		next:            next,
-		listener:        listener,
-		name:            name,
-	}, nil
+	statusCodes, err := types.NewHTTPCodeRanges(config.StatusCodes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status codes: %w", err)
+	}
+
+	if config.Budget != nil && config.Budget.Percent < 0 {
+		return nil, fmt.Errorf("incorrect value for budget percent (%v)", config.Budget.Percent)
+	}
+
+	r := &retry{
+		attempts:            config.Attempts,
+		initialInterval:     time.Duration(config.InitialInterval),
+		statusCodes:         statusCodes,
+		maxRequestBodyBytes: config.MaxRequestBodyBytes,
+		allowNonIdempotent:  config.AllowNonIdempotent,
+		next:                next,
+		listener:            listener,
+		name:                name,
+	}
+
+	if r.maxRequestBodyBytes == 0 {
+		r.maxRequestBodyBytes = defaultMaxRequestBodyBytes
+	}
+
+	if config.Budget != nil && budget != nil {
+		r.budget = budget
+		r.budgetConfig = config.Budget
+	}
+
+	return r, nil
 }
 
 func (r *retry) GetTracingInformation() (string, ext.SpanKindEnum) {
@@ -70,6 +105,13 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	logger := middlewares.GetLogger(req.Context(), r.name, typeName)
+
+	if r.budget != nil {
+		r.budget.requests.Add(1)
+		defer r.budget.requests.Add(-1)
+	}
+
 	closableBody := req.Body
 	defer closableBody.Close()
 
@@ -77,11 +119,57 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	// cf https://github.com/traefik/traefik/issues/1008
 	req.Body = io.NopCloser(closableBody)
 
+	// Retrying on status codes means that the backend may have consumed the body,
+	// so it has to be buffered to be sent again.
+	statusCodes := r.statusCodes
+	if !r.allowNonIdempotent && !isIdempotent(req.Method) {
+		// The backend may have already applied the request.
+		statusCodes = nil
+	}
+
+	var body []byte
+	if len(statusCodes) > 0 && closableBody != nil && closableBody != http.NoBody {
+		reader := io.Reader(closableBody)
+		if r.maxRequestBodyBytes > 0 {
+			reader = io.LimitReader(closableBody, r.maxRequestBodyBytes+1)
+		}
+
+		var err error
+		body, err = io.ReadAll(reader)
+		if err != nil {
+			logger.Debug().Err(err).Msg("Error while reading request body")
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.maxRequestBodyBytes > 0 && int64(len(body)) > r.maxRequestBodyBytes {
+			logger.Debug().Msgf("Request body larger than %d bytes, disabling retries on status codes", r.maxRequestBodyBytes)
+
+			// The body cannot be sent again, so the request is forwarded with the read part followed by the rest.
+			req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), closableBody))
+			body = nil
+			statusCodes = nil
+		}
+	}
+
 	attempts := 1
 
 	operation := func() error {
 		shouldRetry := attempts < r.attempts
 		retryResponseWriter := newResponseWriter(rw, shouldRetry)
+		if shouldRetry {
+			retryResponseWriter.statusCodes = statusCodes
+			retryResponseWriter.allowRetry = r.budgetAllowsRetry
+		}
+
+		if body != nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		if attempts > 1 && r.budget != nil {
+			r.budget.retries.Add(1)
+			defer r.budget.retries.Add(-1)
+		}
 
 		// Disable retries when the backend already received request data
 		trace := &httptrace.ClientTrace{
@@ -105,8 +193,6 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return fmt.Errorf("attempt %d failed", attempts-1)
 	}
 
-	logger := middlewares.GetLogger(req.Context(), r.name, typeName)
-
 	backOff := backoff.WithContext(r.newBackOff(), req.Context())
 
 	notify := func(err error, d time.Duration) {
@@ -121,6 +207,25 @@ func (r *retry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// isIdempotent reports whether the given request method is idempotent, as defined by RFC 9110.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// budgetAllowsRetry reports whether the retry budget of the targeted service allows one more retry.
+func (r *retry) budgetAllowsRetry() bool {
+	if r.budget == nil {
+		return true
+	}
+
+	return r.budget.allow(r.budgetConfig.Percent, r.budgetConfig.MinRetries)
+}
+
 func (r *retry) newBackOff() backoff.BackOff {
 	if r.attempts < 2 || r.initialInterval <= 0 {
 		return &backoff.ZeroBackOff{}
@@ -159,6 +264,11 @@ type responseWriter struct {
 	headers        http.Header
 	shouldRetry    bool
 	written        bool
+
+	// statusCodes are the response status codes triggering a retry.
+	statusCodes types.HTTPCodeRanges
+	// allowRetry, if set, reports whether a failed attempt can be retried, instead of delivering its response.
+	allowRetry func() bool
 }
 
 func (r *responseWriter) ShouldRetry() bool {
@@ -193,6 +303,17 @@ func (r *responseWriter) WriteHeader(code int) {
 		r.DisableRetries()
 	}
 
+	// Even though the request was sent to the backend,
+	// the response status code tells us that it is worth retrying.
+	if r.statusCodes.Contains(code) {
+		r.shouldRetry = true
+	}
+
+	// The retry budget is only checked once the attempt failed, so that it never limits the first attempts.
+	if r.ShouldRetry() && r.allowRetry != nil && !r.allowRetry() {
+		r.DisableRetries()
+	}
+
 	if r.ShouldRetry() {
 		return
 	}
@@ -219,6 +340,11 @@ func (r *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 }
 
 func (r *responseWriter) Flush() {
+	// Flushing would send the headers of a response which is going to be discarded.
+	if r.ShouldRetry() {
+		return
+	}
+
 	if flusher, ok := r.responseWriter.(http.Flusher); ok {
 		flusher.Flush()
 	}
@@ -120,7 +120,7 @@ func TestRetry(t *testing.T) {
 			})
 
 			retryListener := &countingRetryListener{}
-			retry, err := New(context.Background(), next, test.config, retryListener, "traefikTest")
+			retry, err := New(context.Background(), next, test.config, retryListener, nil, "traefikTest")
 			require.NoError(t, err)
 
 			recorder := httptest.NewRecorder()
@@ -140,7 +140,7 @@ func TestRetryEmptyServerList(t *testing.T) {
 	})
 
 	retryListener := &countingRetryListener{}
-	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 3}, retryListener, "traefikTest")
+	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 3}, retryListener, nil, "traefikTest")
 	require.NoError(t, err)
 
 	recorder := httptest.NewRecorder()
@@ -152,6 +152,204 @@ func TestRetryEmptyServerList(t *testing.T) {
 	assert.Equal(t, 0, retryListener.timesCalled)
 }
 
+func TestRetryStatusCodes(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		config             dynamic.Retry
+		method             string
+		statuses           []int
+		wantRetryAttempts  int
+		wantResponseStatus int
+	}{
+		{
+			desc:               "no retry on status without status codes",
+			config:             dynamic.Retry{Attempts: 3},
+			statuses:           []int{http.StatusInternalServerError, http.StatusOK},
+			wantRetryAttempts:  0,
+			wantResponseStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:               "retry on matching status",
+			config:             dynamic.Retry{Attempts: 3, StatusCodes: []string{"500-599"}},
+			statuses:           []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK},
+			wantRetryAttempts:  2,
+			wantResponseStatus: http.StatusOK,
+		},
+		{
+			desc:               "no retry on non matching status",
+			config:             dynamic.Retry{Attempts: 3, StatusCodes: []string{"502"}},
+			statuses:           []int{http.StatusInternalServerError, http.StatusOK},
+			wantRetryAttempts:  0,
+			wantResponseStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:               "max attempts exhausted delivers the last response",
+			config:             dynamic.Retry{Attempts: 2, StatusCodes: []string{"500"}},
+			statuses:           []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+			wantRetryAttempts:  1,
+			wantResponseStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:               "retry on matching status with body size at the maximum",
+			config:             dynamic.Retry{Attempts: 3, StatusCodes: []string{"500"}, MaxRequestBodyBytes: 7},
+			statuses:           []int{http.StatusInternalServerError, http.StatusOK},
+			wantRetryAttempts:  1,
+			wantResponseStatus: http.StatusOK,
+		},
+		{
+			desc:               "no retry on status with body larger than the maximum",
+			config:             dynamic.Retry{Attempts: 3, StatusCodes: []string{"500"}, MaxRequestBodyBytes: 4},
+			statuses:           []int{http.StatusInternalServerError, http.StatusOK},
+			wantRetryAttempts:  0,
+			wantResponseStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:               "retry on matching status without body size maximum",
+			config:             dynamic.Retry{Attempts: 3, StatusCodes: []string{"500"}, MaxRequestBodyBytes: -1},
+			statuses:           []int{http.StatusInternalServerError, http.StatusOK},
+			wantRetryAttempts:  1,
+			wantResponseStatus: http.StatusOK,
+		},
+		{
+			desc:               "no retry on matching status with non-idempotent method",
+			config:             dynamic.Retry{Attempts: 3, StatusCodes: []string{"500"}},
+			method:             http.MethodPost,
+			statuses:           []int{http.StatusInternalServerError, http.StatusOK},
+			wantRetryAttempts:  0,
+			wantResponseStatus: http.StatusInternalServerError,
+		},
+		{
+			desc:               "retry on matching status with allowed non-idempotent method",
+			config:             dynamic.Retry{Attempts: 3, StatusCodes: []string{"500"}, AllowNonIdempotent: true},
+			method:             http.MethodPost,
+			statuses:           []int{http.StatusInternalServerError, http.StatusOK},
+			wantRetryAttempts:  1,
+			wantResponseStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var bodies []string
+			next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				// calls WroteHeaders on httptrace.
+				_ = r.Write(io.Discard)
+
+				bodies = append(bodies, string(body))
+
+				rw.WriteHeader(test.statuses[len(bodies)-1])
+				_, _ = rw.Write([]byte("attempt"))
+			})
+
+			retryListener := &countingRetryListener{}
+			retry, err := New(context.Background(), next, test.config, retryListener, nil, "traefikTest")
+			require.NoError(t, err)
+
+			method := http.MethodPut
+			if test.method != "" {
+				method = test.method
+			}
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(method, "http://localhost:3000/ok", strings.NewReader("payload"))
+
+			retry.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.wantResponseStatus, recorder.Code)
+			assert.Equal(t, "attempt", recorder.Body.String())
+			assert.Equal(t, test.wantRetryAttempts, retryListener.timesCalled)
+
+			if len(test.config.StatusCodes) > 0 {
+				for _, body := range bodies {
+					assert.Equal(t, "payload", body)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	budget := &Budget{}
+
+	// Simulates in-flight requests and retries to the service through other middlewares.
+	budget.requests.Add(5)
+	budget.retries.Add(2)
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	})
+
+	config := dynamic.Retry{
+		Attempts: 3,
+		Budget:   &dynamic.RetryBudget{Percent: 20, MinRetries: 1},
+	}
+
+	retryListener := &countingRetryListener{}
+	retry, err := New(context.Background(), next, config, retryListener, budget, "traefikTest")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	retry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil))
+
+	// 2 in-flight retries for 6 in-flight requests exceeds the 20% budget.
+	assert.Equal(t, http.StatusBadGateway, recorder.Code)
+	assert.Equal(t, 0, retryListener.timesCalled)
+
+	budget.requests.Add(10)
+
+	recorder = httptest.NewRecorder()
+	retry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil))
+
+	// 2 in-flight retries for 16 in-flight requests fits in the 20% budget.
+	assert.Equal(t, http.StatusBadGateway, recorder.Code)
+	assert.Equal(t, 2, retryListener.timesCalled)
+
+	assert.Equal(t, int64(15), budget.requests.Load())
+	assert.Equal(t, int64(2), budget.retries.Load())
+}
+
+func TestRetryBudget_checkedOnFailure(t *testing.T) {
+	budget := &Budget{}
+
+	// The budget is exhausted when the request arrives.
+	budget.retries.Add(2)
+
+	attempts := 0
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			// The other retries end during the first attempt.
+			budget.retries.Add(-2)
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	config := dynamic.Retry{
+		Attempts: 2,
+		Budget:   &dynamic.RetryBudget{Percent: 20, MinRetries: 1},
+	}
+
+	retryListener := &countingRetryListener{}
+	retry, err := New(context.Background(), next, config, retryListener, budget, "traefikTest")
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	retry.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "http://localhost:3000/ok", nil))
+
+	// The budget limits the retries, not the first attempt: it is checked once the first attempt failed.
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 1, retryListener.timesCalled)
+}
+
 func TestRetryListeners(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	retryListeners := Listeners{&countingRetryListener{}, &countingRetryListener{}}
@@ -188,7 +386,7 @@ func TestMultipleRetriesShouldNotLooseHeaders(t *testing.T) {
 		rw.WriteHeader(http.StatusNoContent)
 	})
 
-	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 3}, &countingRetryListener{}, "traefikTest")
+	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 3}, &countingRetryListener{}, nil, "traefikTest")
 	require.NoError(t, err)
 
 	responseRecorder := httptest.NewRecorder()
@@ -234,7 +432,7 @@ func TestRetryWithFlush(t *testing.T) {
 		}
 	})
 
-	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 1}, &countingRetryListener{}, "traefikTest")
+	retry, err := New(context.Background(), next, dynamic.Retry{Attempts: 1}, &countingRetryListener{}, nil, "traefikTest")
 	require.NoError(t, err)
 
 	responseRecorder := httptest.NewRecorder()
@@ -292,7 +490,7 @@ func TestRetryWebsocket(t *testing.T) {
 			})
 
 			retryListener := &countingRetryListener{}
-			retryH, err := New(context.Background(), next, dynamic.Retry{Attempts: test.maxRequestAttempts}, retryListener, "traefikTest")
+			retryH, err := New(context.Background(), next, dynamic.Retry{Attempts: test.maxRequestAttempts}, retryListener, nil, "traefikTest")
 			require.NoError(t, err)
 
 			retryServer := httptest.NewServer(retryH)
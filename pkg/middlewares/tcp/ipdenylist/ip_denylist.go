@@ -0,0 +1,75 @@
+package ipdenylist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/ip"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+const (
+	typeName = "IPDenyListerTCP"
+)
+
+// ipDenyLister is a middleware that provides Checks of the Requesting IP against a set of Denylists.
+type ipDenyLister struct {
+	next       tcp.Handler
+	denyLister *ip.Checker
+	name       string
+}
+
+// New builds a new TCP IPDenyLister given a list of CIDR-Strings to deny.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPIPDenyList, name string) (tcp.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	if len(config.SourceRange) == 0 {
+		return nil, errors.New("sourceRange is empty, IPDenyLister not created")
+	}
+
+	checker, err := ip.NewChecker(config.SourceRange)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse CIDRs %s: %w", config.SourceRange, err)
+	}
+
+	logger.Debug().Msgf("Setting up IPDenyLister with sourceRange: %s", config.SourceRange)
+
+	return &ipDenyLister{
+		denyLister: checker,
+		next:       next,
+		name:       name,
+	}, nil
+}
+
+func (dl *ipDenyLister) ServeTCP(conn tcp.WriteCloser) {
+	logger := middlewares.GetLogger(context.Background(), dl.name, typeName)
+
+	addr := conn.RemoteAddr().String()
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	denied, err := dl.denyLister.Contains(host)
+	if err != nil {
+		logger.Error().Err(err).Msgf("Connection from %s rejected", addr)
+		conn.Close()
+		return
+	}
+
+	if denied {
+		logger.Error().Msgf("Connection from %s rejected: %q matched the denied IPs", addr, host)
+		conn.Close()
+		return
+	}
+
+	logger.Debug().Msgf("Connection from %s accepted", addr)
+
+	dl.next.ServeTCP(conn)
+}
@@ -0,0 +1,101 @@
+package mtlsauthz
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+	"github.com/traefik/traefik/v3/pkg/tls/certauthz"
+)
+
+const (
+	typeName = "MTLSAuthzTCP"
+)
+
+// mTLSAuthz is a middleware that checks the verified client certificate against a set of rules.
+type mTLSAuthz struct {
+	next    tcp.Handler
+	checker *certauthz.Checker
+	name    string
+}
+
+// New builds a new TCP mTLSAuthz given the rules allowing and denying the client certificates.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPMTLSAuthz, name string) (tcp.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	checker, err := certauthz.NewChecker(config.Allow, config.Deny)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse certificate rules: %w", err)
+	}
+
+	return &mTLSAuthz{
+		next:    next,
+		checker: checker,
+		name:    name,
+	}, nil
+}
+
+func (m *mTLSAuthz) ServeTCP(conn tcp.WriteCloser) {
+	logger := middlewares.GetLogger(context.Background(), m.name, typeName)
+
+	addr := conn.RemoteAddr().String()
+
+	// The middleware is only meaningful on routers terminating TLS.
+	tlsConn := tlsConnFrom(conn)
+	if tlsConn == nil {
+		logger.Error().Msgf("Connection from %s rejected: not a TLS connection", addr)
+		conn.Close()
+		return
+	}
+
+	// The handshake is done here, as the client certificate is only known after it.
+	if err := tlsConn.Handshake(); err != nil {
+		logger.Debug().Err(err).Msgf("Connection from %s rejected: TLS handshake failed", addr)
+		conn.Close()
+		return
+	}
+
+	var cert *x509.Certificate
+	if state := tlsConn.ConnectionState(); len(state.VerifiedChains) > 0 && len(state.VerifiedChains[0]) > 0 {
+		cert = state.VerifiedChains[0][0]
+	}
+
+	if err := m.checker.IsAuthorized(cert); err != nil {
+		logger.Error().Err(err).Msgf("Connection from %s rejected", addr)
+		conn.Close()
+		return
+	}
+
+	logger.Debug().Msgf("Connection from %s accepted", addr)
+
+	m.next.ServeTCP(conn)
+}
+
+// wrapperConn is a connection wrapping another one, like the ones of the timeout middleware.
+type wrapperConn interface {
+	NetConn() net.Conn
+}
+
+// tlsConnFrom returns the TLS connection of the given connection, unwrapping it if needed, or nil.
+func tlsConnFrom(conn net.Conn) *tls.Conn {
+	for c := conn; c != nil; {
+		if tlsConn, ok := c.(*tls.Conn); ok {
+			return tlsConn
+		}
+
+		wConn, ok := c.(wrapperConn)
+		if !ok {
+			return nil
+		}
+
+		c = wConn.NetConn()
+	}
+
+	return nil
+}
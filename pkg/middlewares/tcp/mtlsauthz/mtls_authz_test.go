@@ -0,0 +1,194 @@
+package mtlsauthz
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/timeout"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestNewMTLSAuthz(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.TCPMTLSAuthz
+		expectedError bool
+	}{
+		{
+			desc:          "empty config",
+			config:        dynamic.TCPMTLSAuthz{},
+			expectedError: true,
+		},
+		{
+			desc: "valid rules",
+			config: dynamic.TCPMTLSAuthz{
+				Allow: []dynamic.CertificateRule{{CommonName: []string{"foo"}}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+			handler, err := New(context.Background(), next, test.config, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, handler)
+			}
+		})
+	}
+}
+
+func TestMTLSAuthz_ServeTCP(t *testing.T) {
+	ca, caKey := createCertificate(t, "ca", nil, nil)
+	serverCert, serverKey := createCertificate(t, "server", ca, caKey)
+	clientCert, clientKey := createCertificate(t, "client", ca, caKey)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+		ClientCAs:    pool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+	}
+
+	testCases := []struct {
+		desc       string
+		config     dynamic.TCPMTLSAuthz
+		clientCert bool
+		// withTimeout chains the timeout middleware, wrapping the TLS connection, in front of the middleware.
+		withTimeout bool
+		expected    string
+	}{
+		{
+			desc: "allowed certificate",
+			config: dynamic.TCPMTLSAuthz{
+				Allow: []dynamic.CertificateRule{{CommonName: []string{"client"}}},
+			},
+			clientCert: true,
+			expected:   "OK",
+		},
+		{
+			desc: "allowed certificate behind the timeout middleware",
+			config: dynamic.TCPMTLSAuthz{
+				Allow: []dynamic.CertificateRule{{CommonName: []string{"client"}}},
+			},
+			clientCert:  true,
+			withTimeout: true,
+			expected:    "OK",
+		},
+		{
+			desc: "denied certificate behind the timeout middleware",
+			config: dynamic.TCPMTLSAuthz{
+				Allow: []dynamic.CertificateRule{{CommonName: []string{"foo"}}},
+			},
+			clientCert:  true,
+			withTimeout: true,
+		},
+		{
+			desc: "denied certificate",
+			config: dynamic.TCPMTLSAuthz{
+				Deny: []dynamic.CertificateRule{{DNSNames: []string{"regex:.*\\.localhost"}}},
+			},
+			clientCert: true,
+		},
+		{
+			desc: "no client certificate",
+			config: dynamic.TCPMTLSAuthz{
+				Deny: []dynamic.CertificateRule{{CommonName: []string{"foo"}}},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				write, err := conn.Write([]byte("OK"))
+				require.NoError(t, err)
+				assert.Equal(t, 2, write)
+
+				err = conn.Close()
+				require.NoError(t, err)
+			})
+
+			handler, err := New(context.Background(), next, test.config, "traefikTest")
+			require.NoError(t, err)
+
+			if test.withTimeout {
+				handler, err = timeout.New(context.Background(), handler, dynamic.TCPTimeout{IdleTimeout: ptypes.Duration(time.Minute)}, "timeout")
+				require.NoError(t, err)
+			}
+
+			server, client := net.Pipe()
+
+			go func() {
+				handler.ServeTCP(tls.Server(server, serverConfig))
+			}()
+
+			clientConfig := &tls.Config{RootCAs: pool, ServerName: "server.localhost"}
+			if test.clientCert {
+				clientConfig.Certificates = []tls.Certificate{{Certificate: [][]byte{clientCert.Raw}, PrivateKey: clientKey}}
+			}
+
+			read, _ := io.ReadAll(tls.Client(client, clientConfig))
+
+			assert.Equal(t, test.expected, string(read))
+		})
+	}
+}
+
+func createCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name + ".localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert, key
+}
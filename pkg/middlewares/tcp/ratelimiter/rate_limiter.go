@@ -0,0 +1,120 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/mailgun/ttlmap"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+	"golang.org/x/time/rate"
+)
+
+const (
+	typeName   = "RateLimiterTCP"
+	maxSources = 65536
+)
+
+// rateLimiter closes the new connections of a client IP above the rate of the middleware,
+// with one token bucket per client IP.
+type rateLimiter struct {
+	name  string
+	next  tcp.Handler
+	rate  rate.Limit // conns/s
+	burst int64
+	// each rate limiter for a given client IP is stored in the buckets ttlmap.
+	// It is considered expired, and "garbage collected", after it hasn't been used for ttl seconds.
+	ttl int
+
+	buckets *ttlmap.TtlMap // actual buckets, keyed by client IP.
+}
+
+// New returns a rate limiter middleware.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPRateLimit, name string) (tcp.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	if config.Average <= 0 {
+		return nil, errors.New("average must be greater than zero")
+	}
+
+	burst := config.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	period := time.Duration(config.Period)
+	if period < 0 {
+		return nil, fmt.Errorf("negative value not valid for period: %v", period)
+	}
+	if period == 0 {
+		period = time.Second
+	}
+
+	buckets, err := ttlmap.NewConcurrent(maxSources)
+	if err != nil {
+		return nil, err
+	}
+
+	rtl := float64(config.Average*int64(time.Second)) / float64(period)
+
+	// Make the ttl inversely proportional to how often a rate limiter is supposed to see any activity (when maxed out),
+	// for low rate limiters.
+	// Otherwise just make it a second for all the high rate limiters.
+	// Add an extra second in both cases for continuity between the two cases.
+	ttl := 1
+	if rtl >= 1 {
+		ttl++
+	} else {
+		ttl += int(1 / rtl)
+	}
+
+	return &rateLimiter{
+		name:    name,
+		next:    next,
+		rate:    rate.Limit(rtl),
+		burst:   burst,
+		ttl:     ttl,
+		buckets: buckets,
+	}, nil
+}
+
+// ServeTCP serves the given TCP connection, closing it if its client IP exceeds the rate.
+func (rl *rateLimiter) ServeTCP(conn tcp.WriteCloser) {
+	logger := middlewares.GetLogger(context.Background(), rl.name, typeName)
+
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		logger.Error().Err(err).Msg("Cannot parse IP from remote addr")
+		conn.Close()
+		return
+	}
+
+	var bucket *rate.Limiter
+	if rlSource, exists := rl.buckets.Get(ip); exists {
+		bucket = rlSource.(*rate.Limiter)
+	} else {
+		bucket = rate.NewLimiter(rl.rate, int(rl.burst))
+	}
+
+	// We Set even in the case where the source already exists,
+	// because we want to update the expiryTime everytime we get the source,
+	// as the expiryTime is supposed to reflect the activity (or lack thereof) on that source.
+	if err := rl.buckets.Set(ip, bucket, rl.ttl); err != nil {
+		logger.Error().Err(err).Msg("Could not insert/update bucket")
+		conn.Close()
+		return
+	}
+
+	if !bucket.Allow() {
+		logger.Debug().Msgf("Connection from %s rejected: rate limit exceeded", ip)
+		conn.Close()
+		return
+	}
+
+	rl.next.ServeTCP(conn)
+}
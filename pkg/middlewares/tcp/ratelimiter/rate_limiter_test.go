@@ -0,0 +1,127 @@
+package ratelimiter
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.TCPRateLimit
+		expectedError bool
+	}{
+		{
+			desc:          "empty config",
+			config:        dynamic.TCPRateLimit{},
+			expectedError: true,
+		},
+		{
+			desc: "negative period",
+			config: dynamic.TCPRateLimit{
+				Average: 1,
+				Period:  ptypes.Duration(-time.Second),
+			},
+			expectedError: true,
+		},
+		{
+			desc: "valid config",
+			config: dynamic.TCPRateLimit{
+				Average: 10,
+				Burst:   5,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+			rateLimiter, err := New(context.Background(), next, test.config, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, rateLimiter)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_ServeTCP(t *testing.T) {
+	var served int
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		served++
+	})
+
+	config := dynamic.TCPRateLimit{
+		Average: 1,
+		Period:  ptypes.Duration(time.Hour),
+		Burst:   2,
+	}
+	middleware, err := New(context.Background(), next, config, "foo")
+	require.NoError(t, err)
+
+	// The connections within the burst should succeed.
+	for i := 0; i < 2; i++ {
+		conn := &fakeConn{addr: "127.0.0.1:9000"}
+		middleware.ServeTCP(conn)
+		assert.False(t, conn.closed)
+	}
+	assert.Equal(t, 2, served)
+
+	// The next connection from the same IP, even from another port, should be closed as the rate is exceeded.
+	conn := &fakeConn{addr: "127.0.0.1:9001"}
+	middleware.ServeTCP(conn)
+	assert.True(t, conn.closed)
+	assert.Equal(t, 2, served)
+
+	// The connection from another IP should succeed.
+	conn = &fakeConn{addr: "127.0.0.2:9000"}
+	middleware.ServeTCP(conn)
+	assert.False(t, conn.closed)
+	assert.Equal(t, 3, served)
+}
+
+type fakeConn struct {
+	net.Conn
+
+	addr   string
+	closed bool
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr {
+	return fakeAddr{addr: c.addr}
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) CloseWrite() error {
+	panic("implement me")
+}
+
+type fakeAddr struct {
+	addr string
+}
+
+func (a fakeAddr) Network() string {
+	return "tcp"
+}
+
+func (a fakeAddr) String() string {
+	return a.addr
+}
@@ -0,0 +1,148 @@
+package timeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+const typeName = "TimeoutTCP"
+
+// timeout closes the connections which are idle, or opened, for too long.
+type timeout struct {
+	name        string
+	next        tcp.Handler
+	idleTimeout time.Duration
+	maxDuration time.Duration
+}
+
+// New creates a timeout middleware.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPTimeout, name string) (tcp.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	idleTimeout := time.Duration(config.IdleTimeout)
+	if idleTimeout < 0 {
+		return nil, fmt.Errorf("negative value not valid for idleTimeout: %v", idleTimeout)
+	}
+
+	maxDuration := time.Duration(config.MaxDuration)
+	if maxDuration < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxDuration: %v", maxDuration)
+	}
+
+	if idleTimeout == 0 && maxDuration == 0 {
+		return nil, errors.New("at least one of idleTimeout and maxDuration must be set")
+	}
+
+	return &timeout{
+		name:        name,
+		next:        next,
+		idleTimeout: idleTimeout,
+		maxDuration: maxDuration,
+	}, nil
+}
+
+// ServeTCP serves the given TCP connection, closing it once it exceeds the timeouts.
+func (t *timeout) ServeTCP(conn tcp.WriteCloser) {
+	tConn := &timeoutConn{WriteCloser: conn}
+	tConn.touch()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go t.watch(tConn, done)
+
+	t.next.ServeTCP(tConn)
+}
+
+// watch closes the given connection when it exceeds the timeouts, until done is closed.
+func (t *timeout) watch(conn *timeoutConn, done <-chan struct{}) {
+	logger := middlewares.GetLogger(context.Background(), t.name, typeName)
+
+	var maxDurationCh <-chan time.Time
+	if t.maxDuration > 0 {
+		maxDurationTimer := time.NewTimer(t.maxDuration)
+		defer maxDurationTimer.Stop()
+
+		maxDurationCh = maxDurationTimer.C
+	}
+
+	var idleCh <-chan time.Time
+	var idleTimer *time.Timer
+	if t.idleTimeout > 0 {
+		idleTimer = time.NewTimer(t.idleTimeout)
+		defer idleTimer.Stop()
+
+		idleCh = idleTimer.C
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+
+		case <-maxDurationCh:
+			logger.Debug().Msgf("Closing connection from %s: max duration of %s exceeded", conn.RemoteAddr(), t.maxDuration)
+			conn.Close()
+			return
+
+		case <-idleCh:
+			idle := conn.idle()
+			if idle < t.idleTimeout {
+				idleTimer.Reset(t.idleTimeout - idle)
+				continue
+			}
+
+			logger.Debug().Msgf("Closing connection from %s: idle for more than %s", conn.RemoteAddr(), t.idleTimeout)
+			conn.Close()
+			return
+		}
+	}
+}
+
+// timeoutConn is a connection keeping track of the last time data was exchanged in either direction.
+type timeoutConn struct {
+	tcp.WriteCloser
+
+	lastActivity atomic.Int64 // UnixNano.
+}
+
+func (c *timeoutConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	if n > 0 {
+		c.touch()
+	}
+
+	return n, err
+}
+
+func (c *timeoutConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+	if n > 0 {
+		c.touch()
+	}
+
+	return n, err
+}
+
+// NetConn returns the underlying connection.
+func (c *timeoutConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+func (c *timeoutConn) touch() {
+	c.lastActivity.Store(time.Now().UnixNano())
+}
+
+// idle returns the duration since data was last exchanged.
+func (c *timeoutConn) idle() time.Duration {
+	return time.Duration(time.Now().UnixNano() - c.lastActivity.Load())
+}
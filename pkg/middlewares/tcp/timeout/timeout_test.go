@@ -0,0 +1,156 @@
+package timeout
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestNewTimeout(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.TCPTimeout
+		expectedError bool
+	}{
+		{
+			desc:          "empty config",
+			config:        dynamic.TCPTimeout{},
+			expectedError: true,
+		},
+		{
+			desc: "negative idle timeout",
+			config: dynamic.TCPTimeout{
+				IdleTimeout: ptypes.Duration(-time.Second),
+			},
+			expectedError: true,
+		},
+		{
+			desc: "negative max duration",
+			config: dynamic.TCPTimeout{
+				IdleTimeout: ptypes.Duration(time.Second),
+				MaxDuration: ptypes.Duration(-time.Second),
+			},
+			expectedError: true,
+		},
+		{
+			desc: "idle timeout only",
+			config: dynamic.TCPTimeout{
+				IdleTimeout: ptypes.Duration(time.Second),
+			},
+		},
+		{
+			desc: "max duration only",
+			config: dynamic.TCPTimeout{
+				MaxDuration: ptypes.Duration(time.Second),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+			middleware, err := New(context.Background(), next, test.config, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, middleware)
+			}
+		})
+	}
+}
+
+func TestTimeout_ServeTCP(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   dynamic.TCPTimeout
+		messages int
+		expected string
+	}{
+		{
+			desc: "idle connection is closed",
+			config: dynamic.TCPTimeout{
+				IdleTimeout: ptypes.Duration(100 * time.Millisecond),
+			},
+			expected: "",
+		},
+		{
+			desc: "active connection is kept until idle",
+			config: dynamic.TCPTimeout{
+				IdleTimeout: ptypes.Duration(100 * time.Millisecond),
+			},
+			messages: 5,
+			expected: "00000",
+		},
+		{
+			desc: "active connection is closed after max duration",
+			config: dynamic.TCPTimeout{
+				IdleTimeout: ptypes.Duration(100 * time.Millisecond),
+				MaxDuration: ptypes.Duration(125 * time.Millisecond),
+			},
+			messages: 5,
+			expected: "00",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// The handler writes a message every 50ms, then waits for the connection to be closed.
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				for i := 0; i < test.messages; i++ {
+					time.Sleep(50 * time.Millisecond)
+					if _, err := conn.Write([]byte("0")); err != nil {
+						return
+					}
+				}
+
+				_, _ = io.Copy(io.Discard, conn)
+			})
+
+			middleware, err := New(context.Background(), next, test.config, "traefikTest")
+			require.NoError(t, err)
+
+			server, client := net.Pipe()
+
+			served := make(chan struct{})
+			go func() {
+				middleware.ServeTCP(writeCloser{server})
+				close(served)
+			}()
+
+			read, err := io.ReadAll(client)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(read))
+
+			select {
+			case <-served:
+			case <-time.After(time.Second):
+				t.Fatal("Timeout waiting for the connection to be served")
+			}
+		})
+	}
+}
+
+type writeCloser struct {
+	net.Conn
+}
+
+func (c writeCloser) CloseWrite() error {
+	panic("implement me")
+}
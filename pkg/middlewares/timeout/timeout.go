@@ -0,0 +1,75 @@
+package timeout
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+type perTryKey struct{}
+
+// New returns a handler enforcing the timeouts configured on a router.
+// The request timeout bounds the whole handling of the request,
+// and the per-try timeout is stored in the request context to be enforced by the servers handlers (see NewServer).
+func New(next http.Handler, config dynamic.RequestTimeouts) http.Handler {
+	if config.Request <= 0 && config.PerTry <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		if config.PerTry > 0 {
+			ctx = context.WithValue(ctx, perTryKey{}, time.Duration(config.PerTry))
+		}
+
+		if config.Request > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(config.Request))
+			defer cancel()
+		}
+
+		next.ServeHTTP(rw, req.WithContext(ctx))
+	})
+}
+
+// NewServer returns a handler enforcing, on each attempt to reach a server,
+// the smallest of the timeouts configured on its service and the per-try timeout set by a router.
+// As a load-balancer forwards a request to exactly one server,
+// the request timeout of a service is enforced the same way as its per-try timeout.
+func NewServer(next http.Handler, config *dynamic.RequestTimeouts) http.Handler {
+	var serviceTimeout time.Duration
+	if config != nil {
+		serviceTimeout = minTimeout(time.Duration(config.Request), time.Duration(config.PerTry))
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		timeout := serviceTimeout
+		if perTry, ok := req.Context().Value(perTryKey{}).(time.Duration); ok {
+			timeout = minTimeout(timeout, perTry)
+		}
+
+		if timeout <= 0 {
+			next.ServeHTTP(rw, req)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+
+		next.ServeHTTP(rw, req.WithContext(ctx))
+	})
+}
+
+// minTimeout returns the smallest of the given timeouts, ignoring the non-positive ones.
+func minTimeout(a, b time.Duration) time.Duration {
+	if a <= 0 {
+		return b
+	}
+	if b <= 0 || a < b {
+		return a
+	}
+	return b
+}
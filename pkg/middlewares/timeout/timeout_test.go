@@ -0,0 +1,76 @@
+package timeout
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNewServer(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		routerConfig   dynamic.RequestTimeouts
+		serviceConfig  *dynamic.RequestTimeouts
+		expectDeadline bool
+		expectedMax    time.Duration
+	}{
+		{
+			desc: "no timeout",
+		},
+		{
+			desc:           "router request timeout",
+			routerConfig:   dynamic.RequestTimeouts{Request: ptypes.Duration(time.Minute)},
+			expectDeadline: true,
+			expectedMax:    time.Minute,
+		},
+		{
+			desc:           "router per-try timeout",
+			routerConfig:   dynamic.RequestTimeouts{Request: ptypes.Duration(time.Minute), PerTry: ptypes.Duration(time.Second)},
+			expectDeadline: true,
+			expectedMax:    time.Second,
+		},
+		{
+			desc:           "service timeout",
+			serviceConfig:  &dynamic.RequestTimeouts{PerTry: ptypes.Duration(time.Second)},
+			expectDeadline: true,
+			expectedMax:    time.Second,
+		},
+		{
+			desc:           "smallest of service and router per-try timeouts",
+			routerConfig:   dynamic.RequestTimeouts{PerTry: ptypes.Duration(time.Minute)},
+			serviceConfig:  &dynamic.RequestTimeouts{Request: ptypes.Duration(time.Hour), PerTry: ptypes.Duration(time.Second)},
+			expectDeadline: true,
+			expectedMax:    time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				deadline    time.Time
+				hasDeadline bool
+			)
+			server := NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				deadline, hasDeadline = req.Context().Deadline()
+			}), test.serviceConfig)
+
+			handler := New(server, test.routerConfig)
+
+			start := time.Now()
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost", nil))
+
+			assert.Equal(t, test.expectDeadline, hasDeadline)
+			if test.expectDeadline {
+				assert.WithinDuration(t, start.Add(test.expectedMax), deadline, 100*time.Millisecond)
+			}
+		})
+	}
+}
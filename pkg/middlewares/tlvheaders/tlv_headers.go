@@ -0,0 +1,57 @@
+package tlvheaders
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/traefik/traefik/v3/pkg/proxyprotocol"
+)
+
+// tlvHeader is a request header holding the value of a PROXY protocol TLV.
+type tlvHeader struct {
+	tlvType proxyproto.PP2Type
+	name    string
+}
+
+// TLVHeaders is a middleware forwarding the values of the TLVs of the PROXY protocol header,
+// received with the connection of the request, as request headers.
+type TLVHeaders struct {
+	next    http.Handler
+	headers []tlvHeader
+}
+
+// New creates a TLVHeaders middleware, from the names of the headers by TLV type.
+func New(next http.Handler, headers map[string]string) (*TLVHeaders, error) {
+	t := &TLVHeaders{next: next}
+
+	for rawType, name := range headers {
+		tlvType, err := proxyprotocol.ParseTLVType(rawType)
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "" {
+			return nil, fmt.Errorf("empty header name for TLV type %q", rawType)
+		}
+
+		t.headers = append(t.headers, tlvHeader{tlvType: tlvType, name: http.CanonicalHeaderKey(name)})
+	}
+
+	return t, nil
+}
+
+func (t *TLVHeaders) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	header := proxyprotocol.HeaderFromContext(req.Context())
+
+	for _, h := range t.headers {
+		// The headers sent by the client are removed, so that they cannot be spoofed.
+		req.Header.Del(h.name)
+
+		if value, ok := proxyprotocol.TLVValue(header, h.tlvType); ok {
+			req.Header.Set(h.name, value)
+		}
+	}
+
+	t.next.ServeHTTP(rw, req)
+}
@@ -0,0 +1,116 @@
+package tlvheaders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/proxyprotocol"
+)
+
+func TestNew(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+
+	_, err := New(next, map[string]string{"foo": "X-Foo"})
+	assert.Error(t, err)
+
+	_, err = New(next, map[string]string{"0xEA": ""})
+	assert.Error(t, err)
+
+	_, err = New(next, map[string]string{"0xEA": "X-Amzn-Vpce-Id"})
+	assert.NoError(t, err)
+}
+
+func TestTLVHeaders(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		tlvs            []proxyproto.TLV
+		noHeader        bool
+		requestHeaders  map[string]string
+		expectedHeaders map[string]string
+	}{
+		{
+			desc: "TLVs forwarded as headers",
+			tlvs: []proxyproto.TLV{
+				{Type: 0xEA, Value: append([]byte{0x01}, "vpce-08d2bf15fac5001c9"...)},
+				{Type: 0xE5, Value: []byte("foo")},
+			},
+			expectedHeaders: map[string]string{
+				"X-Amzn-Vpce-Id": "vpce-08d2bf15fac5001c9",
+				"X-Custom":       "foo",
+			},
+		},
+		{
+			desc: "missing TLV",
+			tlvs: []proxyproto.TLV{
+				{Type: 0xE5, Value: []byte("foo")},
+			},
+			expectedHeaders: map[string]string{
+				"X-Amzn-Vpce-Id": "",
+				"X-Custom":       "foo",
+			},
+		},
+		{
+			desc: "headers sent by the client are overridden",
+			tlvs: []proxyproto.TLV{
+				{Type: 0xE5, Value: []byte("foo")},
+			},
+			requestHeaders: map[string]string{
+				"X-Amzn-Vpce-Id": "spoofed",
+				"X-Custom":       "spoofed",
+			},
+			expectedHeaders: map[string]string{
+				"X-Amzn-Vpce-Id": "",
+				"X-Custom":       "foo",
+			},
+		},
+		{
+			desc:     "headers sent by the client are removed without PROXY protocol header",
+			noHeader: true,
+			requestHeaders: map[string]string{
+				"X-Amzn-Vpce-Id": "spoofed",
+			},
+			expectedHeaders: map[string]string{
+				"X-Amzn-Vpce-Id": "",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var headers http.Header
+			next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				headers = req.Header
+			})
+
+			handler, err := New(next, map[string]string{
+				"0xEA": "X-Amzn-Vpce-Id",
+				"229":  "x-custom",
+			})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "http://localhost", http.NoBody)
+			for name, value := range test.requestHeaders {
+				req.Header.Set(name, value)
+			}
+
+			if !test.noHeader {
+				header := &proxyproto.Header{}
+				require.NoError(t, header.SetTLVs(test.tlvs))
+				req = req.WithContext(proxyprotocol.WithHeader(req.Context(), header))
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			for name, value := range test.expectedHeaders {
+				assert.Equal(t, value, headers.Get(name), name)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package ipallowlist
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/udp"
+)
+
+func TestNewIPAllowLister(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		allowList     dynamic.UDPIPAllowList
+		expectedError bool
+	}{
+		{
+			desc:          "Empty config",
+			allowList:     dynamic.UDPIPAllowList{},
+			expectedError: true,
+		},
+		{
+			desc: "invalid IP",
+			allowList: dynamic.UDPIPAllowList{
+				SourceRange: []string{"foo"},
+			},
+			expectedError: true,
+		},
+		{
+			desc: "valid IP",
+			allowList: dynamic.UDPIPAllowList{
+				SourceRange: []string{"10.10.10.10"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := udp.HandlerFunc(func(conn *udp.Conn) {})
+			allowLister, err := New(context.Background(), next, test.allowList, "traefikTest")
+
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotNil(t, allowLister)
+			}
+		})
+	}
+}
+
+func TestIPAllowLister_ServeUDP(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		allowList dynamic.UDPIPAllowList
+		expected  string
+	}{
+		{
+			desc: "authorized with remote address",
+			allowList: dynamic.UDPIPAllowList{
+				SourceRange: []string{"127.0.0.1"},
+			},
+			expected: "OK",
+		},
+		{
+			desc: "non authorized with remote address",
+			allowList: dynamic.UDPIPAllowList{
+				SourceRange: []string{"20.20.20.20"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			next := udp.HandlerFunc(func(conn *udp.Conn) {
+				_, _ = conn.Write([]byte("OK"))
+			})
+
+			allowLister, err := New(context.Background(), next, test.allowList, "traefikTest")
+			require.NoError(t, err)
+
+			conn, err := net.Dial("udp", serve(t, allowLister))
+			require.NoError(t, err)
+
+			_, err = conn.Write([]byte("ping"))
+			require.NoError(t, err)
+
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(500*time.Millisecond)))
+
+			b := make([]byte, 16)
+			n, err := conn.Read(b)
+			if test.expected == "" {
+				var netErr net.Error
+				require.ErrorAs(t, err, &netErr)
+				assert.True(t, netErr.Timeout())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(b[:n]))
+		})
+	}
+}
+
+// serve serves the sessions of a UDP listener with the given handler, and returns the address of the listener.
+func serve(t *testing.T, handler udp.Handler) string {
+	t.Helper()
+
+	addr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	listener, err := udp.Listen("udp", addr, 3*time.Second)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go handler.ServeUDP(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
@@ -0,0 +1,102 @@
+package maxsessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/udp"
+)
+
+const typeName = "MaxSessionsUDP"
+
+type maxSessions struct {
+	name        string
+	next        udp.Handler
+	amount      int64
+	perClientIP int64
+
+	mu       sync.Mutex
+	total    int64
+	sessions map[string]int64 // current number of sessions by client IP.
+}
+
+// New creates a max sessions middleware.
+// The sessions are counted as a whole, and grouped by client IP.
+func New(ctx context.Context, next udp.Handler, config dynamic.UDPMaxSessions, name string) (udp.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	if config.Amount <= 0 && config.PerClientIP <= 0 {
+		return nil, errors.New("amount or perClientIP must be greater than zero")
+	}
+
+	return &maxSessions{
+		name:        name,
+		next:        next,
+		amount:      config.Amount,
+		perClientIP: config.PerClientIP,
+		sessions:    make(map[string]int64),
+	}, nil
+}
+
+// ServeUDP serves the given UDP session.
+func (m *maxSessions) ServeUDP(conn *udp.Conn) {
+	logger := middlewares.GetLogger(context.Background(), m.name, typeName)
+
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		logger.Error().Err(err).Msg("Cannot parse IP from remote addr")
+		conn.Close()
+		return
+	}
+
+	if err = m.increment(ip); err != nil {
+		logger.Debug().Err(err).Msg("Session rejected")
+		conn.Close()
+		return
+	}
+
+	defer m.decrement(ip)
+
+	m.next.ServeUDP(conn)
+}
+
+// increment increases the counters for the number of sessions,
+// as a whole and for the given IP.
+// It returns an error if one of them would go above the max allowed number of sessions.
+func (m *maxSessions) increment(ip string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.amount > 0 && m.total >= m.amount {
+		return errors.New("max number of sessions reached")
+	}
+
+	if m.perClientIP > 0 && m.sessions[ip] >= m.perClientIP {
+		return fmt.Errorf("max number of sessions reached for %s", ip)
+	}
+
+	m.total++
+	m.sessions[ip]++
+
+	return nil
+}
+
+// decrement decreases the counters for the number of sessions,
+// as a whole and for the given IP.
+func (m *maxSessions) decrement(ip string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.total--
+
+	m.sessions[ip]--
+	if m.sessions[ip] <= 0 {
+		delete(m.sessions, ip)
+	}
+}
@@ -0,0 +1,121 @@
+package maxsessions
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/udp"
+)
+
+func TestNew(t *testing.T) {
+	next := udp.HandlerFunc(func(conn *udp.Conn) {})
+
+	_, err := New(context.Background(), next, dynamic.UDPMaxSessions{}, "foo")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.UDPMaxSessions{PerClientIP: 1}, "foo")
+	assert.NoError(t, err)
+}
+
+func TestMaxSessions_ServeUDP(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.UDPMaxSessions
+	}{
+		{
+			desc:   "amount",
+			config: dynamic.UDPMaxSessions{Amount: 1},
+		},
+		{
+			desc:   "per client IP",
+			config: dynamic.UDPMaxSessions{PerClientIP: 1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// The sessions are echoed until they are idle.
+			next := udp.HandlerFunc(func(conn *udp.Conn) {
+				b := make([]byte, 16)
+				for {
+					n, err := conn.Read(b)
+					if err != nil {
+						return
+					}
+
+					_, _ = conn.Write(b[:n])
+				}
+			})
+
+			middleware, err := New(context.Background(), next, test.config, "foo")
+			require.NoError(t, err)
+
+			addr := serve(t, middleware, 500*time.Millisecond)
+
+			first, err := net.Dial("udp", addr)
+			require.NoError(t, err)
+			assert.True(t, echoed(t, first))
+
+			// The session from another client port is rejected while the first one is ongoing.
+			second, err := net.Dial("udp", addr)
+			require.NoError(t, err)
+			assert.False(t, echoed(t, second))
+
+			// Once the first session is idle, a new one is accepted.
+			time.Sleep(time.Second)
+
+			third, err := net.Dial("udp", addr)
+			require.NoError(t, err)
+			assert.True(t, echoed(t, third))
+		})
+	}
+}
+
+// echoed reports whether a datagram sent through the given connection is echoed back.
+func echoed(t *testing.T, conn net.Conn) bool {
+	t.Helper()
+
+	_, err := conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+
+	b := make([]byte, 16)
+	n, err := conn.Read(b)
+
+	return err == nil && string(b[:n]) == "ping"
+}
+
+// serve serves the sessions of a UDP listener with the given handler, and returns the address of the listener.
+func serve(t *testing.T, handler udp.Handler, timeout time.Duration) string {
+	t.Helper()
+
+	addr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	listener, err := udp.Listen("udp", addr, timeout)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go handler.ServeUDP(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
@@ -0,0 +1,120 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/udp"
+	"golang.org/x/time/rate"
+)
+
+const typeName = "RateLimiterUDP"
+
+// rateLimiter drops the datagrams sent by a client IP above the rate of the middleware.
+type rateLimiter struct {
+	name  string
+	next  udp.Handler
+	rate  rate.Limit // datagrams/s
+	burst int64
+
+	mu sync.Mutex
+	// buckets are the token buckets of the client IPs with ongoing sessions,
+	// shared by all the sessions of a client IP.
+	buckets map[string]*bucket
+}
+
+// bucket is a token bucket, and the number of sessions using it.
+type bucket struct {
+	limiter  *rate.Limiter
+	sessions int
+}
+
+// New returns a rate limiter middleware.
+func New(ctx context.Context, next udp.Handler, config dynamic.UDPRateLimit, name string) (udp.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	if config.Average <= 0 {
+		return nil, errors.New("average must be greater than zero")
+	}
+
+	burst := config.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	period := time.Duration(config.Period)
+	if period < 0 {
+		return nil, fmt.Errorf("negative value not valid for period: %v", period)
+	}
+	if period == 0 {
+		period = time.Second
+	}
+
+	return &rateLimiter{
+		name:    name,
+		next:    next,
+		rate:    rate.Limit(float64(config.Average*int64(time.Second)) / float64(period)),
+		burst:   burst,
+		buckets: make(map[string]*bucket),
+	}, nil
+}
+
+// ServeUDP serves the given UDP session, dropping its datagrams while its client IP exceeds the rate.
+func (rl *rateLimiter) ServeUDP(conn *udp.Conn) {
+	logger := middlewares.GetLogger(context.Background(), rl.name, typeName)
+
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		logger.Error().Err(err).Msg("Cannot parse IP from remote addr")
+		conn.Close()
+		return
+	}
+
+	limiter := rl.acquire(ip)
+	defer rl.release(ip)
+
+	conn.AddFilter(func(datagram []byte) bool {
+		return limiter.Allow()
+	})
+
+	rl.next.ServeUDP(conn)
+}
+
+// acquire returns the token bucket of the given client IP, creating it if it has no other session.
+func (rl *rateLimiter) acquire(ip string) *rate.Limiter {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b, ok := rl.buckets[ip]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rl.rate, int(rl.burst))}
+		rl.buckets[ip] = b
+	}
+
+	b.sessions++
+
+	return b.limiter
+}
+
+// release forgets the token bucket of the given client IP, once it has no more sessions.
+func (rl *rateLimiter) release(ip string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b, ok := rl.buckets[ip]
+	if !ok {
+		return
+	}
+
+	b.sessions--
+	if b.sessions <= 0 {
+		delete(rl.buckets, ip)
+	}
+}
@@ -0,0 +1,115 @@
+package ratelimiter
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/udp"
+)
+
+func TestNew(t *testing.T) {
+	next := udp.HandlerFunc(func(conn *udp.Conn) {})
+
+	_, err := New(context.Background(), next, dynamic.UDPRateLimit{}, "foo")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.UDPRateLimit{Average: 1, Period: ptypes.Duration(-time.Second)}, "foo")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), next, dynamic.UDPRateLimit{Average: 1}, "foo")
+	assert.NoError(t, err)
+}
+
+func TestRateLimiter_ServeUDP(t *testing.T) {
+	// The datagrams are echoed until the session is idle.
+	next := udp.HandlerFunc(func(conn *udp.Conn) {
+		b := make([]byte, 16)
+		for {
+			n, err := conn.Read(b)
+			if err != nil {
+				return
+			}
+
+			_, _ = conn.Write(b[:n])
+		}
+	})
+
+	config := dynamic.UDPRateLimit{
+		Average: 1,
+		Period:  ptypes.Duration(time.Minute),
+		Burst:   3,
+	}
+
+	middleware, err := New(context.Background(), next, config, "foo")
+	require.NoError(t, err)
+
+	addr := serve(t, middleware)
+
+	first, err := net.Dial("udp", addr)
+	require.NoError(t, err)
+
+	second, err := net.Dial("udp", addr)
+	require.NoError(t, err)
+
+	// The sessions of a client IP share the same rate.
+	for i := 0; i < 3; i++ {
+		_, err = first.Write([]byte("ping"))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, countEchoes(t, first))
+
+	for i := 0; i < 3; i++ {
+		_, err = second.Write([]byte("ping"))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 0, countEchoes(t, second))
+}
+
+// countEchoes returns the number of datagrams echoed through the given connection.
+func countEchoes(t *testing.T, conn net.Conn) int {
+	t.Helper()
+
+	var count int
+	b := make([]byte, 16)
+	for {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+
+		if _, err := conn.Read(b); err != nil {
+			return count
+		}
+
+		count++
+	}
+}
+
+// serve serves the sessions of a UDP listener with the given handler, and returns the address of the listener.
+func serve(t *testing.T, handler udp.Handler) string {
+	t.Helper()
+
+	addr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	listener, err := udp.Listen("udp", addr, 3*time.Second)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go handler.ServeUDP(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
@@ -0,0 +1,228 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v3/pkg/rules"
+)
+
+// indexKey is the host and path under which a route is indexed.
+type indexKey struct {
+	// host is the lowercase host without trailing period, empty when the route matches any host.
+	host string
+	// path is the path, or path prefix, matched by the route.
+	// It is empty when the route matches any path.
+	path string
+	// exact tells whether path is matched exactly, instead of being a prefix.
+	exact bool
+}
+
+// indexKeys returns the keys under which a route with the given rule can be indexed,
+// and whether the rule can be indexed at all.
+// Only the rules made of disjunctions of Host, Path and PathPrefix conjunctions can be indexed.
+func indexKeys(tree *rules.Tree) ([]indexKey, bool) {
+	if tree.Matcher == "or" {
+		left, ok := indexKeys(tree.RuleLeft)
+		if !ok {
+			return nil, false
+		}
+
+		right, ok := indexKeys(tree.RuleRight)
+		if !ok {
+			return nil, false
+		}
+
+		return append(left, right...), true
+	}
+
+	var key indexKey
+	if !conjunctionKey(tree, &key) {
+		return nil, false
+	}
+
+	return []indexKey{key}, true
+}
+
+// conjunctionKey fills the given key with the Host, Path and PathPrefix matchers of the given conjunction,
+// and returns whether the conjunction is only made of these matchers.
+// As the route rules are still evaluated against the requests found in the index,
+// only the first matcher of each kind is kept in the key.
+func conjunctionKey(tree *rules.Tree, key *indexKey) bool {
+	if tree.Matcher == "and" {
+		return conjunctionKey(tree.RuleLeft, key) && conjunctionKey(tree.RuleRight, key)
+	}
+
+	if tree.Not || len(tree.Value) != 1 {
+		return false
+	}
+
+	switch tree.Matcher {
+	case "Host":
+		if key.host == "" {
+			key.host = indexHost(tree.Value[0])
+		}
+	case "Path", "PathPrefix":
+		if key.path == "" {
+			key.path = tree.Value[0]
+			key.exact = tree.Matcher == "Path"
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+// indexHost returns the host under which the given host is indexed.
+func indexHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// routeIndex indexes routes by host and path.
+// It is used to find the candidate routes of a request, without evaluating the rules of all the routes.
+// The candidates are a superset of the indexed routes matching the request, and their rules must still be evaluated.
+type routeIndex struct {
+	// anyHost holds the routes matching any host.
+	anyHost *pathNode
+	hosts   map[string]*pathNode
+}
+
+// add indexes the given route under the given key.
+func (i *routeIndex) add(key indexKey, r *route) {
+	if key.host == "" {
+		if i.anyHost == nil {
+			i.anyHost = &pathNode{}
+		}
+
+		i.anyHost.insert(key.path, key.exact, r)
+		return
+	}
+
+	if i.hosts == nil {
+		i.hosts = make(map[string]*pathNode)
+	}
+
+	node, ok := i.hosts[key.host]
+	if !ok {
+		node = &pathNode{}
+		i.hosts[key.host] = node
+	}
+
+	node.insert(key.path, key.exact, r)
+}
+
+// lookup calls fn with the candidate routes of the given request.
+// A route may be given more than once.
+func (i *routeIndex) lookup(req *http.Request, fn func(*route)) {
+	path := req.URL.Path
+
+	i.anyHost.lookup(path, fn)
+
+	if len(i.hosts) == 0 {
+		return
+	}
+
+	host := indexHost(requestdecorator.GetCanonizedHost(req.Context()))
+	if host != "" {
+		i.hosts[host].lookup(path, fn)
+	}
+
+	if flatHost := indexHost(requestdecorator.GetCNAMEFlatten(req.Context())); flatHost != "" && flatHost != host {
+		i.hosts[flatHost].lookup(path, fn)
+	}
+}
+
+// pathNode is a node of a radix tree holding the routes by path.
+type pathNode struct {
+	// prefix is the part of the path held by the node, following the prefix of its parent.
+	prefix   string
+	children []*pathNode
+	// exact holds the routes matching the path of the node.
+	exact []*route
+	// prefixed holds the routes matching the paths starting with the path of the node.
+	prefixed []*route
+}
+
+// insert adds the given route under the given path, relative to the node.
+func (n *pathNode) insert(path string, exact bool, r *route) {
+	for path != "" {
+		child := n.child(path[0])
+		if child == nil {
+			child = &pathNode{prefix: path}
+			n.children = append(n.children, child)
+		}
+
+		common := commonPrefixLen(child.prefix, path)
+		if common < len(child.prefix) {
+			// Splits the child, so that its prefix is the common part of the paths.
+			split := &pathNode{prefix: child.prefix[:common], children: []*pathNode{child}}
+			n.replaceChild(split)
+			child.prefix = child.prefix[common:]
+			child = split
+		}
+
+		n = child
+		path = path[common:]
+	}
+
+	if exact {
+		n.exact = append(n.exact, r)
+		return
+	}
+
+	n.prefixed = append(n.prefixed, r)
+}
+
+// lookup calls fn with the routes matching the given path, relative to the node.
+func (n *pathNode) lookup(path string, fn func(*route)) {
+	for n != nil {
+		for _, r := range n.prefixed {
+			fn(r)
+		}
+
+		if path == "" {
+			for _, r := range n.exact {
+				fn(r)
+			}
+			return
+		}
+
+		child := n.child(path[0])
+		if child == nil || !strings.HasPrefix(path, child.prefix) {
+			return
+		}
+
+		path = path[len(child.prefix):]
+		n = child
+	}
+}
+
+func (n *pathNode) child(b byte) *pathNode {
+	for _, child := range n.children {
+		if child.prefix[0] == b {
+			return child
+		}
+	}
+
+	return nil
+}
+
+func (n *pathNode) replaceChild(child *pathNode) {
+	for i, c := range n.children {
+		if c.prefix[0] == child.prefix[0] {
+			n.children[i] = child
+			return
+		}
+	}
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
@@ -0,0 +1,256 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v3/pkg/rules"
+	"github.com/traefik/traefik/v3/pkg/testhelpers"
+)
+
+func Test_indexKeys(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		rule         string
+		expected     []indexKey
+		notIndexable bool
+	}{
+		{
+			desc:     "Host",
+			rule:     "Host(`Example.com.`)",
+			expected: []indexKey{{host: "example.com"}},
+		},
+		{
+			desc:     "PathPrefix",
+			rule:     "PathPrefix(`/api`)",
+			expected: []indexKey{{path: "/api"}},
+		},
+		{
+			desc:     "Host and Path",
+			rule:     "Host(`example.com`) && Path(`/api`)",
+			expected: []indexKey{{host: "example.com", path: "/api", exact: true}},
+		},
+		{
+			desc:     "case insensitive matchers",
+			rule:     "host(`example.com`) && PATHPREFIX(`/api`)",
+			expected: []indexKey{{host: "example.com", path: "/api"}},
+		},
+		{
+			desc: "disjunction of conjunctions",
+			rule: "(Host(`example.com`) && PathPrefix(`/api`)) || Host(`example.org`)",
+			expected: []indexKey{
+				{host: "example.com", path: "/api"},
+				{host: "example.org"},
+			},
+		},
+		{
+			desc:     "several matchers of the same kind",
+			rule:     "Host(`example.com`) && Host(`example.org`) && PathPrefix(`/api`) && Path(`/api/v1`)",
+			expected: []indexKey{{host: "example.com", path: "/api"}},
+		},
+		{
+			desc:         "negated matcher",
+			rule:         "Host(`example.com`) && !PathPrefix(`/api`)",
+			notIndexable: true,
+		},
+		{
+			desc:         "other matcher",
+			rule:         "Host(`example.com`) && Method(`GET`)",
+			notIndexable: true,
+		},
+		{
+			desc:         "disjunction in a conjunction",
+			rule:         "Host(`example.com`) && (PathPrefix(`/api`) || PathPrefix(`/v1`))",
+			notIndexable: true,
+		},
+		{
+			desc:         "regexp",
+			rule:         "HostRegexp(`^.+\\.example\\.com$`)",
+			notIndexable: true,
+		},
+	}
+
+	var matchers []string
+	for matcher := range httpFuncs {
+		matchers = append(matchers, matcher)
+	}
+
+	parser, err := rules.NewParser(matchers)
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			parse, err := parser.Parse(test.rule)
+			require.NoError(t, err)
+
+			buildTree, ok := parse.(rules.TreeBuilder)
+			require.True(t, ok)
+
+			keys, ok := indexKeys(buildTree())
+			assert.Equal(t, !test.notIndexable, ok)
+			assert.Equal(t, test.expected, keys)
+		})
+	}
+}
+
+func Test_pathNode(t *testing.T) {
+	routes := map[string]*route{}
+
+	var root pathNode
+	for _, path := range []string{"", "/", "/api", "/api/v1", "/apis", "/app", "=/api", "=/api/v1", "=/b"} {
+		exact := path != "" && path[0] == '='
+		if exact {
+			path = path[1:]
+		}
+
+		r := &route{}
+		routes[fmt.Sprint(exact, path)] = r
+		root.insert(path, exact, r)
+	}
+
+	testCases := []struct {
+		path     string
+		expected []string
+	}{
+		{path: "", expected: []string{"false"}},
+		{path: "/", expected: []string{"false", "false/"}},
+		{path: "/a", expected: []string{"false", "false/"}},
+		{path: "/api", expected: []string{"false", "false/", "false/api", "true/api"}},
+		{path: "/api/", expected: []string{"false", "false/", "false/api"}},
+		{path: "/api/v1", expected: []string{"false", "false/", "false/api", "false/api/v1", "true/api/v1"}},
+		{path: "/apis/foo", expected: []string{"false", "false/", "false/api", "false/apis"}},
+		{path: "/app", expected: []string{"false", "false/", "false/app"}},
+		{path: "/b", expected: []string{"false", "false/", "true/b"}},
+		{path: "/bar", expected: []string{"false", "false/"}},
+	}
+
+	for _, test := range testCases {
+		var expected []*route
+		for _, key := range test.expected {
+			expected = append(expected, routes[key])
+		}
+
+		var found []*route
+		root.lookup(test.path, func(r *route) {
+			found = append(found, r)
+		})
+
+		assert.ElementsMatch(t, expected, found, test.path)
+	}
+}
+
+// TestMuxer_index checks that the indexed routes are matched like the routes evaluated in priority order.
+func TestMuxer_index(t *testing.T) {
+	muxer, err := NewMuxer()
+	require.NoError(t, err)
+
+	routeRules := []string{
+		"Host(`example.com`)",
+		"Host(`example.com`) && PathPrefix(`/api`)",
+		"Host(`example.com`) && Path(`/api/v1`)",
+		"Host(`example.com.`) && PathPrefix(`/api/v2`)",
+		"Host(`example.org`) || (Host(`example.com`) && PathPrefix(`/org`))",
+		"PathPrefix(`/`)",
+		"PathPrefix(`/api`)",
+		"Path(`/health`)",
+		"HostRegexp(`^.+\\.example\\.com$`)",
+		"Host(`example.com`) && Method(`POST`)",
+		"Host(`example.com`) && !PathPrefix(`/api`)",
+		"PathPrefix(`/api`) && Header(`X-Version`, `2`)",
+	}
+
+	for _, rule := range routeRules {
+		// Some rules have the same priority, which is then given by the order of the routes.
+		for _, priority := range []int{0, 10, 100} {
+			if priority == 0 {
+				priority = GetRulePriority(rule)
+			}
+
+			err = muxer.AddRoute("", rule, priority, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+			require.NoError(t, err)
+		}
+	}
+
+	// RequestDecorator is necessary for the host rule
+	reqHost := requestdecorator.New(nil)
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		for _, host := range []string{"example.com", "EXAMPLE.com.", "example.org", "foo.example.com", "example.net"} {
+			for _, path := range []string{"/", "/api", "/api/v1", "/api/v2/foo", "/org", "/health", "/other"} {
+				for _, version := range []string{"", "2"} {
+					req := testhelpers.MustNewRequest(method, "http://"+host+path, http.NoBody)
+					if version != "" {
+						req.Header.Set("X-Version", version)
+					}
+
+					reqHost.ServeHTTP(httptest.NewRecorder(), req, func(_ http.ResponseWriter, req *http.Request) {
+						var expected *route
+						for _, r := range muxer.routes {
+							if r.matchers.match(req) {
+								expected = r
+								break
+							}
+						}
+
+						assert.Same(t, expected, muxer.match(req), req.Method+" "+req.URL.String()+" "+version)
+					})
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkMuxer(b *testing.B) {
+	const routes = 10000
+
+	testCases := []struct {
+		desc string
+		rule string
+	}{
+		{
+			desc: "indexed",
+			rule: "Host(`service-%d.example.com`) && PathPrefix(`/api`)",
+		},
+		{
+			desc: "fallback",
+			rule: "Host(`service-%d.example.com`) && PathPrefix(`/api`) && Method(`GET`)",
+		},
+	}
+
+	for _, test := range testCases {
+		b.Run(test.desc, func(b *testing.B) {
+			muxer, err := NewMuxer()
+			require.NoError(b, err)
+
+			handler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+			for i := 0; i < routes; i++ {
+				rule := fmt.Sprintf(test.rule, i)
+				err = muxer.AddRoute("", rule, GetRulePriority(rule), handler)
+				require.NoError(b, err)
+			}
+
+			// Matches the route with the lowest priority.
+			req := testhelpers.MustNewRequest(http.MethodGet, "http://service-1.example.com/api/foo", http.NoBody)
+			requestdecorator.New(nil).ServeHTTP(httptest.NewRecorder(), req, func(_ http.ResponseWriter, decorated *http.Request) {
+				req = decorated
+			})
+
+			rw := httptest.NewRecorder()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				muxer.ServeHTTP(rw, req)
+			}
+		})
+	}
+}
@@ -1,30 +1,42 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/ip"
 	"github.com/traefik/traefik/v3/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v3/pkg/proxyprotocol"
+	"github.com/traefik/traefik/v3/pkg/tls/fingerprint"
 	"golang.org/x/exp/slices"
 )
 
 var httpFuncs = map[string]func(*matchersTree, ...string) error{
-	"ClientIP":     expectNParameters(clientIP, 1),
-	"Method":       expectNParameters(method, 1),
-	"Host":         expectNParameters(host, 1),
-	"HostRegexp":   expectNParameters(hostRegexp, 1),
-	"Path":         expectNParameters(path, 1),
-	"PathRegexp":   expectNParameters(pathRegexp, 1),
-	"PathPrefix":   expectNParameters(pathPrefix, 1),
-	"Header":       expectNParameters(header, 2),
-	"HeaderRegexp": expectNParameters(headerRegexp, 2),
-	"Query":        expectNParameters(query, 1, 2),
-	"QueryRegexp":  expectNParameters(queryRegexp, 1, 2),
+	"ClientHelloFingerprint": expectNParameters(clientHelloFingerprint, 1),
+	"ClientIP":               expectNParameters(clientIP, 1),
+	"ClientIPWithStrategy":   expectNParameters(clientIPWithStrategy, 2),
+	"Cookie":                 expectNParameters(cookie, 2),
+	"CookieRegexp":           expectNParameters(cookieRegexp, 2),
+	"Method":                 expectNParameters(method, 1),
+	"Proto":                  expectNParameters(proto, 1),
+	"ProxyProtocolTLV":       expectNParameters(proxyProtocolTLV, 2),
+	"SNI":                    expectNParameters(sni, 1),
+	"Host":                   expectNParameters(host, 1),
+	"HostRegexp":             expectNParameters(hostRegexp, 1),
+	"Path":                   expectNParameters(path, 1),
+	"PathRegexp":             expectNParameters(pathRegexp, 1),
+	"PathPrefix":             expectNParameters(pathPrefix, 1),
+	"Header":                 expectNParameters(header, 2),
+	"HeaderRegexp":           expectNParameters(headerRegexp, 2),
+	"HeaderExists":           expectNParameters(headerExists, 1),
+	"Query":                  expectNParameters(query, 1, 2),
+	"QueryRegexp":            expectNParameters(queryRegexp, 1, 2),
 }
 
 func expectNParameters(fn func(*matchersTree, ...string) error, n ...int) func(*matchersTree, ...string) error {
@@ -37,6 +49,36 @@ func expectNParameters(fn func(*matchersTree, ...string) error, n ...int) func(*
 	}
 }
 
+func clientHelloFingerprint(tree *matchersTree, fingerprints ...string) error {
+	fp := fingerprints[0]
+
+	if fp == "" {
+		return errors.New("empty value for ClientHelloFingerprint matcher is not allowed")
+	}
+
+	tree.matcher = func(req *http.Request) bool {
+		return fingerprint.FromContext(req.Context()).Match(fp)
+	}
+
+	return nil
+}
+
+func proxyProtocolTLV(tree *matchersTree, values ...string) error {
+	tlvType, err := proxyprotocol.ParseTLVType(values[0])
+	if err != nil {
+		return fmt.Errorf("parsing ProxyProtocolTLV matcher: %w", err)
+	}
+
+	value := values[1]
+
+	tree.matcher = func(req *http.Request) bool {
+		tlvValue, ok := proxyprotocol.TLVValue(proxyprotocol.HeaderFromContext(req.Context()), tlvType)
+		return ok && tlvValue == value
+	}
+
+	return nil
+}
+
 func clientIP(tree *matchersTree, clientIP ...string) error {
 	checker, err := ip.NewChecker(clientIP)
 	if err != nil {
@@ -58,6 +100,109 @@ func clientIP(tree *matchersTree, clientIP ...string) error {
 	return nil
 }
 
+func clientIPWithStrategy(tree *matchersTree, params ...string) error {
+	checker, err := ip.NewChecker(params[:1])
+	if err != nil {
+		return fmt.Errorf("initializing IP checker for ClientIPWithStrategy matcher: %w", err)
+	}
+
+	strategy, err := parseIPStrategy(params[1])
+	if err != nil {
+		return fmt.Errorf("parsing IP strategy for ClientIPWithStrategy matcher: %w", err)
+	}
+
+	tree.matcher = func(req *http.Request) bool {
+		clientIP := strategy.GetIP(req)
+		if clientIP == "" {
+			// The X-Forwarded-For header does not hold enough IPs.
+			return false
+		}
+
+		ok, err := checker.Contains(clientIP)
+		if err != nil {
+			log.Ctx(req.Context()).Warn().Err(err).Msg("ClientIPWithStrategy matcher: could not match client IP")
+			return false
+		}
+
+		return ok
+	}
+
+	return nil
+}
+
+// parseIPStrategy parses an IP strategy, either depth=<depth> to use the X-Forwarded-For IP at the given depth,
+// starting from the right, or excludedIPs=<ips> to use the first X-Forwarded-For IP,
+// starting from the right, which is not in the given comma-separated IPs.
+func parseIPStrategy(value string) (ip.Strategy, error) {
+	name, param, ok := strings.Cut(value, "=")
+	if !ok {
+		return nil, fmt.Errorf("invalid IP strategy %q, expected depth=<depth> or excludedIPs=<ips>", value)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "depth":
+		depth, err := strconv.Atoi(strings.TrimSpace(param))
+		if err != nil || depth <= 0 {
+			return nil, fmt.Errorf("invalid depth %q, expected a positive integer", param)
+		}
+
+		return &ip.DepthStrategy{Depth: depth}, nil
+	case "excludedips":
+		var excludedIPs []string
+		for _, excludedIP := range strings.Split(param, ",") {
+			if excludedIP = strings.TrimSpace(excludedIP); excludedIP != "" {
+				excludedIPs = append(excludedIPs, excludedIP)
+			}
+		}
+
+		checker, err := ip.NewChecker(excludedIPs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid excluded IPs: %w", err)
+		}
+
+		return &ip.PoolStrategy{Checker: checker}, nil
+	default:
+		return nil, fmt.Errorf("unknown IP strategy %q, expected depth or excludedIPs", name)
+	}
+}
+
+func cookie(tree *matchersTree, cookies ...string) error {
+	name, value := cookies[0], cookies[1]
+
+	tree.matcher = func(req *http.Request) bool {
+		for _, c := range req.Cookies() {
+			if c.Name == name && c.Value == value {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return nil
+}
+
+func cookieRegexp(tree *matchersTree, cookies ...string) error {
+	name := cookies[0]
+
+	re, err := regexp.Compile(cookies[1])
+	if err != nil {
+		return fmt.Errorf("compiling CookieRegexp matcher: %w", err)
+	}
+
+	tree.matcher = func(req *http.Request) bool {
+		for _, c := range req.Cookies() {
+			if c.Name == name && re.MatchString(c.Value) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return nil
+}
+
 func method(tree *matchersTree, methods ...string) error {
 	method := strings.ToUpper(methods[0])
 
@@ -68,6 +213,50 @@ func method(tree *matchersTree, methods ...string) error {
 	return nil
 }
 
+func proto(tree *matchersTree, protos ...string) error {
+	var match func(req *http.Request) bool
+
+	switch strings.ToUpper(protos[0]) {
+	case "HTTP/1":
+		match = func(req *http.Request) bool { return req.ProtoMajor == 1 }
+	case "HTTP/1.0":
+		match = func(req *http.Request) bool { return req.ProtoMajor == 1 && req.ProtoMinor == 0 }
+	case "HTTP/1.1":
+		match = func(req *http.Request) bool { return req.ProtoMajor == 1 && req.ProtoMinor == 1 }
+	case "HTTP/2":
+		// HTTP/3 requests are matched too, as HTTP/3 is HTTP/2 semantics over QUIC.
+		match = func(req *http.Request) bool { return req.ProtoMajor >= 2 }
+	case "HTTP/3":
+		match = func(req *http.Request) bool { return req.ProtoMajor == 3 }
+	default:
+		return fmt.Errorf("invalid value %q for Proto matcher, expected one of HTTP/1, HTTP/1.0, HTTP/1.1, HTTP/2 or HTTP/3", protos[0])
+	}
+
+	tree.matcher = match
+
+	return nil
+}
+
+func sni(tree *matchersTree, serverNames ...string) error {
+	serverName := serverNames[0]
+
+	if !IsASCII(serverName) {
+		return fmt.Errorf("invalid value %q for SNI matcher, non-ASCII characters are not allowed", serverName)
+	}
+
+	serverName = strings.TrimSuffix(strings.ToLower(serverName), ".")
+
+	tree.matcher = func(req *http.Request) bool {
+		if req.TLS == nil {
+			return false
+		}
+
+		return strings.TrimSuffix(strings.ToLower(req.TLS.ServerName), ".") == serverName
+	}
+
+	return nil
+}
+
 func host(tree *matchersTree, hosts ...string) error {
 	host := hosts[0]
 
@@ -214,6 +403,16 @@ func headerRegexp(tree *matchersTree, headers ...string) error {
 	return nil
 }
 
+func headerExists(tree *matchersTree, headers ...string) error {
+	key := http.CanonicalHeaderKey(headers[0])
+
+	tree.matcher = func(req *http.Request) bool {
+		return len(req.Header[key]) > 0
+	}
+
+	return nil
+}
+
 func query(tree *matchersTree, queries ...string) error {
 	key := queries[0]
 
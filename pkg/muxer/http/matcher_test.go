@@ -6,11 +6,164 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pires/go-proxyproto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v3/pkg/proxyprotocol"
+	"github.com/traefik/traefik/v3/pkg/tls/fingerprint"
 )
 
+func TestClientHelloFingerprintMatcher(t *testing.T) {
+	fingerprints := map[string]*fingerprint.Fingerprints{
+		"reference": {
+			JA3: "ada70206e40642a3e4461f35503241d5",
+			JA4: "t13d1516h2_8daaf6152771_e5627efa2ab1",
+		},
+		"other": {JA3: "foo", JA4: "bar"},
+		"none":  nil,
+	}
+
+	testCases := []struct {
+		desc          string
+		rule          string
+		expected      map[string]int
+		expectedError bool
+	}{
+		{
+			desc:          "invalid ClientHelloFingerprint matcher (empty parameter)",
+			rule:          "ClientHelloFingerprint(``)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid ClientHelloFingerprint matcher (too many parameters)",
+			rule:          "ClientHelloFingerprint(`foo`, `bar`)",
+			expectedError: true,
+		},
+		{
+			desc: "valid ClientHelloFingerprint matcher with JA3",
+			rule: "ClientHelloFingerprint(`ada70206e40642a3e4461f35503241d5`)",
+			expected: map[string]int{
+				"reference": http.StatusOK,
+				"other":     http.StatusNotFound,
+				"none":      http.StatusNotFound,
+			},
+		},
+		{
+			desc: "valid ClientHelloFingerprint matcher with JA4",
+			rule: "ClientHelloFingerprint(`T13D1516H2_8DAAF6152771_E5627EFA2AB1`)",
+			expected: map[string]int{
+				"reference": http.StatusOK,
+				"other":     http.StatusNotFound,
+				"none":      http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute("", test.rule, 0, handler)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			results := make(map[string]int)
+			for name := range test.expected {
+				w := httptest.NewRecorder()
+
+				req := httptest.NewRequest(http.MethodGet, "https://example.com", http.NoBody)
+				if fingerprints[name] != nil {
+					req = req.WithContext(fingerprint.WithFingerprints(req.Context(), fingerprints[name]))
+				}
+
+				muxer.ServeHTTP(w, req)
+				results[name] = w.Code
+			}
+			assert.Equal(t, test.expected, results)
+		})
+	}
+}
+
+func TestProxyProtocolTLVMatcher(t *testing.T) {
+	headers := map[string][]proxyproto.TLV{
+		"vpce":  {{Type: 0xEA, Value: append([]byte{0x01}, "vpce-08d2bf15fac5001c9"...)}},
+		"other": {{Type: 0xEA, Value: append([]byte{0x01}, "vpce-foo"...)}},
+		"none":  nil,
+	}
+
+	testCases := []struct {
+		desc          string
+		rule          string
+		expected      map[string]int
+		expectedError bool
+	}{
+		{
+			desc:          "invalid ProxyProtocolTLV matcher (invalid type)",
+			rule:          "ProxyProtocolTLV(`foo`, `bar`)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid ProxyProtocolTLV matcher (missing value)",
+			rule:          "ProxyProtocolTLV(`0xEA`)",
+			expectedError: true,
+		},
+		{
+			desc: "valid ProxyProtocolTLV matcher",
+			rule: "ProxyProtocolTLV(`0xEA`, `vpce-08d2bf15fac5001c9`)",
+			expected: map[string]int{
+				"vpce":  http.StatusOK,
+				"other": http.StatusNotFound,
+				"none":  http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute("", test.rule, 0, handler)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			results := make(map[string]int)
+			for name := range test.expected {
+				w := httptest.NewRecorder()
+
+				req := httptest.NewRequest(http.MethodGet, "https://example.com", http.NoBody)
+				if headers[name] != nil {
+					header := &proxyproto.Header{}
+					require.NoError(t, header.SetTLVs(headers[name]))
+					req = req.WithContext(proxyprotocol.WithHeader(req.Context(), header))
+				}
+
+				muxer.ServeHTTP(w, req)
+				results[name] = w.Code
+			}
+			assert.Equal(t, test.expected, results)
+		})
+	}
+}
+
 func TestClientIPMatcher(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -73,7 +226,7 @@ func TestClientIPMatcher(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 				return
@@ -96,6 +249,180 @@ func TestClientIPMatcher(t *testing.T) {
 	}
 }
 
+func TestClientIPWithStrategyMatcher(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		expected      map[string]int
+		expectedError bool
+	}{
+		{
+			desc:          "invalid ClientIPWithStrategy matcher (missing strategy)",
+			rule:          "ClientIPWithStrategy(`127.0.0.1`)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid ClientIPWithStrategy matcher (invalid IP)",
+			rule:          "ClientIPWithStrategy(`1`, `depth=1`)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid ClientIPWithStrategy matcher (unknown strategy)",
+			rule:          "ClientIPWithStrategy(`127.0.0.1`, `foo=1`)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid ClientIPWithStrategy matcher (invalid depth)",
+			rule:          "ClientIPWithStrategy(`127.0.0.1`, `depth=0`)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid ClientIPWithStrategy matcher (invalid excluded IPs)",
+			rule:          "ClientIPWithStrategy(`127.0.0.1`, `excludedIPs=foo`)",
+			expectedError: true,
+		},
+		{
+			desc: "valid ClientIPWithStrategy matcher with depth",
+			rule: "ClientIPWithStrategy(`192.168.1.0/24`, `depth=2`)",
+			expected: map[string]int{
+				"":                                   http.StatusNotFound,
+				"192.168.1.1":                        http.StatusNotFound,
+				"192.168.1.1, 10.0.0.1":              http.StatusOK,
+				"10.0.0.1, 192.168.1.1, 10.0.0.2":    http.StatusOK,
+				"192.168.1.1, 10.0.0.1, 10.0.0.2":    http.StatusNotFound,
+				"192.168.2.1, 10.0.0.1":              http.StatusNotFound,
+				"192.168.1.1, 10.0.0.1, 192.168.1.2": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "valid ClientIPWithStrategy matcher with excluded IPs",
+			rule: "ClientIPWithStrategy(`192.168.1.0/24`, `excludedIPs=10.0.0.1, 10.0.0.2`)",
+			expected: map[string]int{
+				"":                                http.StatusNotFound,
+				"192.168.1.1":                     http.StatusOK,
+				"192.168.1.1, 10.0.0.1, 10.0.0.2": http.StatusOK,
+				"192.168.1.1, 10.0.0.3, 10.0.0.2": http.StatusNotFound,
+				"10.0.0.1, 10.0.0.2":              http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute("", test.rule, 0, handler)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			results := make(map[string]int)
+			for xff := range test.expected {
+				w := httptest.NewRecorder()
+
+				req := httptest.NewRequest(http.MethodGet, "https://example.com", http.NoBody)
+				req.RemoteAddr = "192.168.1.100:1234"
+				if xff != "" {
+					req.Header.Set("X-Forwarded-For", xff)
+				}
+
+				muxer.ServeHTTP(w, req)
+				results[xff] = w.Code
+			}
+			assert.Equal(t, test.expected, results)
+		})
+	}
+}
+
+func TestCookieMatcher(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		expected      map[string]int
+		expectedError bool
+	}{
+		{
+			desc:          "invalid Cookie matcher (missing value parameter)",
+			rule:          "Cookie(`session`)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid Cookie matcher (empty value parameter)",
+			rule:          "Cookie(`session`, ``)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid CookieRegexp matcher (invalid regexp)",
+			rule:          "CookieRegexp(`session`, `(`)",
+			expectedError: true,
+		},
+		{
+			desc: "valid Cookie matcher",
+			rule: "Cookie(`canary`, `true`)",
+			expected: map[string]int{
+				"":                         http.StatusNotFound,
+				"canary=true":              http.StatusOK,
+				"session=foo; canary=true": http.StatusOK,
+				"canary=false":             http.StatusNotFound,
+				"Canary=true":              http.StatusNotFound,
+				"other=true":               http.StatusNotFound,
+			},
+		},
+		{
+			desc: "valid CookieRegexp matcher",
+			rule: "CookieRegexp(`canary`, `^(true|yes)$`)",
+			expected: map[string]int{
+				"":                        http.StatusNotFound,
+				"canary=true":             http.StatusOK,
+				"session=foo; canary=yes": http.StatusOK,
+				"canary=false":            http.StatusNotFound,
+				"other=true":              http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute("", test.rule, 0, handler)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			results := make(map[string]int)
+			for cookies := range test.expected {
+				w := httptest.NewRecorder()
+
+				req := httptest.NewRequest(http.MethodGet, "https://example.com", http.NoBody)
+				if cookies != "" {
+					req.Header.Set("Cookie", cookies)
+				}
+
+				muxer.ServeHTTP(w, req)
+				results[cookies] = w.Code
+			}
+			assert.Equal(t, test.expected, results)
+		})
+	}
+}
+
 func TestMethodMatcher(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -147,7 +474,7 @@ func TestMethodMatcher(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 				return
@@ -169,6 +496,164 @@ func TestMethodMatcher(t *testing.T) {
 	}
 }
 
+func TestProtoMatcher(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		expected      map[string]int
+		expectedError bool
+	}{
+		{
+			desc:          "invalid Proto matcher",
+			rule:          "Proto(`SPDY/3`)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid Proto matcher (too many parameters)",
+			rule:          "Proto(`HTTP/1.1`, `HTTP/2`)",
+			expectedError: true,
+		},
+		{
+			desc: "valid Proto matcher for HTTP/1",
+			rule: "Proto(`HTTP/1`)",
+			expected: map[string]int{
+				"HTTP/1.0": http.StatusOK,
+				"HTTP/1.1": http.StatusOK,
+				"HTTP/2.0": http.StatusNotFound,
+				"HTTP/3.0": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "valid Proto matcher for HTTP/1.1",
+			rule: "Proto(`http/1.1`)",
+			expected: map[string]int{
+				"HTTP/1.0": http.StatusNotFound,
+				"HTTP/1.1": http.StatusOK,
+				"HTTP/2.0": http.StatusNotFound,
+			},
+		},
+		{
+			desc: "valid Proto matcher for HTTP/2",
+			rule: "Proto(`HTTP/2`)",
+			expected: map[string]int{
+				"HTTP/1.1": http.StatusNotFound,
+				"HTTP/2.0": http.StatusOK,
+				"HTTP/3.0": http.StatusOK,
+			},
+		},
+		{
+			desc: "valid Proto matcher for HTTP/3",
+			rule: "Proto(`HTTP/3`)",
+			expected: map[string]int{
+				"HTTP/1.1": http.StatusNotFound,
+				"HTTP/2.0": http.StatusNotFound,
+				"HTTP/3.0": http.StatusOK,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute("", test.rule, 0, handler)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			results := make(map[string]int)
+			for proto := range test.expected {
+				w := httptest.NewRecorder()
+
+				req := httptest.NewRequest(http.MethodGet, "https://example.com", http.NoBody)
+
+				var ok bool
+				req.Proto = proto
+				req.ProtoMajor, req.ProtoMinor, ok = http.ParseHTTPVersion(proto)
+				require.True(t, ok)
+
+				muxer.ServeHTTP(w, req)
+				results[proto] = w.Code
+			}
+			assert.Equal(t, test.expected, results)
+		})
+	}
+}
+
+func TestSNIMatcher(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		expected      map[string]int
+		expectedError bool
+	}{
+		{
+			desc:          "invalid SNI matcher (non-ASCII)",
+			rule:          "SNI(`éxample.com`)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid SNI matcher (too many parameters)",
+			rule:          "SNI(`example.com`, `example.org`)",
+			expectedError: true,
+		},
+		{
+			desc: "valid SNI matcher",
+			rule: "SNI(`Example.com.`)",
+			expected: map[string]int{
+				"":             http.StatusNotFound,
+				"example.com":  http.StatusOK,
+				"EXAMPLE.com.": http.StatusOK,
+				"example.org":  http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute("", test.rule, 0, handler)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			results := make(map[string]int)
+			for serverName := range test.expected {
+				w := httptest.NewRecorder()
+
+				// The host of the request is always example.org, so that only the SNI differs.
+				req := httptest.NewRequest(http.MethodGet, "https://example.org", http.NoBody)
+				if serverName == "" {
+					req.TLS = nil
+				} else {
+					req.TLS.ServerName = serverName
+				}
+
+				muxer.ServeHTTP(w, req)
+				results[serverName] = w.Code
+			}
+			assert.Equal(t, test.expected, results)
+		})
+	}
+}
+
 func TestHostMatcher(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -265,7 +750,7 @@ func TestHostMatcher(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 				return
@@ -365,7 +850,7 @@ func TestHostRegexpMatcher(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 				return
@@ -439,7 +924,7 @@ func TestPathMatcher(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 				return
@@ -532,7 +1017,7 @@ func TestPathRegexpMatcher(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 				return
@@ -604,7 +1089,7 @@ func TestPathPrefixMatcher(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 				return
@@ -692,7 +1177,7 @@ func TestHeaderMatcher(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 				return
@@ -800,7 +1285,7 @@ func TestHeaderRegexpMatcher(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 				return
@@ -821,6 +1306,66 @@ func TestHeaderRegexpMatcher(t *testing.T) {
 	}
 }
 
+func TestHeaderExistsMatcher(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		expected      map[*http.Header]int
+		expectedError bool
+	}{
+		{
+			desc:          "invalid HeaderExists matcher (no parameter)",
+			rule:          "HeaderExists()",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid HeaderExists matcher (too many parameters)",
+			rule:          "HeaderExists(`X-Forwarded-Host`, `example.com`)",
+			expectedError: true,
+		},
+		{
+			desc: "valid HeaderExists matcher",
+			rule: "HeaderExists(`x-canary`)",
+			expected: map[*http.Header]int{
+				{"X-Canary": []string{"true"}}:        http.StatusOK,
+				{"X-Canary": []string{""}}:            http.StatusOK,
+				{"X-Canary": []string{}}:              http.StatusNotFound,
+				{"X-Forwarded-Host": []string{"foo"}}: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute("", test.rule, 0, handler)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			for headers := range test.expected {
+				w := httptest.NewRecorder()
+
+				req := httptest.NewRequest(http.MethodGet, "https://example.com", http.NoBody)
+				req.Header = *headers
+
+				muxer.ServeHTTP(w, req)
+				assert.Equal(t, test.expected[headers], w.Code, headers)
+			}
+		})
+	}
+}
+
 func TestQueryMatcher(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -889,7 +1434,7 @@ func TestQueryMatcher(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 				return
@@ -1003,7 +1548,7 @@ func TestQueryRegexpMatcher(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 				return
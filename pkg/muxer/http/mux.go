@@ -11,9 +11,15 @@ import (
 )
 
 // Muxer handles routing with rules.
+// The routes whose rules only depend on the request host and path are indexed,
+// the others are evaluated in priority order.
 type Muxer struct {
 	routes routes
-	parser predicate.Parser
+	// index holds the routes whose rules can be indexed by host and path.
+	index routeIndex
+	// fallback holds, in priority order, the routes whose rules cannot be indexed.
+	fallback routes
+	parser   predicate.Parser
 }
 
 // NewMuxer returns a new muxer instance.
@@ -36,14 +42,34 @@ func NewMuxer() (*Muxer, error) {
 // ServeHTTP forwards the connection to the matching HTTP handler.
 // Serves 404 if no handler is found.
 func (m *Muxer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	for _, route := range m.routes {
+	if route := m.match(req); route != nil {
+		route.handler.ServeHTTP(rw, req)
+		return
+	}
+
+	http.NotFoundHandler().ServeHTTP(rw, req)
+}
+
+// match returns the route with the highest priority matching the request, or nil.
+func (m *Muxer) match(req *http.Request) *route {
+	var matched *route
+	m.index.lookup(req, func(candidate *route) {
+		if (matched == nil || candidate.before(matched)) && candidate.matchers.match(req) {
+			matched = candidate
+		}
+	})
+
+	for _, route := range m.fallback {
+		if matched != nil && !route.before(matched) {
+			break
+		}
+
 		if route.matchers.match(req) {
-			route.handler.ServeHTTP(rw, req)
-			return
+			return route
 		}
 	}
 
-	http.NotFoundHandler().ServeHTTP(rw, req)
+	return matched
 }
 
 // GetRulePriority computes the priority for a given rule.
@@ -52,8 +78,31 @@ func GetRulePriority(rule string) int {
 	return len(rule)
 }
 
-// AddRoute add a new route to the router.
-func (m *Muxer) AddRoute(rule string, priority int, handler http.Handler) error {
+// Explain explains, in priority order, whether the routes match the given request,
+// and returns the name of the route handling it, if any.
+// It has no side effect: the handlers are not called.
+func (m *Muxer) Explain(req *http.Request) (string, []RouteExplanation) {
+	var name string
+	if route := m.match(req); route != nil {
+		name = route.name
+	}
+
+	explanations := make([]RouteExplanation, 0, len(m.routes))
+	for _, route := range m.routes {
+		explanations = append(explanations, RouteExplanation{
+			Name:     route.name,
+			Rule:     route.rule,
+			Priority: route.priority,
+			Matched:  route.matchers.match(req),
+			Matchers: route.matchers.explain(req, nil),
+		})
+	}
+
+	return name, explanations
+}
+
+// AddRoute add a new route, identified by the given name, to the router.
+func (m *Muxer) AddRoute(name, rule string, priority int, handler http.Handler) error {
 	parse, err := m.parser.Parse(rule)
 	if err != nil {
 		return fmt.Errorf("error while parsing rule %s: %w", rule, err)
@@ -64,19 +113,34 @@ func (m *Muxer) AddRoute(rule string, priority int, handler http.Handler) error
 		return fmt.Errorf("error while parsing rule %s", rule)
 	}
 
+	ruleTree := buildTree()
+
 	var matchers matchersTree
-	err = matchers.addRule(buildTree())
+	err = matchers.addRule(ruleTree)
 	if err != nil {
 		return fmt.Errorf("error while adding rule %s: %w", rule, err)
 	}
 
-	m.routes = append(m.routes, &route{
+	r := &route{
+		name:     name,
+		rule:     rule,
 		handler:  handler,
 		matchers: matchers,
 		priority: priority,
-	})
+		order:    len(m.routes),
+	}
 
-	sort.Sort(m.routes)
+	m.routes = m.routes.insert(r)
+
+	keys, ok := indexKeys(ruleTree)
+	if !ok {
+		m.fallback = m.fallback.insert(r)
+		return nil
+	}
+
+	for _, key := range keys {
+		m.index.add(key, r)
+	}
 
 	return nil
 }
@@ -106,21 +170,42 @@ func ParseDomains(rule string) ([]string, error) {
 	return buildTree().ParseMatchers([]string{"Host"}), nil
 }
 
-// routes implements sort.Interface.
+// routes is a list of routes sorted by priority.
 type routes []*route
 
-// Len implements sort.Interface.
-func (r routes) Len() int { return len(r) }
+// insert inserts the given route after the routes which come before it.
+func (r routes) insert(rt *route) routes {
+	i := sort.Search(len(r), func(i int) bool { return rt.before(r[i]) })
 
-// Swap implements sort.Interface.
-func (r routes) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+	r = append(r, nil)
+	copy(r[i+1:], r[i:])
+	r[i] = rt
 
-// Less implements sort.Interface.
-func (r routes) Less(i, j int) bool { return r[i].priority > r[j].priority }
+	return r
+}
+
+// RouteExplanation explains whether a route matches a request.
+type RouteExplanation struct {
+	Name     string               `json:"name"`
+	Rule     string               `json:"rule"`
+	Priority int                  `json:"priority"`
+	Matched  bool                 `json:"matched"`
+	Matchers []MatcherExplanation `json:"matchers,omitempty"`
+}
+
+// MatcherExplanation explains whether a matcher of a rule matches a request.
+type MatcherExplanation struct {
+	Matcher string `json:"matcher"`
+	Matched bool   `json:"matched"`
+}
 
 // route holds the matchers to match HTTP route,
 // and the handler that will serve the request.
 type route struct {
+	// name of the route, usually the name of the router.
+	name string
+	// rule of the route, from which the matchers are built.
+	rule string
 	// matchers tree structure reflecting the rule.
 	matchers matchersTree
 	// handler responsible for handling the route.
@@ -128,6 +213,18 @@ type route struct {
 	// priority is used to disambiguate between two (or more) rules that would all match for a given request.
 	// Computed from the matching rule length, if not user-set.
 	priority int
+	// order is the order in which the route has been added,
+	// used to disambiguate between routes with the same priority.
+	order int
+}
+
+// before returns whether the route has precedence over the given one.
+func (r *route) before(other *route) bool {
+	if r.priority != other.priority {
+		return r.priority > other.priority
+	}
+
+	return r.order < other.order
 }
 
 // matchersTree represents the matchers tree structure.
@@ -136,6 +233,8 @@ type matchersTree struct {
 	// If matcher is not nil, it means that this matcherTree is a leaf of the tree.
 	// It is therefore mutually exclusive with left and right.
 	matcher func(*http.Request) bool
+	// expression is the matcher expression, as written in the rule, of a leaf.
+	expression string
 	// operator to combine the evaluation of left and right leaves.
 	operator string
 	// Mutually exclusive with matcher.
@@ -166,6 +265,24 @@ func (m *matchersTree) match(req *http.Request) bool {
 	}
 }
 
+// explain appends, to the given explanations, whether each matcher of the tree matches the request.
+// All the matchers are evaluated, regardless of the operators.
+func (m *matchersTree) explain(req *http.Request, explanations []MatcherExplanation) []MatcherExplanation {
+	if m == nil {
+		return explanations
+	}
+
+	if m.matcher != nil {
+		return append(explanations, MatcherExplanation{
+			Matcher: m.expression,
+			Matched: m.matcher(req),
+		})
+	}
+
+	explanations = m.left.explain(req, explanations)
+	return m.right.explain(req, explanations)
+}
+
 func (m *matchersTree) addRule(rule *rules.Tree) error {
 	switch rule.Matcher {
 	case "and", "or":
@@ -189,6 +306,8 @@ func (m *matchersTree) addRule(rule *rules.Tree) error {
 			return fmt.Errorf("error while adding rule %s: %w", rule.Matcher, err)
 		}
 
+		m.expression = rule.String()
+
 		if rule.Not {
 			matcherFunc := m.matcher
 			m.matcher = func(req *http.Request) bool {
@@ -231,7 +231,7 @@ func TestMuxer(t *testing.T) {
 			require.NoError(t, err)
 
 			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			if test.expectedError {
 				require.Error(t, err)
 				return
@@ -394,7 +394,7 @@ func Test_addRoutePriority(t *testing.T) {
 					route.priority = GetRulePriority(route.rule)
 				}
 
-				err := muxer.AddRoute(route.rule, route.priority, handler)
+				err := muxer.AddRoute("", route.rule, route.priority, handler)
 				require.NoError(t, err, route.rule)
 			}
 
@@ -408,6 +408,63 @@ func Test_addRoutePriority(t *testing.T) {
 	}
 }
 
+func TestMuxer_Explain(t *testing.T) {
+	muxer, err := NewMuxer()
+	require.NoError(t, err)
+
+	handler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("the handler must not be called")
+	})
+
+	err = muxer.AddRoute("api", "Host(`example.com`) && PathPrefix(`/api`) && !Method(`POST`)", 100, handler)
+	require.NoError(t, err)
+
+	err = muxer.AddRoute("web", "Host(`example.com`) || Header(`X-Web`, `true`)", 10, handler)
+	require.NoError(t, err)
+
+	err = muxer.AddRoute("other", "Host(`example.org`)", 10, handler)
+	require.NoError(t, err)
+
+	req := testhelpers.MustNewRequest(http.MethodPost, "http://example.com/api/foo", http.NoBody)
+
+	// RequestDecorator is necessary for the host rule
+	requestdecorator.New(nil).ServeHTTP(httptest.NewRecorder(), req, func(_ http.ResponseWriter, req *http.Request) {
+		router, routes := muxer.Explain(req)
+
+		assert.Equal(t, "web", router)
+		assert.Equal(t, []RouteExplanation{
+			{
+				Name:     "api",
+				Rule:     "Host(`example.com`) && PathPrefix(`/api`) && !Method(`POST`)",
+				Priority: 100,
+				Matchers: []MatcherExplanation{
+					{Matcher: "Host(`example.com`)", Matched: true},
+					{Matcher: "PathPrefix(`/api`)", Matched: true},
+					{Matcher: "!Method(`POST`)"},
+				},
+			},
+			{
+				Name:     "web",
+				Rule:     "Host(`example.com`) || Header(`X-Web`, `true`)",
+				Priority: 10,
+				Matched:  true,
+				Matchers: []MatcherExplanation{
+					{Matcher: "Host(`example.com`)", Matched: true},
+					{Matcher: "Header(`X-Web`, `true`)"},
+				},
+			},
+			{
+				Name:     "other",
+				Rule:     "Host(`example.org`)",
+				Priority: 10,
+				Matchers: []MatcherExplanation{
+					{Matcher: "Host(`example.org`)"},
+				},
+			},
+		}, routes)
+	})
+}
+
 func TestParseDomains(t *testing.T) {
 	testCases := []struct {
 		description   string
@@ -519,7 +576,7 @@ func TestEmptyHost(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, handler)
+			err = muxer.AddRoute("", test.rule, 0, handler)
 			require.NoError(t, err)
 
 			// RequestDecorator is necessary for the host rule
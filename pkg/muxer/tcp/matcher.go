@@ -1,21 +1,30 @@
 package tcp
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"regexp"
+	"regexp/syntax"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/ip"
+	"github.com/traefik/traefik/v3/pkg/proxyprotocol"
 )
 
 var tcpFuncs = map[string]func(*matchersTree, ...string) error{
-	"ALPN":          expect1Parameter(alpn),
-	"ClientIP":      expect1Parameter(clientIP),
-	"HostSNI":       expect1Parameter(hostSNI),
-	"HostSNIRegexp": expect1Parameter(hostSNIRegexp),
+	"ALPN":                   expect1Parameter(alpn),
+	"ClientHelloFingerprint": expect1Parameter(clientHelloFingerprint),
+	"ClientIP":               expect1Parameter(clientIP),
+	"HostSNI":                expect1Parameter(hostSNI),
+	"HostSNIRegexp":          expect1Parameter(hostSNIRegexp),
+	"PeekRegexp":             peekRegexp,
+	"Protocol":               expect1Parameter(protocol),
+	"ProxyProtocolTLV":       proxyProtocolTLV,
 }
 
 func expect1Parameter(fn func(*matchersTree, ...string) error) func(*matchersTree, ...string) error {
@@ -49,6 +58,21 @@ func alpn(tree *matchersTree, protos ...string) error {
 	return nil
 }
 
+// clientHelloFingerprint checks if the JA3 or JA4 fingerprint of the connection ClientHello matches the matcher fingerprint.
+func clientHelloFingerprint(tree *matchersTree, fingerprints ...string) error {
+	fp := fingerprints[0]
+
+	if fp == "" {
+		return errors.New("empty value for ClientHelloFingerprint matcher is not allowed")
+	}
+
+	tree.matcher = func(meta ConnData) bool {
+		return meta.fingerprints.Match(fp)
+	}
+
+	return nil
+}
+
 func clientIP(tree *matchersTree, clientIP ...string) error {
 	checker, err := ip.NewChecker(clientIP)
 	if err != nil {
@@ -122,6 +146,110 @@ func hostSNIRegexp(tree *matchersTree, templates ...string) error {
 	return nil
 }
 
+// protocol checks if the first bytes sent by the client of the connection belong to the matcher protocol.
+func protocol(tree *matchersTree, protocols ...string) error {
+	name := strings.ToLower(protocols[0])
+
+	detect, ok := protocolDetectors[name]
+	if !ok {
+		return fmt.Errorf("invalid value for Protocol matcher, %q is not a supported protocol", protocols[0])
+	}
+
+	tree.matcher = func(meta ConnData) bool {
+		return meta.peeker.detect(detect, defaultPeekTimeout)
+	}
+
+	return nil
+}
+
+// proxyProtocolTLV checks if the value of the TLV of the matcher type,
+// in the PROXY protocol header received with the connection, is the matcher value.
+func proxyProtocolTLV(tree *matchersTree, values ...string) error {
+	if len(values) != 2 {
+		return fmt.Errorf("unexpected number of parameters; got %d, expected 2", len(values))
+	}
+
+	tlvType, err := proxyprotocol.ParseTLVType(values[0])
+	if err != nil {
+		return fmt.Errorf("parsing ProxyProtocolTLV matcher: %w", err)
+	}
+
+	value := values[1]
+
+	tree.matcher = func(meta ConnData) bool {
+		tlvValue, ok := proxyprotocol.TLVValue(meta.proxyHeader, tlvType)
+		return ok && tlvValue == value
+	}
+
+	return nil
+}
+
+// peekRegexp checks if the first bytes sent by the client of the connection match the matcher regexp,
+// waiting for them at most until the optional matcher timeout.
+func peekRegexp(tree *matchersTree, values ...string) error {
+	if len(values) < 1 || len(values) > 2 {
+		return fmt.Errorf("unexpected number of parameters; got %d, expected 1 or 2", len(values))
+	}
+
+	re, err := regexp.Compile(values[0])
+	if err != nil {
+		return fmt.Errorf("compiling PeekRegexp matcher: %w", err)
+	}
+
+	timeout := defaultPeekTimeout
+	if len(values) == 2 {
+		timeout, err = time.ParseDuration(values[1])
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid timeout for PeekRegexp matcher, %q is not a positive duration", values[1])
+		}
+	}
+
+	prefix := anchoredPrefix(values[0])
+
+	detect := func(b []byte) (bool, bool) {
+		// No more bytes are needed to know that the first bytes do not start with the prefix the regexp is anchored to.
+		if !bytes.HasPrefix(b, prefix) && !bytes.HasPrefix(prefix, b) {
+			return false, false
+		}
+
+		if re.Match(b) {
+			return true, false
+		}
+
+		return false, true
+	}
+
+	tree.matcher = func(meta ConnData) bool {
+		return meta.peeker.detect(detect, timeout)
+	}
+
+	return nil
+}
+
+// anchoredPrefix returns the literal prefix of the given regexp, if it is anchored to the beginning of the text.
+func anchoredPrefix(expr string) []byte {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil
+	}
+
+	re = re.Simplify()
+	if re.Op != syntax.OpConcat || len(re.Sub) == 0 || re.Sub[0].Op != syntax.OpBeginText {
+		return nil
+	}
+
+	var prefix []byte
+	for _, sub := range re.Sub[1:] {
+		if sub.Op != syntax.OpLiteral || sub.Flags&syntax.FoldCase != 0 {
+			break
+		}
+
+		prefix = append(prefix, string(sub.Rune)...)
+	}
+
+	return prefix
+}
+
 // isASCII checks if the given string contains only ASCII characters.
 func isASCII(s string) bool {
 	for i := 0; i < len(s); i++ {
@@ -1,11 +1,15 @@
 package tcp
 
 import (
+	"bufio"
+	"strings"
 	"testing"
 
+	"github.com/pires/go-proxyproto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/tcp"
+	"github.com/traefik/traefik/v3/pkg/tls/fingerprint"
 )
 
 func Test_HostSNICatchAll(t *testing.T) {
@@ -38,7 +42,7 @@ func Test_HostSNICatchAll(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			err = muxer.AddRoute("", test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
 			require.NoError(t, err)
 
 			handler, catchAll := muxer.Match(ConnData{
@@ -144,7 +148,7 @@ func Test_HostSNI(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			err = muxer.AddRoute("", test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
 			if test.buildErr {
 				require.Error(t, err)
 				return
@@ -227,7 +231,7 @@ func Test_HostSNIRegexp(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			err = muxer.AddRoute("", test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
 			if test.buildErr {
 				require.Error(t, err)
 				return
@@ -299,7 +303,7 @@ func Test_ClientIP(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			err = muxer.AddRoute("", test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
 			if test.buildErr {
 				require.Error(t, err)
 				return
@@ -363,7 +367,7 @@ func Test_ALPN(t *testing.T) {
 			muxer, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = muxer.AddRoute(test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			err = muxer.AddRoute("", test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
 			if test.buildErr {
 				require.Error(t, err)
 				return
@@ -381,3 +385,364 @@ func Test_ALPN(t *testing.T) {
 		})
 	}
 }
+
+func Test_ClientHelloFingerprint(t *testing.T) {
+	fingerprints := &fingerprint.Fingerprints{
+		JA3: "ada70206e40642a3e4461f35503241d5",
+		JA4: "t13d1516h2_8daaf6152771_e5627efa2ab1",
+	}
+
+	testCases := []struct {
+		desc     string
+		rule     string
+		expected map[*fingerprint.Fingerprints]bool
+		buildErr bool
+	}{
+		{
+			desc:     "Invalid ClientHelloFingerprint matcher (empty parameters)",
+			rule:     "ClientHelloFingerprint(``)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid ClientHelloFingerprint matcher (too many parameters)",
+			rule:     "ClientHelloFingerprint(`ada70206e40642a3e4461f35503241d5`, `t13d1516h2_8daaf6152771_e5627efa2ab1`)",
+			buildErr: true,
+		},
+		{
+			desc: "Valid ClientHelloFingerprint matcher with JA3",
+			rule: "ClientHelloFingerprint(`ADA70206E40642A3E4461F35503241D5`)",
+			expected: map[*fingerprint.Fingerprints]bool{
+				fingerprints:             true,
+				{JA3: "foo", JA4: "bar"}: false,
+				nil:                      false,
+			},
+		},
+		{
+			desc: "Valid ClientHelloFingerprint matcher with JA4",
+			rule: "ClientHelloFingerprint(`t13d1516h2_8daaf6152771_e5627efa2ab1`)",
+			expected: map[*fingerprint.Fingerprints]bool{
+				fingerprints:             true,
+				{JA3: "foo", JA4: "bar"}: false,
+				nil:                      false,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute("", test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for fp, match := range test.expected {
+				meta := ConnData{
+					fingerprints: fp,
+				}
+
+				handler, _ := muxer.Match(meta)
+				assert.Equal(t, match, handler != nil, fp)
+			}
+		})
+	}
+}
+
+func Test_Protocol(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		expected map[string]bool
+		buildErr bool
+	}{
+		{
+			desc:     "Invalid Protocol matcher (unknown protocol)",
+			rule:     "Protocol(`ftp`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid Protocol matcher (too many parameters)",
+			rule:     "Protocol(`ssh`, `tls`)",
+			buildErr: true,
+		},
+		{
+			desc: "Valid Protocol matcher with SSH",
+			rule: "Protocol(`SSH`)",
+			expected: map[string]bool{
+				"SSH-2.0-OpenSSH_9.6\r\n": true,
+				"SSH":                     false,
+				"GET / HTTP/1.1\r\n":      false,
+				"":                        false,
+			},
+		},
+		{
+			desc: "Valid Protocol matcher with HTTP/1",
+			rule: "Protocol(`http/1`)",
+			expected: map[string]bool{
+				"GET / HTTP/1.1\r\n":                       true,
+				"OPTIONS * HTTP/1.1\r\n":                   true,
+				"CONNECT example.com:443 HTTP/1.1\r\n":     true,
+				"PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n":         false,
+				"GETTING / HTTP/1.1\r\n":                   false,
+				"get / HTTP/1.1\r\n":                       false,
+				"SSH-2.0-OpenSSH_9.6\r\n":                  false,
+				"POST":                                     false,
+				"\x16\x03\x01\x00\x05\x01\x00\x00\x01\x00": false,
+			},
+		},
+		{
+			desc: "Valid Protocol matcher with HTTP/2",
+			rule: "Protocol(`http/2`)",
+			expected: map[string]bool{
+				"PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n\x00\x00": true,
+				"PRI * HTTP/2.0\r\n":                       false,
+				"GET / HTTP/1.1\r\n":                       false,
+			},
+		},
+		{
+			desc: "Valid Protocol matcher with TLS",
+			rule: "Protocol(`tls`)",
+			expected: map[string]bool{
+				"\x16\x03\x01\x00\x05\x01\x00\x00\x01\x00": true,
+				"\x16\x03\x09\x00\x05\x01\x00\x00\x01\x00": false,
+				"\x16\x03":           false,
+				"GET / HTTP/1.1\r\n": false,
+			},
+		},
+		{
+			desc: "Valid Protocol matcher with Postgres",
+			rule: "Protocol(`postgres`)",
+			expected: map[string]bool{
+				"\x00\x00\x00\x29\x00\x03\x00\x00user\x00postgres\x00": true,
+				"\x00\x00\x00\x08\x04\xd2\x16\x2f":                     true,
+				"\x00\x00\x00\x10\x04\xd2\x16\x2e\x00\x00\x00\x01":     true,
+				"\x00\x00\x00\x09\x04\xd2\x16\x2f":                     false,
+				"\x00\x00\x00\x29\x00\x02\x00\x00":                     false,
+				"GET / HTTP/1.1\r\n":                                   false,
+			},
+		},
+		{
+			desc: "Valid Protocol matcher with MQTT",
+			rule: "Protocol(`mqtt`)",
+			expected: map[string]bool{
+				"\x10\x10\x00\x04MQTT\x04\x02\x00\x3c":         true,
+				"\x10\x80\x01\x00\x04MQTT\x05\x02\x00\x3c":     true,
+				"\x10\x12\x00\x06MQIsdp\x03\x02\x00\x3c":       true,
+				"\x10\x10\x00\x04MQTX\x04\x02\x00\x3c":         false,
+				"\x20\x10\x00\x04MQTT\x04\x02\x00\x3c":         false,
+				"\x10\x80\x80\x80\x80\x01\x00\x04MQTT\x04\x02": false,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute("", test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for data, match := range test.expected {
+				meta := ConnData{
+					peeker: NewPeeker(nil, bufio.NewReader(strings.NewReader(data))),
+				}
+
+				handler, _ := muxer.Match(meta)
+				assert.Equal(t, match, handler != nil, data)
+			}
+
+			// Without peeker, the first bytes of the connection are unknown.
+			handler, _ := muxer.Match(ConnData{})
+			assert.Nil(t, handler)
+		})
+	}
+}
+
+func Test_PeekRegexp(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		expected map[string]bool
+		buildErr bool
+	}{
+		{
+			desc:     "Invalid PeekRegexp matcher (invalid regexp)",
+			rule:     "PeekRegexp(`^(`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid PeekRegexp matcher (invalid timeout)",
+			rule:     "PeekRegexp(`^SSH-`, `foo`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid PeekRegexp matcher (negative timeout)",
+			rule:     "PeekRegexp(`^SSH-`, `-1s`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid PeekRegexp matcher (too many parameters)",
+			rule:     "PeekRegexp(`^SSH-`, `1s`, `2s`)",
+			buildErr: true,
+		},
+		{
+			desc: "Valid PeekRegexp matcher",
+			rule: "PeekRegexp(`^SSH-2\\.0-`)",
+			expected: map[string]bool{
+				"SSH-2.0-OpenSSH_9.6\r\n": true,
+				"SSH-1.99-foo\r\n":        false,
+				"":                        false,
+			},
+		},
+		{
+			desc: "Valid PeekRegexp matcher with timeout",
+			rule: "PeekRegexp(`^\\x00\\x00\\x00\\x01`, `500ms`)",
+			expected: map[string]bool{
+				"\x00\x00\x00\x01\x02": true,
+				"\x00\x00\x00\x02\x01": false,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute("", test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for data, match := range test.expected {
+				meta := ConnData{
+					peeker: NewPeeker(nil, bufio.NewReader(strings.NewReader(data))),
+				}
+
+				handler, _ := muxer.Match(meta)
+				assert.Equal(t, match, handler != nil, data)
+			}
+		})
+	}
+}
+
+func Test_ProxyProtocolTLV(t *testing.T) {
+	vpceTLV := proxyproto.TLV{Type: 0xEA, Value: append([]byte{0x01}, "vpce-08d2bf15fac5001c9"...)}
+	customTLV := proxyproto.TLV{Type: 0xE5, Value: []byte("foo")}
+
+	testCases := []struct {
+		desc     string
+		rule     string
+		tlvs     []proxyproto.TLV
+		noHeader bool
+		expected bool
+		buildErr bool
+	}{
+		{
+			desc:     "Invalid ProxyProtocolTLV matcher (invalid type)",
+			rule:     "ProxyProtocolTLV(`foo`, `bar`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid ProxyProtocolTLV matcher (type out of range)",
+			rule:     "ProxyProtocolTLV(`256`, `bar`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid ProxyProtocolTLV matcher (missing value)",
+			rule:     "ProxyProtocolTLV(`0xEA`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Matching AWS VPC endpoint ID",
+			rule:     "ProxyProtocolTLV(`0xEA`, `vpce-08d2bf15fac5001c9`)",
+			tlvs:     []proxyproto.TLV{customTLV, vpceTLV},
+			expected: true,
+		},
+		{
+			desc:     "Not matching AWS VPC endpoint ID",
+			rule:     "ProxyProtocolTLV(`0xEA`, `vpce-foo`)",
+			tlvs:     []proxyproto.TLV{vpceTLV},
+			expected: false,
+		},
+		{
+			desc:     "Matching custom TLV with a decimal type",
+			rule:     "ProxyProtocolTLV(`229`, `foo`)",
+			tlvs:     []proxyproto.TLV{customTLV},
+			expected: true,
+		},
+		{
+			desc:     "Missing TLV",
+			rule:     "ProxyProtocolTLV(`0xE6`, `foo`)",
+			tlvs:     []proxyproto.TLV{customTLV},
+			expected: false,
+		},
+		{
+			desc:     "Missing PROXY protocol header",
+			rule:     "ProxyProtocolTLV(`0xE5`, `foo`)",
+			noHeader: true,
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute("", test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var meta ConnData
+			if !test.noHeader {
+				meta.proxyHeader = &proxyproto.Header{}
+				require.NoError(t, meta.proxyHeader.SetTLVs(test.tlvs))
+			}
+
+			handler, _ := muxer.Match(meta)
+			assert.Equal(t, test.expected, handler != nil)
+		})
+	}
+}
+
+func Test_anchoredPrefix(t *testing.T) {
+	testCases := map[string]string{
+		"^HELLO [a-z]+": "HELLO ",
+		`^SSH-2\.0-`:    "SSH-2.0-",
+		`\A\x00\x01.*`:  "\x00\x01",
+		"^(?i)hello":    "",
+		"HELLO":         "",
+		"^[A-Z]+":       "",
+	}
+
+	for expr, expected := range testCases {
+		assert.Equal(t, expected, string(anchoredPrefix(expr)), expr)
+	}
+}
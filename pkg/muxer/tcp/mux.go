@@ -6,9 +6,12 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/pires/go-proxyproto"
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/proxyprotocol"
 	"github.com/traefik/traefik/v3/pkg/rules"
 	"github.com/traefik/traefik/v3/pkg/tcp"
+	"github.com/traefik/traefik/v3/pkg/tls/fingerprint"
 	"github.com/traefik/traefik/v3/pkg/types"
 	"github.com/vulcand/predicate"
 )
@@ -18,10 +21,16 @@ type ConnData struct {
 	serverName string
 	remoteIP   string
 	alpnProtos []string
+	// fingerprints are the ClientHello fingerprints, nil when the connection is not TLS.
+	fingerprints *fingerprint.Fingerprints
+	// peeker peeks the first bytes sent by the client, nil when they cannot be peeked.
+	peeker *Peeker
+	// proxyHeader is the PROXY protocol header received with the connection, nil when there is none.
+	proxyHeader *proxyproto.Header
 }
 
 // NewConnData builds a connData struct from the given parameters.
-func NewConnData(serverName string, conn tcp.WriteCloser, alpnProtos []string) (ConnData, error) {
+func NewConnData(serverName string, conn tcp.WriteCloser, alpnProtos []string, fingerprints *fingerprint.Fingerprints, peeker *Peeker) (ConnData, error) {
 	remoteIP, _, err := net.SplitHostPort(conn.RemoteAddr().String())
 	if err != nil {
 		return ConnData{}, fmt.Errorf("error while parsing remote address %q: %w", conn.RemoteAddr().String(), err)
@@ -33,9 +42,12 @@ func NewConnData(serverName string, conn tcp.WriteCloser, alpnProtos []string) (
 	serverName = types.CanonicalDomain(serverName)
 
 	return ConnData{
-		serverName: types.CanonicalDomain(serverName),
-		remoteIP:   remoteIP,
-		alpnProtos: alpnProtos,
+		serverName:   types.CanonicalDomain(serverName),
+		remoteIP:     remoteIP,
+		alpnProtos:   alpnProtos,
+		fingerprints: fingerprints,
+		peeker:       peeker,
+		proxyHeader:  proxyprotocol.HeaderFromConn(conn),
 	}, nil
 }
 
@@ -72,6 +84,34 @@ func (m Muxer) Match(meta ConnData) (tcp.Handler, bool) {
 	return nil, false
 }
 
+// Explain explains, in priority order, whether the routes match the given connection metadata,
+// and returns the name of the route handling the connection, if any.
+// It has no side effect: the handlers are not called.
+func (m Muxer) Explain(meta ConnData) (string, []RouteExplanation) {
+	var name string
+	var matched bool
+
+	explanations := make([]RouteExplanation, 0, len(m.routes))
+	for _, route := range m.routes {
+		explanation := RouteExplanation{
+			Name:     route.name,
+			Rule:     route.rule,
+			Priority: route.priority,
+			Matched:  route.matchers.match(meta),
+			Matchers: route.matchers.explain(meta, nil),
+		}
+
+		if explanation.Matched && !matched {
+			name = route.name
+			matched = true
+		}
+
+		explanations = append(explanations, explanation)
+	}
+
+	return name, explanations
+}
+
 // GetRulePriority computes the priority for a given rule.
 // The priority is calculated using the length of rule.
 // There is a special case where the HostSNI(`*`) has a priority of -1.
@@ -104,9 +144,9 @@ func GetRulePriority(rule string) int {
 	return len(rule)
 }
 
-// AddRoute adds a new route, associated to the given handler, at the given
-// priority, to the muxer.
-func (m *Muxer) AddRoute(rule string, priority int, handler tcp.Handler) error {
+// AddRoute adds a new route, identified by the given name and associated to the given handler,
+// at the given priority, to the muxer.
+func (m *Muxer) AddRoute(name, rule string, priority int, handler tcp.Handler) error {
 	parse, err := m.parser.Parse(rule)
 	if err != nil {
 		return fmt.Errorf("error while parsing rule %s: %w", rule, err)
@@ -131,6 +171,8 @@ func (m *Muxer) AddRoute(rule string, priority int, handler tcp.Handler) error {
 	}
 
 	newRoute := &route{
+		name:     name,
+		rule:     rule,
 		handler:  handler,
 		matchers: matchers,
 		catchAll: catchAll,
@@ -186,9 +228,28 @@ func (r routes) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
 // Less implements sort.Interface.
 func (r routes) Less(i, j int) bool { return r[i].priority > r[j].priority }
 
+// RouteExplanation explains whether a route matches a connection.
+type RouteExplanation struct {
+	Name     string               `json:"name"`
+	Rule     string               `json:"rule"`
+	Priority int                  `json:"priority"`
+	Matched  bool                 `json:"matched"`
+	Matchers []MatcherExplanation `json:"matchers,omitempty"`
+}
+
+// MatcherExplanation explains whether a matcher of a rule matches a connection.
+type MatcherExplanation struct {
+	Matcher string `json:"matcher"`
+	Matched bool   `json:"matched"`
+}
+
 // route holds the matchers to match TCP route,
 // and the handler that will serve the connection.
 type route struct {
+	// name of the route, usually the name of the router.
+	name string
+	// rule of the route, from which the matchers are built.
+	rule string
 	// matchers tree structure reflecting the rule.
 	matchers matchersTree
 	// handler responsible for handling the route.
@@ -207,6 +268,8 @@ type matchersTree struct {
 	// If matcher is not nil, it means that this matcherTree is a leaf of the tree.
 	// It is therefore mutually exclusive with left and right.
 	matcher func(ConnData) bool
+	// expression is the matcher expression, as written in the rule, of a leaf.
+	expression string
 	// operator to combine the evaluation of left and right leaves.
 	operator string
 	// Mutually exclusive with matcher.
@@ -237,6 +300,24 @@ func (m *matchersTree) match(meta ConnData) bool {
 	}
 }
 
+// explain appends, to the given explanations, whether each matcher of the tree matches the connection metadata.
+// All the matchers are evaluated, regardless of the operators.
+func (m *matchersTree) explain(meta ConnData, explanations []MatcherExplanation) []MatcherExplanation {
+	if m == nil {
+		return explanations
+	}
+
+	if m.matcher != nil {
+		return append(explanations, MatcherExplanation{
+			Matcher: m.expression,
+			Matched: m.matcher(meta),
+		})
+	}
+
+	explanations = m.left.explain(meta, explanations)
+	return m.right.explain(meta, explanations)
+}
+
 func (m *matchersTree) addRule(rule *rules.Tree) error {
 	switch rule.Matcher {
 	case "and", "or":
@@ -260,6 +341,8 @@ func (m *matchersTree) addRule(rule *rules.Tree) error {
 			return err
 		}
 
+		m.expression = rule.String()
+
 		if rule.Not {
 			matcherFunc := m.matcher
 			m.matcher = func(meta ConnData) bool {
@@ -277,7 +277,7 @@ func Test_addTCPRoute(t *testing.T) {
 			router, err := NewMuxer()
 			require.NoError(t, err)
 
-			err = router.AddRoute(test.rule, 0, handler)
+			err = router.AddRoute("", test.rule, 0, handler)
 			if test.routeErr {
 				require.Error(t, err)
 				return
@@ -295,7 +295,7 @@ func Test_addTCPRoute(t *testing.T) {
 				remoteAddr: fakeAddr{addr: addr},
 			}
 
-			connData, err := NewConnData(test.serverName, conn, test.protos)
+			connData, err := NewConnData(test.serverName, conn, test.protos, nil, nil)
 			require.NoError(t, err)
 
 			matchingHandler, _ := router.Match(connData)
@@ -447,7 +447,7 @@ func Test_Priority(t *testing.T) {
 			matchedRule := ""
 			for rule, priority := range test.rules {
 				rule := rule
-				err := muxer.AddRoute(rule, priority, tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				err := muxer.AddRoute("", rule, priority, tcp.HandlerFunc(func(conn tcp.WriteCloser) {
 					matchedRule = rule
 				}))
 				require.NoError(t, err)
@@ -464,6 +464,55 @@ func Test_Priority(t *testing.T) {
 	}
 }
 
+func TestMuxer_Explain(t *testing.T) {
+	muxer, err := NewMuxer()
+	require.NoError(t, err)
+
+	handler := tcp.HandlerFunc(func(conn tcp.WriteCloser) {})
+
+	err = muxer.AddRoute("catchAll", "HostSNI(`*`)", -1, handler)
+	require.NoError(t, err)
+
+	err = muxer.AddRoute("foo", "HostSNI(`foo.localhost`) && !ClientIP(`10.0.0.1`)", 20, handler)
+	require.NoError(t, err)
+
+	err = muxer.AddRoute("bar", "HostSNI(`bar.localhost`) || ALPN(`h2`)", 10, handler)
+	require.NoError(t, err)
+
+	router, routes := muxer.Explain(ConnData{serverName: "foo.localhost", remoteIP: "10.0.0.1"})
+
+	assert.Equal(t, "catchAll", router)
+	assert.Equal(t, []RouteExplanation{
+		{
+			Name:     "foo",
+			Rule:     "HostSNI(`foo.localhost`) && !ClientIP(`10.0.0.1`)",
+			Priority: 20,
+			Matchers: []MatcherExplanation{
+				{Matcher: "HostSNI(`foo.localhost`)", Matched: true},
+				{Matcher: "!ClientIP(`10.0.0.1`)"},
+			},
+		},
+		{
+			Name:     "bar",
+			Rule:     "HostSNI(`bar.localhost`) || ALPN(`h2`)",
+			Priority: 10,
+			Matchers: []MatcherExplanation{
+				{Matcher: "HostSNI(`bar.localhost`)"},
+				{Matcher: "ALPN(`h2`)"},
+			},
+		},
+		{
+			Name:     "catchAll",
+			Rule:     "HostSNI(`*`)",
+			Priority: -1,
+			Matched:  true,
+			Matchers: []MatcherExplanation{
+				{Matcher: "HostSNI(`*`)", Matched: true},
+			},
+		},
+	}, routes)
+}
+
 func TestGetRulePriority(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -0,0 +1,249 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultPeekTimeout is the maximum duration, since the start of the peeking, to wait for the bytes needed by the Protocol matcher.
+const defaultPeekTimeout = time.Second
+
+// readDeadliner is the part of a connection the Peeker needs to stop waiting for bytes.
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// Peeker peeks the first bytes sent by the client of a connection, for the matchers detecting its protocol.
+// Peeked bytes remain in the reader, so they can be forwarded once the connection is routed.
+type Peeker struct {
+	reader *bufio.Reader
+	// conn is the connection read by the reader, nil if only the bytes already buffered by the reader can be peeked.
+	conn  readDeadliner
+	start time.Time
+}
+
+// NewPeeker returns a Peeker reading the given reader, which reads the given connection.
+// If conn is nil, only the bytes already buffered by the reader are peeked.
+func NewPeeker(conn readDeadliner, reader *bufio.Reader) *Peeker {
+	return &Peeker{
+		reader: reader,
+		conn:   conn,
+		start:  time.Now(),
+	}
+}
+
+// detector checks whether the given first bytes of a connection belong to a protocol.
+// It returns whether they do, and whether more bytes are needed to decide.
+type detector func(b []byte) (matched, more bool)
+
+// detect runs the given detector on the first bytes of the connection,
+// waiting for more bytes, as long as the detector needs them, at most until the given timeout since the start of the peeking.
+func (p *Peeker) detect(d detector, timeout time.Duration) bool {
+	if p == nil {
+		return false
+	}
+
+	n := p.reader.Buffered()
+	if n == 0 {
+		n = 1
+	}
+
+	for {
+		b := p.peek(n, timeout)
+
+		matched, more := d(b)
+		if !more {
+			return matched
+		}
+
+		// The client sent nothing more before the timeout, or the buffer is full.
+		if len(b) < n || n >= p.reader.Size() {
+			return false
+		}
+
+		n = p.reader.Buffered()
+		if n == len(b) {
+			n++
+		}
+	}
+}
+
+// peek returns the first n bytes of the connection,
+// or less if they are not received until the given timeout since the start of the peeking.
+func (p *Peeker) peek(n int, timeout time.Duration) []byte {
+	if n > p.reader.Size() {
+		n = p.reader.Size()
+	}
+
+	if p.reader.Buffered() >= n || p.conn == nil {
+		b, _ := p.reader.Peek(n)
+		return b
+	}
+
+	deadline := p.start.Add(timeout)
+	if !time.Now().Before(deadline) {
+		b, _ := p.reader.Peek(p.reader.Buffered())
+		return b
+	}
+
+	if err := p.conn.SetReadDeadline(deadline); err != nil {
+		log.Error().Err(err).Msg("Error while setting read deadline")
+	}
+
+	b, _ := p.reader.Peek(n)
+
+	// Remove read deadline and delegate this to the handler of the connection.
+	if err := p.conn.SetReadDeadline(time.Time{}); err != nil {
+		log.Error().Err(err).Msg("Error while setting read deadline")
+	}
+
+	return b
+}
+
+// protocolDetectors are the detectors of the protocols supported by the Protocol matcher.
+var protocolDetectors = map[string]detector{
+	"ssh":      prefixDetector([]byte("SSH-")),
+	"http/1":   detectHTTP1,
+	"http/2":   prefixDetector([]byte("PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n")),
+	"tls":      detectTLS,
+	"postgres": detectPostgres,
+	"mqtt":     detectMQTT,
+}
+
+// prefixDetector returns a detector checking that the first bytes are the given prefix.
+func prefixDetector(prefix []byte) detector {
+	return func(b []byte) (bool, bool) {
+		if len(b) < len(prefix) {
+			return false, bytes.HasPrefix(prefix, b)
+		}
+
+		return bytes.HasPrefix(b, prefix), false
+	}
+}
+
+// http1Methods are the methods which can start an HTTP/1 request.
+var http1Methods = map[string]struct{}{
+	"GET": {}, "HEAD": {}, "POST": {}, "PUT": {}, "DELETE": {}, "CONNECT": {}, "OPTIONS": {}, "TRACE": {}, "PATCH": {},
+}
+
+// detectHTTP1 checks that the first bytes are an HTTP/1 method, followed by a request target.
+func detectHTTP1(b []byte) (bool, bool) {
+	const maxMethodLength = len("OPTIONS")
+
+	i := bytes.IndexByte(b, ' ')
+	if i < 0 {
+		for _, c := range b {
+			if c < 'A' || c > 'Z' {
+				return false, false
+			}
+		}
+		return false, len(b) <= maxMethodLength
+	}
+
+	if _, ok := http1Methods[string(b[:i])]; !ok {
+		return false, false
+	}
+
+	if len(b) == i+1 {
+		return false, true
+	}
+
+	// The request target is in origin, asterisk, absolute or authority form.
+	c := b[i+1]
+	return c == '/' || c == '*' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9'), false
+}
+
+// detectTLS checks that the first bytes are the header of a TLS handshake record.
+func detectTLS(b []byte) (bool, bool) {
+	const recordTypeHandshake = 0x16
+
+	switch {
+	case len(b) > 0 && b[0] != recordTypeHandshake:
+		return false, false
+	case len(b) > 1 && b[1] != 0x03:
+		return false, false
+	case len(b) > 2:
+		// From SSL 3.0 to TLS 1.3.
+		return b[2] <= 0x04, false
+	default:
+		return false, true
+	}
+}
+
+// Postgres request codes.
+const (
+	postgresProtocolV3     = 196608
+	postgresCancelRequest  = 80877102
+	postgresSSLRequest     = 80877103
+	postgresGSSENCRequest  = 80877104
+	postgresMaxStartupSize = 10000
+)
+
+// detectPostgres checks that the first bytes are the header of a Postgres startup, cancel, SSL or GSSAPI encryption request.
+func detectPostgres(b []byte) (bool, bool) {
+	// The length of the message never uses the most significant byte.
+	if len(b) > 0 && b[0] != 0 {
+		return false, false
+	}
+
+	if len(b) < 8 {
+		return false, true
+	}
+
+	length := binary.BigEndian.Uint32(b)
+
+	switch binary.BigEndian.Uint32(b[4:]) {
+	case postgresProtocolV3:
+		return length > 8 && length <= postgresMaxStartupSize, false
+	case postgresSSLRequest, postgresGSSENCRequest:
+		return length == 8, false
+	case postgresCancelRequest:
+		return length == 16, false
+	default:
+		return false, false
+	}
+}
+
+// detectMQTT checks that the first bytes are an MQTT CONNECT packet header, for MQTT 3.1, 3.1.1 or 5.
+func detectMQTT(b []byte) (bool, bool) {
+	const packetTypeConnect = 0x10
+
+	if len(b) == 0 {
+		return false, true
+	}
+
+	if b[0] != packetTypeConnect {
+		return false, false
+	}
+
+	// The remaining length is a variable length integer of at most four bytes.
+	i := 1
+	for ; ; i++ {
+		if len(b) <= i {
+			return false, true
+		}
+
+		if b[i]&0x80 == 0 {
+			break
+		}
+
+		if i == 4 {
+			return false, false
+		}
+	}
+
+	variableHeader := b[i+1:]
+
+	for _, name := range []string{"\x00\x04MQTT", "\x00\x06MQIsdp"} {
+		matched, more := prefixDetector([]byte(name))(variableHeader)
+		if matched || more {
+			return matched, more
+		}
+	}
+
+	return false, false
+}
@@ -0,0 +1,50 @@
+package tcp
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeeker_detect(t *testing.T) {
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = server.Close()
+	})
+
+	br := bufio.NewReader(server)
+	peeker := NewPeeker(server, br)
+
+	go func() {
+		_, _ = client.Write([]byte("SSH-2.0-"))
+		_, _ = client.Write([]byte("OpenSSH_9.6\r\n"))
+	}()
+
+	assert.True(t, peeker.detect(protocolDetectors["ssh"], time.Second))
+
+	// The client waits for the server before sending more bytes, so the detection stops at the timeout.
+	start := time.Now()
+	assert.False(t, peeker.detect(prefixDetector([]byte("SSH-2.0-OpenSSH_9.6\r\nmore")), 100*time.Millisecond))
+	assert.Less(t, time.Since(start), time.Second)
+
+	// The peeked bytes are still readable.
+	b := make([]byte, 8)
+	_, err := io.ReadFull(br, b)
+	require.NoError(t, err)
+	assert.Equal(t, "SSH-2.0-", string(b))
+
+	// The read deadline has been removed.
+	go func() { _, _ = client.Write([]byte("!")) }()
+
+	time.Sleep(150 * time.Millisecond)
+
+	rest, err := br.ReadString('!')
+	require.NoError(t, err)
+	assert.Equal(t, "OpenSSH_9.6\r\n!", rest)
+}
@@ -0,0 +1,89 @@
+package udp
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/ip"
+)
+
+var udpFuncs = map[string]func(*matchersTree, ...string) error{
+	"ClientIP":      expect1Parameter(clientIP),
+	"PayloadPrefix": expect1Parameter(payloadPrefix),
+}
+
+func expect1Parameter(fn func(*matchersTree, ...string) error) func(*matchersTree, ...string) error {
+	return func(route *matchersTree, s ...string) error {
+		if len(s) != 1 {
+			return fmt.Errorf("unexpected number of parameters; got %d, expected 1", len(s))
+		}
+
+		return fn(route, s...)
+	}
+}
+
+func clientIP(tree *matchersTree, clientIP ...string) error {
+	checker, err := ip.NewChecker(clientIP)
+	if err != nil {
+		return fmt.Errorf("initializing IP checker for ClientIP matcher: %w", err)
+	}
+
+	tree.matcher = func(meta ConnData) bool {
+		ok, err := checker.Contains(meta.remoteIP)
+		if err != nil {
+			log.Warn().Err(err).Msg("ClientIP matcher: could not match remote address")
+			return false
+		}
+		return ok
+	}
+
+	return nil
+}
+
+// payloadPrefix checks if the first datagram of the session starts with the matcher prefix.
+// The prefix is hex encoded, where each ? hex digit matches any half byte.
+func payloadPrefix(tree *matchersTree, prefixes ...string) error {
+	prefix := prefixes[0]
+
+	if prefix == "" || len(prefix)%2 != 0 {
+		return fmt.Errorf("invalid value for PayloadPrefix matcher, %q is not an even number of hex digits", prefix)
+	}
+
+	// The wildcard half bytes are zeroed in the value, and in the mask applied to the payload.
+	value, err := hex.DecodeString(strings.ReplaceAll(prefix, "?", "0"))
+	if err != nil {
+		return fmt.Errorf("invalid value for PayloadPrefix matcher, %q is not hex encoded: %w", prefix, err)
+	}
+
+	mask := make([]byte, len(value))
+	for i := range mask {
+		mask[i] = halfByteMask(prefix[2*i])<<4 | halfByteMask(prefix[2*i+1])
+	}
+
+	tree.matcher = func(meta ConnData) bool {
+		if len(meta.payload) < len(value) {
+			return false
+		}
+
+		for i, b := range value {
+			if meta.payload[i]&mask[i] != b {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return nil
+}
+
+// halfByteMask returns the mask of the half byte encoded by the given hex digit.
+func halfByteMask(digit byte) byte {
+	if digit == '?' {
+		return 0
+	}
+
+	return 0xf
+}
@@ -0,0 +1,192 @@
+package udp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/udp"
+)
+
+func Test_addRoute(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		rule          string
+		remoteAddr    string
+		payload       []byte
+		expectedError bool
+		matched       bool
+	}{
+		{
+			desc:          "no rule",
+			expectedError: true,
+		},
+		{
+			desc:          "unknown matcher",
+			rule:          "HostSNI(`example.com`)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid ClientIP",
+			rule:          "ClientIP(`invalid`)",
+			expectedError: true,
+		},
+		{
+			desc:       "matching ClientIP",
+			rule:       "ClientIP(`10.0.0.0/8`)",
+			remoteAddr: "10.0.0.1:53",
+			matched:    true,
+		},
+		{
+			desc:       "non matching ClientIP",
+			rule:       "ClientIP(`10.0.0.0/8`)",
+			remoteAddr: "192.168.0.1:53",
+		},
+		{
+			desc:       "matching IPv6 ClientIP",
+			rule:       "ClientIP(`2001:db8::/32`)",
+			remoteAddr: "[2001:db8::1]:53",
+			matched:    true,
+		},
+		{
+			desc:          "empty PayloadPrefix",
+			rule:          "PayloadPrefix(``)",
+			expectedError: true,
+		},
+		{
+			desc:          "odd PayloadPrefix",
+			rule:          "PayloadPrefix(`c00`)",
+			expectedError: true,
+		},
+		{
+			desc:          "invalid PayloadPrefix",
+			rule:          "PayloadPrefix(`zz`)",
+			expectedError: true,
+		},
+		{
+			desc:          "PayloadPrefix with multiple parameters",
+			rule:          "PayloadPrefix(`c0`, `00`)",
+			expectedError: true,
+		},
+		{
+			desc:       "matching PayloadPrefix",
+			rule:       "PayloadPrefix(`c000`)",
+			remoteAddr: "10.0.0.1:443",
+			payload:    []byte{0xc0, 0x00, 0x01},
+			matched:    true,
+		},
+		{
+			desc:       "matching uppercase PayloadPrefix",
+			rule:       "PayloadPrefix(`C0FF`)",
+			remoteAddr: "10.0.0.1:443",
+			payload:    []byte{0xc0, 0xff},
+			matched:    true,
+		},
+		{
+			desc:       "non matching PayloadPrefix",
+			rule:       "PayloadPrefix(`c000`)",
+			remoteAddr: "10.0.0.1:443",
+			payload:    []byte{0xc0, 0x01},
+		},
+		{
+			desc:       "payload shorter than PayloadPrefix",
+			rule:       "PayloadPrefix(`c000`)",
+			remoteAddr: "10.0.0.1:443",
+			payload:    []byte{0xc0},
+		},
+		{
+			desc:       "matching PayloadPrefix with wildcards",
+			rule:       "PayloadPrefix(`c?00000001`)",
+			remoteAddr: "10.0.0.1:443",
+			payload:    []byte{0xcf, 0x00, 0x00, 0x00, 0x01, 0x08},
+			matched:    true,
+		},
+		{
+			desc:       "non matching PayloadPrefix with wildcards",
+			rule:       "PayloadPrefix(`c?00000001`)",
+			remoteAddr: "10.0.0.1:443",
+			payload:    []byte{0x4f, 0x00, 0x00, 0x00, 0x01, 0x08},
+		},
+		{
+			desc:       "matching negated PayloadPrefix",
+			rule:       "!PayloadPrefix(`c0`)",
+			remoteAddr: "10.0.0.1:53",
+			payload:    []byte{0x12, 0x34},
+			matched:    true,
+		},
+		{
+			desc:       "matching ClientIP and PayloadPrefix",
+			rule:       "ClientIP(`10.0.0.0/8`) && PayloadPrefix(`1234`)",
+			remoteAddr: "10.0.0.1:53",
+			payload:    []byte{0x12, 0x34},
+			matched:    true,
+		},
+		{
+			desc:       "non matching ClientIP or PayloadPrefix",
+			rule:       "ClientIP(`192.168.0.0/16`) || PayloadPrefix(`4321`)",
+			remoteAddr: "10.0.0.1:53",
+			payload:    []byte{0x12, 0x34},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			handler := udp.HandlerFunc(func(conn *udp.Conn) {})
+
+			err = muxer.AddRoute("", test.rule, 0, handler)
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			remoteAddr, err := net.ResolveUDPAddr("udp", test.remoteAddr)
+			require.NoError(t, err)
+
+			meta, err := NewConnData(remoteAddr, test.payload)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.matched, muxer.Match(meta) != nil)
+		})
+	}
+}
+
+func TestMuxer_Match_priority(t *testing.T) {
+	muxer, err := NewMuxer()
+	require.NoError(t, err)
+
+	var served string
+	newHandler := func(name string) udp.Handler {
+		return udp.HandlerFunc(func(conn *udp.Conn) {
+			served = name
+		})
+	}
+
+	err = muxer.AddRoute("client", "ClientIP(`10.0.0.1`)", GetRulePriority("ClientIP(`10.0.0.1`)"), newHandler("client"))
+	require.NoError(t, err)
+
+	err = muxer.AddRoute("quic", "PayloadPrefix(`c?00000001`)", 100, newHandler("quic"))
+	require.NoError(t, err)
+
+	remoteAddr, err := net.ResolveUDPAddr("udp", "10.0.0.1:443")
+	require.NoError(t, err)
+
+	meta, err := NewConnData(remoteAddr, []byte{0xc3, 0x00, 0x00, 0x00, 0x01})
+	require.NoError(t, err)
+
+	muxer.Match(meta).ServeUDP(nil)
+	assert.Equal(t, "quic", served)
+
+	meta, err = NewConnData(remoteAddr, []byte{0x12, 0x34})
+	require.NoError(t, err)
+
+	muxer.Match(meta).ServeUDP(nil)
+	assert.Equal(t, "client", served)
+}
@@ -0,0 +1,315 @@
+package acme
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kvtools/valkeyrie/store"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/provider/kv"
+	"github.com/traefik/traefik/v3/pkg/provider/kv/consul"
+	"github.com/traefik/traefik/v3/pkg/provider/kv/etcd"
+	"github.com/traefik/traefik/v3/pkg/provider/kv/redis"
+	"github.com/traefik/traefik/v3/pkg/provider/kv/zk"
+	"github.com/traefik/traefik/v3/pkg/safe"
+)
+
+const (
+	// kvLockTTL is the TTL of the locks, which are renewed while held.
+	kvLockTTL = 30 * time.Second
+	// kvSaveMaxAttempts is the maximum number of attempts to save a certificate concurrently modified by other instances.
+	kvSaveMaxAttempts = 5
+)
+
+var _ SharedStore = (*KVStore)(nil)
+
+// KVStorage contains the configuration of the KV store shared between several Traefik instances.
+type KVStorage struct {
+	Consul    *consul.ProviderBuilder `description:"Use a Consul KV store." json:"consul,omitempty" toml:"consul,omitempty" yaml:"consul,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Etcd      *etcd.Provider          `description:"Use an etcd KV store." json:"etcd,omitempty" toml:"etcd,omitempty" yaml:"etcd,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	Redis     *redis.Provider         `description:"Use a Redis KV store." json:"redis,omitempty" toml:"redis,omitempty" yaml:"redis,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+	ZooKeeper *zk.Provider            `description:"Use a ZooKeeper KV store." json:"zooKeeper,omitempty" toml:"zooKeeper,omitempty" yaml:"zooKeeper,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
+}
+
+// Client returns the client of the configured KV store, and its root key.
+func (s *KVStorage) Client() (store.Store, string, error) {
+	var provider *kv.Provider
+
+	switch {
+	case s.Consul != nil:
+		providers := s.Consul.BuildProviders()
+		if len(providers) > 1 {
+			return nil, "", errors.New("only one Consul namespace is supported")
+		}
+		if err := providers[0].Init(); err != nil {
+			return nil, "", err
+		}
+		provider = &providers[0].Provider
+	case s.Etcd != nil:
+		if err := s.Etcd.Init(); err != nil {
+			return nil, "", err
+		}
+		provider = &s.Etcd.Provider
+	case s.Redis != nil:
+		if err := s.Redis.Init(); err != nil {
+			return nil, "", err
+		}
+		provider = &s.Redis.Provider
+	case s.ZooKeeper != nil:
+		if err := s.ZooKeeper.Init(); err != nil {
+			return nil, "", err
+		}
+		provider = &s.ZooKeeper.Provider
+	default:
+		return nil, "", errors.New("no KV store defined")
+	}
+
+	return provider.KVClient(), provider.RootKey, nil
+}
+
+// KVStore Stores implementation for KV stores, sharing the ACME data between several Traefik instances.
+// The data of a resolver is stored under the <rootKey>/acme/<resolverName> key.
+type KVStore struct {
+	client  store.Store
+	rootKey string
+}
+
+// NewKVStore initializes a new KVStore with a KV store configuration.
+func NewKVStore(config *KVStorage) (*KVStore, error) {
+	client, rootKey, err := config.Client()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to KV store: %w", err)
+	}
+
+	return &KVStore{client: client, rootKey: rootKey}, nil
+}
+
+func (s *KVStore) key(resolverName string, elem ...string) string {
+	return path.Join(append([]string{s.rootKey, "acme", resolverName}, elem...)...)
+}
+
+// GetAccount returns ACME Account.
+func (s *KVStore) GetAccount(resolverName string) (*Account, error) {
+	pair, err := s.client.Get(context.Background(), s.key(resolverName, "account"), nil)
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var account Account
+	if err := json.Unmarshal(pair.Value, &account); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// SaveAccount stores ACME Account.
+func (s *KVStore) SaveAccount(resolverName string, account *Account) error {
+	data, err := json.Marshal(account)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Put(context.Background(), s.key(resolverName, "account"), data, nil)
+}
+
+// GetCertificates returns ACME Certificates list.
+func (s *KVStore) GetCertificates(resolverName string) ([]*CertAndStore, error) {
+	pairs, err := s.client.List(context.Background(), s.key(resolverName, "certificates"), nil)
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeCertificates(pairs), nil
+}
+
+// SaveCertificates stores ACME Certificates list.
+// Each certificate is stored under its own key, so that the Traefik instances can save different certificates concurrently.
+// A stored certificate is only replaced by a certificate expiring later,
+// so that an instance which has not seen the renewals of the other instances yet does not overwrite them.
+// The stored certificates missing from the given list are kept, as they may have been obtained by other instances.
+func (s *KVStore) SaveCertificates(resolverName string, certificates []*CertAndStore) error {
+	ctx := context.Background()
+
+	for _, certificate := range certificates {
+		if err := s.saveCertificate(ctx, resolverName, certificate); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveCertificate stores the given certificate, unless the stored one is identical or expires later.
+// The stored certificate is replaced atomically, and compared again if it has been modified in the meantime.
+func (s *KVStore) saveCertificate(ctx context.Context, resolverName string, certificate *CertAndStore) error {
+	data, err := json.Marshal(certificate)
+	if err != nil {
+		return err
+	}
+
+	key := s.key(resolverName, "certificates", hashName(domainsName(certificate.Domain.ToStrArray())))
+
+	for attempt := 1; ; attempt++ {
+		pair, err := s.client.Get(ctx, key, nil)
+		if errors.Is(err, store.ErrKeyNotFound) {
+			pair = nil
+		} else if err != nil {
+			return err
+		}
+
+		if pair != nil && (bytes.Equal(pair.Value, data) || !expiresLater(ctx, certificate, pair.Value)) {
+			return nil
+		}
+
+		_, _, err = s.client.AtomicPut(ctx, key, data, pair, nil)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, store.ErrKeyModified) && !errors.Is(err, store.ErrKeyExists) || attempt >= kvSaveMaxAttempts {
+			return err
+		}
+	}
+}
+
+// Lock acquires the lock of the given name, for the given resolver, waiting until it is available.
+func (s *KVStore) Lock(ctx context.Context, resolverName, name string) (func(), error) {
+	renew := make(chan struct{})
+
+	locker, err := s.client.NewLock(ctx, s.key(resolverName, "locks", hashName(name)), &store.LockOptions{
+		TTL:       kvLockTTL,
+		RenewLock: renew,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := locker.Lock(ctx); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		close(renew)
+
+		if err := locker.Unlock(context.Background()); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("lock", name).Msg("Unable to release ACME lock")
+		}
+	}, nil
+}
+
+// WatchCertificates sends the certificates of the given resolver each time they are updated by any Traefik instance.
+func (s *KVStore) WatchCertificates(ctx context.Context, resolverName string) (<-chan []*CertAndStore, error) {
+	events, err := s.client.WatchTree(ctx, s.key(resolverName, "certificates"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := make(chan []*CertAndStore)
+
+	safe.Go(func() {
+		defer close(certificates)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case pairs, ok := <-events:
+				if !ok {
+					return
+				}
+
+				select {
+				case certificates <- decodeCertificates(pairs):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	})
+
+	return certificates, nil
+}
+
+// decodeCertificates decodes the certificates of the given KV pairs, ignoring the invalid ones.
+func decodeCertificates(pairs []*store.KVPair) []*CertAndStore {
+	logger := log.With().Str(logs.ProviderName, "acme").Logger()
+
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Key < pairs[j].Key
+	})
+
+	var certificates []*CertAndStore
+	for _, pair := range pairs {
+		if len(pair.Value) == 0 {
+			continue
+		}
+
+		var certificate CertAndStore
+		if err := json.Unmarshal(pair.Value, &certificate); err != nil {
+			logger.Error().Err(err).Str("key", pair.Key).Msg("Unable to decode ACME certificate")
+			continue
+		}
+
+		if len(certificate.Certificate.Certificate) == 0 || len(certificate.Key) == 0 {
+			logger.Debug().Msgf("Ignoring empty certificate for %v", certificate.Domain.ToStrArray())
+			continue
+		}
+
+		certificates = append(certificates, &certificate)
+	}
+
+	return certificates
+}
+
+// expiresLater returns whether the given certificate expires after the stored one.
+// A stored certificate which cannot be decoded is always replaced.
+func expiresLater(ctx context.Context, certificate *CertAndStore, stored []byte) bool {
+	var storedCertificate CertAndStore
+	if err := json.Unmarshal(stored, &storedCertificate); err != nil || len(storedCertificate.Certificate.Certificate) == 0 {
+		return true
+	}
+
+	storedCrt, err := getX509Certificate(ctx, &storedCertificate.Certificate)
+	if err != nil {
+		return true
+	}
+
+	crt, err := getX509Certificate(ctx, &certificate.Certificate)
+	if err != nil {
+		return false
+	}
+
+	return crt.NotAfter.After(storedCrt.NotAfter)
+}
+
+// domainsName returns a name identifying the given domains, regardless of their order.
+func domainsName(domains []string) string {
+	sorted := make([]string, len(domains))
+	copy(sorted, domains)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, ",")
+}
+
+// hashName returns a name which can be safely used in a key, for all the KV stores.
+func hashName(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,283 @@
+package acme
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/registration"
+	"github.com/kvtools/valkeyrie/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/safe"
+	"github.com/traefik/traefik/v3/pkg/testhelpers/kvtest"
+	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+func TestKVStore_Account(t *testing.T) {
+	kvStore := &KVStore{client: kvtest.NewMemoryStore(), rootKey: "traefik"}
+
+	account, err := kvStore.GetAccount("test")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+
+	expected := &Account{Email: "foo@example.com", Registration: &registration.Resource{URI: "https://acme.example.com/acct/1"}}
+	require.NoError(t, kvStore.SaveAccount("test", expected))
+
+	account, err = kvStore.GetAccount("test")
+	require.NoError(t, err)
+	assert.Equal(t, expected, account)
+
+	// The accounts are stored by resolver.
+	account, err = kvStore.GetAccount("other")
+	require.NoError(t, err)
+	assert.Nil(t, account)
+}
+
+func TestKVStore_Certificates(t *testing.T) {
+	kv := kvtest.NewMemoryStore()
+
+	// Two Traefik instances sharing the same KV store.
+	storeA := &KVStore{client: kv, rootKey: "traefik"}
+	storeB := &KVStore{client: kv, rootKey: "traefik"}
+
+	certificates, err := storeA.GetCertificates("test")
+	require.NoError(t, err)
+	assert.Empty(t, certificates)
+
+	fooCert := createCertAndStore(t, types.Domain{Main: "foo.localhost"}, time.Now().Add(time.Hour))
+	barCert := createCertAndStore(t, types.Domain{Main: "bar.localhost", SANs: []string{"baz.localhost"}}, time.Now().Add(time.Hour))
+
+	// Each instance saves the certificates it knows, without overwriting the certificates of the other one.
+	require.NoError(t, storeA.SaveCertificates("test", []*CertAndStore{fooCert}))
+	require.NoError(t, storeB.SaveCertificates("test", []*CertAndStore{barCert}))
+
+	certificates, err = storeA.GetCertificates("test")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []*CertAndStore{fooCert, barCert}, certificates)
+
+	// Empty certificates are ignored.
+	require.NoError(t, storeA.SaveCertificates("test", []*CertAndStore{{Certificate: Certificate{Domain: types.Domain{Main: "empty.localhost"}}}}))
+
+	certificates, err = storeB.GetCertificates("test")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []*CertAndStore{fooCert, barCert}, certificates)
+}
+
+func TestKVStore_SaveCertificates_stale(t *testing.T) {
+	kv := kvtest.NewMemoryStore()
+
+	storeA := &KVStore{client: kv, rootKey: "traefik"}
+	storeB := &KVStore{client: kv, rootKey: "traefik"}
+
+	domain := types.Domain{Main: "foo.localhost"}
+	fooCert := createCertAndStore(t, domain, time.Now().Add(time.Hour))
+	renewedFooCert := createCertAndStore(t, domain, time.Now().Add(2*time.Hour))
+
+	require.NoError(t, storeA.SaveCertificates("test", []*CertAndStore{fooCert}))
+	require.NoError(t, storeA.SaveCertificates("test", []*CertAndStore{renewedFooCert}))
+
+	// The instance B, which has not seen the renewal yet, saves its stale certificate.
+	require.NoError(t, storeB.SaveCertificates("test", []*CertAndStore{fooCert}))
+
+	certificates, err := storeB.GetCertificates("test")
+	require.NoError(t, err)
+	assert.Equal(t, []*CertAndStore{renewedFooCert}, certificates)
+}
+
+func TestKVStore_SaveCertificates_concurrentModification(t *testing.T) {
+	kv := kvtest.NewMemoryStore()
+
+	domain := types.Domain{Main: "foo.localhost"}
+	fooCert := createCertAndStore(t, domain, time.Now().Add(time.Hour))
+	renewedFooCert := createCertAndStore(t, domain, time.Now().Add(2*time.Hour))
+
+	storeA := &KVStore{client: kv, rootKey: "traefik"}
+	require.NoError(t, storeA.SaveCertificates("test", []*CertAndStore{fooCert}))
+
+	// The instance A saves its renewed certificate between the read and the write of the instance B.
+	storeB := &KVStore{client: &racingStore{MemoryStore: kv, race: func() {
+		require.NoError(t, storeA.SaveCertificates("test", []*CertAndStore{renewedFooCert}))
+	}}, rootKey: "traefik"}
+
+	require.NoError(t, storeB.SaveCertificates("test", []*CertAndStore{createCertAndStore(t, domain, time.Now().Add(90*time.Minute))}))
+
+	certificates, err := storeA.GetCertificates("test")
+	require.NoError(t, err)
+	assert.Equal(t, []*CertAndStore{renewedFooCert}, certificates)
+}
+
+// racingStore is a KV store calling race before its first atomic put.
+type racingStore struct {
+	*kvtest.MemoryStore
+
+	race func()
+	done bool
+}
+
+func (s *racingStore) AtomicPut(ctx context.Context, key string, value []byte, previous *store.KVPair, options *store.WriteOptions) (bool, *store.KVPair, error) {
+	if !s.done {
+		s.done = true
+		s.race()
+	}
+
+	return s.MemoryStore.AtomicPut(ctx, key, value, previous, options)
+}
+
+func TestKVStore_Lock(t *testing.T) {
+	kvStore := &KVStore{client: kvtest.NewMemoryStore(), rootKey: "traefik"}
+
+	unlock, err := kvStore.Lock(context.Background(), "test", "foo.localhost")
+	require.NoError(t, err)
+
+	// A different lock can be acquired.
+	unlockOther, err := kvStore.Lock(context.Background(), "test", "bar.localhost")
+	require.NoError(t, err)
+	unlockOther()
+
+	acquired := make(chan func())
+	go func() {
+		unlock, err := kvStore.Lock(context.Background(), "test", "foo.localhost")
+		if err == nil {
+			acquired <- unlock
+		}
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("the lock has been acquired twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+
+	select {
+	case unlock := <-acquired:
+		unlock()
+	case <-time.After(time.Second):
+		t.Fatal("the lock has not been acquired after being released")
+	}
+}
+
+func TestProvider_sharedStore(t *testing.T) {
+	kv := kvtest.NewMemoryStore()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	pool := safe.NewPool(ctx)
+	t.Cleanup(pool.Stop)
+
+	// newProvider returns a provider of a Traefik instance, sharing the KV store with the other instances.
+	// The provider watching the certificates of the other instances is started,
+	// and the ACME server is not reachable, so that any attempt to obtain a certificate fails.
+	newProvider := func(watch bool) (*Provider, chan dynamic.Message) {
+		p := &Provider{
+			Configuration: &Configuration{CAServer: "https://acme.localhost/directory", CertificatesDuration: 24 * 90, KVStorage: &KVStorage{}},
+			ResolverName:  "test",
+			Store:         &KVStore{client: kv, rootKey: "traefik"},
+		}
+		p.SetTLSManager(traefiktls.NewManager(nil))
+		require.NoError(t, p.Init())
+
+		messages := make(chan dynamic.Message, 10)
+		if !watch {
+			p.configurationChan = messages
+			return p, messages
+		}
+
+		p.SetConfigListenerChan(make(chan dynamic.Configuration))
+		require.NoError(t, p.Provide(messages, pool))
+
+		// Initial configuration.
+		<-messages
+
+		return p, messages
+	}
+
+	providerA, _ := newProvider(false)
+	_, messagesB := newProvider(true)
+	providerC, _ := newProvider(false)
+
+	// The certificate obtained by an instance is picked up by the watching ones.
+	fooCert := createCertAndStore(t, types.Domain{Main: "foo.localhost"}, time.Now().Add(90*24*time.Hour))
+	err := providerA.addCertificateForDomain(fooCert.Domain, &certificate.Resource{
+		Certificate: fooCert.Certificate.Certificate,
+		PrivateKey:  fooCert.Key,
+	}, fooCert.Store)
+	require.NoError(t, err)
+
+	select {
+	case msg := <-messagesB:
+		require.Len(t, msg.Configuration.TLS.Certificates, 1)
+		assert.Equal(t, traefiktls.FileOrContent(fooCert.Certificate.Certificate), msg.Configuration.TLS.Certificates[0].CertFile)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the certificate has not been picked up")
+	}
+
+	// The instance acquiring the lock does not obtain the certificate obtained by another one meanwhile.
+	domain, cert, err := providerC.resolveCertificate(context.Background(), types.Domain{Main: "foo.localhost"}, traefiktls.DefaultTLSStoreName)
+	require.NoError(t, err)
+	assert.Nil(t, cert)
+	assert.Empty(t, domain)
+	assert.True(t, providerC.certExists([]string{"foo.localhost"}))
+	assert.Nil(t, providerC.account)
+
+	// The instance acquiring the lock does not renew the certificate renewed by another one meanwhile.
+	renewedCert := createCertAndStore(t, types.Domain{Main: "foo.localhost"}, time.Now().Add(180*24*time.Hour))
+	require.NoError(t, providerA.Store.SaveCertificates("test", []*CertAndStore{renewedCert}))
+
+	providerC.certificatesMu.RLock()
+	currentCert := providerC.certificates[0]
+	providerC.certificatesMu.RUnlock()
+
+	require.NoError(t, providerC.renewCertificate(context.Background(), currentCert))
+	assert.Nil(t, providerC.account)
+
+	providerC.certificatesMu.RLock()
+	defer providerC.certificatesMu.RUnlock()
+
+	require.Len(t, providerC.certificates, 1)
+	assert.Equal(t, renewedCert.Certificate.Certificate, providerC.certificates[0].Certificate.Certificate)
+}
+
+func createCertAndStore(t *testing.T, domain types.Domain, notAfter time.Time) *CertAndStore {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(time.Now().UnixNano()),
+		Subject:        pkix.Name{CommonName: domain.Main},
+		DNSNames:       domain.ToStrArray(),
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       notAfter,
+		AuthorityKeyId: []byte{0x01, 0x02, 0x03, 0x04},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &CertAndStore{
+		Certificate: Certificate{
+			Domain:      domain,
+			Certificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			Key:         pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		},
+		Store: traefiktls.DefaultTLSStoreName,
+	}
+}
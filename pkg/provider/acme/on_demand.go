@@ -0,0 +1,247 @@
+package acme
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/safe"
+)
+
+const (
+	// onDemandAskTimeout is the timeout of the requests to the ask URL.
+	onDemandAskTimeout = 5 * time.Second
+	// onDemandMaxAttempts is the maximum number of attempts kept, i.e. of domains checked or obtained during the retry interval.
+	onDemandMaxAttempts = 1000
+	// onDemandMaxRunning is the maximum number of attempts concurrently checking or obtaining a certificate.
+	onDemandMaxRunning = 10
+)
+
+// OnDemand contains the configuration of the certificates obtained during the TLS handshakes.
+type OnDemand struct {
+	Ask            string          `description:"URL asked whether a certificate can be obtained for a domain, given in the domain query parameter. A 2xx response allows it." json:"ask,omitempty" toml:"ask,omitempty" yaml:"ask,omitempty"`
+	AllowedDomains []string        `description:"Regular expressions matching the whole domains for which a certificate can be obtained, without asking the ask URL." json:"allowedDomains,omitempty" toml:"allowedDomains,omitempty" yaml:"allowedDomains,omitempty" export:"true"`
+	RetryInterval  ptypes.Duration `description:"Minimum duration between two attempts to obtain a certificate for the same domain." json:"retryInterval,omitempty" toml:"retryInterval,omitempty" yaml:"retryInterval,omitempty" export:"true"`
+	Timeout        ptypes.Duration `description:"Maximum duration a TLS handshake waits for its certificate to be obtained, before being served the default certificate." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (o *OnDemand) SetDefaults() {
+	o.RetryInterval = ptypes.Duration(10 * time.Minute)
+	o.Timeout = ptypes.Duration(10 * time.Second)
+}
+
+// onDemandAttempt is an attempt to obtain the certificate of a domain.
+type onDemandAttempt struct {
+	startedAt time.Time
+	// done is closed when the attempt is over, and the certificate or the error are set.
+	done chan struct{}
+	cert *tls.Certificate
+	err  error
+}
+
+// onDemandIssuer obtains the certificates of the domains allowed by the allowlist or the ask URL, during the TLS handshakes.
+// The attempts are rate limited by domain: a domain is not checked nor obtained again during the retry interval.
+// The number of attempts kept and running are bounded, and the new domains are rejected once the bounds are reached.
+type onDemandIssuer struct {
+	config  OnDemand
+	allowed []*regexp.Regexp
+	client  *http.Client
+	obtain  func(ctx context.Context, domain string) (*tls.Certificate, error)
+
+	maxAttempts int
+	// running is a semaphore bounding the number of running attempts.
+	running chan struct{}
+
+	mu       sync.Mutex
+	attempts map[string]*onDemandAttempt
+	purgedAt time.Time
+}
+
+func newOnDemandIssuer(config OnDemand, obtain func(ctx context.Context, domain string) (*tls.Certificate, error)) (*onDemandIssuer, error) {
+	if config.Ask == "" && len(config.AllowedDomains) == 0 {
+		return nil, errors.New("on-demand certificates require an ask URL or allowed domains")
+	}
+
+	if config.Ask != "" {
+		askURL, err := url.Parse(config.Ask)
+		if err != nil {
+			return nil, fmt.Errorf("parsing ask URL: %w", err)
+		}
+		if askURL.Scheme != "http" && askURL.Scheme != "https" {
+			return nil, fmt.Errorf("invalid ask URL scheme: %q", askURL.Scheme)
+		}
+	}
+
+	issuer := &onDemandIssuer{
+		config:      config,
+		client:      &http.Client{Timeout: onDemandAskTimeout},
+		obtain:      obtain,
+		maxAttempts: onDemandMaxAttempts,
+		running:     make(chan struct{}, onDemandMaxRunning),
+		attempts:    make(map[string]*onDemandAttempt),
+	}
+
+	for _, allowedDomain := range config.AllowedDomains {
+		allowed, err := regexp.Compile("^(?:" + allowedDomain + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("compiling allowed domain %q: %w", allowedDomain, err)
+		}
+		issuer.allowed = append(issuer.allowed, allowed)
+	}
+
+	return issuer, nil
+}
+
+// getCertificate returns the certificate of the given domain, waiting for it to be obtained until the timeout.
+func (o *onDemandIssuer) getCertificate(ctx context.Context, domain string) (*tls.Certificate, error) {
+	now := time.Now()
+
+	o.mu.Lock()
+	attempt, ok := o.attempts[domain]
+	if !ok || o.expired(attempt, now) {
+		var err error
+		attempt, err = o.start(domain, now)
+		if err != nil {
+			o.mu.Unlock()
+			return nil, err
+		}
+	}
+	o.mu.Unlock()
+
+	timer := time.NewTimer(time.Duration(o.config.Timeout))
+	defer timer.Stop()
+
+	select {
+	case <-attempt.done:
+		return attempt.cert, attempt.err
+	case <-timer.C:
+		return nil, fmt.Errorf("certificate for %s is still being obtained", domain)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// start starts a new attempt to obtain the certificate of the given domain,
+// unless the maximum number of attempts kept or running is reached.
+// It must be called with the lock held.
+func (o *onDemandIssuer) start(domain string, now time.Time) (*onDemandAttempt, error) {
+	o.purge(now, false)
+
+	if _, ok := o.attempts[domain]; !ok && len(o.attempts) >= o.maxAttempts {
+		o.purge(now, true)
+
+		if len(o.attempts) >= o.maxAttempts {
+			return nil, fmt.Errorf("too many on-demand certificate attempts, rejecting domain %s", domain)
+		}
+	}
+
+	select {
+	case o.running <- struct{}{}:
+	default:
+		return nil, fmt.Errorf("too many on-demand certificates being obtained, rejecting domain %s", domain)
+	}
+
+	attempt := &onDemandAttempt{startedAt: now, done: make(chan struct{})}
+	o.attempts[domain] = attempt
+
+	safe.Go(func() {
+		defer func() { <-o.running }()
+
+		o.run(domain, attempt)
+	})
+
+	return attempt, nil
+}
+
+// run checks whether the certificate of the given domain can be obtained, and obtains it.
+// The attempt outlives the handshake which started it, so that the certificate is available for the next ones.
+func (o *onDemandIssuer) run(domain string, attempt *onDemandAttempt) {
+	defer close(attempt.done)
+
+	logger := log.With().Str("domain", domain).Logger()
+	ctx := logger.WithContext(context.Background())
+
+	if attempt.err = o.allow(ctx, domain); attempt.err != nil {
+		logger.Debug().Err(attempt.err).Msg("On-demand certificate denied")
+		return
+	}
+
+	attempt.cert, attempt.err = o.obtain(ctx, domain)
+	if attempt.err != nil {
+		logger.Error().Err(attempt.err).Msg("Unable to obtain on-demand certificate")
+	}
+}
+
+// allow returns an error when the certificate of the given domain is neither allowed by the allowed domains nor by the ask URL.
+func (o *onDemandIssuer) allow(ctx context.Context, domain string) error {
+	for _, allowed := range o.allowed {
+		if allowed.MatchString(domain) {
+			return nil
+		}
+	}
+
+	if o.config.Ask == "" {
+		return fmt.Errorf("domain %s is not allowed", domain)
+	}
+
+	askURL, err := url.Parse(o.config.Ask)
+	if err != nil {
+		return fmt.Errorf("parsing ask URL: %w", err)
+	}
+
+	query := askURL.Query()
+	query.Set("domain", domain)
+	askURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, askURL.String(), http.NoBody)
+	if err != nil {
+		return fmt.Errorf("creating ask request: %w", err)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("asking whether domain %s is allowed: %w", domain, err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("domain %s is not allowed by the ask URL: %s", domain, resp.Status)
+	}
+
+	return nil
+}
+
+// expired returns whether the given attempt is over, and another one can be made.
+// It must be called with the lock held.
+func (o *onDemandIssuer) expired(attempt *onDemandAttempt, now time.Time) bool {
+	select {
+	case <-attempt.done:
+		return now.Sub(attempt.startedAt) >= time.Duration(o.config.RetryInterval)
+	default:
+		return false
+	}
+}
+
+// purge forgets the expired attempts, at most once per retry interval unless forced.
+// It must be called with the lock held.
+func (o *onDemandIssuer) purge(now time.Time, force bool) {
+	if !force && now.Sub(o.purgedAt) < time.Duration(o.config.RetryInterval) {
+		return
+	}
+	o.purgedAt = now
+
+	for domain, attempt := range o.attempts {
+		if o.expired(attempt, now) {
+			delete(o.attempts, domain)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package acme
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+)
+
+func TestNewOnDemandIssuer(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      OnDemand
+		expectedErr string
+	}{
+		{
+			desc:        "no ask URL nor allowed domains",
+			config:      OnDemand{},
+			expectedErr: "on-demand certificates require an ask URL or allowed domains",
+		},
+		{
+			desc:        "invalid ask URL scheme",
+			config:      OnDemand{Ask: "ftp://example.com"},
+			expectedErr: `invalid ask URL scheme: "ftp"`,
+		},
+		{
+			desc:        "invalid allowed domain",
+			config:      OnDemand{AllowedDomains: []string{"(foo"}},
+			expectedErr: `compiling allowed domain "(foo"`,
+		},
+		{
+			desc:   "valid",
+			config: OnDemand{Ask: "http://localhost/ask", AllowedDomains: []string{`.+\.example\.com`}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newOnDemandIssuer(test.config, nil)
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOnDemandIssuer_allowedDomains(t *testing.T) {
+	var obtained atomic.Int32
+	obtain := func(_ context.Context, _ string) (*tls.Certificate, error) {
+		obtained.Add(1)
+		return &tls.Certificate{}, nil
+	}
+
+	issuer, err := newOnDemandIssuer(OnDemand{
+		AllowedDomains: []string{`[a-z]+\.example\.com`},
+		RetryInterval:  ptypes.Duration(time.Hour),
+		Timeout:        ptypes.Duration(time.Second),
+	}, obtain)
+	require.NoError(t, err)
+
+	cert, err := issuer.getCertificate(context.Background(), "foo.example.com")
+	require.NoError(t, err)
+	assert.NotNil(t, cert)
+
+	// The allowed domains match the whole domain.
+	_, err = issuer.getCertificate(context.Background(), "foo.example.com.evil.com")
+	assert.ErrorContains(t, err, "is not allowed")
+
+	// The obtained certificate is kept during the retry interval.
+	_, err = issuer.getCertificate(context.Background(), "foo.example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), obtained.Load())
+}
+
+func TestOnDemandIssuer_ask(t *testing.T) {
+	var asked atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		asked.Add(1)
+
+		if req.URL.Query().Get("domain") != "customer.com" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	obtain := func(_ context.Context, _ string) (*tls.Certificate, error) {
+		return &tls.Certificate{}, nil
+	}
+
+	issuer, err := newOnDemandIssuer(OnDemand{
+		Ask:           server.URL + "/ask?token=secret",
+		RetryInterval: ptypes.Duration(time.Hour),
+		Timeout:       ptypes.Duration(time.Second),
+	}, obtain)
+	require.NoError(t, err)
+
+	_, err = issuer.getCertificate(context.Background(), "customer.com")
+	require.NoError(t, err)
+
+	_, err = issuer.getCertificate(context.Background(), "unknown.com")
+	assert.ErrorContains(t, err, "is not allowed by the ask URL: 404 Not Found")
+
+	// The denied domains are not asked again during the retry interval.
+	_, err = issuer.getCertificate(context.Background(), "unknown.com")
+	assert.Error(t, err)
+
+	assert.Equal(t, int32(2), asked.Load())
+}
+
+func TestOnDemandIssuer_timeout(t *testing.T) {
+	release := make(chan struct{})
+
+	var obtained atomic.Int32
+	obtain := func(_ context.Context, _ string) (*tls.Certificate, error) {
+		obtained.Add(1)
+		<-release
+		return &tls.Certificate{}, nil
+	}
+
+	issuer, err := newOnDemandIssuer(OnDemand{
+		AllowedDomains: []string{"example.com"},
+		RetryInterval:  ptypes.Duration(time.Hour),
+		Timeout:        ptypes.Duration(10 * time.Millisecond),
+	}, obtain)
+	require.NoError(t, err)
+
+	// The certificate is still being obtained after the handshakes timed out.
+	_, err = issuer.getCertificate(context.Background(), "example.com")
+	assert.ErrorContains(t, err, "is still being obtained")
+
+	_, err = issuer.getCertificate(context.Background(), "example.com")
+	assert.ErrorContains(t, err, "is still being obtained")
+
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		cert, err := issuer.getCertificate(context.Background(), "example.com")
+		return err == nil && cert != nil
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, int32(1), obtained.Load())
+}
+
+func TestOnDemandIssuer_retryInterval(t *testing.T) {
+	var obtained atomic.Int32
+	obtain := func(_ context.Context, _ string) (*tls.Certificate, error) {
+		obtained.Add(1)
+		return nil, assert.AnError
+	}
+
+	issuer, err := newOnDemandIssuer(OnDemand{
+		AllowedDomains: []string{"example.com"},
+		RetryInterval:  ptypes.Duration(time.Hour),
+		Timeout:        ptypes.Duration(time.Second),
+	}, obtain)
+	require.NoError(t, err)
+
+	_, err = issuer.getCertificate(context.Background(), "example.com")
+	assert.ErrorIs(t, err, assert.AnError)
+
+	_, err = issuer.getCertificate(context.Background(), "example.com")
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, int32(1), obtained.Load())
+
+	// Another attempt is made once the retry interval elapsed.
+	issuer.attempts["example.com"].startedAt = time.Now().Add(-time.Hour)
+
+	_, err = issuer.getCertificate(context.Background(), "example.com")
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, int32(2), obtained.Load())
+}
+
+func TestOnDemandIssuer_maxAttempts(t *testing.T) {
+	obtain := func(_ context.Context, _ string) (*tls.Certificate, error) {
+		return nil, assert.AnError
+	}
+
+	issuer, err := newOnDemandIssuer(OnDemand{
+		AllowedDomains: []string{".+"},
+		RetryInterval:  ptypes.Duration(time.Hour),
+		Timeout:        ptypes.Duration(time.Second),
+	}, obtain)
+	require.NoError(t, err)
+
+	issuer.maxAttempts = 2
+
+	_, err = issuer.getCertificate(context.Background(), "foo.example.com")
+	assert.ErrorIs(t, err, assert.AnError)
+
+	_, err = issuer.getCertificate(context.Background(), "bar.example.com")
+	assert.ErrorIs(t, err, assert.AnError)
+
+	// The new domains are rejected, while the known ones are still served their attempt.
+	_, err = issuer.getCertificate(context.Background(), "baz.example.com")
+	assert.ErrorContains(t, err, "too many on-demand certificate attempts")
+
+	_, err = issuer.getCertificate(context.Background(), "foo.example.com")
+	assert.ErrorIs(t, err, assert.AnError)
+
+	// The expired attempts are forgotten to make room for the new domains.
+	issuer.attempts["foo.example.com"].startedAt = time.Now().Add(-time.Hour)
+
+	_, err = issuer.getCertificate(context.Background(), "baz.example.com")
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Len(t, issuer.attempts, 2)
+}
+
+func TestOnDemandIssuer_maxRunning(t *testing.T) {
+	release := make(chan struct{})
+
+	var obtained atomic.Int32
+	obtain := func(_ context.Context, _ string) (*tls.Certificate, error) {
+		obtained.Add(1)
+		<-release
+		return &tls.Certificate{}, nil
+	}
+
+	issuer, err := newOnDemandIssuer(OnDemand{
+		AllowedDomains: []string{".+"},
+		RetryInterval:  ptypes.Duration(time.Hour),
+		Timeout:        ptypes.Duration(10 * time.Millisecond),
+	}, obtain)
+	require.NoError(t, err)
+
+	issuer.running = make(chan struct{}, 1)
+
+	_, err = issuer.getCertificate(context.Background(), "foo.example.com")
+	assert.ErrorContains(t, err, "is still being obtained")
+
+	_, err = issuer.getCertificate(context.Background(), "bar.example.com")
+	assert.ErrorContains(t, err, "too many on-demand certificates being obtained")
+
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		cert, err := issuer.getCertificate(context.Background(), "bar.example.com")
+		return err == nil && cert != nil
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, int32(2), obtained.Load())
+}
@@ -1,7 +1,9 @@
 package acme
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -13,6 +15,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
+	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/certificate"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
@@ -22,6 +26,7 @@ import (
 	"github.com/rs/zerolog/log"
 	ptypes "github.com/traefik/paerser/types"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/job"
 	"github.com/traefik/traefik/v3/pkg/logs"
 	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
 	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
@@ -34,15 +39,20 @@ import (
 // ocspMustStaple enables OCSP stapling as from https://github.com/go-acme/lego/issues/270.
 var ocspMustStaple = false
 
+// accountLockName is the name of the lock held while registering the account in a shared store.
+const accountLockName = "account"
+
 // Configuration holds ACME configuration provided by users.
 type Configuration struct {
-	Email                string `description:"Email address used for registration." json:"email,omitempty" toml:"email,omitempty" yaml:"email,omitempty"`
-	CAServer             string `description:"CA server to use." json:"caServer,omitempty" toml:"caServer,omitempty" yaml:"caServer,omitempty"`
-	PreferredChain       string `description:"Preferred chain to use." json:"preferredChain,omitempty" toml:"preferredChain,omitempty" yaml:"preferredChain,omitempty" export:"true"`
-	Storage              string `description:"Storage to use." json:"storage,omitempty" toml:"storage,omitempty" yaml:"storage,omitempty" export:"true"`
-	KeyType              string `description:"KeyType used for generating certificate private key. Allow value 'EC256', 'EC384', 'RSA2048', 'RSA4096', 'RSA8192'." json:"keyType,omitempty" toml:"keyType,omitempty" yaml:"keyType,omitempty" export:"true"`
-	EAB                  *EAB   `description:"External Account Binding to use." json:"eab,omitempty" toml:"eab,omitempty" yaml:"eab,omitempty"`
-	CertificatesDuration int    `description:"Certificates' duration in hours." json:"certificatesDuration,omitempty" toml:"certificatesDuration,omitempty" yaml:"certificatesDuration,omitempty" export:"true"`
+	Email                string     `description:"Email address used for registration." json:"email,omitempty" toml:"email,omitempty" yaml:"email,omitempty"`
+	CAServer             string     `description:"CA server to use." json:"caServer,omitempty" toml:"caServer,omitempty" yaml:"caServer,omitempty"`
+	PreferredChain       string     `description:"Preferred chain to use." json:"preferredChain,omitempty" toml:"preferredChain,omitempty" yaml:"preferredChain,omitempty" export:"true"`
+	Storage              string     `description:"Storage to use." json:"storage,omitempty" toml:"storage,omitempty" yaml:"storage,omitempty" export:"true"`
+	KVStorage            *KVStorage `description:"KV store used to share the account and certificates between several Traefik instances, instead of the storage file." json:"kvStorage,omitempty" toml:"kvStorage,omitempty" yaml:"kvStorage,omitempty" export:"true"`
+	KeyType              string     `description:"KeyType used for generating certificate private key. Allow value 'EC256', 'EC384', 'RSA2048', 'RSA4096', 'RSA8192'." json:"keyType,omitempty" toml:"keyType,omitempty" yaml:"keyType,omitempty" export:"true"`
+	EAB                  *EAB       `description:"External Account Binding to use." json:"eab,omitempty" toml:"eab,omitempty" yaml:"eab,omitempty"`
+	CertificatesDuration int        `description:"Certificates' duration in hours." json:"certificatesDuration,omitempty" toml:"certificatesDuration,omitempty" yaml:"certificatesDuration,omitempty" export:"true"`
+	OnDemand             *OnDemand  `description:"Obtain the certificates during the TLS handshakes, for the allowed domains without certificate." json:"onDemand,omitempty" toml:"onDemand,omitempty" yaml:"onDemand,omitempty" export:"true"`
 
 	DNSChallenge  *DNSChallenge  `description:"Activate DNS-01 Challenge." json:"dnsChallenge,omitempty" toml:"dnsChallenge,omitempty" yaml:"dnsChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	HTTPChallenge *HTTPChallenge `description:"Activate HTTP-01 Challenge." json:"httpChallenge,omitempty" toml:"httpChallenge,omitempty" yaml:"httpChallenge,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
@@ -113,6 +123,10 @@ type Provider struct {
 	pool                   *safe.Pool
 	resolvingDomains       map[string]struct{}
 	resolvingDomainsMutex  sync.RWMutex
+	renewalInfo            *renewalInfoClient
+	renewalSchedules       map[string]*renewalSchedule
+	orderReplaces          *orderReplaces
+	onDemand               *onDemandIssuer
 }
 
 // SetTLSManager sets the tls manager to use.
@@ -134,7 +148,7 @@ func (p *Provider) ListenConfiguration(config dynamic.Configuration) {
 func (p *Provider) Init() error {
 	logger := log.With().Str(logs.ProviderName, p.ResolverName+".acme").Logger()
 
-	if len(p.Configuration.Storage) == 0 {
+	if len(p.Configuration.Storage) == 0 && p.KVStorage == nil {
 		return errors.New("unable to initialize ACME provider with no storage location for the certificates")
 	}
 
@@ -165,6 +179,23 @@ func (p *Provider) Init() error {
 	// Init the currently resolved domain map
 	p.resolvingDomains = make(map[string]struct{})
 
+	caServer := lego.LEDirectoryProduction
+	if len(p.CAServer) > 0 {
+		caServer = p.CAServer
+	}
+
+	// The HTTP client of the lego configuration trusts the same CAs as the lego client.
+	p.renewalInfo = newRenewalInfoClient(lego.NewConfig(nil).HTTPClient, fmt.Sprintf("containous-traefik/%s", version.Version), caServer)
+	p.renewalSchedules = make(map[string]*renewalSchedule)
+	p.orderReplaces = newOrderReplaces()
+
+	if p.OnDemand != nil {
+		p.onDemand, err = newOnDemandIssuer(*p.OnDemand, p.obtainOnDemand)
+		if err != nil {
+			return fmt.Errorf("unable to initialize on-demand certificates: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -210,20 +241,30 @@ func (p *Provider) Provide(configurationChan chan<- dynamic.Message, pool *safe.
 
 	p.configurationChan <- msg
 
+	// The certificates can only be obtained on demand once they can be sent in the configuration.
+	if p.onDemand != nil && p.tlsManager != nil {
+		p.tlsManager.SetOnDemandCertificates(p)
+	}
+
+	if sharedStore, ok := p.Store.(SharedStore); ok {
+		p.watchCertificates(ctx, sharedStore)
+	}
+
 	renewPeriod, renewInterval := getCertificateRenewDurations(p.CertificatesDuration)
 	logger.Debug().Msgf("Attempt to renew certificates %q before expiry and check every %q",
 		renewPeriod, renewInterval)
 
-	p.renewCertificates(ctx, renewPeriod)
+	next := p.renewCertificates(ctx, renewPeriod, renewInterval)
 
-	ticker := time.NewTicker(renewInterval)
+	timer := time.NewTimer(time.Until(next))
 	pool.GoCtx(func(ctxPool context.Context) {
 		for {
 			select {
-			case <-ticker.C:
-				p.renewCertificates(ctx, renewPeriod)
+			case <-timer.C:
+				next = p.renewCertificates(ctx, renewPeriod, renewInterval)
+				timer.Reset(time.Until(next))
 			case <-ctxPool.Done():
-				ticker.Stop()
+				timer.Stop()
 				return
 			}
 		}
@@ -244,6 +285,17 @@ func (p *Provider) getClient() (*lego.Client, error) {
 		return p.client, nil
 	}
 
+	// Prevents the Traefik instances sharing the store from registering several accounts.
+	if p.account == nil || p.account.GetRegistration() == nil {
+		unlock, err := p.lock(ctx, accountLockName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to lock ACME account: %w", err)
+		}
+		defer unlock()
+
+		p.syncAccount(ctx)
+	}
+
 	account, err := p.initAccount(ctx)
 	if err != nil {
 		return nil, err
@@ -262,6 +314,10 @@ func (p *Provider) getClient() (*lego.Client, error) {
 	config.Certificate.KeyType = GetKeyType(ctx, p.KeyType)
 	config.UserAgent = fmt.Sprintf("containous-traefik/%s", version.Version)
 
+	if p.orderReplaces != nil {
+		config.HTTPClient.Transport = p.orderReplaces.transport(config.HTTPClient.Transport, account.GetPrivateKey())
+	}
+
 	client, err := lego.NewClient(config)
 	if err != nil {
 		return nil, err
@@ -573,6 +629,18 @@ func (p *Provider) resolveDefaultCertificate(ctx context.Context, domains []stri
 
 	defer p.removeResolvingDomains(append(domains, domainKey))
 
+	unlock, err := p.lock(ctx, domainsName(domains))
+	if err != nil {
+		return nil, fmt.Errorf("unable to lock domains %v: %w", domains, err)
+	}
+	defer unlock()
+
+	// Another Traefik instance may have obtained the certificate while waiting for the lock.
+	if p.syncCertificates(ctx) && p.certExists(domains) {
+		logger.Debug().Msgf("Default certificate for domains %+v obtained by another instance", domains)
+		return nil, nil
+	}
+
 	logger.Debug().Msgf("Loading ACME certificates %+v...", domains)
 
 	client, err := p.getClient()
@@ -618,6 +686,19 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 	defer p.removeResolvingDomains(uncheckedDomains)
 
 	logger := log.Ctx(ctx)
+
+	unlock, err := p.lock(ctx, domainsName(domains))
+	if err != nil {
+		return types.Domain{}, nil, fmt.Errorf("unable to lock domains %v: %w", domains, err)
+	}
+	defer unlock()
+
+	// Another Traefik instance may have obtained the certificate while waiting for the lock.
+	if p.syncCertificates(ctx) && p.certExists(domains) {
+		logger.Debug().Msgf("Certificates for domains %+v obtained by another instance", domains)
+		return types.Domain{}, nil, nil
+	}
+
 	logger.Debug().Msgf("Loading ACME certificates %+v...", uncheckedDomains)
 
 	client, err := p.getClient()
@@ -653,6 +734,56 @@ func (p *Provider) resolveCertificate(ctx context.Context, domain types.Domain,
 	return domain, cert, nil
 }
 
+// ObtainCertificate returns the certificate of the given domain, obtained on demand if it is allowed.
+func (p *Provider) ObtainCertificate(ctx context.Context, domain string) (*tls.Certificate, error) {
+	if p.onDemand == nil {
+		return nil, errors.New("on-demand certificates are disabled")
+	}
+
+	return p.onDemand.getCertificate(ctx, domain)
+}
+
+// obtainOnDemand obtains the certificate of the given domain, and adds it to the configuration and the store.
+func (p *Provider) obtainOnDemand(ctx context.Context, domain string) (*tls.Certificate, error) {
+	logger := log.Ctx(ctx).With().Str(logs.ProviderName, p.ResolverName+".acme").Logger()
+	ctx = logger.WithContext(ctx)
+
+	dom, crt, err := p.resolveCertificate(ctx, types.Domain{Main: domain}, traefiktls.DefaultTLSStoreName)
+	if err != nil {
+		return nil, err
+	}
+
+	if crt != nil {
+		if err := p.addCertificateForDomain(dom, crt, traefiktls.DefaultTLSStoreName); err != nil {
+			logger.Error().Err(err).Strs("domains", dom.ToStrArray()).Msg("Error adding certificate for domain")
+		}
+
+		cert, err := tls.X509KeyPair(crt.Certificate, crt.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("loading certificate: %w", err)
+		}
+		return &cert, nil
+	}
+
+	// The certificate has already been obtained, by another Traefik instance or for a router, or is being obtained for a router.
+	p.certificatesMu.RLock()
+	defer p.certificatesMu.RUnlock()
+
+	for _, cert := range p.certificates {
+		if !isDomainAlreadyChecked(domain, cert.Domain.ToStrArray()) {
+			continue
+		}
+
+		tlsCert, err := tls.X509KeyPair(cert.Certificate.Certificate, cert.Key)
+		if err != nil {
+			return nil, fmt.Errorf("loading certificate: %w", err)
+		}
+		return &tlsCert, nil
+	}
+
+	return nil, fmt.Errorf("certificate for %s is being obtained", domain)
+}
+
 func (p *Provider) removeResolvingDomains(resolvingDomains []string) {
 	p.resolvingDomainsMutex.Lock()
 	defer p.resolvingDomainsMutex.Unlock()
@@ -690,6 +821,147 @@ func (p *Provider) addCertificateForDomain(domain types.Domain, crt *certificate
 	return p.Store.SaveCertificates(p.ResolverName, p.certificates)
 }
 
+// lock acquires the lock of the given name, when the store is shared between several Traefik instances.
+func (p *Provider) lock(ctx context.Context, name string) (func(), error) {
+	sharedStore, ok := p.Store.(SharedStore)
+	if !ok {
+		return func() {}, nil
+	}
+
+	return sharedStore.Lock(ctx, p.ResolverName, name)
+}
+
+// syncAccount loads the account registered by another Traefik instance, when the store is shared.
+func (p *Provider) syncAccount(ctx context.Context) {
+	if _, ok := p.Store.(SharedStore); !ok {
+		return
+	}
+
+	account, err := p.Store.GetAccount(p.ResolverName)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Unable to get ACME account")
+		return
+	}
+
+	if account != nil && account.Registration != nil && isAccountMatchingCaServer(ctx, account.Registration.URI, p.CAServer) {
+		p.account = account
+	}
+}
+
+// syncCertificates merges the certificates obtained or renewed by the other Traefik instances, when the store is shared.
+// It returns whether the store is shared.
+func (p *Provider) syncCertificates(ctx context.Context) bool {
+	if _, ok := p.Store.(SharedStore); !ok {
+		return false
+	}
+
+	certificates, err := p.Store.GetCertificates(p.ResolverName)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Unable to get ACME certificates")
+		return true
+	}
+
+	p.mergeCertificates(ctx, certificates)
+
+	return true
+}
+
+// mergeCertificates adds the given certificates, and replaces the current ones expiring before them.
+func (p *Provider) mergeCertificates(ctx context.Context, certificates []*CertAndStore) {
+	p.certificatesMu.Lock()
+	defer p.certificatesMu.Unlock()
+
+	var updated bool
+	for _, cert := range certificates {
+		var current *CertAndStore
+		for _, domainsCertificate := range p.certificates {
+			if reflect.DeepEqual(cert.Domain, domainsCertificate.Certificate.Domain) {
+				current = domainsCertificate
+				break
+			}
+		}
+
+		if current == nil {
+			p.certificates = append(p.certificates, cert)
+			updated = true
+			continue
+		}
+
+		if bytes.Equal(current.Certificate.Certificate, cert.Certificate.Certificate) {
+			continue
+		}
+
+		newCrt, err := getX509Certificate(ctx, &cert.Certificate)
+		if err != nil {
+			continue
+		}
+
+		crt, err := getX509Certificate(ctx, &current.Certificate)
+		if err == nil && !newCrt.NotAfter.After(crt.NotAfter) {
+			continue
+		}
+
+		current.Certificate = cert.Certificate
+		updated = true
+	}
+
+	if updated {
+		p.configurationChan <- p.buildMessage()
+	}
+}
+
+// certificateReplaced returns whether the certificate of the given domain is not the given one anymore.
+func (p *Provider) certificateReplaced(domain types.Domain, certificate []byte) bool {
+	p.certificatesMu.RLock()
+	defer p.certificatesMu.RUnlock()
+
+	for _, cert := range p.certificates {
+		if reflect.DeepEqual(domain, cert.Certificate.Domain) {
+			return !bytes.Equal(certificate, cert.Certificate.Certificate)
+		}
+	}
+
+	return false
+}
+
+// watchCertificates merges the certificates obtained or renewed by the other Traefik instances sharing the store.
+func (p *Provider) watchCertificates(ctx context.Context, sharedStore SharedStore) {
+	p.pool.GoCtx(func(ctxPool context.Context) {
+		operation := func() error {
+			certificates, err := sharedStore.WatchCertificates(ctxPool, p.ResolverName)
+			if err != nil {
+				return fmt.Errorf("failed to watch ACME certificates: %w", err)
+			}
+
+			for {
+				select {
+				case <-ctxPool.Done():
+					return nil
+				case certs, ok := <-certificates:
+					if !ok {
+						if ctxPool.Err() != nil {
+							return nil
+						}
+						return errors.New("the ACME certificates watch channel is closed")
+					}
+
+					p.mergeCertificates(ctx, certs)
+				}
+			}
+		}
+
+		notify := func(err error, time time.Duration) {
+			log.Ctx(ctx).Error().Err(err).Msgf("ACME certificates watch error, retrying in %s", time)
+		}
+
+		err := backoff.RetryNotify(safe.OperationWithRecover(operation),
+			backoff.WithContext(job.NewBackOff(backoff.NewExponentialBackOff()), ctxPool), notify)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Cannot watch ACME certificates")
+		}
+	})
+}
+
 // getCertificateRenewDurations returns renew durations calculated from the given certificatesDuration in hours.
 // The first (RenewPeriod) is the period before the end of the certificate duration, during which the certificate should be renewed.
 // The second (RenewInterval) is the interval between renew attempts.
@@ -792,53 +1064,159 @@ func (p *Provider) buildMessage() dynamic.Message {
 	return conf
 }
 
-func (p *Provider) renewCertificates(ctx context.Context, renewPeriod time.Duration) {
+// renewCertificates renews the certificates whose renewal is due, and returns the time of the next renewal check.
+// The renewal windows suggested by the CA server through ACME Renewal Information (ARI) take precedence over the default ones.
+func (p *Provider) renewCertificates(ctx context.Context, renewPeriod, renewInterval time.Duration) time.Time {
 	logger := log.Ctx(ctx)
 
-	logger.Info().Msg("Testing certificate renew...")
+	logger.Debug().Msg("Testing certificate renew...")
 
 	p.certificatesMu.RLock()
+	certificates := make([]*CertAndStore, len(p.certificates))
+	copy(certificates, p.certificates)
+	p.certificatesMu.RUnlock()
+
+	now := time.Now()
+	next := now.Add(renewInterval)
+
+	schedules := make(map[string]*renewalSchedule)
+	for _, cert := range certificates {
+		name := domainsName(cert.Domain.ToStrArray())
 
-	var certificates []*CertAndStore
-	for _, cert := range p.certificates {
-		crt, err := getX509Certificate(ctx, &cert.Certificate)
 		// If there's an error, we assume the cert is broken, and needs update
-		if err != nil || crt == nil || crt.NotAfter.Before(time.Now().Add(renewPeriod)) {
-			certificates = append(certificates, cert)
+		crt, _ := getX509Certificate(ctx, &cert.Certificate)
+
+		schedule, ok := p.renewalSchedules[name]
+		if !ok || schedule.fingerprint != sha256.Sum256(cert.Certificate.Certificate) {
+			schedule = newRenewalSchedule(&cert.Certificate, crt, renewPeriod, renewInterval)
+		}
+		schedules[name] = schedule
+
+		if crt != nil && p.renewalInfo != nil && !now.Before(schedule.renewalInfoAt) {
+			p.updateRenewalWindow(ctx, schedule, cert.Domain, crt, now)
+		}
+
+		if schedule.due(now) {
+			if err := p.renewCertificate(ctx, cert); err != nil {
+				logger.Error().Err(err).Msgf("Error renewing certificate from LE: %v", cert.Domain)
+				schedule.failed(now)
+			} else {
+				// The schedule of the renewed certificate is computed at the next check.
+				delete(schedules, name)
+				continue
+			}
+		}
+
+		if scheduleNext := schedule.next(); scheduleNext.Before(next) {
+			next = scheduleNext
 		}
 	}
 
-	p.certificatesMu.RUnlock()
+	p.renewalSchedules = schedules
 
-	for _, cert := range certificates {
-		client, err := p.getClient()
-		if err != nil {
-			logger.Info().Err(err).Msgf("Error renewing certificate from LE : %+v", cert.Domain)
-			continue
+	return next
+}
+
+// updateRenewalWindow updates the renewal window of the given certificate with the one suggested by the CA server.
+func (p *Provider) updateRenewalWindow(ctx context.Context, schedule *renewalSchedule, domain types.Domain, crt *x509.Certificate, now time.Time) {
+	logger := log.Ctx(ctx)
+
+	info, retryAfter, err := p.renewalInfo.getRenewalInfo(ctx, crt)
+	if err != nil {
+		if errors.Is(err, errRenewalInfoUnsupported) {
+			logger.Debug().Err(err).Msg("Using the default renewal window")
+		} else {
+			logger.Warn().Err(err).Msgf("Unable to get the renewal information of the certificate for %v", domain)
 		}
 
-		logger.Info().Msgf("Renewing certificate from LE : %+v", cert.Domain)
+		schedule.renewalInfoAt = now.Add(defaultRenewalInfoRetryAfter)
+		return
+	}
+
+	schedule.renewalInfoAt = now.Add(retryAfter)
+
+	if info.SuggestedWindow.Start.Equal(schedule.window.Start) && info.SuggestedWindow.End.Equal(schedule.window.End) {
+		return
+	}
+
+	schedule.setWindow(info.SuggestedWindow)
 
-		renewedCert, err := client.Certificate.Renew(certificate.Resource{
-			Domain:      cert.Domain.Main,
-			PrivateKey:  cert.Key,
-			Certificate: cert.Certificate.Certificate,
+	logger.Info().Msgf("The CA server suggests to renew the certificate for %v between %s and %s, renewal scheduled at %s",
+		domain, info.SuggestedWindow.Start, info.SuggestedWindow.End, schedule.renewAt)
+
+	if info.ExplanationURL != "" {
+		logger.Info().Msgf("Renewal explanation for %v: %s", domain, info.ExplanationURL)
+	}
+}
+
+func (p *Provider) renewCertificate(ctx context.Context, cert *CertAndStore) error {
+	logger := log.Ctx(ctx)
+
+	renewing := cert.Certificate
+
+	unlock, err := p.lock(ctx, domainsName(renewing.Domain.ToStrArray()))
+	if err != nil {
+		return fmt.Errorf("locking certificate renewal: %w", err)
+	}
+	defer unlock()
+
+	// Another Traefik instance may have renewed the certificate while waiting for the lock.
+	if p.syncCertificates(ctx) && p.certificateReplaced(renewing.Domain, renewing.Certificate) {
+		logger.Debug().Msgf("Certificate renewed by another instance: %+v", renewing.Domain)
+		return nil
+	}
+
+	client, err := p.getClient()
+	if err != nil {
+		return fmt.Errorf("getting ACME client: %w", err)
+	}
+
+	logger.Info().Msgf("Renewing certificate from LE : %+v", renewing.Domain)
+
+	renew := func() (*certificate.Resource, error) {
+		return client.Certificate.Renew(certificate.Resource{
+			Domain:      renewing.Domain.Main,
+			PrivateKey:  renewing.Key,
+			Certificate: renewing.Certificate,
 		}, true, ocspMustStaple, p.PreferredChain)
-		if err != nil {
-			logger.Error().Err(err).Msgf("Error renewing certificate from LE: %v", cert.Domain)
-			continue
-		}
+	}
 
-		if len(renewedCert.Certificate) == 0 || len(renewedCert.PrivateKey) == 0 {
-			logger.Error().Msgf("domains %v renew certificate with no value: %v", cert.Domain.ToStrArray(), cert)
-			continue
+	// The CA server supporting ARI is told which certificate is replaced by the renewal.
+	var replaces string
+	if p.orderReplaces != nil && p.renewalInfo != nil && p.renewalInfo.supported() {
+		if crt, errC := getX509Certificate(ctx, &renewing); errC == nil {
+			replaces, _ = certificateID(crt)
 		}
+	}
 
-		err = p.addCertificateForDomain(cert.Domain, renewedCert, cert.Store)
-		if err != nil {
-			logger.Error().Err(err).Msg("Error adding certificate for domain")
+	var renewedCert *certificate.Resource
+	if replaces != "" {
+		unset := p.orderReplaces.set(renewing.Domain.ToStrArray(), replaces)
+		renewedCert, err = renew()
+		unset()
+
+		var problem *acme.ProblemDetails
+		if errors.As(err, &problem) && problem.Type == acmeAlreadyReplacedErr {
+			logger.Debug().Err(err).Msgf("Certificate already replaced, renewing it without replacement: %+v", renewing.Domain)
+			renewedCert, err = renew()
 		}
+	} else {
+		renewedCert, err = renew()
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(renewedCert.Certificate) == 0 || len(renewedCert.PrivateKey) == 0 {
+		return fmt.Errorf("domains %v renew certificate with no value", renewing.Domain.ToStrArray())
+	}
+
+	err = p.addCertificateForDomain(renewing.Domain, renewedCert, cert.Store)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error adding certificate for domain")
 	}
+
+	return nil
 }
 
 // Get provided certificate which check a domains list (Main and SANs)
@@ -957,12 +1335,14 @@ func (p *Provider) certExists(validDomains []string) bool {
 	p.certificatesMu.RLock()
 	defer p.certificatesMu.RUnlock()
 
-	sort.Strings(validDomains)
+	sortedDomains := make([]string, len(validDomains))
+	copy(sortedDomains, validDomains)
+	sort.Strings(sortedDomains)
 
 	for _, cert := range p.certificates {
 		domains := cert.Certificate.Domain.ToStrArray()
 		sort.Strings(domains)
-		if reflect.DeepEqual(domains, validDomains) {
+		if reflect.DeepEqual(domains, sortedDomains) {
 			return true
 		}
 	}
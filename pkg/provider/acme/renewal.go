@@ -0,0 +1,95 @@
+package acme
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"math/rand"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// renewalSchedule holds the renewal schedule of a certificate.
+type renewalSchedule struct {
+	// fingerprint identifies the certificate for which the schedule has been computed.
+	fingerprint [sha256.Size]byte
+
+	window  renewalWindow
+	renewAt time.Time
+
+	// renewalInfoAt is the time after which the renewal information of the certificate should be polled.
+	renewalInfoAt time.Time
+
+	// retryAt is the time after which a failed renewal can be retried.
+	retryAt time.Time
+	backOff backoff.BackOff
+}
+
+// newRenewalSchedule returns the schedule of the given certificate, renewing it at a random time of the default renewal window,
+// which is the first half of the renew period before the end of the certificate.
+// A certificate which cannot be parsed is renewed immediately.
+func newRenewalSchedule(cert *Certificate, crt *x509.Certificate, renewPeriod, renewInterval time.Duration) *renewalSchedule {
+	exponentialBackOff := backoff.NewExponentialBackOff()
+	exponentialBackOff.InitialInterval = minDuration(time.Minute, renewInterval)
+	exponentialBackOff.MaxInterval = renewInterval
+	exponentialBackOff.MaxElapsedTime = 0
+	exponentialBackOff.Reset()
+
+	schedule := &renewalSchedule{
+		fingerprint: sha256.Sum256(cert.Certificate),
+		backOff:     exponentialBackOff,
+	}
+
+	if crt == nil {
+		return schedule
+	}
+
+	schedule.setWindow(renewalWindow{
+		Start: crt.NotAfter.Add(-renewPeriod),
+		End:   crt.NotAfter.Add(-renewPeriod / 2),
+	})
+
+	return schedule
+}
+
+// setWindow sets the renewal window, and picks a random renewal time within it,
+// to spread the renewals of the certificates, and of the Traefik instances, over the window.
+func (s *renewalSchedule) setWindow(window renewalWindow) {
+	s.window = window
+	s.renewAt = window.Start
+
+	if width := window.End.Sub(window.Start); width > 0 {
+		s.renewAt = window.Start.Add(time.Duration(rand.Int63n(int64(width))))
+	}
+}
+
+// due returns whether the certificate should be renewed at the given time.
+func (s *renewalSchedule) due(now time.Time) bool {
+	return !now.Before(s.renewAt) && !now.Before(s.retryAt)
+}
+
+// failed schedules the retry of the failed renewal, with an exponential backoff.
+func (s *renewalSchedule) failed(now time.Time) {
+	s.retryAt = now.Add(s.backOff.NextBackOff())
+}
+
+// next returns the next time at which the schedule requires an action.
+func (s *renewalSchedule) next() time.Time {
+	next := s.renewAt
+	if s.retryAt.After(next) {
+		next = s.retryAt
+	}
+
+	if !s.renewalInfoAt.IsZero() && s.renewalInfoAt.Before(next) {
+		next = s.renewalInfoAt
+	}
+
+	return next
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,358 @@
+package acme
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRenewalInfoRetryAfter is the delay before polling again the renewal information,
+	// when the CA server does not provide one.
+	defaultRenewalInfoRetryAfter = 6 * time.Hour
+	minRenewalInfoRetryAfter     = time.Minute
+	maxRenewalInfoRetryAfter     = 24 * time.Hour
+)
+
+// acmeAlreadyReplacedErr is the ACME error returned when the certificate replaced by an order has already been replaced.
+// https://www.rfc-editor.org/rfc/rfc9773.html#section-7.4
+const acmeAlreadyReplacedErr = "urn:ietf:params:acme:error:alreadyReplaced"
+
+// errRenewalInfoUnsupported is returned when the CA server directory does not advertise the renewalInfo resource.
+var errRenewalInfoUnsupported = errors.New("the CA server does not support ACME Renewal Information")
+
+// renewalWindow is a period during which a certificate should be renewed.
+type renewalWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// renewalInfo is the ACME Renewal Information (ARI) of a certificate.
+// https://www.rfc-editor.org/rfc/rfc9773.html#section-4.2
+type renewalInfo struct {
+	SuggestedWindow renewalWindow `json:"suggestedWindow"`
+	ExplanationURL  string        `json:"explanationURL,omitempty"`
+}
+
+// renewalInfoClient retrieves the ACME Renewal Information of the certificates from a CA server.
+type renewalInfoClient struct {
+	httpClient *http.Client
+	userAgent  string
+	caServer   string
+
+	renewalInfoURLMu sync.Mutex
+	renewalInfoURL   string
+}
+
+func newRenewalInfoClient(httpClient *http.Client, userAgent, caServer string) *renewalInfoClient {
+	return &renewalInfoClient{
+		httpClient: httpClient,
+		userAgent:  userAgent,
+		caServer:   caServer,
+	}
+}
+
+// getRenewalInfo returns the renewal information of the given certificate,
+// and the delay before which it should not be polled again.
+func (c *renewalInfoClient) getRenewalInfo(ctx context.Context, cert *x509.Certificate) (*renewalInfo, time.Duration, error) {
+	renewalInfoURL, err := c.getRenewalInfoURL(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	id, err := certificateID(cert)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := c.get(ctx, strings.TrimSuffix(renewalInfoURL, "/")+"/"+id)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("unexpected renewal information status code: %d", resp.StatusCode)
+	}
+
+	var info renewalInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, 0, fmt.Errorf("decoding renewal information: %w", err)
+	}
+
+	if info.SuggestedWindow.Start.IsZero() || !info.SuggestedWindow.End.After(info.SuggestedWindow.Start) {
+		return nil, 0, fmt.Errorf("invalid suggested renewal window: %s - %s", info.SuggestedWindow.Start, info.SuggestedWindow.End)
+	}
+
+	return &info, parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), nil
+}
+
+// supported returns whether the CA server is known to support ACME Renewal Information.
+func (c *renewalInfoClient) supported() bool {
+	c.renewalInfoURLMu.Lock()
+	defer c.renewalInfoURLMu.Unlock()
+
+	return c.renewalInfoURL != ""
+}
+
+// getRenewalInfoURL returns the URL of the renewalInfo resource advertised by the CA server directory.
+func (c *renewalInfoClient) getRenewalInfoURL(ctx context.Context) (string, error) {
+	c.renewalInfoURLMu.Lock()
+	defer c.renewalInfoURLMu.Unlock()
+
+	if c.renewalInfoURL != "" {
+		return c.renewalInfoURL, nil
+	}
+
+	resp, err := c.get(ctx, c.caServer)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected directory status code: %d", resp.StatusCode)
+	}
+
+	var directory struct {
+		RenewalInfo string `json:"renewalInfo"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&directory); err != nil {
+		return "", fmt.Errorf("decoding directory: %w", err)
+	}
+
+	if directory.RenewalInfo == "" {
+		return "", errRenewalInfoUnsupported
+	}
+
+	c.renewalInfoURL = directory.RenewalInfo
+
+	return c.renewalInfoURL, nil
+}
+
+func (c *renewalInfoClient) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// Drains the body to allow the connection reuse.
+		_, _ = io.Copy(io.Discard, resp.Body)
+	}
+
+	return resp, nil
+}
+
+// certificateID returns the unique identifier of the given certificate used by the renewalInfo resource.
+// https://www.rfc-editor.org/rfc/rfc9773.html#section-4.1
+func certificateID(cert *x509.Certificate) (string, error) {
+	if len(cert.AuthorityKeyId) == 0 {
+		return "", errors.New("the certificate has no authority key identifier")
+	}
+
+	if cert.SerialNumber == nil {
+		return "", errors.New("the certificate has no serial number")
+	}
+
+	// The serial number is encoded as the content of a DER integer, which is prefixed with a zero byte when it would be negative.
+	serial := cert.SerialNumber.Bytes()
+	if len(serial) == 0 || serial[0]&0x80 != 0 {
+		serial = append([]byte{0}, serial...)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(cert.AuthorityKeyId) + "." + base64.RawURLEncoding.EncodeToString(serial), nil
+}
+
+// orderReplaces holds the identifiers of the certificates being renewed, by domains,
+// which are sent in the replaces field of the new order requests.
+// https://www.rfc-editor.org/rfc/rfc9773.html#section-5
+type orderReplaces struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+func newOrderReplaces() *orderReplaces {
+	return &orderReplaces{ids: make(map[string]string)}
+}
+
+// set sets the identifier of the certificate replaced by the orders of the given domains, until the returned function is called.
+func (o *orderReplaces) set(domains []string, id string) func() {
+	name := domainsName(domains)
+
+	o.mu.Lock()
+	o.ids[name] = id
+	o.mu.Unlock()
+
+	return func() {
+		o.mu.Lock()
+		delete(o.ids, name)
+		o.mu.Unlock()
+	}
+}
+
+func (o *orderReplaces) get(domains []string) string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.ids[domainsName(domains)]
+}
+
+// transport returns a RoundTripper adding the replaces field to the new order requests signed with the given account key.
+// The ACME client does not support this field, so the requests are modified and signed again.
+func (o *orderReplaces) transport(next http.RoundTripper, accountKey crypto.PrivateKey) http.RoundTripper {
+	return &orderReplacesTransport{replaces: o, next: next, accountKey: accountKey}
+}
+
+type orderReplacesTransport struct {
+	replaces   *orderReplaces
+	next       http.RoundTripper
+	accountKey crypto.PrivateKey
+}
+
+// jws is a JSON Web Signature, in the flattened JSON serialization used by ACME.
+type jws struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+func (t *orderReplacesTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost || req.Body == nil || req.Body == http.NoBody {
+		return t.next.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	replaced, err := t.addReplaces(body)
+	if err != nil {
+		return nil, fmt.Errorf("adding replaces field to the order: %w", err)
+	}
+	if replaced != nil {
+		body = replaced
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// addReplaces returns the given request body with the replaces field added, and signed again,
+// or nil if the body is not a new order request of a certificate being renewed.
+func (t *orderReplacesTransport) addReplaces(body []byte) ([]byte, error) {
+	var signed jws
+	if err := json.Unmarshal(body, &signed); err != nil {
+		return nil, nil
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(signed.Payload)
+	if err != nil || len(payload) == 0 {
+		return nil, nil
+	}
+
+	var order map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &order); err != nil {
+		return nil, nil
+	}
+
+	var identifiers []struct {
+		Value string `json:"value"`
+	}
+	if _, ok := order["replaces"]; ok || json.Unmarshal(order["identifiers"], &identifiers) != nil || len(identifiers) == 0 {
+		return nil, nil
+	}
+
+	domains := make([]string, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		domains = append(domains, identifier.Value)
+	}
+
+	id := t.replaces.get(domains)
+	if id == "" {
+		return nil, nil
+	}
+
+	order["replaces"], err = json.Marshal(id)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err = json.Marshal(order)
+	if err != nil {
+		return nil, err
+	}
+
+	signed.Payload = base64.RawURLEncoding.EncodeToString(payload)
+
+	signature, err := t.sign([]byte(signed.Protected + "." + signed.Payload))
+	if err != nil {
+		return nil, err
+	}
+
+	signed.Signature = base64.RawURLEncoding.EncodeToString(signature)
+
+	return json.Marshal(signed)
+}
+
+// sign signs the given JWS signing input with the account key, which is always an RSA key.
+func (t *orderReplacesTransport) sign(input []byte) ([]byte, error) {
+	key, ok := t.accountKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported account key type %T", t.accountKey)
+	}
+
+	digest := sha256.Sum256(input)
+
+	return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+}
+
+// parseRetryAfter parses the given Retry-After header value,
+// and returns the delay clamped to reasonable values, as recommended by RFC 9773.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	delay := defaultRenewalInfoRetryAfter
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = date.Sub(now)
+	}
+
+	switch {
+	case delay < minRenewalInfoRetryAfter:
+		return minRenewalInfoRetryAfter
+	case delay > maxRenewalInfoRetryAfter:
+		return maxRenewalInfoRetryAfter
+	default:
+		return delay
+	}
+}
@@ -0,0 +1,261 @@
+package acme
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_certificateID(t *testing.T) {
+	// Example of https://www.rfc-editor.org/rfc/rfc9773.html#section-4.1
+	cert := &x509.Certificate{
+		AuthorityKeyId: []byte{0x69, 0x88, 0x5B, 0x6B, 0x87, 0x46, 0x40, 0x41, 0xE1, 0xB3, 0x7B, 0x84, 0x7B, 0xA0, 0xAE, 0x2C, 0xDE, 0x01, 0xC8, 0xD4},
+		SerialNumber:   big.NewInt(0x87654321),
+	}
+
+	id, err := certificateID(cert)
+	require.NoError(t, err)
+	assert.Equal(t, "aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE", id)
+
+	_, err = certificateID(&x509.Certificate{SerialNumber: big.NewInt(1)})
+	assert.Error(t, err)
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		value    string
+		expected time.Duration
+	}{
+		{
+			desc:     "empty",
+			expected: defaultRenewalInfoRetryAfter,
+		},
+		{
+			desc:     "invalid",
+			value:    "foo",
+			expected: defaultRenewalInfoRetryAfter,
+		},
+		{
+			desc:     "seconds",
+			value:    "3600",
+			expected: time.Hour,
+		},
+		{
+			desc:     "date",
+			value:    now.Add(2 * time.Hour).Format(http.TimeFormat),
+			expected: 2 * time.Hour,
+		},
+		{
+			desc:     "too short",
+			value:    "1",
+			expected: minRenewalInfoRetryAfter,
+		},
+		{
+			desc:     "too long",
+			value:    "604800",
+			expected: maxRenewalInfoRetryAfter,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, parseRetryAfter(test.value, now))
+		})
+	}
+}
+
+func TestRenewalInfoClient_getRenewalInfo(t *testing.T) {
+	window := renewalWindow{
+		Start: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	testCases := []struct {
+		desc               string
+		disableRenewalInfo bool
+		status             int
+		info               renewalInfo
+		expectedErr        bool
+	}{
+		{
+			desc:   "suggested window",
+			status: http.StatusOK,
+			info:   renewalInfo{SuggestedWindow: window, ExplanationURL: "https://example.com/incident"},
+		},
+		{
+			desc:               "renewal information not supported",
+			disableRenewalInfo: true,
+			expectedErr:        true,
+		},
+		{
+			desc:        "unknown certificate",
+			status:      http.StatusNotFound,
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid window",
+			status:      http.StatusOK,
+			info:        renewalInfo{SuggestedWindow: renewalWindow{Start: window.End, End: window.Start}},
+			expectedErr: true,
+		},
+	}
+
+	cert := &x509.Certificate{
+		AuthorityKeyId: []byte{0x01, 0x02},
+		SerialNumber:   big.NewInt(1),
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			mux.HandleFunc("/directory", func(rw http.ResponseWriter, req *http.Request) {
+				directory := map[string]string{"newNonce": server.URL + "/nonce"}
+				if !test.disableRenewalInfo {
+					directory["renewalInfo"] = server.URL + "/renewal-info/"
+				}
+				_ = json.NewEncoder(rw).Encode(directory)
+			})
+			mux.HandleFunc("/renewal-info/AQI.AQ", func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("Retry-After", "3600")
+				rw.WriteHeader(test.status)
+				_ = json.NewEncoder(rw).Encode(test.info)
+			})
+
+			client := newRenewalInfoClient(http.DefaultClient, "test", server.URL+"/directory")
+
+			info, retryAfter, err := client.getRenewalInfo(context.Background(), cert)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.info, *info)
+			assert.Equal(t, time.Hour, retryAfter)
+		})
+	}
+}
+
+func TestOrderReplaces_transport(t *testing.T) {
+	accountKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	replaces := newOrderReplaces()
+	t.Cleanup(replaces.set([]string{"foo.localhost", "bar.localhost"}, "aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE"))
+
+	testCases := []struct {
+		desc             string
+		payload          string
+		expectedReplaces string
+	}{
+		{
+			desc:             "new order of a certificate being renewed",
+			payload:          `{"identifiers":[{"type":"dns","value":"bar.localhost"},{"type":"dns","value":"foo.localhost"}]}`,
+			expectedReplaces: "aYhba4dGQEHhs3uEe6CuLN4ByNQ.AIdlQyE",
+		},
+		{
+			desc:    "new order of other domains",
+			payload: `{"identifiers":[{"type":"dns","value":"foo.localhost"}]}`,
+		},
+		{
+			desc:    "finalize request",
+			payload: `{"csr":"foo"}`,
+		},
+		{
+			desc: "POST-as-GET request",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var received jws
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(body)), req.ContentLength)
+				require.NoError(t, json.Unmarshal(body, &received))
+			}))
+			t.Cleanup(server.Close)
+
+			signed := signJWS(t, accountKey, server.URL, test.payload)
+
+			client := &http.Client{Transport: replaces.transport(http.DefaultTransport, accountKey)}
+			resp, err := client.Post(server.URL, "application/jose+json", strings.NewReader(signed))
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			// The received request is validly signed by the account key.
+			digest := sha256.Sum256([]byte(received.Protected + "." + received.Payload))
+			signature, err := base64.RawURLEncoding.DecodeString(received.Signature)
+			require.NoError(t, err)
+			require.NoError(t, rsa.VerifyPKCS1v15(&accountKey.PublicKey, crypto.SHA256, digest[:], signature))
+
+			payload, err := base64.RawURLEncoding.DecodeString(received.Payload)
+			require.NoError(t, err)
+
+			if test.expectedReplaces == "" {
+				assert.Equal(t, test.payload, string(payload))
+				return
+			}
+
+			var order struct {
+				Identifiers []map[string]string `json:"identifiers"`
+				Replaces    string              `json:"replaces"`
+			}
+			require.NoError(t, json.Unmarshal(payload, &order))
+			assert.Len(t, order.Identifiers, 2)
+			assert.Equal(t, test.expectedReplaces, order.Replaces)
+		})
+	}
+}
+
+// signJWS returns the given payload signed by the given key, in the flattened JSON serialization.
+func signJWS(t *testing.T, key *rsa.PrivateKey, url, payload string) string {
+	t.Helper()
+
+	protected := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","nonce":"foo","url":"` + url + `"}`))
+	encodedPayload := base64.RawURLEncoding.EncodeToString([]byte(payload))
+
+	digest := sha256.Sum256([]byte(protected + "." + encodedPayload))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	signed, err := json.Marshal(jws{
+		Protected: protected,
+		Payload:   encodedPayload,
+		Signature: base64.RawURLEncoding.EncodeToString(signature),
+	})
+	require.NoError(t, err)
+
+	return string(signed)
+}
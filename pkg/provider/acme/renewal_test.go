@@ -0,0 +1,149 @@
+package acme
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+func TestProvider_renewCertificates(t *testing.T) {
+	renewPeriod, renewInterval := getCertificateRenewDurations(3 * 30 * 24)
+
+	testCases := []struct {
+		desc            string
+		notAfter        time.Duration
+		suggestedWindow *renewalWindow
+		expectedRenewal bool
+	}{
+		{
+			desc:     "default window",
+			notAfter: 60 * 24 * time.Hour,
+		},
+		{
+			desc:            "default window reached",
+			notAfter:        10 * 24 * time.Hour,
+			expectedRenewal: true,
+		},
+		{
+			desc:     "suggested window",
+			notAfter: 60 * 24 * time.Hour,
+			suggestedWindow: &renewalWindow{
+				Start: time.Now().Add(10 * 24 * time.Hour).Truncate(time.Second),
+				End:   time.Now().Add(12 * 24 * time.Hour).Truncate(time.Second),
+			},
+		},
+		{
+			desc:     "suggested window in the past",
+			notAfter: 60 * 24 * time.Hour,
+			suggestedWindow: &renewalWindow{
+				Start: time.Now().Add(-2 * time.Hour),
+				End:   time.Now().Add(-time.Hour),
+			},
+			expectedRenewal: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var renewals atomic.Int32
+
+			mux := http.NewServeMux()
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			mux.HandleFunc("/directory", func(rw http.ResponseWriter, req *http.Request) {
+				directory := map[string]string{}
+				if test.suggestedWindow != nil {
+					directory["renewalInfo"] = server.URL + "/renewal-info"
+				}
+				_ = json.NewEncoder(rw).Encode(directory)
+			})
+			mux.HandleFunc("/renewal-info/", func(rw http.ResponseWriter, req *http.Request) {
+				_ = json.NewEncoder(rw).Encode(renewalInfo{SuggestedWindow: *test.suggestedWindow})
+			})
+			mux.HandleFunc("/new-order", func(rw http.ResponseWriter, req *http.Request) {
+				renewals.Add(1)
+				rw.WriteHeader(http.StatusInternalServerError)
+			})
+
+			cert := createCertAndStore(t, types.Domain{Main: "foo.localhost"}, time.Now().Add(test.notAfter))
+			crt, err := getX509Certificate(context.Background(), &cert.Certificate)
+			require.NoError(t, err)
+
+			p := &Provider{
+				Configuration: &Configuration{
+					CAServer: server.URL + "/directory",
+					KeyType:  "EC256",
+				},
+				ResolverName:      "test",
+				certificates:      []*CertAndStore{cert},
+				configurationChan: make(chan dynamic.Message, 10),
+				renewalInfo:       newRenewalInfoClient(http.DefaultClient, "test", server.URL+"/directory"),
+				renewalSchedules:  make(map[string]*renewalSchedule),
+			}
+
+			now := time.Now()
+			next := p.renewCertificates(context.Background(), renewPeriod, renewInterval)
+
+			schedule := p.renewalSchedules["foo.localhost"]
+			require.NotNil(t, schedule)
+
+			if test.expectedRenewal {
+				// The renewal fails, as the account cannot be registered, and is retried later.
+				assert.True(t, schedule.retryAt.After(now))
+				assert.False(t, next.After(schedule.retryAt))
+				return
+			}
+
+			assert.True(t, schedule.retryAt.IsZero())
+
+			window := renewalWindow{Start: crt.NotAfter.Add(-renewPeriod), End: crt.NotAfter.Add(-renewPeriod / 2)}
+			if test.suggestedWindow != nil {
+				window = *test.suggestedWindow
+			}
+
+			assert.True(t, window.Start.Equal(schedule.window.Start))
+			assert.True(t, window.End.Equal(schedule.window.End))
+			assert.False(t, schedule.renewAt.Before(window.Start))
+			assert.False(t, schedule.renewAt.After(window.End))
+			assert.False(t, next.After(now.Add(renewInterval)))
+		})
+	}
+}
+
+func TestRenewalSchedule_failed(t *testing.T) {
+	cert := createCertAndStore(t, types.Domain{Main: "foo.localhost"}, time.Now())
+
+	schedule := newRenewalSchedule(&cert.Certificate, nil, time.Hour, 10*time.Minute)
+
+	now := time.Now()
+	require.True(t, schedule.due(now))
+
+	var previous time.Duration
+	for i := 0; i < 10; i++ {
+		schedule.failed(now)
+		assert.False(t, schedule.due(now))
+
+		delay := schedule.retryAt.Sub(now)
+		assert.Greater(t, delay, time.Duration(0))
+		// The randomization of the backoff applies to the maximum interval.
+		assert.LessOrEqual(t, delay, 15*time.Minute)
+
+		previous = delay
+	}
+
+	// The delay reaches the maximum interval.
+	assert.Greater(t, previous, 4*time.Minute)
+}
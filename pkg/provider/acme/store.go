@@ -1,5 +1,7 @@
 package acme
 
+import "context"
+
 // StoredData represents the data managed by Store.
 type StoredData struct {
 	Account      *Account
@@ -13,3 +15,14 @@ type Store interface {
 	GetCertificates(string) ([]*CertAndStore, error)
 	SaveCertificates(string, []*CertAndStore) error
 }
+
+// SharedStore is a Store shared between several Traefik instances.
+type SharedStore interface {
+	Store
+
+	// Lock acquires the lock of the given name, for the given resolver, waiting until it is available.
+	// It returns the function releasing the lock.
+	Lock(ctx context.Context, resolverName, name string) (func(), error)
+	// WatchCertificates sends the certificates of the given resolver each time they are updated by any Traefik instance.
+	WatchCertificates(ctx context.Context, resolverName string) (<-chan []*CertAndStore, error)
+}
@@ -56,6 +56,9 @@ func Merge(ctx context.Context, configurations map[string]*dynamic.Configuration
 	routersUDPToDelete := map[string]struct{}{}
 	routersUDP := map[string][]string{}
 
+	middlewaresUDPToDelete := map[string]struct{}{}
+	middlewaresUDP := map[string][]string{}
+
 	middlewaresToDelete := map[string]struct{}{}
 	middlewares := map[string][]string{}
 
@@ -145,6 +148,13 @@ func Merge(ctx context.Context, configurations map[string]*dynamic.Configuration
 				middlewaresTCPToDelete[middlewareName] = struct{}{}
 			}
 		}
+
+		for middlewareName, middleware := range conf.UDP.Middlewares {
+			middlewaresUDP[middlewareName] = append(middlewaresUDP[middlewareName], root)
+			if !AddMiddlewareUDP(configuration.UDP, middlewareName, middleware) {
+				middlewaresUDPToDelete[middlewareName] = struct{}{}
+			}
+		}
 	}
 
 	for serviceName := range servicesToDelete {
@@ -217,6 +227,13 @@ func Merge(ctx context.Context, configurations map[string]*dynamic.Configuration
 		delete(configuration.TCP.Middlewares, middlewareName)
 	}
 
+	for middlewareName := range middlewaresUDPToDelete {
+		logger.Error().Str(logs.MiddlewareName, middlewareName).
+			Interface("configuration", middlewaresUDP[middlewareName]).
+			Msg("UDP Middleware defined multiple times with different configurations")
+		delete(configuration.UDP.Middlewares, middlewareName)
+	}
+
 	return configuration
 }
 
@@ -310,6 +327,20 @@ func AddRouterUDP(configuration *dynamic.UDPConfiguration, routerName string, ro
 	return reflect.DeepEqual(configuration.Routers[routerName], router)
 }
 
+// AddMiddlewareUDP adds a middleware to a configuration.
+func AddMiddlewareUDP(configuration *dynamic.UDPConfiguration, middlewareName string, middleware *dynamic.UDPMiddleware) bool {
+	if configuration.Middlewares == nil {
+		configuration.Middlewares = make(map[string]*dynamic.UDPMiddleware)
+	}
+
+	if _, ok := configuration.Middlewares[middlewareName]; !ok {
+		configuration.Middlewares[middlewareName] = middleware
+		return true
+	}
+
+	return reflect.DeepEqual(configuration.Middlewares[middlewareName], middleware)
+}
+
 // AddService adds a service to a configuration.
 func AddService(configuration *dynamic.HTTPConfiguration, serviceName string, service *dynamic.Service) bool {
 	if _, ok := configuration.Services[serviceName]; !ok {
@@ -355,8 +355,9 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 				Options: make(map[string]tls.Options),
 			},
 			UDP: &dynamic.UDPConfiguration{
-				Routers:  make(map[string]*dynamic.UDPRouter),
-				Services: make(map[string]*dynamic.UDPService),
+				Routers:     make(map[string]*dynamic.UDPRouter),
+				Services:    make(map[string]*dynamic.UDPService),
+				Middlewares: make(map[string]*dynamic.UDPMiddleware),
 			},
 		}
 	}
@@ -459,6 +460,14 @@ func (p *Provider) loadFileConfigFromDirectory(ctx context.Context, directory st
 			}
 		}
 
+		for name, conf := range c.UDP.Middlewares {
+			if _, exists := configuration.UDP.Middlewares[name]; exists {
+				logger.Warn().Str(logs.MiddlewareName, name).Msg("UDP middleware already configured, skipping")
+			} else {
+				configuration.UDP.Middlewares[name] = conf
+			}
+		}
+
 		for name, conf := range c.UDP.Services {
 			if _, exists := configuration.UDP.Services[name]; exists {
 				logger.Warn().Str(logs.ServiceName, name).Msg("UDP service already configured, skipping")
@@ -575,8 +584,9 @@ func (p *Provider) decodeConfiguration(filePath, content string) (*dynamic.Confi
 			Options: make(map[string]tls.Options),
 		},
 		UDP: &dynamic.UDPConfiguration{
-			Routers:  make(map[string]*dynamic.UDPRouter),
-			Services: make(map[string]*dynamic.UDPService),
+			Routers:     make(map[string]*dynamic.UDPRouter),
+			Services:    make(map[string]*dynamic.UDPService),
+			Middlewares: make(map[string]*dynamic.UDPMiddleware),
 		},
 	}
 
@@ -0,0 +1,145 @@
+package internalca
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+// backdate is the duration by which the validity of the issued certificates is backdated, to tolerate clock skews.
+const backdate = 5 * time.Minute
+
+// authority is a CA issuing certificates with its key.
+type authority struct {
+	cert   *x509.Certificate
+	signer crypto.Signer
+	// chain is the PEM encoded chain appended to the issued certificates, which excludes the self-signed root.
+	chain []byte
+}
+
+// loadAuthority loads the CA from the given PEM encoded certificate and key files.
+// The certificate file can contain the intermediate certificates of the CA, after the CA certificate.
+func loadAuthority(certFile, keyFile string) (*authority, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificate: %w", err)
+	}
+
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA key: %w", err)
+	}
+
+	return newAuthority(certPEM, keyPEM)
+}
+
+func newAuthority(certPEM, keyPEM []byte) (*authority, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("loading CA key pair: %w", err)
+	}
+
+	signer, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported CA key type")
+	}
+
+	ca := &authority{signer: signer}
+
+	for i, der := range pair.Certificate {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("parsing CA certificate: %w", err)
+		}
+
+		if i == 0 {
+			if !cert.IsCA {
+				return nil, errors.New("the certificate is not a CA certificate")
+			}
+
+			ca.cert = cert
+		}
+
+		// The self-signed root is already trusted by the clients.
+		if bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil {
+			continue
+		}
+
+		ca.chain = append(ca.chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+
+	return ca, nil
+}
+
+// issue issues a certificate for the given subject, valid during the given duration.
+// The names which are IP addresses are added as IP SANs, the other ones as DNS SANs.
+// It returns the PEM encoded certificate chain and key.
+func (a *authority) issue(commonName string, names []string, extKeyUsage x509.ExtKeyUsage, duration time.Duration) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generating key: %w", err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("generating serial number: %w", err)
+	}
+
+	now := time.Now()
+
+	// The issued certificates cannot outlive the CA.
+	notAfter := now.Add(duration)
+	if notAfter.After(a.cert.NotAfter) {
+		notAfter = a.cert.NotAfter
+	}
+
+	if !notAfter.After(now) {
+		return nil, nil, fmt.Errorf("the CA certificate expired at %s", a.cert.NotAfter)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now.Add(-backdate),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{extKeyUsage},
+		BasicConstraintsValid: true,
+	}
+
+	for _, name := range names {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+			continue
+		}
+
+		template.DNSNames = append(template.DNSNames, name)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, key.Public(), a.signer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("signing certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding key: %w", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	certPEM = append(certPEM, a.chain...)
+
+	return certPEM, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), nil
+}
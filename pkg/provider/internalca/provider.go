@@ -0,0 +1,454 @@
+package internalca
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/muxer/http"
+	"github.com/traefik/traefik/v3/pkg/muxer/tcp"
+	"github.com/traefik/traefik/v3/pkg/safe"
+	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+// maxRenewInterval is the maximum interval between two checks of the certificates renewal.
+const maxRenewInterval = time.Hour
+
+// Configuration holds the internal CA certificates resolver configuration.
+type Configuration struct {
+	CertFile             string          `description:"CA certificate file (PEM), optionally followed by its intermediate certificates." json:"certFile,omitempty" toml:"certFile,omitempty" yaml:"certFile,omitempty"`
+	KeyFile              string          `description:"CA private key file (PEM)." json:"keyFile,omitempty" toml:"keyFile,omitempty" yaml:"keyFile,omitempty" loggable:"false"`
+	CertificatesDuration ptypes.Duration `description:"Duration of the issued certificates." json:"certificatesDuration,omitempty" toml:"certificatesDuration,omitempty" yaml:"certificatesDuration,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values.
+func (c *Configuration) SetDefaults() {
+	c.CertificatesDuration = ptypes.Duration(24 * time.Hour)
+}
+
+// issuedCertificate is a certificate issued by the internal CA.
+type issuedCertificate struct {
+	domains     []string
+	certificate traefiktls.Certificate
+	renewAt     time.Time
+}
+
+// Provider is the internal CA certificates provider implementation.
+// It receives configuration updates from Traefik core, issues the certificates of the routers referencing the resolver with a local CA,
+// and sends back to Traefik core a configuration updated with the certificates.
+// It also issues the client certificates of the servers transports referencing the resolver.
+type Provider struct {
+	*Configuration
+	ResolverName string
+
+	dynConfigs  chan dynamic.Configuration // updates from Traefik core
+	dynMessages chan<- dynamic.Message     // update to Traefik core
+	caChanged   chan struct{}
+
+	caMu sync.RWMutex
+	ca   *authority
+
+	// certs holds the issued server certificates, by domain set. It is only accessed by the provider loop.
+	certs map[string]*issuedCertificate
+
+	clientCertsMu sync.Mutex
+	clientCerts   map[string]*tls.Certificate
+}
+
+// ThrottleDuration implements the aggregator.throttled interface, in order to
+// ensure that this provider is unthrottled.
+func (p *Provider) ThrottleDuration() time.Duration {
+	return 0
+}
+
+// Init implements the provider.Provider interface.
+func (p *Provider) Init() error {
+	if p.Configuration == nil || p.CertFile == "" || p.KeyFile == "" {
+		return errors.New("the CA certificate and key files are required")
+	}
+
+	if p.CertificatesDuration <= 0 {
+		return errors.New("the certificates duration must be positive")
+	}
+
+	ca, err := loadAuthority(p.CertFile, p.KeyFile)
+	if err != nil {
+		return fmt.Errorf("loading CA: %w", err)
+	}
+
+	p.ca = ca
+	p.dynConfigs = make(chan dynamic.Configuration)
+	p.caChanged = make(chan struct{}, 1)
+	p.certs = make(map[string]*issuedCertificate)
+	p.clientCerts = make(map[string]*tls.Certificate)
+
+	return nil
+}
+
+// HandleConfigUpdate hands out a configuration update to the provider.
+func (p *Provider) HandleConfigUpdate(cfg dynamic.Configuration) {
+	p.dynConfigs <- cfg
+}
+
+// Provide starts the provider, which will henceforth send configuration
+// updates on dynMessages.
+func (p *Provider) Provide(dynMessages chan<- dynamic.Message, pool *safe.Pool) error {
+	p.dynMessages = dynMessages
+
+	logger := log.With().Str(logs.ProviderName, p.ResolverName+".internalca").Logger()
+
+	files, err := traefiktls.NewFileWatcher()
+	if err != nil {
+		return err
+	}
+	files.Watch([]string{p.CertFile, p.KeyFile})
+
+	pool.GoCtx(func(ctx context.Context) {
+		files.Run(logger.WithContext(ctx), func() {
+			select {
+			case p.caChanged <- struct{}{}:
+			default:
+			}
+		})
+	})
+
+	pool.GoCtx(func(ctx context.Context) {
+		p.loop(logger.WithContext(ctx))
+	})
+
+	return nil
+}
+
+// GetClientCertificate returns the client certificate presented by the given servers transport,
+// which is issued on demand, and renewed when it reaches two thirds of its lifetime.
+func (p *Provider) GetClientCertificate(name string) (*tls.Certificate, error) {
+	p.clientCertsMu.Lock()
+	defer p.clientCertsMu.Unlock()
+
+	if cert, ok := p.clientCerts[name]; ok && time.Now().Before(renewalTime(cert.Leaf)) {
+		return cert, nil
+	}
+
+	// The provider suffix of the servers transport name is not part of the identity.
+	commonName, _, _ := strings.Cut(name, "@")
+
+	p.caMu.RLock()
+	certPEM, keyPEM, err := p.ca.issue(commonName, nil, x509.ExtKeyUsageClientAuth, time.Duration(p.CertificatesDuration))
+	p.caMu.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("issuing client certificate for %s: %w", name, err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("loading client certificate for %s: %w", name, err)
+	}
+
+	cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("parsing client certificate for %s: %w", name, err)
+	}
+
+	p.clientCerts[name] = &cert
+
+	return &cert, nil
+}
+
+// loop issues the certificates of the new domains, renews the certificates before they expire,
+// and reissues all the certificates when the CA changes.
+func (p *Provider) loop(ctx context.Context) {
+	renewInterval := time.Duration(p.CertificatesDuration) / 10
+	if renewInterval > maxRenewInterval {
+		renewInterval = maxRenewInterval
+	}
+
+	ticker := time.NewTicker(renewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case cfg := <-p.dynConfigs:
+			domains := p.findDomains(ctx, cfg)
+			issued := p.issueCertificates(ctx, domains, false)
+			purged := p.purgeUnusedCerts(domains)
+
+			if !issued && !purged {
+				continue
+			}
+
+			p.sendDynamicConfig()
+
+		case <-ticker.C:
+			if p.renewCertificates(ctx, false) {
+				p.sendDynamicConfig()
+			}
+
+		case <-p.caChanged:
+			if err := p.reloadCA(); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("Unable to reload the CA, the previous one is kept")
+				continue
+			}
+
+			log.Ctx(ctx).Info().Msg("CA reloaded, reissuing the certificates")
+
+			if p.renewCertificates(ctx, true) {
+				p.sendDynamicConfig()
+			}
+		}
+	}
+}
+
+// reloadCA reloads the CA from its files, and drops the client certificates issued by the previous one.
+func (p *Provider) reloadCA() error {
+	ca, err := loadAuthority(p.CertFile, p.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	p.caMu.Lock()
+	p.ca = ca
+	p.caMu.Unlock()
+
+	p.clientCertsMu.Lock()
+	p.clientCerts = make(map[string]*tls.Certificate)
+	p.clientCertsMu.Unlock()
+
+	return nil
+}
+
+// findDomains goes through the given dynamic.Configuration and returns the domain sets of the routers using the resolver.
+func (p *Provider) findDomains(ctx context.Context, cfg dynamic.Configuration) [][]string {
+	logger := log.Ctx(ctx)
+
+	var domains []types.Domain
+
+	if cfg.HTTP != nil {
+		for _, router := range cfg.HTTP.Routers {
+			if router.TLS == nil || router.TLS.CertResolver != p.ResolverName {
+				continue
+			}
+
+			if len(router.TLS.Domains) > 0 {
+				domains = append(domains, router.TLS.Domains...)
+				continue
+			}
+
+			parsedDomains, err := http.ParseDomains(router.Rule)
+			if err != nil {
+				logger.Error().Err(err).Msg("Unable to parse HTTP router domains")
+				continue
+			}
+
+			var domain types.Domain
+			domain.Set(parsedDomains)
+			domains = append(domains, domain)
+		}
+	}
+
+	if cfg.TCP != nil {
+		for _, router := range cfg.TCP.Routers {
+			if router.TLS == nil || router.TLS.CertResolver != p.ResolverName {
+				continue
+			}
+
+			if len(router.TLS.Domains) > 0 {
+				domains = append(domains, router.TLS.Domains...)
+				continue
+			}
+
+			parsedDomains, err := tcp.ParseHostSNI(router.Rule)
+			if err != nil {
+				logger.Error().Err(err).Msg("Unable to parse TCP router domains")
+				continue
+			}
+
+			var domain types.Domain
+			domain.Set(parsedDomains)
+			domains = append(domains, domain)
+		}
+	}
+
+	seen := make(map[string]struct{})
+
+	var domainSets [][]string
+	for _, domain := range domains {
+		names := sanitizeNames(domain.ToStrArray())
+		if len(names) == 0 {
+			continue
+		}
+
+		key := domainsKey(names)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		domainSets = append(domainSets, names)
+	}
+
+	return domainSets
+}
+
+// issueCertificates issues the certificates of the given domain sets which have not been issued yet,
+// and returns whether some certificates have been issued.
+func (p *Provider) issueCertificates(ctx context.Context, domainSets [][]string, force bool) bool {
+	var issued bool
+	for _, domains := range domainSets {
+		if _, ok := p.certs[domainsKey(domains)]; ok && !force {
+			continue
+		}
+
+		if p.issueCertificate(ctx, domains) {
+			issued = true
+		}
+	}
+
+	return issued
+}
+
+// renewCertificates renews the certificates reaching two thirds of their lifetime, or all of them if force is set,
+// and returns whether some certificates have been renewed.
+func (p *Provider) renewCertificates(ctx context.Context, force bool) bool {
+	now := time.Now()
+
+	var domainSets [][]string
+	for _, cert := range p.certs {
+		if force || !now.Before(cert.renewAt) {
+			domainSets = append(domainSets, cert.domains)
+		}
+	}
+
+	return p.issueCertificates(ctx, domainSets, true)
+}
+
+// issueCertificate issues the certificate of the given domains, and returns whether it succeeded.
+// On failure, the previously issued certificate, if any, is kept.
+func (p *Provider) issueCertificate(ctx context.Context, domains []string) bool {
+	logger := log.Ctx(ctx)
+
+	p.caMu.RLock()
+	certPEM, keyPEM, err := p.ca.issue(domains[0], domains, x509.ExtKeyUsageServerAuth, time.Duration(p.CertificatesDuration))
+	p.caMu.RUnlock()
+	if err != nil {
+		logger.Error().Err(err).Strs("domains", domains).Msg("Unable to issue certificate")
+		return false
+	}
+
+	cert := traefiktls.Certificate{
+		CertFile: traefiktls.FileOrContent(certPEM),
+		KeyFile:  traefiktls.FileOrContent(keyPEM),
+	}
+
+	tlsCert, err := cert.GetCertificateFromBytes()
+	if err != nil {
+		logger.Error().Err(err).Strs("domains", domains).Msg("Unable to load issued certificate")
+		return false
+	}
+
+	leaf, err := x509.ParseCertificate(tlsCert.Certificate[0])
+	if err != nil {
+		logger.Error().Err(err).Strs("domains", domains).Msg("Unable to parse issued certificate")
+		return false
+	}
+
+	logger.Debug().Strs("domains", domains).Msgf("Issued certificate valid until %s", leaf.NotAfter)
+
+	p.certs[domainsKey(domains)] = &issuedCertificate{
+		domains:     domains,
+		certificate: cert,
+		renewAt:     renewalTime(leaf),
+	}
+
+	return true
+}
+
+// purgeUnusedCerts removes the certificates which are not used anymore,
+// and returns whether some certificates have been removed.
+func (p *Provider) purgeUnusedCerts(domainSets [][]string) bool {
+	used := make(map[string]struct{}, len(domainSets))
+	for _, domains := range domainSets {
+		used[domainsKey(domains)] = struct{}{}
+	}
+
+	var purged bool
+	for key := range p.certs {
+		if _, ok := used[key]; !ok {
+			delete(p.certs, key)
+			purged = true
+		}
+	}
+
+	return purged
+}
+
+// sendDynamicConfig sends a dynamic.Message with the dynamic.Configuration
+// containing the issued certificates.
+func (p *Provider) sendDynamicConfig() {
+	// The certificates are sorted to make sure that two identical sets do not trigger another configuration update.
+	var keys []string
+	for key := range p.certs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var certs []*traefiktls.CertAndStores
+	for _, key := range keys {
+		// Only the default store is supported.
+		certs = append(certs, &traefiktls.CertAndStores{
+			Stores:      []string{traefiktls.DefaultTLSStoreName},
+			Certificate: p.certs[key].certificate,
+		})
+	}
+
+	p.dynMessages <- dynamic.Message{
+		ProviderName: p.ResolverName + ".internalca",
+		Configuration: &dynamic.Configuration{
+			TLS: &dynamic.TLSConfiguration{Certificates: certs},
+		},
+	}
+}
+
+// sanitizeNames lowercases the given names, and removes the empty and duplicated ones.
+func sanitizeNames(names []string) []string {
+	seen := make(map[string]struct{})
+
+	var sanitized []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		sanitized = append(sanitized, name)
+	}
+
+	return sanitized
+}
+
+func domainsKey(domains []string) string {
+	return strings.Join(domains, ",")
+}
+
+// renewalTime returns the time at which the given certificate reaches two thirds of its lifetime.
+func renewalTime(cert *x509.Certificate) time.Time {
+	return cert.NotBefore.Add(cert.NotAfter.Sub(cert.NotBefore) * 2 / 3)
+}
@@ -0,0 +1,325 @@
+package internalca
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/safe"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
+
+func TestProvider_findDomains(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config dynamic.Configuration
+		want   [][]string
+	}{
+		{
+			desc: "ignore routers with non-matching resolver",
+			config: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"foo": {
+							Rule: "Host(`foo.internal`)",
+							TLS:  &dynamic.RouterTLSConfig{CertResolver: "bar"},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "domains from HTTP and TCP router rules",
+			config: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"foo": {
+							Rule: "Host(`foo.internal`) || Host(`Bar.local`)",
+							TLS:  &dynamic.RouterTLSConfig{CertResolver: "ca"},
+						},
+					},
+				},
+				TCP: &dynamic.TCPConfiguration{
+					Routers: map[string]*dynamic.TCPRouter{
+						"foo": {
+							Rule: "HostSNI(`db.internal`)",
+							TLS:  &dynamic.RouterTCPTLSConfig{CertResolver: "ca"},
+						},
+					},
+				},
+			},
+			want: [][]string{{"db.internal"}, {"foo.internal", "bar.local"}},
+		},
+		{
+			desc: "explicit domains and duplicates",
+			config: dynamic.Configuration{
+				HTTP: &dynamic.HTTPConfiguration{
+					Routers: map[string]*dynamic.Router{
+						"foo": {
+							Rule: "Host(`foo.internal`)",
+							TLS: &dynamic.RouterTLSConfig{
+								CertResolver: "ca",
+								Domains:      []types.Domain{{Main: "foo.internal", SANs: []string{"10.0.0.1", "foo.internal"}}},
+							},
+						},
+						"bar": {
+							Rule: "Host(`foo.internal`) || Host(`10.0.0.1`)",
+							TLS:  &dynamic.RouterTLSConfig{CertResolver: "ca"},
+						},
+					},
+				},
+			},
+			want: [][]string{{"foo.internal", "10.0.0.1"}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			p := Provider{ResolverName: "ca"}
+
+			got := p.findDomains(context.Background(), test.config)
+			sort.Slice(got, func(i, j int) bool { return domainsKey(got[i]) < domainsKey(got[j]) })
+
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestProvider_issuesCertificates(t *testing.T) {
+	certFile, keyFile := createCA(t, time.Now().Add(time.Hour))
+
+	p := &Provider{
+		Configuration: &Configuration{
+			CertFile:             certFile,
+			KeyFile:              keyFile,
+			CertificatesDuration: ptypes.Duration(24 * time.Hour),
+		},
+		ResolverName: "ca",
+	}
+	require.NoError(t, p.Init())
+
+	dynMessages := make(chan dynamic.Message, 1)
+	pool := safe.NewPool(context.Background())
+	t.Cleanup(pool.Stop)
+
+	require.NoError(t, p.Provide(dynMessages, pool))
+
+	p.HandleConfigUpdate(dynamic.Configuration{
+		HTTP: &dynamic.HTTPConfiguration{
+			Routers: map[string]*dynamic.Router{
+				"foo": {
+					Rule: "Host(`foo.internal`)",
+					TLS:  &dynamic.RouterTLSConfig{CertResolver: "ca"},
+				},
+			},
+		},
+	})
+
+	var msg dynamic.Message
+	select {
+	case msg = <-dynMessages:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no configuration received")
+	}
+
+	assert.Equal(t, "ca.internalca", msg.ProviderName)
+	require.Len(t, msg.Configuration.TLS.Certificates, 1)
+
+	cert, err := msg.Configuration.TLS.Certificates[0].Certificate.GetCertificateFromBytes()
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"foo.internal"}, leaf.DNSNames)
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, leaf.ExtKeyUsage)
+	// The certificate lifetime is capped to the CA one.
+	assert.False(t, leaf.NotAfter.After(p.ca.cert.NotAfter))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(p.ca.cert)
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "foo.internal", Roots: roots})
+	require.NoError(t, err)
+}
+
+func TestProvider_renewCertificates(t *testing.T) {
+	certFile, keyFile := createCA(t, time.Now().Add(24*time.Hour))
+
+	p := &Provider{
+		Configuration: &Configuration{
+			CertFile:             certFile,
+			KeyFile:              keyFile,
+			CertificatesDuration: ptypes.Duration(time.Hour),
+		},
+		ResolverName: "ca",
+	}
+	require.NoError(t, p.Init())
+
+	ctx := context.Background()
+
+	require.True(t, p.issueCertificates(ctx, [][]string{{"foo.internal"}, {"bar.internal"}}, false))
+	assert.False(t, p.issueCertificates(ctx, [][]string{{"foo.internal"}}, false))
+	assert.False(t, p.renewCertificates(ctx, false))
+
+	p.certs["foo.internal"].renewAt = time.Now().Add(-time.Minute)
+	previous := p.certs["foo.internal"].certificate
+
+	assert.True(t, p.renewCertificates(ctx, false))
+	assert.NotEqual(t, previous, p.certs["foo.internal"].certificate)
+
+	assert.True(t, p.purgeUnusedCerts([][]string{{"foo.internal"}}))
+	assert.Len(t, p.certs, 1)
+}
+
+func TestProvider_GetClientCertificate(t *testing.T) {
+	certFile, keyFile := createCA(t, time.Now().Add(24*time.Hour))
+
+	p := &Provider{
+		Configuration: &Configuration{
+			CertFile:             certFile,
+			KeyFile:              keyFile,
+			CertificatesDuration: ptypes.Duration(time.Hour),
+		},
+		ResolverName: "ca",
+	}
+	require.NoError(t, p.Init())
+
+	cert, err := p.GetClientCertificate("backend@file")
+	require.NoError(t, err)
+
+	assert.Equal(t, "backend", cert.Leaf.Subject.CommonName)
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, cert.Leaf.ExtKeyUsage)
+
+	cached, err := p.GetClientCertificate("backend@file")
+	require.NoError(t, err)
+	assert.Same(t, cert, cached)
+
+	// The client certificates are reissued with the reloaded CA.
+	createCAFiles(t, certFile, keyFile, time.Now().Add(24*time.Hour))
+	require.NoError(t, p.reloadCA())
+
+	reissued, err := p.GetClientCertificate("backend@file")
+	require.NoError(t, err)
+	assert.NotSame(t, cert, reissued)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(p.ca.cert)
+	_, err = reissued.Leaf.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	require.NoError(t, err)
+}
+
+func TestProvider_Init(t *testing.T) {
+	dir := t.TempDir()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "not a CA"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certFile, keyFile := writeKeyPair(t, filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), template, key)
+
+	p := &Provider{
+		Configuration: &Configuration{
+			CertFile:             certFile,
+			KeyFile:              keyFile,
+			CertificatesDuration: ptypes.Duration(time.Hour),
+		},
+	}
+	assert.ErrorContains(t, p.Init(), "not a CA certificate")
+
+	p = &Provider{Configuration: &Configuration{KeyFile: keyFile}}
+	assert.Error(t, p.Init())
+}
+
+func TestAuthority_issue(t *testing.T) {
+	// The certificates issued by a CA expiring soon are valid until the CA expiry.
+	caNotAfter := time.Now().Add(time.Minute).Truncate(time.Second)
+
+	ca, err := loadAuthority(createCA(t, caNotAfter))
+	require.NoError(t, err)
+
+	certPEM, _, err := ca.issue("foo", []string{"foo.localhost"}, x509.ExtKeyUsageServerAuth, time.Hour)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode(certPEM)
+	require.NotNil(t, block)
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	assert.True(t, cert.NotAfter.Equal(caNotAfter))
+	assert.True(t, cert.NotAfter.After(cert.NotBefore))
+
+	// No certificate is issued by an expired CA.
+	expiredCA, err := loadAuthority(createCA(t, time.Now().Add(-time.Minute)))
+	require.NoError(t, err)
+
+	_, _, err = expiredCA.issue("foo", []string{"foo.localhost"}, x509.ExtKeyUsageServerAuth, time.Hour)
+	assert.ErrorContains(t, err, "the CA certificate expired")
+}
+
+// createCA creates a self-signed CA valid until the given time, and returns its certificate and key files.
+func createCA(t *testing.T, notAfter time.Time) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "ca.pem"), filepath.Join(dir, "ca-key.pem")
+
+	createCAFiles(t, certFile, keyFile, notAfter)
+
+	return certFile, keyFile
+}
+
+func createCAFiles(t *testing.T, certFile, keyFile string, notAfter time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Internal CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	writeKeyPair(t, certFile, keyFile, template, key)
+}
+
+func writeKeyPair(t *testing.T, certFile, keyFile string, template *x509.Certificate, key *ecdsa.PrivateKey) (string, string) {
+	t.Helper()
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certFile, keyFile
+}
@@ -12,7 +12,8 @@ type storeWrapper struct {
 }
 
 func (s *storeWrapper) Put(ctx context.Context, key string, value []byte, options *store.WriteOptions) error {
-	log.Debug().Msgf("Put: %s %s", key, string(value))
+	// The value is not logged, as it may be sensitive (e.g. ACME private keys).
+	log.Debug().Msgf("Put: %s", key)
 
 	if s.Store == nil {
 		return nil
@@ -93,7 +94,7 @@ func (s *storeWrapper) DeleteTree(ctx context.Context, directory string) error {
 }
 
 func (s *storeWrapper) AtomicPut(ctx context.Context, key string, value []byte, previous *store.KVPair, options *store.WriteOptions) (bool, *store.KVPair, error) {
-	log.Debug().Msgf("AtomicPut: %s %s %v", key, string(value), previous)
+	log.Debug().Msgf("AtomicPut: %s", key)
 
 	if s.Store == nil {
 		return true, nil, nil
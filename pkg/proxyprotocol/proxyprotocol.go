@@ -0,0 +1,243 @@
+package proxyprotocol
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/pires/go-proxyproto/tlvparse"
+)
+
+// headerConn is a connection which received a PROXY protocol header, such as *proxyproto.Conn.
+type headerConn interface {
+	ProxyHeader() *proxyproto.Header
+}
+
+// wrapperConn is a connection wrapping another connection, such as *tls.Conn.
+type wrapperConn interface {
+	NetConn() net.Conn
+}
+
+// HeaderFromConn returns the PROXY protocol header received with the given connection, or nil.
+// The connections wrapping another connection with a NetConn method are unwrapped to find the header.
+func HeaderFromConn(conn net.Conn) *proxyproto.Header {
+	for conn != nil {
+		if hConn, ok := conn.(headerConn); ok {
+			return hConn.ProxyHeader()
+		}
+
+		wConn, ok := conn.(wrapperConn)
+		if !ok {
+			return nil
+		}
+
+		conn = wConn.NetConn()
+	}
+
+	return nil
+}
+
+// TLVs returns the TLVs to send in the PROXY protocol v2 header of a connection forwarded from the given connection.
+// They are the TLVs of the PROXY protocol header received with the connection,
+// and, if the connection is a TLS connection terminated by Traefik,
+// the ALPN, authority and SSL TLVs describing its TLS session, instead of the received ones.
+// The TLS handshake of the connection is completed if it has not been yet.
+func TLVs(conn net.Conn) ([]proxyproto.TLV, error) {
+	var tlsConn *tls.Conn
+	for c := conn; c != nil; {
+		if typedConn, ok := c.(*tls.Conn); ok {
+			tlsConn = typedConn
+			break
+		}
+
+		wConn, ok := c.(wrapperConn)
+		if !ok {
+			break
+		}
+
+		c = wConn.NetConn()
+	}
+
+	var tlvs []proxyproto.TLV
+
+	if header := HeaderFromConn(conn); header != nil {
+		received, err := header.TLVs()
+		if err != nil {
+			return nil, fmt.Errorf("reading received TLVs: %w", err)
+		}
+
+		for _, tlv := range received {
+			switch tlv.Type {
+			case proxyproto.PP2_TYPE_CRC32C:
+				// The checksum of the received header does not match the forwarded one.
+				continue
+			case proxyproto.PP2_TYPE_ALPN, proxyproto.PP2_TYPE_AUTHORITY, proxyproto.PP2_TYPE_SSL:
+				if tlsConn != nil {
+					continue
+				}
+			}
+
+			tlvs = append(tlvs, tlv)
+		}
+	}
+
+	if tlsConn == nil {
+		return tlvs, nil
+	}
+
+	if err := tlsConn.Handshake(); err != nil {
+		return nil, fmt.Errorf("TLS handshake: %w", err)
+	}
+
+	tlsTLVs, err := sessionTLVs(tlsConn.ConnectionState())
+	if err != nil {
+		return nil, err
+	}
+
+	return append(tlvs, tlsTLVs...), nil
+}
+
+// sessionTLVs returns the ALPN, authority and SSL TLVs describing the given TLS session.
+func sessionTLVs(state tls.ConnectionState) ([]proxyproto.TLV, error) {
+	var tlvs []proxyproto.TLV
+
+	if state.NegotiatedProtocol != "" {
+		tlvs = append(tlvs, proxyproto.TLV{Type: proxyproto.PP2_TYPE_ALPN, Value: []byte(state.NegotiatedProtocol)})
+	}
+
+	if state.ServerName != "" {
+		tlvs = append(tlvs, proxyproto.TLV{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte(state.ServerName)})
+	}
+
+	ssl := tlvparse.PP2SSL{
+		Client: tlvparse.PP2_BITFIELD_CLIENT_SSL,
+		// Verify is zero only when the client presented a certificate, and it was verified.
+		Verify: 1,
+		TLV: []proxyproto.TLV{
+			{Type: proxyproto.PP2_SUBTYPE_SSL_VERSION, Value: []byte(versionName(state.Version))},
+			{Type: proxyproto.PP2_SUBTYPE_SSL_CIPHER, Value: []byte(tls.CipherSuiteName(state.CipherSuite))},
+		},
+	}
+
+	if len(state.PeerCertificates) > 0 {
+		ssl.Client |= tlvparse.PP2_BITFIELD_CLIENT_CERT_CONN | tlvparse.PP2_BITFIELD_CLIENT_CERT_SESS
+
+		if len(state.VerifiedChains) > 0 {
+			ssl.Verify = 0
+		}
+
+		if cn := state.PeerCertificates[0].Subject.CommonName; cn != "" {
+			ssl.TLV = append(ssl.TLV, proxyproto.TLV{Type: proxyproto.PP2_SUBTYPE_SSL_CN, Value: []byte(cn)})
+		}
+	}
+
+	sslTLV, err := ssl.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("marshaling SSL TLV: %w", err)
+	}
+
+	return append(tlvs, sslTLV), nil
+}
+
+// versionName returns the name of the given TLS version, as sent by HAProxy.
+func versionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS13:
+		return "TLSv1.3"
+	case tls.VersionTLS12:
+		return "TLSv1.2"
+	case tls.VersionTLS11:
+		return "TLSv1.1"
+	case tls.VersionTLS10:
+		return "TLSv1"
+	default:
+		return fmt.Sprintf("0x%04x", version)
+	}
+}
+
+// ParseTLVType parses a TLV type, given as a decimal or hexadecimal (0x prefixed) number.
+func ParseTLVType(value string) (proxyproto.PP2Type, error) {
+	tlvType, err := strconv.ParseUint(value, 0, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid TLV type %q: must be a number between 0 and 255", value)
+	}
+
+	return proxyproto.PP2Type(tlvType), nil
+}
+
+// TLVValue returns the value of the TLV of the given type in the given header, and whether the TLV is present.
+// The AWS VPC endpoint ID, Azure private endpoint link ID and GCP Private Service Connect ID TLVs are decoded,
+// other TLV values are returned as is when they are printable, and hexadecimal encoded otherwise.
+func TLVValue(header *proxyproto.Header, tlvType proxyproto.PP2Type) (string, bool) {
+	if header == nil {
+		return "", false
+	}
+
+	tlvs, err := header.TLVs()
+	if err != nil {
+		return "", false
+	}
+
+	for _, tlv := range tlvs {
+		if tlv.Type != tlvType {
+			continue
+		}
+
+		switch {
+		case tlvparse.IsAWSVPCEndpointID(tlv):
+			if id, err := tlvparse.AWSVPCEndpointID(tlv); err == nil {
+				return id, true
+			}
+
+		case tlv.Type == tlvparse.PP2_TYPE_AZURE:
+			if id, ok := tlvparse.FindAzurePrivateEndpointLinkID([]proxyproto.TLV{tlv}); ok {
+				return strconv.FormatUint(uint64(id), 10), true
+			}
+
+		case tlv.Type == tlvparse.PP2_TYPE_GCP && len(tlv.Value) == 8:
+			return strconv.FormatUint(binary.BigEndian.Uint64(tlv.Value), 10), true
+		}
+
+		if isPrintable(tlv.Value) {
+			return string(tlv.Value), true
+		}
+
+		return hex.EncodeToString(tlv.Value), true
+	}
+
+	return "", false
+}
+
+func isPrintable(value []byte) bool {
+	if !utf8.Valid(value) {
+		return false
+	}
+
+	for _, r := range string(value) {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type contextKey struct{}
+
+// WithHeader returns a copy of the given context, holding the given PROXY protocol header.
+func WithHeader(ctx context.Context, header *proxyproto.Header) context.Context {
+	return context.WithValue(ctx, contextKey{}, header)
+}
+
+// HeaderFromContext returns the PROXY protocol header held by the given context, or nil.
+func HeaderFromContext(ctx context.Context) *proxyproto.Header {
+	header, _ := ctx.Value(contextKey{}).(*proxyproto.Header)
+	return header
+}
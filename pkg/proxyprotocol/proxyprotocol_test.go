@@ -0,0 +1,171 @@
+package proxyprotocol
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/pires/go-proxyproto/tlvparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLVValue(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		tlvs          []proxyproto.TLV
+		tlvType       proxyproto.PP2Type
+		expected      string
+		expectedFound bool
+	}{
+		{
+			desc:    "missing TLV",
+			tlvs:    []proxyproto.TLV{{Type: 0xE5, Value: []byte("foo")}},
+			tlvType: 0xE6,
+		},
+		{
+			desc:          "printable value",
+			tlvs:          []proxyproto.TLV{{Type: 0xE5, Value: []byte("foo")}},
+			tlvType:       0xE5,
+			expected:      "foo",
+			expectedFound: true,
+		},
+		{
+			desc:          "binary value",
+			tlvs:          []proxyproto.TLV{{Type: 0xE5, Value: []byte{0x00, 0xff}}},
+			tlvType:       0xE5,
+			expected:      "00ff",
+			expectedFound: true,
+		},
+		{
+			desc:          "AWS VPC endpoint ID",
+			tlvs:          []proxyproto.TLV{{Type: 0xEA, Value: append([]byte{0x01}, "vpce-08d2bf15fac5001c9"...)}},
+			tlvType:       0xEA,
+			expected:      "vpce-08d2bf15fac5001c9",
+			expectedFound: true,
+		},
+		{
+			desc:          "Azure private endpoint link ID",
+			tlvs:          []proxyproto.TLV{{Type: 0xEE, Value: []byte{0x01, 0x01, 0x00, 0x00, 0x00}}},
+			tlvType:       0xEE,
+			expected:      "1",
+			expectedFound: true,
+		},
+		{
+			desc:          "GCP Private Service Connect ID",
+			tlvs:          []proxyproto.TLV{{Type: 0xE0, Value: []byte{0, 0, 0, 0, 0, 0, 0x01, 0x00}}},
+			tlvType:       0xE0,
+			expected:      "256",
+			expectedFound: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			header := &proxyproto.Header{}
+			require.NoError(t, header.SetTLVs(test.tlvs))
+
+			value, found := TLVValue(header, test.tlvType)
+			assert.Equal(t, test.expectedFound, found)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func TestParseTLVType(t *testing.T) {
+	tlvType, err := ParseTLVType("0xEA")
+	require.NoError(t, err)
+	assert.Equal(t, proxyproto.PP2Type(0xEA), tlvType)
+
+	tlvType, err = ParseTLVType("234")
+	require.NoError(t, err)
+	assert.Equal(t, proxyproto.PP2Type(0xEA), tlvType)
+
+	_, err = ParseTLVType("256")
+	assert.Error(t, err)
+
+	_, err = ParseTLVType("foo")
+	assert.Error(t, err)
+}
+
+func TestHeaderFromConn(t *testing.T) {
+	header := &proxyproto.Header{Version: 2}
+
+	conn := wrappingConn{Conn: wrappingConn{Conn: headerConnMock{header: header}}}
+	assert.Same(t, header, HeaderFromConn(conn))
+
+	assert.Nil(t, HeaderFromConn(wrappingConn{Conn: &net.TCPConn{}}))
+}
+
+func TestTLVs(t *testing.T) {
+	header := &proxyproto.Header{}
+	err := header.SetTLVs([]proxyproto.TLV{
+		{Type: 0xE5, Value: []byte("foo")},
+		{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("upstream.localhost")},
+		{Type: proxyproto.PP2_TYPE_CRC32C, Value: []byte{0, 0, 0, 0}},
+	})
+	require.NoError(t, err)
+
+	tlvs, err := TLVs(wrappingConn{Conn: headerConnMock{header: header}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []proxyproto.TLV{
+		{Type: 0xE5, Value: []byte("foo")},
+		{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("upstream.localhost")},
+	}, tlvs)
+}
+
+func TestSessionTLVs(t *testing.T) {
+	tlvs, err := sessionTLVs(tls.ConnectionState{
+		Version:            tls.VersionTLS12,
+		CipherSuite:        tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		NegotiatedProtocol: "h2",
+		ServerName:         "foo.localhost",
+		PeerCertificates:   []*x509.Certificate{{Subject: pkix.Name{CommonName: "client"}}},
+		VerifiedChains:     [][]*x509.Certificate{{}},
+	})
+	require.NoError(t, err)
+	require.Len(t, tlvs, 3)
+
+	assert.Equal(t, proxyproto.TLV{Type: proxyproto.PP2_TYPE_ALPN, Value: []byte("h2")}, tlvs[0])
+	assert.Equal(t, proxyproto.TLV{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("foo.localhost")}, tlvs[1])
+
+	ssl, err := tlvparse.SSL(tlvs[2])
+	require.NoError(t, err)
+
+	assert.True(t, ssl.ClientSSL())
+	assert.True(t, ssl.ClientCertConn())
+	assert.True(t, ssl.Verified())
+
+	version, ok := ssl.SSLVersion()
+	assert.True(t, ok)
+	assert.Equal(t, "TLSv1.2", version)
+
+	cn, ok := ssl.ClientCN()
+	assert.True(t, ok)
+	assert.Equal(t, "client", cn)
+}
+
+type headerConnMock struct {
+	net.Conn
+
+	header *proxyproto.Header
+}
+
+func (c headerConnMock) ProxyHeader() *proxyproto.Header {
+	return c.header
+}
+
+type wrappingConn struct {
+	net.Conn
+}
+
+func (c wrappingConn) NetConn() net.Conn {
+	return c.Conn
+}
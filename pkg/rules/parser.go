@@ -115,6 +115,28 @@ func (tree *Tree) ParseMatchers(matchers []string) []string {
 	}
 }
 
+// String returns the Tree as a rule.
+func (tree *Tree) String() string {
+	switch tree.Matcher {
+	case and:
+		return fmt.Sprintf("(%s && %s)", tree.RuleLeft, tree.RuleRight)
+	case or:
+		return fmt.Sprintf("(%s || %s)", tree.RuleLeft, tree.RuleRight)
+	}
+
+	values := make([]string, 0, len(tree.Value))
+	for _, value := range tree.Value {
+		values = append(values, "`"+value+"`")
+	}
+
+	var not string
+	if tree.Not {
+		not = "!"
+	}
+
+	return fmt.Sprintf("%s%s(%s)", not, tree.Matcher, strings.Join(values, ", "))
+}
+
 // CheckRule validates the given rule.
 func CheckRule(rule *Tree) error {
 	if len(rule.Value) == 0 {
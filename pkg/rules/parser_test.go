@@ -299,3 +299,36 @@ func checkEquivalence(t *testing.T, expected *testTree, actual *Tree) {
 		assert.NoError(t, CheckRule(actual))
 	}
 }
+
+func TestTree_String(t *testing.T) {
+	parser, err := NewParser([]string{"Host", "Path", "Header"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		rule     string
+		expected string
+	}{
+		{
+			rule:     "Host(`example.com`)",
+			expected: "Host(`example.com`)",
+		},
+		{
+			rule:     "host(`example.com`) && !Header(`X-Foo`, `bar`)",
+			expected: "(Host(`example.com`) && !Header(`X-Foo`, `bar`))",
+		},
+		{
+			rule:     "!(Host(`example.com`) || Path(`/foo`))",
+			expected: "(!Host(`example.com`) && !Path(`/foo`))",
+		},
+	}
+
+	for _, test := range testCases {
+		parse, err := parser.Parse(test.rule)
+		require.NoError(t, err)
+
+		treeBuilder, ok := parse.(TreeBuilder)
+		require.True(t, ok)
+
+		assert.Equal(t, test.expected, treeBuilder().String(), test.rule)
+	}
+}
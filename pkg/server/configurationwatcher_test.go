@@ -101,8 +101,9 @@ func TestNewConfigurationWatcher(t *testing.T) {
 				Stores: map[string]tls.Store{},
 			},
 			UDP: &dynamic.UDPConfiguration{
-				Routers:  map[string]*dynamic.UDPRouter{},
-				Services: map[string]*dynamic.UDPService{},
+				Routers:     map[string]*dynamic.UDPRouter{},
+				Services:    map[string]*dynamic.UDPService{},
+				Middlewares: map[string]*dynamic.UDPMiddleware{},
 			},
 		}
 
@@ -231,8 +232,9 @@ func TestIgnoreTransientConfiguration(t *testing.T) {
 			ServersTransports: map[string]*dynamic.TCPServersTransport{},
 		},
 		UDP: &dynamic.UDPConfiguration{
-			Routers:  map[string]*dynamic.UDPRouter{},
-			Services: map[string]*dynamic.UDPService{},
+			Routers:     map[string]*dynamic.UDPRouter{},
+			Services:    map[string]*dynamic.UDPService{},
+			Middlewares: map[string]*dynamic.UDPMiddleware{},
 		},
 		TLS: &dynamic.TLSConfiguration{
 			Options: map[string]tls.Options{
@@ -400,8 +402,9 @@ func TestListenProvidersDoesNotSkipFlappingConfiguration(t *testing.T) {
 			ServersTransports: map[string]*dynamic.TCPServersTransport{},
 		},
 		UDP: &dynamic.UDPConfiguration{
-			Routers:  map[string]*dynamic.UDPRouter{},
-			Services: map[string]*dynamic.UDPService{},
+			Routers:     map[string]*dynamic.UDPRouter{},
+			Services:    map[string]*dynamic.UDPService{},
+			Middlewares: map[string]*dynamic.UDPMiddleware{},
 		},
 		TLS: &dynamic.TLSConfiguration{
 			Options: map[string]tls.Options{
@@ -490,8 +493,9 @@ func TestListenProvidersIgnoreSameConfig(t *testing.T) {
 			ServersTransports: map[string]*dynamic.TCPServersTransport{},
 		},
 		UDP: &dynamic.UDPConfiguration{
-			Routers:  map[string]*dynamic.UDPRouter{},
-			Services: map[string]*dynamic.UDPService{},
+			Routers:     map[string]*dynamic.UDPRouter{},
+			Services:    map[string]*dynamic.UDPService{},
+			Middlewares: map[string]*dynamic.UDPMiddleware{},
 		},
 		TLS: &dynamic.TLSConfiguration{
 			Options: map[string]tls.Options{
@@ -625,8 +629,9 @@ func TestListenProvidersIgnoreIntermediateConfigs(t *testing.T) {
 			ServersTransports: map[string]*dynamic.TCPServersTransport{},
 		},
 		UDP: &dynamic.UDPConfiguration{
-			Routers:  map[string]*dynamic.UDPRouter{},
-			Services: map[string]*dynamic.UDPService{},
+			Routers:     map[string]*dynamic.UDPRouter{},
+			Services:    map[string]*dynamic.UDPService{},
+			Middlewares: map[string]*dynamic.UDPMiddleware{},
 		},
 		TLS: &dynamic.TLSConfiguration{
 			Options: map[string]tls.Options{
@@ -699,8 +704,9 @@ func TestListenProvidersPublishesConfigForEachProvider(t *testing.T) {
 			Stores: map[string]tls.Store{},
 		},
 		UDP: &dynamic.UDPConfiguration{
-			Routers:  map[string]*dynamic.UDPRouter{},
-			Services: map[string]*dynamic.UDPService{},
+			Routers:     map[string]*dynamic.UDPRouter{},
+			Services:    map[string]*dynamic.UDPService{},
+			Middlewares: map[string]*dynamic.UDPMiddleware{},
 		},
 	}
 
@@ -15,6 +15,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/middlewares/buffering"
 	"github.com/traefik/traefik/v3/pkg/middlewares/chain"
 	"github.com/traefik/traefik/v3/pkg/middlewares/circuitbreaker"
+	"github.com/traefik/traefik/v3/pkg/middlewares/coalesce"
 	"github.com/traefik/traefik/v3/pkg/middlewares/compress"
 	"github.com/traefik/traefik/v3/pkg/middlewares/contenttype"
 	"github.com/traefik/traefik/v3/pkg/middlewares/customerrors"
@@ -22,6 +23,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/middlewares/headers"
 	"github.com/traefik/traefik/v3/pkg/middlewares/inflightreq"
 	"github.com/traefik/traefik/v3/pkg/middlewares/ipallowlist"
+	"github.com/traefik/traefik/v3/pkg/middlewares/mtlsauthz"
 	"github.com/traefik/traefik/v3/pkg/middlewares/passtlsclientcert"
 	"github.com/traefik/traefik/v3/pkg/middlewares/ratelimiter"
 	"github.com/traefik/traefik/v3/pkg/middlewares/redirect"
@@ -38,6 +40,7 @@ type middlewareStackType int
 
 const (
 	middlewareStackKey middlewareStackType = iota
+	serviceNameKey
 )
 
 // Builder the middleware builder.
@@ -45,6 +48,7 @@ type Builder struct {
 	configs        map[string]*runtime.MiddlewareInfo
 	pluginBuilder  PluginsBuilder
 	serviceBuilder serviceBuilder
+	retryBudgets   *retry.Budgets
 }
 
 type serviceBuilder interface {
@@ -52,8 +56,14 @@ type serviceBuilder interface {
 }
 
 // NewBuilder creates a new Builder.
-func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder) *Builder {
-	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder}
+// The retry budgets, which can be nil, outlive the builder so that they are kept across configuration reloads.
+func NewBuilder(configs map[string]*runtime.MiddlewareInfo, serviceBuilder serviceBuilder, pluginBuilder PluginsBuilder, retryBudgets *retry.Budgets) *Builder {
+	return &Builder{configs: configs, serviceBuilder: serviceBuilder, pluginBuilder: pluginBuilder, retryBudgets: retryBudgets}
+}
+
+// WithServiceName returns a context holding the name of the service targeted by the middlewares built with it.
+func WithServiceName(ctx context.Context, serviceName string) context.Context {
+	return context.WithValue(ctx, serviceNameKey, serviceName)
 }
 
 // BuildChain creates a middleware chain.
@@ -166,6 +176,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// Coalesce
+	if config.Coalesce != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return coalesce.New(ctx, next, *config.Coalesce, middlewareName)
+		}
+	}
+
 	// Compress
 	if config.Compress != nil {
 		if middleware != nil {
@@ -256,6 +276,16 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// MTLSAuthz
+	if config.MTLSAuthz != nil {
+		if middleware != nil {
+			return nil, badConf
+		}
+		middleware = func(next http.Handler) (http.Handler, error) {
+			return mtlsauthz.New(ctx, next, *config.MTLSAuthz, middlewareName)
+		}
+	}
+
 	// PassTLSClientCert
 	if config.PassTLSClientCert != nil {
 		if middleware != nil {
@@ -321,9 +351,14 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		if middleware != nil {
 			return nil, badConf
 		}
+		var budget *retry.Budget
+		if serviceName, ok := ctx.Value(serviceNameKey).(string); ok && b.retryBudgets != nil {
+			budget = b.retryBudgets.Get(serviceName)
+		}
+
 		middleware = func(next http.Handler) (http.Handler, error) {
 			// TODO missing metrics / accessLog
-			return retry.New(ctx, next, *config.Retry, retry.Listeners{}, middlewareName)
+			return retry.New(ctx, next, *config.Retry, retry.Listeners{}, budget, middlewareName)
 		}
 	}
 
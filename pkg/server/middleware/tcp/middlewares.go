@@ -8,6 +8,10 @@ import (
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/inflightconn"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ipallowlist"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ipdenylist"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/mtlsauthz"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ratelimiter"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/timeout"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 )
@@ -101,6 +105,34 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// IPDenyList
+	if config.IPDenyList != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return ipdenylist.New(ctx, next, *config.IPDenyList, middlewareName)
+		}
+	}
+
+	// MTLSAuthz
+	if config.MTLSAuthz != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return mtlsauthz.New(ctx, next, *config.MTLSAuthz, middlewareName)
+		}
+	}
+
+	// RateLimit
+	if config.RateLimit != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return ratelimiter.New(ctx, next, *config.RateLimit, middlewareName)
+		}
+	}
+
+	// Timeout
+	if config.Timeout != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return timeout.New(ctx, next, *config.Timeout, middlewareName)
+		}
+	}
+
 	if middleware == nil {
 		return nil, fmt.Errorf("invalid middleware %q configuration: invalid middleware type or middleware does not exist", middlewareName)
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
 	metricsMiddle "github.com/traefik/traefik/v3/pkg/middlewares/metrics"
 	"github.com/traefik/traefik/v3/pkg/middlewares/recovery"
+	"github.com/traefik/traefik/v3/pkg/middlewares/timeout"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tracing"
 	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
@@ -39,6 +40,8 @@ type Manager struct {
 	chainBuilder       *middleware.ChainBuilder
 	conf               *runtime.Configuration
 	tlsManager         *tls.Manager
+	// muxers holds the muxers of the entry points, by TLS state and entry point name.
+	muxers map[bool]map[string]*httpmuxer.Muxer
 }
 
 // NewManager creates a new Manager.
@@ -51,9 +54,16 @@ func NewManager(conf *runtime.Configuration, serviceManager serviceManager, midd
 		chainBuilder:       chainBuilder,
 		conf:               conf,
 		tlsManager:         tlsManager,
+		muxers:             make(map[bool]map[string]*httpmuxer.Muxer),
 	}
 }
 
+// GetMuxers returns the muxers of the entry points built with the given TLS state, by entry point name.
+// The muxers must not be modified.
+func (m *Manager) GetMuxers(tls bool) map[string]*httpmuxer.Muxer {
+	return m.muxers[tls]
+}
+
 func (m *Manager) getHTTPRouters(ctx context.Context, entryPoints []string, tls bool) map[string]map[string]*runtime.RouterInfo {
 	if m.conf != nil {
 		return m.conf.GetRoutersByEntryPoints(ctx, entryPoints, tls)
@@ -65,6 +75,8 @@ func (m *Manager) getHTTPRouters(ctx context.Context, entryPoints []string, tls
 // BuildHandlers Builds handler for all entry points.
 func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, tls bool) map[string]http.Handler {
 	entryPointHandlers := make(map[string]http.Handler)
+	muxers := make(map[string]*httpmuxer.Muxer)
+	m.muxers[tls] = muxers
 
 	for entryPointName, routers := range m.getHTTPRouters(rootCtx, entryPoints, tls) {
 		entryPointName := entryPointName
@@ -72,12 +84,14 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, t
 		logger := log.Ctx(rootCtx).With().Str(logs.EntryPointName, entryPointName).Logger()
 		ctx := logger.WithContext(rootCtx)
 
-		handler, err := m.buildEntryPointHandler(ctx, routers)
+		handler, muxer, err := m.buildEntryPointHandler(ctx, routers)
 		if err != nil {
 			logger.Error().Err(err).Send()
 			continue
 		}
 
+		muxers[entryPointName] = muxer
+
 		handlerWithAccessLog, err := alice.New(func(next http.Handler) (http.Handler, error) {
 			return accesslog.NewFieldHandler(next, logs.EntryPointName, entryPointName, accesslog.AddOriginFields), nil
 		}).Then(handler)
@@ -109,10 +123,10 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string, t
 	return entryPointHandlers
 }
 
-func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.RouterInfo) (http.Handler, error) {
+func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.RouterInfo) (http.Handler, *httpmuxer.Muxer, error) {
 	muxer, err := httpmuxer.NewMuxer()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	for routerName, routerConfig := range configs {
@@ -130,7 +144,7 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 			continue
 		}
 
-		if err = muxer.AddRoute(routerConfig.Rule, routerConfig.Priority, handler); err != nil {
+		if err = muxer.AddRoute(routerName, routerConfig.Rule, routerConfig.Priority, handler); err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error().Err(err).Send()
 			continue
@@ -142,7 +156,12 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 		return recovery.New(ctx, next)
 	})
 
-	return chain.Then(muxer)
+	handler, err := chain.Then(muxer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return handler, muxer, nil
 }
 
 func (m *Manager) buildRouterHandler(ctx context.Context, routerName string, routerConfig *runtime.RouterInfo) (http.Handler, error) {
@@ -195,7 +214,9 @@ func (m *Manager) buildHTTPHandler(ctx context.Context, router *runtime.RouterIn
 		return nil, err
 	}
 
-	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
+	serviceName := provider.GetQualifiedName(ctx, router.Service)
+
+	mHandler := m.middlewaresBuilder.BuildChain(middleware.WithServiceName(ctx, serviceName), router.Middlewares)
 
 	tHandler := func(next http.Handler) (http.Handler, error) {
 		return tracing.NewForwarder(ctx, routerName, router.Service, next), nil
@@ -204,7 +225,13 @@ func (m *Manager) buildHTTPHandler(ctx context.Context, router *runtime.RouterIn
 	chain := alice.New()
 
 	if m.metricsRegistry != nil && m.metricsRegistry.IsRouterEnabled() {
-		chain = chain.Append(metricsMiddle.WrapRouterHandler(ctx, m.metricsRegistry, routerName, provider.GetQualifiedName(ctx, router.Service)))
+		chain = chain.Append(metricsMiddle.WrapRouterHandler(ctx, m.metricsRegistry, routerName, serviceName))
+	}
+
+	if router.Timeouts != nil {
+		chain = chain.Append(func(next http.Handler) (http.Handler, error) {
+			return timeout.New(next, *router.Timeouts), nil
+		})
 	}
 
 	return chain.Extend(*mHandler).Append(tHandler).Then(sHandler)
@@ -0,0 +1,239 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// ldapStartTLSOID is the name of the LDAP StartTLS extended operation.
+const ldapStartTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// maxLDAPStartTLSRequestSize is the maximum size of an LDAP StartTLS extended request, controls included.
+const maxLDAPStartTLSRequestSize = 256
+
+const (
+	berSequence            = 0x30
+	berInteger             = 0x02
+	berEnumerated          = 0x0a
+	berOctetString         = 0x04
+	ldapExtendedRequest    = 0x77 // [APPLICATION 23]
+	ldapExtendedResponse   = 0x78 // [APPLICATION 24]
+	ldapRequestName        = 0x80 // [0]
+	ldapResponseName       = 0x8a // [10]
+	ldapResultCodeSuccess  = 0x00
+	ldapResultCodeOpsError = 0x01
+)
+
+// isLDAPStartTLS determines whether the buffer starts with an LDAP StartTLS extended request,
+// and returns it if it is the case.
+func isLDAPStartTLS(br *bufio.Reader) ([]byte, error) {
+	// Peek the message incrementally, to prevent blocking on peek
+	// if the underlying conn does not send enough bytes.
+	peeked, err := peekLDAP(br, 1)
+	if err != nil || peeked[0] != berSequence {
+		return nil, err
+	}
+
+	if peeked, err = peekLDAP(br, 2); err != nil {
+		return nil, err
+	}
+
+	if peeked[1]&0x80 != 0 {
+		// Long form, in which the low bits are the number of bytes of the length.
+		size := 2 + int(peeked[1]&0x7f)
+		if size > 4 {
+			return nil, nil
+		}
+
+		if peeked, err = peekLDAP(br, size); err != nil {
+			return nil, err
+		}
+	}
+
+	_, length, size, ok := berHeader(peeked)
+	if !ok || size+length > maxLDAPStartTLSRequestSize {
+		return nil, nil
+	}
+
+	message, err := peekLDAP(br, size+length)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := parseLDAPStartTLSRequest(message); !ok {
+		return nil, nil
+	}
+
+	return append([]byte(nil), message...), nil
+}
+
+func peekLDAP(br *bufio.Reader, n int) ([]byte, error) {
+	peeked, err := br.Peek(n)
+	if err != nil {
+		var opErr *net.OpError
+		if !errors.Is(err, io.EOF) && (!errors.As(err, &opErr) || opErr.Timeout()) {
+			log.Error().Err(err).Msg("Error while Peeking LDAP message")
+		}
+		return nil, err
+	}
+
+	return peeked, nil
+}
+
+// serveLDAP serves a connection with an LDAP client requesting the StartTLS extended operation.
+// It handles TCP TLS routing, after accepting to start the TLS session.
+func (r *Router) serveLDAP(conn tcp.WriteCloser, request []byte) {
+	messageID, _ := parseLDAPStartTLSRequest(request)
+
+	br := bufio.NewReader(conn)
+	if _, err := br.Discard(len(request)); err != nil {
+		conn.Close()
+		return
+	}
+
+	// Requests pipelined after the StartTLS request would be injected into the TLS session.
+	if br.Buffered() > 0 {
+		_, _ = conn.Write(ldapStartTLSResponse(messageID, ldapResultCodeOpsError))
+		conn.Close()
+		return
+	}
+
+	if _, err := conn.Write(ldapStartTLSResponse(messageID, ldapResultCodeSuccess)); err != nil {
+		conn.Close()
+		return
+	}
+
+	r.routeStartTLS(conn, br, func(handler tcp.Handler) (tcp.Handler, error) {
+		if _, ok := handler.(*tcp.TLSHandler); ok {
+			return handler, nil
+		}
+
+		// We are in passthrough, so the StartTLS extended operation is requested to the backend on behalf of the client.
+		return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+			handler.ServeTCP(newStartTLSConn(conn, starttlsStep{request: request, reply: ldapStartTLSReply}))
+		}), nil
+	})
+}
+
+// parseLDAPStartTLSRequest parses an LDAP StartTLS extended request, and returns its message ID.
+func parseLDAPStartTLSRequest(message []byte) ([]byte, bool) {
+	tag, content, _, ok := berElement(message)
+	if !ok || tag != berSequence {
+		return nil, false
+	}
+
+	tag, messageID, content, ok := berElement(content)
+	if !ok || tag != berInteger {
+		return nil, false
+	}
+
+	// The optional controls follow the request.
+	tag, request, _, ok := berElement(content)
+	if !ok || tag != ldapExtendedRequest {
+		return nil, false
+	}
+
+	tag, name, _, ok := berElement(request)
+	if !ok || tag != ldapRequestName || string(name) != ldapStartTLSOID {
+		return nil, false
+	}
+
+	return messageID, true
+}
+
+// ldapStartTLSResponse returns the LDAP StartTLS extended response with the given message ID and result code.
+func ldapStartTLSResponse(messageID []byte, resultCode byte) []byte {
+	response := []byte{berEnumerated, 1, resultCode, berOctetString, 0, berOctetString, 0}
+	response = append(response, berEncode(ldapResponseName, []byte(ldapStartTLSOID))...)
+
+	message := berEncode(berInteger, messageID)
+	message = append(message, berEncode(ldapExtendedResponse, response)...)
+
+	return berEncode(berSequence, message)
+}
+
+// ldapStartTLSReply consumes a successful LDAP StartTLS extended response.
+func ldapStartTLSReply(b []byte) (int, error) {
+	tag, content, rest, ok := berElement(b)
+	if !ok {
+		// The response is not complete yet.
+		return 0, nil
+	}
+
+	if tag != berSequence {
+		return 0, errInvalidReply
+	}
+
+	tag, _, content, ok = berElement(content)
+	if !ok || tag != berInteger {
+		return 0, errInvalidReply
+	}
+
+	tag, response, _, ok := berElement(content)
+	if !ok || tag != ldapExtendedResponse {
+		return 0, errInvalidReply
+	}
+
+	tag, resultCode, _, ok := berElement(response)
+	if !ok || tag != berEnumerated || !bytes.Equal(resultCode, []byte{ldapResultCodeSuccess}) {
+		return 0, errInvalidReply
+	}
+
+	return len(b) - len(rest), nil
+}
+
+// berHeader parses the header of a BER element, and returns its tag, the length of its content,
+// and the size of the header.
+func berHeader(b []byte) (byte, int, int, bool) {
+	if len(b) < 2 {
+		return 0, 0, 0, false
+	}
+
+	if b[1]&0x80 == 0 {
+		return b[0], int(b[1]), 2, true
+	}
+
+	size := int(b[1] & 0x7f)
+	if size == 0 || size > 3 || len(b) < 2+size {
+		return 0, 0, 0, false
+	}
+
+	var length int
+	for _, c := range b[2 : 2+size] {
+		length = length<<8 | int(c)
+	}
+
+	return b[0], length, 2 + size, true
+}
+
+// berElement parses the BER element at the start of the given bytes,
+// and returns its tag, its content, and the bytes following it.
+func berElement(b []byte) (byte, []byte, []byte, bool) {
+	tag, length, size, ok := berHeader(b)
+	if !ok || len(b) < size+length {
+		return 0, nil, nil, false
+	}
+
+	return tag, b[size : size+length], b[size+length:], true
+}
+
+// berEncode encodes a BER element with the given tag and content.
+func berEncode(tag byte, content []byte) []byte {
+	var header []byte
+	switch length := len(content); {
+	case length < 0x80:
+		header = []byte{tag, byte(length)}
+	case length <= 0xff:
+		header = []byte{tag, 0x81, byte(length)}
+	default:
+		header = []byte{tag, 0x82, byte(length >> 8), byte(length)}
+	}
+
+	return append(header, content...)
+}
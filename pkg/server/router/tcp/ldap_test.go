@@ -0,0 +1,165 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcp2 "github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestIsLDAPStartTLS(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		data     []byte
+		expected bool
+	}{
+		{
+			desc:     "StartTLS request",
+			data:     ldapStartTLSRequest([]byte{1}, nil),
+			expected: true,
+		},
+		{
+			desc:     "StartTLS request with controls",
+			data:     ldapStartTLSRequest([]byte{0x01, 0x00}, berEncode(0xa0, berEncode(berSequence, berEncode(berOctetString, []byte("1.2.3"))))),
+			expected: true,
+		},
+		{
+			desc: "other extended request",
+			data: berEncode(berSequence, append(berEncode(berInteger, []byte{1}),
+				berEncode(ldapExtendedRequest, berEncode(ldapRequestName, []byte("1.3.6.1.4.1.4203.1.11.3")))...)),
+		},
+		{
+			desc: "bind request",
+			data: berEncode(berSequence, append(berEncode(berInteger, []byte{1}),
+				berEncode(0x60, append([]byte{berInteger, 1, 3}, berEncode(berOctetString, nil)...))...)),
+		},
+		{
+			desc: "HTTP request",
+			data: []byte("GET / HTTP/1.1\r\n\r\n"),
+		},
+		{
+			desc: "TLS ClientHello",
+			data: []byte{0x16, 0x03, 0x01, 0x00, 0x05, 0x01, 0x00, 0x00, 0x01, 0x00},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			request, err := isLDAPStartTLS(bufio.NewReader(bytes.NewReader(test.data)))
+			require.NoError(t, err)
+
+			if !test.expected {
+				assert.Nil(t, request)
+				return
+			}
+
+			assert.Equal(t, test.data, request)
+		})
+	}
+}
+
+func TestLDAPStartTLSReply(t *testing.T) {
+	response := ldapStartTLSResponse([]byte{2}, ldapResultCodeSuccess)
+
+	n, err := ldapStartTLSReply(response[:len(response)-1])
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = ldapStartTLSReply(append(response, "data"...))
+	require.NoError(t, err)
+	assert.Equal(t, len(response), n)
+
+	_, err = ldapStartTLSReply(ldapStartTLSResponse([]byte{2}, ldapResultCodeOpsError))
+	assert.ErrorIs(t, err, errInvalidReply)
+}
+
+func TestRouter_LDAPStartTLS(t *testing.T) {
+	request := ldapStartTLSRequest([]byte{1}, nil)
+
+	testCases := []struct {
+		desc    string
+		handler func(t *testing.T) tcp2.Handler
+	}{
+		{
+			desc: "TLS termination",
+			handler: func(t *testing.T) tcp2.Handler {
+				t.Helper()
+
+				return newTLSHandler(t, tcp2.HandlerFunc(serveLDAPSearch))
+			},
+		},
+		{
+			desc: "TLS passthrough",
+			handler: func(t *testing.T) tcp2.Handler {
+				t.Helper()
+
+				tlsConfig := newTLSConfig(t)
+
+				// The backend receives the StartTLS request on behalf of the client.
+				return tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+					received := make([]byte, len(request))
+					if _, err := io.ReadFull(conn, received); err != nil || !bytes.Equal(request, received) {
+						conn.Close()
+						return
+					}
+
+					_, _ = conn.Write(ldapStartTLSResponse([]byte{1}, ldapResultCodeSuccess))
+
+					serveLDAPSearch(tls.Server(conn, tlsConfig))
+				})
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			err = router.muxerTCPTLS.AddRoute("", "HostSNI(`ldap.example.com`)", 0, test.handler(t))
+			require.NoError(t, err)
+
+			conn := dialRouter(t, router)
+
+			exchange{
+				send:     string(request),
+				expected: string(ldapStartTLSResponse([]byte{1}, ldapResultCodeSuccess)),
+			}.check(t, bufio.NewReader(conn), conn)
+
+			tlsConn := tls.Client(conn, &tls.Config{ServerName: "ldap.example.com", InsecureSkipVerify: true})
+			exchange{send: "search\n", expected: "result\n"}.check(t, bufio.NewReader(tlsConn), tlsConn)
+		})
+	}
+}
+
+// serveLDAPSearch serves a fake search operation, within the TLS session.
+func serveLDAPSearch(conn tcp2.WriteCloser) {
+	defer conn.Close()
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || line != "search\n" {
+		return
+	}
+
+	_, _ = conn.Write([]byte("result\n"))
+}
+
+// ldapStartTLSRequest returns an LDAP StartTLS extended request, with the given message ID and controls.
+func ldapStartTLSRequest(messageID, controls []byte) []byte {
+	message := berEncode(berInteger, messageID)
+	message = append(message, berEncode(ldapExtendedRequest, berEncode(ldapRequestName, []byte(ldapStartTLSOID)))...)
+	message = append(message, controls...)
+
+	return berEncode(berSequence, message)
+}
@@ -0,0 +1,233 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// lineAction is what happens after replying to a command of a line-based protocol.
+type lineAction int
+
+const (
+	lineContinue lineAction = iota
+	lineStartTLS
+	lineClose
+)
+
+var errSessionClosed = errors.New("session closed by the client before STARTTLS")
+
+// lineNegotiator negotiates STARTTLS sessions in a line-based protocol in which the server speaks first,
+// such as SMTP, IMAP or POP3.
+type lineNegotiator struct {
+	// greeting is sent to the client when it connects.
+	greeting string
+	// reply returns the reply to the given command line, and what happens after it.
+	reply func(command string) (string, lineAction)
+
+	// greetingReply consumes the greeting of the backend.
+	greetingReply func(b []byte) (int, error)
+	// steps negotiate the STARTTLS session with the backend, after its greeting.
+	steps []starttlsStep
+}
+
+func (n lineNegotiator) negotiate(conn tcp.WriteCloser, br *bufio.Reader) error {
+	if _, err := conn.Write([]byte(n.greeting)); err != nil {
+		return err
+	}
+
+	for {
+		line, err := br.ReadSlice('\n')
+		if err != nil {
+			return err
+		}
+
+		reply, action := n.reply(strings.TrimRight(string(line), "\r\n"))
+
+		// Commands pipelined after the STARTTLS command would be injected into the TLS session.
+		if action == lineStartTLS && br.Buffered() > 0 {
+			return errors.New("data received after the STARTTLS command")
+		}
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return err
+		}
+
+		switch action {
+		case lineStartTLS:
+			return nil
+		case lineClose:
+			return errSessionClosed
+		}
+	}
+}
+
+func (n lineNegotiator) backendHandler(handler tcp.Handler) (tcp.Handler, error) {
+	// The client has already received a greeting, and does not expect a new one once TLS has started.
+	if tlsHandler, ok := handler.(*tcp.TLSHandler); ok {
+		return wrapTLSHandler(tlsHandler, func(conn tcp.WriteCloser) tcp.WriteCloser {
+			return newStartTLSConn(conn, starttlsStep{reply: n.greetingReply})
+		}), nil
+	}
+
+	// We are in passthrough, so the STARTTLS session is negotiated with the backend on behalf of the client.
+	steps := append([]starttlsStep{{reply: n.greetingReply}}, n.steps...)
+
+	return tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		handler.ServeTCP(newStartTLSConn(conn, steps...))
+	}), nil
+}
+
+// splitCommand returns the uppercased verb of a command line, and its arguments.
+func splitCommand(line string) (string, string) {
+	verb, args, _ := strings.Cut(line, " ")
+	return strings.ToUpper(verb), args
+}
+
+var smtpNegotiator = lineNegotiator{
+	greeting: "220 traefik ESMTP ready\r\n",
+	reply: func(command string) (string, lineAction) {
+		verb, _ := splitCommand(command)
+
+		switch verb {
+		case "EHLO":
+			return "250-traefik\r\n250 STARTTLS\r\n", lineContinue
+		case "HELO":
+			return "250 traefik\r\n", lineContinue
+		case "NOOP", "RSET":
+			return "250 2.0.0 OK\r\n", lineContinue
+		case "STARTTLS":
+			return "220 2.0.0 Ready to start TLS\r\n", lineStartTLS
+		case "QUIT":
+			return "221 2.0.0 Bye\r\n", lineClose
+		default:
+			return "530 5.7.0 Must issue a STARTTLS command first\r\n", lineContinue
+		}
+	},
+	greetingReply: smtpReply("220"),
+	steps: []starttlsStep{
+		{request: []byte("EHLO traefik\r\n"), reply: smtpReply("250")},
+		{request: []byte("STARTTLS\r\n"), reply: smtpReply("220")},
+	},
+}
+
+// smtpReply returns a function consuming a, possibly multiline, SMTP reply with the given code.
+func smtpReply(code string) func(b []byte) (int, error) {
+	return func(b []byte) (int, error) {
+		var n int
+		for {
+			i := bytes.IndexByte(b[n:], '\n')
+			if i < 0 {
+				return 0, nil
+			}
+
+			line := b[n : n+i+1]
+			n += i + 1
+
+			if len(line) < 4 || string(line[:3]) != code {
+				return 0, fmt.Errorf("%w: %q", errInvalidReply, line)
+			}
+
+			// A hyphen after the code means that the reply continues on the next line.
+			if line[3] != '-' {
+				return n, nil
+			}
+		}
+	}
+}
+
+var imapNegotiator = lineNegotiator{
+	greeting: "* OK [CAPABILITY IMAP4rev1 STARTTLS LOGINDISABLED] traefik ready\r\n",
+	reply: func(command string) (string, lineAction) {
+		tag, rest, ok := strings.Cut(command, " ")
+		if !ok || tag == "" {
+			return "* BAD Invalid command\r\n", lineContinue
+		}
+
+		verb, _ := splitCommand(rest)
+
+		switch verb {
+		case "CAPABILITY":
+			return "* CAPABILITY IMAP4rev1 STARTTLS LOGINDISABLED\r\n" + tag + " OK CAPABILITY completed\r\n", lineContinue
+		case "NOOP":
+			return tag + " OK NOOP completed\r\n", lineContinue
+		case "STARTTLS":
+			return tag + " OK Begin TLS negotiation now\r\n", lineStartTLS
+		case "LOGOUT":
+			return "* BYE traefik logging out\r\n" + tag + " OK LOGOUT completed\r\n", lineClose
+		default:
+			return tag + " BAD Must issue a STARTTLS command first\r\n", lineContinue
+		}
+	},
+	greetingReply: imapReply("*"),
+	steps: []starttlsStep{
+		{request: []byte("a STARTTLS\r\n"), reply: imapReply("a")},
+	},
+}
+
+// imapReply returns a function consuming an IMAP OK response with the given tag,
+// and the untagged responses preceding it.
+func imapReply(tag string) func(b []byte) (int, error) {
+	return func(b []byte) (int, error) {
+		var n int
+		for {
+			i := bytes.IndexByte(b[n:], '\n')
+			if i < 0 {
+				return 0, nil
+			}
+
+			line := string(b[n : n+i+1])
+			n += i + 1
+
+			if strings.HasPrefix(line, tag+" OK") {
+				return n, nil
+			}
+
+			if tag == "*" || !strings.HasPrefix(line, "* ") {
+				return 0, fmt.Errorf("%w: %q", errInvalidReply, line)
+			}
+		}
+	}
+}
+
+var pop3Negotiator = lineNegotiator{
+	greeting: "+OK traefik ready\r\n",
+	reply: func(command string) (string, lineAction) {
+		verb, _ := splitCommand(command)
+
+		switch verb {
+		case "CAPA":
+			return "+OK Capability list follows\r\nSTLS\r\n.\r\n", lineContinue
+		case "NOOP":
+			return "+OK\r\n", lineContinue
+		case "STLS":
+			return "+OK Begin TLS negotiation\r\n", lineStartTLS
+		case "QUIT":
+			return "+OK Bye\r\n", lineClose
+		default:
+			return "-ERR Must issue a STLS command first\r\n", lineContinue
+		}
+	},
+	greetingReply: pop3Reply,
+	steps: []starttlsStep{
+		{request: []byte("STLS\r\n"), reply: pop3Reply},
+	},
+}
+
+// pop3Reply consumes a single line POP3 positive reply.
+func pop3Reply(b []byte) (int, error) {
+	i := bytes.IndexByte(b, '\n')
+	if i < 0 {
+		return 0, nil
+	}
+
+	if !bytes.HasPrefix(b, []byte("+OK")) {
+		return 0, fmt.Errorf("%w: %q", errInvalidReply, b[:i+1])
+	}
+
+	return i + 1, nil
+}
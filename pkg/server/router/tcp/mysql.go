@@ -0,0 +1,443 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// MySQL capability flags.
+const (
+	mysqlClientLongPassword     = 0x00000001
+	mysqlClientFoundRows        = 0x00000002
+	mysqlClientLongFlag         = 0x00000004
+	mysqlClientConnectWithDB    = 0x00000008
+	mysqlClientProtocol41       = 0x00000200
+	mysqlClientSSL              = 0x00000800
+	mysqlClientTransactions     = 0x00002000
+	mysqlClientSecureConnection = 0x00008000
+	mysqlClientMultiStatements  = 0x00010000
+	mysqlClientMultiResults     = 0x00020000
+	mysqlClientPSMultiResults   = 0x00040000
+	mysqlClientPluginAuth       = 0x00080000
+	mysqlClientConnectAttrs     = 0x00100000
+	mysqlClientPluginAuthLenEnc = 0x00200000
+
+	// mysqlCapabilities are the capabilities advertised to the clients,
+	// which are the ones that any backend is expected to support.
+	mysqlCapabilities = mysqlClientLongPassword | mysqlClientFoundRows | mysqlClientLongFlag | mysqlClientConnectWithDB |
+		mysqlClientProtocol41 | mysqlClientSSL | mysqlClientTransactions | mysqlClientSecureConnection |
+		mysqlClientMultiStatements | mysqlClientMultiResults | mysqlClientPSMultiResults |
+		mysqlClientPluginAuth | mysqlClientConnectAttrs | mysqlClientPluginAuthLenEnc
+)
+
+const (
+	mysqlServerVersion = "8.0.0-traefik"
+	mysqlAuthPlugin    = "mysql_native_password"
+
+	// mysqlAuthSwitchPlugin is sent to the backends as the authentication method used by the clients,
+	// to make them request an authentication method switch,
+	// as the clients authenticated against the scramble of Traefik and not the one of the backend.
+	mysqlAuthSwitchPlugin = "traefik_auth_switch"
+
+	// mysqlSSLRequestSize is the size of the payload of an SSLRequest packet.
+	mysqlSSLRequestSize = 32
+
+	mysqlPacketOK           = 0x00
+	mysqlPacketAuthMoreData = 0x01
+	mysqlPacketAuthSwitch   = 0xfe
+	mysqlPacketERR          = 0xff
+
+	// mysqlPerformFullAuthentication is sent by the caching_sha2_password method,
+	// in an AuthMoreData packet, when the password of the account is not cached by the backend.
+	mysqlPerformFullAuthentication = 0x04
+)
+
+// mysqlCleartextPlugins are the authentication methods with which
+// a client on a secure connection sends the password in clear text.
+var mysqlCleartextPlugins = map[string]struct{}{
+	"sha256_password":      {},
+	"mysql_clear_password": {},
+}
+
+// mysqlNegotiator negotiates STARTTLS sessions with MySQL clients,
+// by sending a greeting to which the clients reply with an SSLRequest.
+type mysqlNegotiator struct{}
+
+func (mysqlNegotiator) negotiate(conn tcp.WriteCloser, br *bufio.Reader) error {
+	greeting, err := mysqlGreeting()
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn.Write(mysqlPacket(0, greeting)); err != nil {
+		return err
+	}
+
+	sequenceID, payload, err := readMySQLPacket(br)
+	if err != nil {
+		return err
+	}
+
+	if len(payload) != mysqlSSLRequestSize || binary.LittleEndian.Uint32(payload)&mysqlClientSSL == 0 {
+		_, _ = conn.Write(mysqlPacket(sequenceID+1, mysqlError(3159, "HY000", "Connections using insecure transport are prohibited")))
+		return errors.New("MySQL client did not request SSL")
+	}
+
+	// The ClientHello must not have been sent yet, as the client waits for no reply.
+	return nil
+}
+
+func (mysqlNegotiator) backendHandler(handler tcp.Handler) (tcp.Handler, error) {
+	tlsHandler, ok := handler.(*tcp.TLSHandler)
+	if !ok {
+		// The client authenticates within the TLS session, against the scramble of the greeting sent by Traefik,
+		// which a backend cannot check.
+		return nil, errors.New("MySQL STARTTLS is only supported with TLS termination")
+	}
+
+	return wrapTLSHandler(tlsHandler, func(conn tcp.WriteCloser) tcp.WriteCloser {
+		return &mysqlConn{WriteCloser: conn, br: bufio.NewReader(conn)}
+	}), nil
+}
+
+// mysqlGreeting returns the payload of a Handshake v10 packet.
+func mysqlGreeting() ([]byte, error) {
+	// The scramble must not contain NUL bytes.
+	scramble := make([]byte, 20)
+	if _, err := rand.Read(scramble); err != nil {
+		return nil, err
+	}
+	for i := range scramble {
+		scramble[i] = scramble[i]%94 + 33
+	}
+
+	capabilities := uint32(mysqlCapabilities)
+
+	var greeting bytes.Buffer
+	greeting.WriteByte(10) // Protocol version.
+	greeting.WriteString(mysqlServerVersion)
+	greeting.WriteByte(0)
+	greeting.Write([]byte{1, 0, 0, 0}) // Connection ID.
+	greeting.Write(scramble[:8])
+	greeting.WriteByte(0)
+	greeting.Write([]byte{byte(capabilities), byte(capabilities >> 8)})
+	greeting.WriteByte(0xff)     // utf8mb4_0900_ai_ci character set.
+	greeting.Write([]byte{2, 0}) // SERVER_STATUS_AUTOCOMMIT status.
+	greeting.Write([]byte{byte(capabilities >> 16), byte(capabilities >> 24)})
+	greeting.WriteByte(byte(len(scramble) + 1))
+	greeting.Write(make([]byte, 10)) // Reserved.
+	greeting.Write(scramble[8:])
+	greeting.WriteByte(0)
+	greeting.WriteString(mysqlAuthPlugin)
+	greeting.WriteByte(0)
+
+	return greeting.Bytes(), nil
+}
+
+// mysqlError returns the payload of an ERR packet.
+func mysqlError(code uint16, state, message string) []byte {
+	payload := []byte{mysqlPacketERR, byte(code), byte(code >> 8), '#'}
+	payload = append(payload, state...)
+	return append(payload, message...)
+}
+
+// mysqlPacket returns a packet with the given sequence ID and payload.
+func mysqlPacket(sequenceID byte, payload []byte) []byte {
+	packet := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), sequenceID}
+	return append(packet, payload...)
+}
+
+// readMySQLPacket reads a packet, and returns its sequence ID and payload.
+func readMySQLPacket(r io.Reader) (byte, []byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	payload := make([]byte, int(header[0])|int(header[1])<<8|int(header[2])<<16)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return header[3], payload, nil
+}
+
+// mysqlConn is a tcp.WriteCloser which hides to the backend, and to the client,
+// that the client negotiated the TLS session with Traefik.
+// It drops the greeting of the backend, which the client does not expect,
+// makes the backend request an authentication method switch,
+// as the authentication response of the client was computed from the greeting of Traefik,
+// and shifts the sequence IDs of the authentication packets,
+// as the client has already sent its SSLRequest packet.
+// It refuses the authentication methods with which the client would send its password in clear text to the backend.
+type mysqlConn struct {
+	tcp.WriteCloser
+
+	br *bufio.Reader
+
+	// authenticated is set once the backend has accepted, or refused, the authentication of the client.
+	authenticated atomic.Bool
+
+	// handshakeResponseSent is whether the handshake response of the client has already been sent to the backend.
+	handshakeResponseSent bool
+	// pending holds the bytes of the client packet being read.
+	pending []byte
+
+	// greetingDropped is whether the greeting of the backend has already been dropped.
+	greetingDropped bool
+	// replies holds the bytes of the backend packets being written.
+	replies []byte
+}
+
+// Read reads bytes from the underlying connection (tcp.WriteCloser).
+// During the authentication, it rewrites the client packets for the backend.
+// Read does not support concurrent calls.
+func (c *mysqlConn) Read(p []byte) (int, error) {
+	if len(c.pending) > 0 {
+		n := copy(p, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+
+	if c.authenticated.Load() {
+		return c.br.Read(p)
+	}
+
+	sequenceID, payload, err := readMySQLPacket(c.br)
+	if err != nil {
+		return 0, err
+	}
+
+	if !c.handshakeResponseSent {
+		c.handshakeResponseSent = true
+
+		payload, err = mysqlHandshakeResponse(payload)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// Packets sent after the authentication start a new sequence.
+	if sequenceID > 0 {
+		sequenceID--
+	}
+
+	packet := mysqlPacket(sequenceID, payload)
+
+	n := copy(p, packet)
+	c.pending = packet[n:]
+
+	return n, nil
+}
+
+// Write writes bytes to the underlying connection (tcp.WriteCloser).
+// During the authentication, it rewrites the backend packets for the client.
+// Write does not support concurrent calls.
+func (c *mysqlConn) Write(p []byte) (int, error) {
+	if c.authenticated.Load() && len(c.replies) == 0 {
+		return c.WriteCloser.Write(p)
+	}
+
+	c.replies = append(c.replies, p...)
+
+	var out []byte
+	for !c.authenticated.Load() && len(c.replies) >= 4 {
+		length := int(c.replies[0]) | int(c.replies[1])<<8 | int(c.replies[2])<<16
+		if len(c.replies) < 4+length {
+			break
+		}
+
+		if length > maxStartTLSReplySize {
+			return 0, errInvalidReply
+		}
+
+		sequenceID, payload := c.replies[3], c.replies[4:4+length]
+		c.replies = c.replies[4+length:]
+
+		if !c.greetingDropped {
+			c.greetingDropped = true
+
+			if length == 0 || payload[0] == mysqlPacketERR {
+				return 0, fmt.Errorf("%w: MySQL error packet instead of greeting", errInvalidReply)
+			}
+			continue
+		}
+
+		if mysqlCleartextAuthentication(payload) {
+			// The client believes it is on a secure connection and would send its password in clear text,
+			// while the connection to the backend is not encrypted.
+			out = append(out, mysqlPacket(sequenceID+1, mysqlError(1045, "28000", "Authentication methods requiring a secure connection to the backend are not supported"))...)
+			_, _ = c.WriteCloser.Write(out)
+			_ = c.WriteCloser.Close()
+
+			return 0, fmt.Errorf("%w: MySQL authentication method requiring a secure connection", errInvalidReply)
+		}
+
+		if length > 0 && (payload[0] == mysqlPacketOK || payload[0] == mysqlPacketERR) {
+			c.authenticated.Store(true)
+		}
+
+		out = append(out, mysqlPacket(sequenceID+1, payload)...)
+	}
+
+	if c.authenticated.Load() {
+		out = append(out, c.replies...)
+		c.replies = nil
+	}
+
+	if len(out) > 0 {
+		if _, err := c.WriteCloser.Write(out); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// NetConn returns the underlying connection.
+func (c *mysqlConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// mysqlCleartextAuthentication returns whether the given backend packet makes the client send its password in clear text.
+// It is the case of an authentication method switch to sha256_password or mysql_clear_password,
+// and of a caching_sha2_password full authentication.
+func mysqlCleartextAuthentication(payload []byte) bool {
+	if len(payload) == 2 && payload[0] == mysqlPacketAuthMoreData && payload[1] == mysqlPerformFullAuthentication {
+		return true
+	}
+
+	if len(payload) == 0 || payload[0] != mysqlPacketAuthSwitch {
+		return false
+	}
+
+	plugin, _, _ := bytes.Cut(payload[1:], []byte{0})
+	_, ok := mysqlCleartextPlugins[string(plugin)]
+
+	return ok
+}
+
+// mysqlHandshakeResponse rewrites the HandshakeResponse41 payload of a client for the backend.
+// It removes the SSL capability and the authentication response,
+// and replaces the authentication method to make the backend request a switch.
+func mysqlHandshakeResponse(payload []byte) ([]byte, error) {
+	// Capabilities, max packet size, character set and filler.
+	if len(payload) < 32 {
+		return nil, errors.New("invalid MySQL handshake response")
+	}
+
+	capabilities := binary.LittleEndian.Uint32(payload)
+	if capabilities&mysqlClientProtocol41 == 0 {
+		return nil, errors.New("unsupported MySQL handshake response protocol")
+	}
+
+	rest := payload[32:]
+
+	username, rest, ok := bytes.Cut(rest, []byte{0})
+	if !ok {
+		return nil, errors.New("invalid MySQL handshake response username")
+	}
+
+	var authResponseLength int
+	switch {
+	case capabilities&mysqlClientPluginAuthLenEnc != 0:
+		length, size, ok := mysqlLenEncInt(rest)
+		if !ok {
+			return nil, errors.New("invalid MySQL handshake response authentication")
+		}
+		authResponseLength = size + length
+	case capabilities&mysqlClientSecureConnection != 0:
+		if len(rest) == 0 {
+			return nil, errors.New("invalid MySQL handshake response authentication")
+		}
+		authResponseLength = 1 + int(rest[0])
+	default:
+		i := bytes.IndexByte(rest, 0)
+		if i < 0 {
+			return nil, errors.New("invalid MySQL handshake response authentication")
+		}
+		authResponseLength = i + 1
+	}
+
+	if len(rest) < authResponseLength {
+		return nil, errors.New("invalid MySQL handshake response authentication")
+	}
+	rest = rest[authResponseLength:]
+
+	var database []byte
+	if capabilities&mysqlClientConnectWithDB != 0 {
+		database, rest, ok = bytes.Cut(rest, []byte{0})
+		if !ok {
+			return nil, errors.New("invalid MySQL handshake response database")
+		}
+	}
+
+	if capabilities&mysqlClientPluginAuth != 0 {
+		// The client authentication method is replaced, and the connection attributes follow.
+		if _, rest, ok = bytes.Cut(rest, []byte{0}); !ok {
+			return nil, errors.New("invalid MySQL handshake response authentication method")
+		}
+	}
+
+	response := binary.LittleEndian.AppendUint32(nil, capabilities&^mysqlClientSSL)
+	response = append(response, payload[4:32]...)
+	response = append(response, username...)
+	response = append(response, 0)
+	// Empty authentication response, in any of its encodings.
+	response = append(response, 0)
+
+	if capabilities&mysqlClientConnectWithDB != 0 {
+		response = append(response, database...)
+		response = append(response, 0)
+	}
+
+	if capabilities&mysqlClientPluginAuth != 0 {
+		response = append(response, mysqlAuthSwitchPlugin...)
+		response = append(response, 0)
+	}
+
+	return append(response, rest...), nil
+}
+
+// mysqlLenEncInt parses a length-encoded integer, and returns its value and size.
+func mysqlLenEncInt(b []byte) (int, int, bool) {
+	if len(b) == 0 {
+		return 0, 0, false
+	}
+
+	size := 1
+	switch b[0] {
+	case 0xfc:
+		size = 3
+	case 0xfd:
+		size = 4
+	case 0xfe:
+		size = 9
+	case 0xff:
+		return 0, 0, false
+	}
+
+	if len(b) < size {
+		return 0, 0, false
+	}
+
+	if size == 1 {
+		return int(b[0]), 1, true
+	}
+
+	var value int
+	for i := size - 1; i > 0; i-- {
+		value = value<<8 | int(b[i])
+	}
+
+	return value, size, true
+}
@@ -0,0 +1,265 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcp2 "github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestMySQLHandshakeResponse(t *testing.T) {
+	capabilities := uint32(mysqlClientProtocol41 | mysqlClientSSL | mysqlClientSecureConnection | mysqlClientConnectWithDB |
+		mysqlClientPluginAuth | mysqlClientPluginAuthLenEnc | mysqlClientConnectAttrs)
+
+	attributes := []byte{9, 4, '_', 'o', 's', 4, 'l', 'i', 'n', 'x'}
+
+	response, err := mysqlHandshakeResponse(mysqlHandshakeResponsePayload(capabilities, attributes))
+	require.NoError(t, err)
+
+	expected := binary.LittleEndian.AppendUint32(nil, capabilities&^mysqlClientSSL)
+	expected = append(expected, 0, 0, 0, 1, 0xff)
+	expected = append(expected, make([]byte, 23)...)
+	expected = append(expected, "user\x00\x00mydb\x00traefik_auth_switch\x00"...)
+	expected = append(expected, attributes...)
+
+	assert.Equal(t, expected, response)
+
+	_, err = mysqlHandshakeResponse([]byte{1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestRouter_MySQLStartTLS(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	require.NoError(t, router.SetStartTLSProtocol("mysql"))
+
+	backend := tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+		defer conn.Close()
+
+		br := bufio.NewReader(conn)
+
+		// The greeting of the backend is dropped.
+		greeting, err := mysqlGreeting()
+		if err != nil {
+			return
+		}
+		_, _ = conn.Write(mysqlPacket(0, greeting))
+
+		// The backend requests an authentication method switch.
+		sequenceID, payload, err := readMySQLPacket(br)
+		if err != nil || sequenceID != 1 || !bytes.Contains(payload, []byte("\x00\x00mydb\x00traefik_auth_switch\x00")) {
+			return
+		}
+		_, _ = conn.Write(mysqlPacket(2, []byte("\xfemysql_native_password\x00scramble\x00")))
+
+		sequenceID, payload, err = readMySQLPacket(br)
+		if err != nil || sequenceID != 3 || string(payload) != "auth" {
+			return
+		}
+		_, _ = conn.Write(mysqlPacket(4, []byte{mysqlPacketOK, 0, 0, 2, 0, 0, 0}))
+
+		// The commands are forwarded as is.
+		sequenceID, payload, err = readMySQLPacket(br)
+		if err != nil || sequenceID != 0 || string(payload) != "\x03SELECT 1" {
+			return
+		}
+		_, _ = conn.Write(mysqlPacket(1, []byte("result")))
+	})
+
+	err = router.muxerTCPTLS.AddRoute("", "HostSNI(`db.example.com`)", 0, newTLSHandler(t, backend))
+	require.NoError(t, err)
+
+	conn := dialRouter(t, router)
+
+	sequenceID, greeting, err := readMySQLPacket(conn)
+	require.NoError(t, err)
+	assert.Equal(t, byte(0), sequenceID)
+	assert.Equal(t, byte(10), greeting[0])
+
+	capabilities := uint32(mysqlClientProtocol41 | mysqlClientSSL | mysqlClientSecureConnection | mysqlClientConnectWithDB | mysqlClientPluginAuth)
+
+	sslRequest := mysqlHandshakeResponsePayload(capabilities, nil)[:mysqlSSLRequestSize]
+	_, err = conn.Write(mysqlPacket(1, sslRequest))
+	require.NoError(t, err)
+
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: "db.example.com", InsecureSkipVerify: true})
+
+	_, err = tlsConn.Write(mysqlPacket(2, mysqlHandshakeResponsePayload(capabilities, nil)))
+	require.NoError(t, err)
+
+	sequenceID, payload, err := readMySQLPacket(tlsConn)
+	require.NoError(t, err)
+	assert.Equal(t, byte(3), sequenceID)
+	assert.Equal(t, "\xfemysql_native_password\x00scramble\x00", string(payload))
+
+	_, err = tlsConn.Write(mysqlPacket(4, []byte("auth")))
+	require.NoError(t, err)
+
+	sequenceID, payload, err = readMySQLPacket(tlsConn)
+	require.NoError(t, err)
+	assert.Equal(t, byte(5), sequenceID)
+	assert.Equal(t, byte(mysqlPacketOK), payload[0])
+
+	_, err = tlsConn.Write(mysqlPacket(0, []byte("\x03SELECT 1")))
+	require.NoError(t, err)
+
+	sequenceID, payload, err = readMySQLPacket(tlsConn)
+	require.NoError(t, err)
+	assert.Equal(t, byte(1), sequenceID)
+	assert.Equal(t, "result", string(payload))
+}
+
+func TestRouter_MySQLStartTLS_cleartextAuthentication(t *testing.T) {
+	testCases := []struct {
+		desc string
+		// replies are the authentication packets sent by the backend, each one answered by the client.
+		replies []string
+	}{
+		{
+			desc:    "sha256_password",
+			replies: []string{"\xfesha256_password\x00scramble\x00"},
+		},
+		{
+			desc:    "mysql_clear_password",
+			replies: []string{"\xfemysql_clear_password\x00"},
+		},
+		{
+			desc:    "caching_sha2_password full authentication",
+			replies: []string{"\xfecaching_sha2_password\x00scramble\x00", "\x01\x04"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			require.NoError(t, router.SetStartTLSProtocol("mysql"))
+
+			passwordReceived := make(chan bool, 1)
+
+			backend := tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+				defer conn.Close()
+
+				var received bool
+				defer func() { passwordReceived <- received }()
+
+				br := bufio.NewReader(conn)
+
+				greeting, err := mysqlGreeting()
+				if err != nil {
+					return
+				}
+				_, _ = conn.Write(mysqlPacket(0, greeting))
+
+				if _, _, err = readMySQLPacket(br); err != nil {
+					return
+				}
+
+				for i, reply := range test.replies {
+					_, _ = conn.Write(mysqlPacket(byte(2+2*i), []byte(reply)))
+
+					if _, _, err = readMySQLPacket(br); err != nil {
+						return
+					}
+				}
+
+				received = true
+			})
+
+			err = router.muxerTCPTLS.AddRoute("", "HostSNI(`db.example.com`)", 0, newTLSHandler(t, backend))
+			require.NoError(t, err)
+
+			conn := dialRouter(t, router)
+
+			_, _, err = readMySQLPacket(conn)
+			require.NoError(t, err)
+
+			capabilities := uint32(mysqlClientProtocol41 | mysqlClientSSL | mysqlClientSecureConnection | mysqlClientConnectWithDB | mysqlClientPluginAuth)
+
+			sslRequest := mysqlHandshakeResponsePayload(capabilities, nil)[:mysqlSSLRequestSize]
+			_, err = conn.Write(mysqlPacket(1, sslRequest))
+			require.NoError(t, err)
+
+			tlsConn := tls.Client(conn, &tls.Config{ServerName: "db.example.com", InsecureSkipVerify: true})
+
+			_, err = tlsConn.Write(mysqlPacket(2, mysqlHandshakeResponsePayload(capabilities, nil)))
+			require.NoError(t, err)
+
+			for i := range test.replies[:len(test.replies)-1] {
+				sequenceID, payload, err := readMySQLPacket(tlsConn)
+				require.NoError(t, err)
+				assert.Equal(t, byte(3+2*i), sequenceID)
+				assert.Equal(t, test.replies[i], string(payload))
+
+				_, err = tlsConn.Write(mysqlPacket(byte(4+2*i), []byte("auth")))
+				require.NoError(t, err)
+			}
+
+			sequenceID, payload, err := readMySQLPacket(tlsConn)
+			require.NoError(t, err)
+			assert.Equal(t, byte(1+2*len(test.replies)), sequenceID)
+			assert.Equal(t, mysqlError(1045, "28000", "Authentication methods requiring a secure connection to the backend are not supported"), payload)
+
+			// The password of the client never reaches the backend.
+			_, _ = tlsConn.Write(mysqlPacket(byte(2+2*len(test.replies)), []byte("password")))
+
+			select {
+			case received := <-passwordReceived:
+				assert.False(t, received)
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout waiting for the backend")
+			}
+		})
+	}
+}
+
+func TestRouter_MySQLStartTLS_insecure(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	require.NoError(t, router.SetStartTLSProtocol("mysql"))
+
+	conn := dialRouter(t, router)
+
+	_, _, err = readMySQLPacket(conn)
+	require.NoError(t, err)
+
+	capabilities := uint32(mysqlClientProtocol41 | mysqlClientSecureConnection | mysqlClientPluginAuth)
+
+	_, err = conn.Write(mysqlPacket(1, mysqlHandshakeResponsePayload(capabilities, nil)))
+	require.NoError(t, err)
+
+	sequenceID, payload, err := readMySQLPacket(conn)
+	require.NoError(t, err)
+	assert.Equal(t, byte(2), sequenceID)
+	assert.Equal(t, mysqlError(3159, "HY000", "Connections using insecure transport are prohibited"), payload)
+}
+
+// mysqlHandshakeResponsePayload returns the payload of a HandshakeResponse41 packet,
+// authenticating the user "user" against the database "mydb".
+func mysqlHandshakeResponsePayload(capabilities uint32, attributes []byte) []byte {
+	payload := binary.LittleEndian.AppendUint32(nil, capabilities)
+	payload = append(payload, 0, 0, 0, 1, 0xff)
+	payload = append(payload, make([]byte, 23)...)
+	payload = append(payload, "user\x00"...)
+
+	// The length of a short authentication response is a single byte, whether it is length-encoded or not.
+	authResponse := bytes.Repeat([]byte{'a'}, 20)
+	payload = append(payload, byte(len(authResponse)))
+	payload = append(payload, authResponse...)
+
+	payload = append(payload, "mydb\x00mysql_native_password\x00"...)
+
+	return append(payload, attributes...)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"net"
+	"strings"
 	"sync"
 
 	"github.com/rs/zerolog/log"
@@ -70,7 +71,7 @@ func (r *Router) servePostgres(conn tcp.WriteCloser) {
 		return
 	}
 
-	connData, err := tcpmuxer.NewConnData(hello.serverName, conn, hello.protos)
+	connData, err := tcpmuxer.NewConnData(hello.serverName, conn, hello.protos, hello.fingerprints, tcpmuxer.NewPeeker(nil, bufio.NewReader(strings.NewReader(hello.peeked))))
 	if err != nil {
 		log.Error().Err(err).Msg("Error while reading TCP connection data")
 		conn.Close()
@@ -85,7 +86,7 @@ func (r *Router) servePostgres(conn tcp.WriteCloser) {
 	}
 
 	// We are in TLS mode and if the handler is not TLSHandler, we are in passthrough.
-	proxiedConn := r.GetConn(conn, hello.peeked)
+	proxiedConn := r.getHelloConn(conn, hello)
 	if _, ok := handlerTCPTLS.(*tcp.TLSHandler); !ok {
 		proxiedConn = &postgresConn{WriteCloser: proxiedConn}
 	}
@@ -164,3 +165,8 @@ func (c *postgresConn) Write(p []byte) (n int, err error) {
 
 	return 1, nil
 }
+
+// NetConn returns the underlying connection.
+func (c *postgresConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
@@ -8,11 +8,13 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
 	"github.com/traefik/traefik/v3/pkg/tcp"
+	"github.com/traefik/traefik/v3/pkg/tls/fingerprint"
 )
 
 const defaultBufSize = 4096
@@ -42,6 +44,9 @@ type Router struct {
 	// hostHTTPTLSConfig contains TLS configs keyed by SNI.
 	// A nil config is the hint to set up a brokenTLSRouter.
 	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+
+	// starttls negotiates STARTTLS sessions with all the clients, when the entry point serves a server-first protocol.
+	starttls starttlsNegotiator
 }
 
 // NewRouter returns a new TCP router.
@@ -81,13 +86,24 @@ func (r *Router) GetTLSGetClientInfo() func(info *tls.ClientHelloInfo) (*tls.Con
 
 // ServeTCP forwards the connection to the right TCP/HTTP handler.
 func (r *Router) ServeTCP(conn tcp.WriteCloser) {
+	// In a server-first protocol, the client waits for the server to speak,
+	// so the STARTTLS session must be negotiated before anything can be peeked.
+	if r.starttls != nil {
+		r.serveStartTLS(conn)
+		return
+	}
+
 	// Handling Non-TLS TCP connection early if there is neither HTTP(S) nor TLS routers on the entryPoint,
 	// and if there is at least one non-TLS TCP router.
 	// In the case of a non-TLS TCP client (that does not "send" first),
 	// we would block forever on clientHelloInfo,
 	// which is why we want to detect and handle that case first and foremost.
+	// The reader is shared by all the steps peeking the connection, so that no peeked byte is lost.
+	br := bufio.NewReader(conn)
+
 	if r.muxerTCP.HasRoutes() && !r.muxerTCPTLS.HasRoutes() && !r.muxerHTTPS.HasRoutes() {
-		connData, err := tcpmuxer.NewConnData("", conn, nil)
+		// The bytes are only peeked if a matcher needs them, e.g. the Protocol matcher.
+		connData, err := tcpmuxer.NewConnData("", conn, nil, nil, tcpmuxer.NewPeeker(conn, br))
 		if err != nil {
 			log.Error().Err(err).Msg("Error while reading TCP connection data")
 			conn.Close()
@@ -98,6 +114,11 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		// If there is a handler matching the connection metadata,
 		// we let it handle the connection.
 		if handler != nil {
+			if br.Buffered() > 0 {
+				handler.ServeTCP(r.GetConn(conn, getPeeked(br)))
+				return
+			}
+
 			handler.ServeTCP(conn)
 			return
 		}
@@ -108,7 +129,6 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 	}
 
 	// TODO -- Check if ProxyProtocol changes the first bytes of the request
-	br := bufio.NewReader(conn)
 	postgres, err := isPostgres(br)
 	if err != nil {
 		conn.Close()
@@ -120,6 +140,21 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		return
 	}
 
+	// The LDAP StartTLS extended operation is only handled if there are TCP TLS routers to route it with,
+	// so that it still reaches the non-TLS TCP routers otherwise.
+	if r.muxerTCPTLS.HasRoutes() {
+		request, err := isLDAPStartTLS(br)
+		if err != nil {
+			conn.Close()
+			return
+		}
+
+		if request != nil {
+			r.serveLDAP(r.GetConn(conn, getPeeked(br)), request)
+			return
+		}
+	}
+
 	hello, err := clientHelloInfo(br)
 	if err != nil {
 		conn.Close()
@@ -137,7 +172,14 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		log.Error().Err(err).Msg("Error while setting write deadline")
 	}
 
-	connData, err := tcpmuxer.NewConnData(hello.serverName, conn, hello.protos)
+	// Only the bytes of a TLS connection which are already peeked can be peeked again,
+	// as the reader of the ClientHello might not be the one of the connection.
+	peeker := tcpmuxer.NewPeeker(nil, bufio.NewReader(strings.NewReader(hello.peeked)))
+	if !hello.isTLS {
+		peeker = tcpmuxer.NewPeeker(conn, br)
+	}
+
+	connData, err := tcpmuxer.NewConnData(hello.serverName, conn, hello.protos, hello.fingerprints, peeker)
 	if err != nil {
 		log.Error().Err(err).Msg("Error while reading TCP connection data")
 		conn.Close()
@@ -146,11 +188,14 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 
 	if !hello.isTLS {
 		handler, _ := r.muxerTCP.Match(connData)
+		// The matchers might have peeked more bytes.
+		hello.peeked = getPeeked(br)
+
 		switch {
 		case handler != nil:
-			handler.ServeTCP(r.GetConn(conn, hello.peeked))
+			handler.ServeTCP(r.getHelloConn(conn, hello))
 		case r.httpForwarder != nil:
-			r.httpForwarder.ServeTCP(r.GetConn(conn, hello.peeked))
+			r.httpForwarder.ServeTCP(r.getHelloConn(conn, hello))
 		default:
 			conn.Close()
 		}
@@ -167,14 +212,14 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		// In order not to depart from the behavior in 2.6,
 		// we only allow an HTTPS router to take precedence over a TCP-TLS router if it is _not_ an HostSNI(*) router
 		// (so basically any router that has a specific HostSNI based rule).
-		handlerHTTPS.ServeTCP(r.GetConn(conn, hello.peeked))
+		handlerHTTPS.ServeTCP(r.getHelloConn(conn, hello))
 		return
 	}
 
 	// Contains also TCP TLS passthrough routes.
 	handlerTCPTLS, catchAllTCPTLS := r.muxerTCPTLS.Match(connData)
 	if handlerTCPTLS != nil && !catchAllTCPTLS {
-		handlerTCPTLS.ServeTCP(r.GetConn(conn, hello.peeked))
+		handlerTCPTLS.ServeTCP(r.getHelloConn(conn, hello))
 		return
 	}
 
@@ -182,28 +227,28 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 	// We end up here for e.g. an HTTPS router that only has a PathPrefix rule,
 	// which under the scenes is counted as an HostSNI(*) rule.
 	if handlerHTTPS != nil {
-		handlerHTTPS.ServeTCP(r.GetConn(conn, hello.peeked))
+		handlerHTTPS.ServeTCP(r.getHelloConn(conn, hello))
 		return
 	}
 
 	// Fallback on TCP TLS catchAll.
 	if handlerTCPTLS != nil {
-		handlerTCPTLS.ServeTCP(r.GetConn(conn, hello.peeked))
+		handlerTCPTLS.ServeTCP(r.getHelloConn(conn, hello))
 		return
 	}
 
 	// To handle 404s for HTTPS.
 	if r.httpsForwarder != nil {
-		r.httpsForwarder.ServeTCP(r.GetConn(conn, hello.peeked))
+		r.httpsForwarder.ServeTCP(r.getHelloConn(conn, hello))
 		return
 	}
 
 	conn.Close()
 }
 
-// AddRoute defines a handler for the given rule.
-func (r *Router) AddRoute(rule string, priority int, target tcp.Handler) error {
-	return r.muxerTCP.AddRoute(rule, priority, target)
+// AddRoute defines a handler for the given rule, identified by the given name.
+func (r *Router) AddRoute(name, rule string, priority int, target tcp.Handler) error {
+	return r.muxerTCP.AddRoute(name, rule, priority, target)
 }
 
 // AddHTTPTLSConfig defines a handler for a given sniHost and sets the matching tlsConfig.
@@ -226,6 +271,26 @@ func (r *Router) GetConn(conn tcp.WriteCloser, peeked string) tcp.WriteCloser {
 	return conn
 }
 
+// getHelloConn creates a connection proxy with the bytes peeked from the hello,
+// which exposes the ClientHello fingerprints.
+func (r *Router) getHelloConn(conn tcp.WriteCloser, hello *clientHello) tcp.WriteCloser {
+	return &Conn{
+		Peeked:       []byte(hello.peeked),
+		Fingerprints: hello.fingerprints,
+		WriteCloser:  conn,
+	}
+}
+
+// GetTCPMuxer gets the muxer of the TCP routes without TLS.
+func (r *Router) GetTCPMuxer() *tcpmuxer.Muxer {
+	return &r.muxerTCP
+}
+
+// GetTCPTLSMuxer gets the muxer of the TCP routes with TLS.
+func (r *Router) GetTCPTLSMuxer() *tcpmuxer.Muxer {
+	return &r.muxerTCPTLS
+}
+
 // GetHTTPHandler gets the attached http handler.
 func (r *Router) GetHTTPHandler() http.Handler {
 	return r.httpHandler
@@ -268,7 +333,7 @@ func (r *Router) SetHTTPSForwarder(handler tcp.Handler) {
 
 		// muxerHTTPS only contains single HostSNI rules (and no other kind of rules),
 		// so there's no need for specifying a priority for them.
-		if err := r.muxerHTTPS.AddRoute("HostSNI(`"+sniHost+"`)", 0, tcpHandler); err != nil {
+		if err := r.muxerHTTPS.AddRoute(sniHost, "HostSNI(`"+sniHost+"`)", 0, tcpHandler); err != nil {
 			log.Error().Err(err).Msg("Error while adding route for host")
 		}
 	}
@@ -302,6 +367,9 @@ type Conn struct {
 	// It set to nil by Read when fully consumed.
 	Peeked []byte
 
+	// Fingerprints are the fingerprints of the ClientHello peeked from Conn, if any.
+	Fingerprints *fingerprint.Fingerprints
+
 	// Conn is the underlying connection.
 	// It can be type asserted against *net.TCPConn or other types as needed.
 	// It should not be read from directly unless Peeked is nil.
@@ -321,11 +389,23 @@ func (c *Conn) Read(p []byte) (n int, err error) {
 	return c.WriteCloser.Read(p)
 }
 
+// ClientHelloFingerprints returns the fingerprints of the ClientHello peeked from the connection, if any.
+func (c *Conn) ClientHelloFingerprints() *fingerprint.Fingerprints {
+	return c.Fingerprints
+}
+
+// NetConn returns the underlying connection.
+func (c *Conn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
 type clientHello struct {
 	serverName string   // SNI server name
 	protos     []string // ALPN protocols list
 	isTLS      bool     // whether we are a TLS handshake
 	peeked     string   // the bytes peeked from the hello while getting the info
+
+	fingerprints *fingerprint.Fingerprints // JA3 and JA4 fingerprints of the hello
 }
 
 // clientHelloInfo returns various data from the clientHello handshake,
@@ -394,11 +474,17 @@ func clientHelloInfo(br *bufio.Reader) (*clientHello, error) {
 	})
 	_ = server.Handshake()
 
+	fingerprints, err := fingerprint.Compute(helloBytes)
+	if err != nil {
+		log.Debug().Err(err).Msg("Error while computing ClientHello fingerprints")
+	}
+
 	return &clientHello{
-		serverName: sni,
-		isTLS:      true,
-		peeked:     getPeeked(br),
-		protos:     protos,
+		serverName:   sni,
+		isTLS:        true,
+		peeked:       getPeeked(br),
+		protos:       protos,
+		fingerprints: fingerprints,
 	}, nil
 }
 
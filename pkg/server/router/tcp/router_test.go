@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/tls"
@@ -22,6 +23,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/server/service/tcp"
 	tcp2 "github.com/traefik/traefik/v3/pkg/tcp"
 	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/tls/fingerprint"
 )
 
 type applyRouter func(conf *runtime.Configuration)
@@ -167,7 +169,7 @@ func Test_Routing(t *testing.T) {
 	serviceManager := tcp.NewManager(conf, dialerManager)
 
 	// Creates the tlsManager and defines the TLS 1.0 and 1.2 TLSOptions.
-	tlsManager := traefiktls.NewManager()
+	tlsManager := traefiktls.NewManager(nil)
 	tlsManager.UpdateConfigs(
 		context.Background(),
 		map[string]traefiktls.Store{},
@@ -932,10 +934,10 @@ func TestPostgres(t *testing.T) {
 	// This test requires to have a TLS route, but does not actually check the
 	// content of the handler. It would require to code a TLS handshake to
 	// check the SNI and content of the handlerFunc.
-	err = router.muxerTCPTLS.AddRoute("HostSNI(`test.localhost`)", 0, nil)
+	err = router.muxerTCPTLS.AddRoute("", "HostSNI(`test.localhost`)", 0, nil)
 	require.NoError(t, err)
 
-	err = router.AddRoute("HostSNI(`*`)", 0, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+	err = router.AddRoute("", "HostSNI(`*`)", 0, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
 		_, _ = conn.Write([]byte("OK"))
 		_ = conn.Close()
 	}))
@@ -956,6 +958,161 @@ func TestPostgres(t *testing.T) {
 	require.Equal(t, []byte("OK"), b)
 }
 
+func TestRouter_Protocol(t *testing.T) {
+	testCases := []struct {
+		desc string
+		// withTLSRouter makes the connections go through the TLS detection before the TCP routing.
+		withTLSRouter bool
+	}{
+		{
+			desc: "only TCP routers",
+		},
+		{
+			desc:          "TCP and TCP TLS routers",
+			withTLSRouter: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			echo := func(name string) tcp2.Handler {
+				return tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+					defer conn.Close()
+
+					line, err := bufio.NewReader(conn).ReadString('\n')
+					if err != nil {
+						return
+					}
+
+					_, _ = conn.Write([]byte(name + ": " + line))
+				})
+			}
+
+			err = router.AddRoute("ssh", "Protocol(`ssh`)", 0, echo("ssh"))
+			require.NoError(t, err)
+
+			err = router.AddRoute("custom", "PeekRegexp(`^HELLO [a-z]+`)", 0, echo("custom"))
+			require.NoError(t, err)
+
+			if test.withTLSRouter {
+				err = router.muxerTCPTLS.AddRoute("", "HostSNI(`example.com`)", 0, echo("tls"))
+				require.NoError(t, err)
+			}
+
+			for _, data := range []string{"SSH-2.0-OpenSSH_9.6\r\n", "HELLO world\n"} {
+				listener, err := net.Listen("tcp", "127.0.0.1:0")
+				require.NoError(t, err)
+
+				go func() {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+
+					router.ServeTCP(conn.(*net.TCPConn))
+				}()
+
+				conn, err := net.Dial("tcp", listener.Addr().String())
+				require.NoError(t, err)
+
+				// The first bytes are sent in two writes, to check that none is lost while peeking.
+				_, err = conn.Write([]byte(data[:3]))
+				require.NoError(t, err)
+
+				time.Sleep(10 * time.Millisecond)
+
+				_, err = conn.Write([]byte(data[3:]))
+				require.NoError(t, err)
+
+				reply, err := bufio.NewReader(conn).ReadString('\n')
+				require.NoError(t, err)
+
+				expected := "ssh: " + data
+				if strings.HasPrefix(data, "HELLO") {
+					expected = "custom: " + data
+				}
+				assert.Equal(t, expected, reply)
+
+				_ = conn.Close()
+				_ = listener.Close()
+			}
+		})
+	}
+}
+
+func TestClientHelloFingerprint(t *testing.T) {
+	// The first connection is routed by a catch-all route, exposing the fingerprints of the client.
+	catchAll, err := NewRouter()
+	require.NoError(t, err)
+
+	fingerprints := make(chan *fingerprint.Fingerprints, 1)
+	err = catchAll.muxerTCPTLS.AddRoute("", "HostSNI(`*`)", 0, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+		fingerprints <- fingerprint.FromConn(conn)
+		_ = conn.Close()
+	}))
+	require.NoError(t, err)
+
+	serveTLSClient(t, catchAll)
+
+	fp := <-fingerprints
+	require.NotNil(t, fp)
+
+	// The next connection of the same client is routed by its fingerprints.
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	matched := make(chan string, 2)
+	err = router.muxerTCPTLS.AddRoute("", "ClientHelloFingerprint(`foo`)", 0, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+		matched <- "foo"
+		_ = conn.Close()
+	}))
+	require.NoError(t, err)
+
+	err = router.muxerTCPTLS.AddRoute("", fmt.Sprintf("ClientHelloFingerprint(`%s`)", fp.JA4), 0, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+		matched <- "ja4"
+		_ = conn.Close()
+	}))
+	require.NoError(t, err)
+
+	serveTLSClient(t, router)
+
+	assert.Equal(t, "ja4", <-matched)
+}
+
+// serveTLSClient serves, with the given router, the connection of a TLS client.
+func serveTLSClient(t *testing.T, router *Router) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_ = tls.Client(conn, &tls.Config{
+			ServerName:         "example.com",
+			NextProtos:         []string{"h2", "http/1.1"},
+			InsecureSkipVerify: true,
+		}).Handshake()
+	}()
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+
+	router.ServeTCP(conn.(*net.TCPConn))
+}
+
 func NewMockConn() *MockConn {
 	return &MockConn{
 		dataRead:  make(chan []byte),
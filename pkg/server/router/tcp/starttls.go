@@ -0,0 +1,252 @@
+package tcp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// maxStartTLSReplySize is the maximum size of the backend replies during a STARTTLS negotiation.
+const maxStartTLSReplySize = 64 * 1024
+
+var errInvalidReply = errors.New("invalid STARTTLS reply from the backend")
+
+// starttlsNegotiator negotiates a STARTTLS session with a client in a protocol in which the server speaks first.
+type starttlsNegotiator interface {
+	// negotiate negotiates the STARTTLS session with the client, until it can start the TLS handshake.
+	negotiate(conn tcp.WriteCloser, br *bufio.Reader) error
+	// backendHandler returns the handler of the connections, once negotiated, forwarded by the given handler.
+	backendHandler(handler tcp.Handler) (tcp.Handler, error)
+}
+
+// SetStartTLSProtocol sets the protocol, in which the server speaks first,
+// used to negotiate STARTTLS sessions with all the clients of the router.
+// An empty protocol disables the negotiation.
+func (r *Router) SetStartTLSProtocol(protocol string) error {
+	switch protocol {
+	case "":
+		r.starttls = nil
+	case static.StartTLSMySQL:
+		r.starttls = mysqlNegotiator{}
+	case static.StartTLSSMTP:
+		r.starttls = smtpNegotiator
+	case static.StartTLSIMAP:
+		r.starttls = imapNegotiator
+	case static.StartTLSPOP3:
+		r.starttls = pop3Negotiator
+	default:
+		return fmt.Errorf("unknown STARTTLS protocol %q", protocol)
+	}
+
+	return nil
+}
+
+// serveStartTLS serves a connection with a client of the server-first protocol of the router.
+// It handles TCP TLS routing, after negotiating the STARTTLS session with the client.
+func (r *Router) serveStartTLS(conn tcp.WriteCloser) {
+	br := bufio.NewReader(conn)
+
+	if err := r.starttls.negotiate(conn, br); err != nil {
+		log.Debug().Err(err).Msg("Error while negotiating STARTTLS")
+		conn.Close()
+		return
+	}
+
+	r.routeStartTLS(conn, br, r.starttls.backendHandler)
+}
+
+// routeStartTLS handles the TCP TLS routing of a connection on which a STARTTLS session has been negotiated.
+// The given function returns the handler of the connection, from the handler of the matching TCP TLS route.
+func (r *Router) routeStartTLS(conn tcp.WriteCloser, br *bufio.Reader, backendHandler func(tcp.Handler) (tcp.Handler, error)) {
+	hello, err := clientHelloInfo(br)
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	if !hello.isTLS {
+		conn.Close()
+		return
+	}
+
+	// Remove read/write deadline and delegate this to underlying tcp server.
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		log.Error().Err(err).Msg("Error while setting deadline")
+	}
+
+	connData, err := tcpmuxer.NewConnData(hello.serverName, conn, hello.protos, hello.fingerprints, tcpmuxer.NewPeeker(nil, bufio.NewReader(strings.NewReader(hello.peeked))))
+	if err != nil {
+		log.Error().Err(err).Msg("Error while reading TCP connection data")
+		conn.Close()
+		return
+	}
+
+	// Contains also TCP TLS passthrough routes.
+	handlerTCPTLS, _ := r.muxerTCPTLS.Match(connData)
+	if handlerTCPTLS == nil {
+		conn.Close()
+		return
+	}
+
+	handler, err := backendHandler(handlerTCPTLS)
+	if err != nil {
+		log.Error().Err(err).Str("serverName", hello.serverName).Msg("Error while handling STARTTLS connection")
+		conn.Close()
+		return
+	}
+
+	handler.ServeTCP(r.getHelloConn(conn, hello))
+}
+
+// starttlsStep is a step of a STARTTLS negotiation with a backend.
+type starttlsStep struct {
+	// request is sent to the backend, if not nil.
+	request []byte
+	// reply consumes the reply of the backend to the request from the given bytes.
+	// It returns the number of consumed bytes, or zero when the reply is not complete yet.
+	reply func(b []byte) (int, error)
+}
+
+// starttlsConn is a tcp.WriteCloser which negotiates a session with the backend,
+// before exchanging any data with the client.
+// It is used to negotiate a STARTTLS session with a backend, on behalf of a client with which it has already been negotiated,
+// or to drop the greeting of a backend which the client has already received.
+type starttlsConn struct {
+	tcp.WriteCloser
+
+	steps []starttlsStep
+
+	// requests holds the requests to send to the backend, and is closed once the negotiation is over.
+	requests chan []byte
+	// err is the error of the negotiation, set before requests is closed.
+	err error
+
+	// pending holds the bytes of the request being read.
+	pending []byte
+	// replies holds the bytes of the backend replies being written.
+	replies []byte
+}
+
+func newStartTLSConn(conn tcp.WriteCloser, steps ...starttlsStep) *starttlsConn {
+	c := &starttlsConn{
+		WriteCloser: conn,
+		steps:       steps,
+		requests:    make(chan []byte, len(steps)),
+	}
+
+	if len(steps) > 0 && steps[0].request != nil {
+		c.requests <- steps[0].request
+	}
+
+	return c
+}
+
+// Read reads bytes from the underlying connection (tcp.WriteCloser).
+// While the negotiation with the backend is not over, it only returns the requests of the negotiation.
+// Read does not support concurrent calls.
+func (c *starttlsConn) Read(p []byte) (int, error) {
+	if len(c.pending) > 0 {
+		n := copy(p, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+
+	if c.requests == nil {
+		return c.WriteCloser.Read(p)
+	}
+
+	request, ok := <-c.requests
+	if !ok {
+		if c.err != nil {
+			return 0, c.err
+		}
+
+		c.requests = nil
+		return c.WriteCloser.Read(p)
+	}
+
+	n := copy(p, request)
+	c.pending = request[n:]
+
+	return n, nil
+}
+
+// Write writes bytes to the underlying connection (tcp.WriteCloser).
+// While the negotiation with the backend is not over, it consumes the bytes as the replies of the backend.
+// Write does not support concurrent calls.
+func (c *starttlsConn) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	if len(c.steps) == 0 {
+		return c.WriteCloser.Write(p)
+	}
+
+	c.replies = append(c.replies, p...)
+	if len(c.replies) > maxStartTLSReplySize {
+		c.fail(errInvalidReply)
+		return 0, errInvalidReply
+	}
+
+	for len(c.steps) > 0 {
+		n, err := c.steps[0].reply(c.replies)
+		if err != nil {
+			c.fail(err)
+			return 0, err
+		}
+
+		if n == 0 {
+			return len(p), nil
+		}
+
+		c.replies = c.replies[n:]
+		c.steps = c.steps[1:]
+
+		if len(c.steps) > 0 && c.steps[0].request != nil {
+			c.requests <- c.steps[0].request
+		}
+	}
+
+	close(c.requests)
+
+	if len(c.replies) > 0 {
+		if _, err := c.WriteCloser.Write(c.replies); err != nil {
+			return 0, err
+		}
+	}
+	c.replies = nil
+
+	return len(p), nil
+}
+
+// NetConn returns the underlying connection.
+func (c *starttlsConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// fail ends the negotiation with the given error.
+func (c *starttlsConn) fail(err error) {
+	c.err = err
+	c.steps = nil
+	close(c.requests)
+}
+
+// wrapTLSHandler returns a handler which terminates TLS like the given handler,
+// but wraps the decrypted connection with the given function before forwarding it.
+func wrapTLSHandler(handler *tcp.TLSHandler, wrap func(tcp.WriteCloser) tcp.WriteCloser) tcp.Handler {
+	return &tcp.TLSHandler{
+		Config: handler.Config,
+		Next: tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+			handler.Next.ServeTCP(wrap(conn))
+		}),
+	}
+}
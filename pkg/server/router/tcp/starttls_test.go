@@ -0,0 +1,327 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcp2 "github.com/traefik/traefik/v3/pkg/tcp"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
+)
+
+func TestStartTLSConn(t *testing.T) {
+	underlying := &bufferConn{reader: strings.NewReader("client data")}
+
+	conn := newStartTLSConn(underlying,
+		starttlsStep{reply: smtpReply("220")},
+		starttlsStep{request: []byte("EHLO traefik\r\n"), reply: smtpReply("250")},
+	)
+
+	read := make(chan string)
+	go func() {
+		for {
+			b := make([]byte, 64)
+			n, err := conn.Read(b)
+			if err != nil {
+				close(read)
+				return
+			}
+			read <- string(b[:n])
+		}
+	}()
+
+	_, err := conn.Write([]byte("220 backend "))
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("ready\r\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "EHLO traefik\r\n", <-read)
+
+	_, err = conn.Write([]byte("250-backend\r\n250 STARTTLS\r\nbackend data"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "client data", <-read)
+	assert.Equal(t, "backend data", underlying.written.String())
+
+	_, err = conn.Write([]byte(" and more"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "backend data and more", underlying.written.String())
+}
+
+func TestStartTLSConn_invalidReply(t *testing.T) {
+	underlying := &bufferConn{reader: strings.NewReader("client data")}
+
+	conn := newStartTLSConn(underlying, starttlsStep{reply: smtpReply("220")})
+
+	_, err := conn.Write([]byte("554 No SMTP service here\r\n"))
+	require.ErrorIs(t, err, errInvalidReply)
+
+	_, err = conn.Read(make([]byte, 64))
+	require.ErrorIs(t, err, errInvalidReply)
+
+	_, err = conn.Write([]byte("data"))
+	require.ErrorIs(t, err, errInvalidReply)
+
+	assert.Empty(t, underlying.written.String())
+}
+
+func TestRouter_StartTLS(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		protocol string
+		// dialog is the plaintext dialog of the client with Traefik, which ends with the STARTTLS command.
+		dialog []exchange
+		// backendGreeting is sent by the backend, and must not reach the client.
+		backendGreeting string
+		// session is the exchange of the client with the backend, within the TLS session.
+		session exchange
+	}{
+		{
+			desc:     "SMTP",
+			protocol: "smtp",
+			dialog: []exchange{
+				{expected: "220 traefik ESMTP ready\r\n"},
+				{send: "MAIL FROM:<foo@example.com>\r\n", expected: "530 5.7.0 Must issue a STARTTLS command first\r\n"},
+				{send: "EHLO client\r\n", expected: "250-traefik\r\n250 STARTTLS\r\n"},
+				{send: "STARTTLS\r\n", expected: "220 2.0.0 Ready to start TLS\r\n"},
+			},
+			backendGreeting: "220 backend ESMTP\r\n",
+			session:         exchange{send: "EHLO client\r\n", expected: "250-backend\r\n250 AUTH PLAIN\r\n"},
+		},
+		{
+			desc:     "IMAP",
+			protocol: "imap",
+			dialog: []exchange{
+				{expected: "* OK [CAPABILITY IMAP4rev1 STARTTLS LOGINDISABLED] traefik ready\r\n"},
+				{send: "a1 CAPABILITY\r\n", expected: "* CAPABILITY IMAP4rev1 STARTTLS LOGINDISABLED\r\na1 OK CAPABILITY completed\r\n"},
+				{send: "a2 LOGIN foo bar\r\n", expected: "a2 BAD Must issue a STARTTLS command first\r\n"},
+				{send: "a3 starttls\r\n", expected: "a3 OK Begin TLS negotiation now\r\n"},
+			},
+			backendGreeting: "* OK backend ready\r\n",
+			session:         exchange{send: "a4 NOOP\r\n", expected: "a4 OK NOOP completed\r\n"},
+		},
+		{
+			desc:     "POP3",
+			protocol: "pop3",
+			dialog: []exchange{
+				{expected: "+OK traefik ready\r\n"},
+				{send: "CAPA\r\n", expected: "+OK Capability list follows\r\nSTLS\r\n.\r\n"},
+				{send: "USER foo\r\n", expected: "-ERR Must issue a STLS command first\r\n"},
+				{send: "STLS\r\n", expected: "+OK Begin TLS negotiation\r\n"},
+			},
+			backendGreeting: "+OK backend ready\r\n",
+			session:         exchange{send: "USER foo\r\n", expected: "+OK\r\n"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			require.NoError(t, router.SetStartTLSProtocol(test.protocol))
+
+			backend := tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+				defer conn.Close()
+
+				_, _ = conn.Write([]byte(test.backendGreeting))
+
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil || line != test.session.send {
+					return
+				}
+
+				_, _ = conn.Write([]byte(test.session.expected))
+			})
+
+			err = router.muxerTCPTLS.AddRoute("", "HostSNI(`example.com`)", 0, newTLSHandler(t, backend))
+			require.NoError(t, err)
+
+			conn := dialRouter(t, router)
+
+			br := bufio.NewReader(conn)
+			for _, e := range test.dialog {
+				e.check(t, br, conn)
+			}
+
+			tlsConn := tls.Client(conn, &tls.Config{ServerName: "example.com", InsecureSkipVerify: true})
+			test.session.check(t, bufio.NewReader(tlsConn), tlsConn)
+		})
+	}
+}
+
+func TestRouter_SetStartTLSProtocol(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	require.NoError(t, router.SetStartTLSProtocol("smtp"))
+	assert.NotNil(t, router.starttls)
+
+	assert.Error(t, router.SetStartTLSProtocol("ftp"))
+
+	require.NoError(t, router.SetStartTLSProtocol(""))
+	assert.Nil(t, router.starttls)
+}
+
+func TestRouter_StartTLS_passthrough(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	require.NoError(t, router.SetStartTLSProtocol("smtp"))
+
+	// The backend negotiates STARTTLS with Traefik, on behalf of the client.
+	backend := tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+		defer conn.Close()
+
+		br := bufio.NewReader(conn)
+		for _, e := range []exchange{
+			{send: "220 backend ESMTP\r\n", expected: "EHLO traefik\r\n"},
+			{send: "250-backend\r\n250 STARTTLS\r\n", expected: "STARTTLS\r\n"},
+		} {
+			_, _ = conn.Write([]byte(e.send))
+
+			line, err := br.ReadString('\n')
+			if err != nil || line != e.expected {
+				return
+			}
+		}
+
+		_, _ = conn.Write([]byte("220 2.0.0 Go ahead\r\n"))
+
+		tlsConn := tls.Server(&Conn{WriteCloser: conn, Peeked: []byte(getPeeked(br))}, newTLSConfig(t))
+
+		line, err := bufio.NewReader(tlsConn).ReadString('\n')
+		if err != nil || line != "EHLO client\r\n" {
+			return
+		}
+
+		_, _ = tlsConn.Write([]byte("250 backend\r\n"))
+	})
+
+	err = router.muxerTCPTLS.AddRoute("", "HostSNI(`example.com`)", 0, backend)
+	require.NoError(t, err)
+
+	conn := dialRouter(t, router)
+
+	br := bufio.NewReader(conn)
+	exchange{expected: "220 traefik ESMTP ready\r\n"}.check(t, br, conn)
+	exchange{send: "STARTTLS\r\n", expected: "220 2.0.0 Ready to start TLS\r\n"}.check(t, br, conn)
+
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: "example.com", InsecureSkipVerify: true})
+	exchange{send: "EHLO client\r\n", expected: "250 backend\r\n"}.check(t, bufio.NewReader(tlsConn), tlsConn)
+}
+
+func TestRouter_StartTLS_pipelining(t *testing.T) {
+	router, err := NewRouter()
+	require.NoError(t, err)
+
+	require.NoError(t, router.SetStartTLSProtocol("smtp"))
+
+	err = router.muxerTCPTLS.AddRoute("", "HostSNI(`*`)", 0, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+		t.Error("the handler must not be called")
+	}))
+	require.NoError(t, err)
+
+	conn := dialRouter(t, router)
+
+	br := bufio.NewReader(conn)
+	exchange{expected: "220 traefik ESMTP ready\r\n"}.check(t, br, conn)
+
+	_, err = conn.Write([]byte("STARTTLS\r\nMAIL FROM:<foo@example.com>\r\n"))
+	require.NoError(t, err)
+
+	_, err = br.ReadString('\n')
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+// exchange is a message sent to a connection, and the reply expected from it.
+type exchange struct {
+	send     string
+	expected string
+}
+
+func (e exchange) check(t *testing.T, r *bufio.Reader, w io.Writer) {
+	t.Helper()
+
+	if e.send != "" {
+		_, err := w.Write([]byte(e.send))
+		require.NoError(t, err)
+	}
+
+	reply := make([]byte, len(e.expected))
+	_, err := io.ReadFull(r, reply)
+	require.NoError(t, err)
+
+	assert.Equal(t, e.expected, string(reply))
+}
+
+// dialRouter returns a connection to the given router.
+func dialRouter(t *testing.T, router *Router) net.Conn {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		router.ServeTCP(conn.(*net.TCPConn))
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// newTLSHandler returns a handler terminating TLS with a self-signed certificate.
+func newTLSHandler(t *testing.T, next tcp2.Handler) *tcp2.TLSHandler {
+	t.Helper()
+
+	return &tcp2.TLSHandler{Next: next, Config: newTLSConfig(t)}
+}
+
+func newTLSConfig(t *testing.T) *tls.Config {
+	t.Helper()
+
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	return &tls.Config{Certificates: []tls.Certificate{*cert}}
+}
+
+// bufferConn is a tcp.WriteCloser reading from a reader, and writing to a buffer.
+type bufferConn struct {
+	net.Conn
+
+	reader  io.Reader
+	written bytes.Buffer
+}
+
+func (c *bufferConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+func (c *bufferConn) Write(p []byte) (int, error) {
+	return c.written.Write(p)
+}
+
+func (c *bufferConn) CloseWrite() error {
+	return errors.New("not implemented")
+}
@@ -8,25 +8,33 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/logs"
+	udpmuxer "github.com/traefik/traefik/v3/pkg/muxer/udp"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	udpservice "github.com/traefik/traefik/v3/pkg/server/service/udp"
 	"github.com/traefik/traefik/v3/pkg/udp"
 )
 
+type middlewareBuilder interface {
+	BuildChain(ctx context.Context, names []string) *udp.Chain
+}
+
 // NewManager Creates a new Manager.
 func NewManager(conf *runtime.Configuration,
 	serviceManager *udpservice.Manager,
+	middlewaresBuilder middlewareBuilder,
 ) *Manager {
 	return &Manager{
-		serviceManager: serviceManager,
-		conf:           conf,
+		serviceManager:     serviceManager,
+		middlewaresBuilder: middlewaresBuilder,
+		conf:               conf,
 	}
 }
 
 // Manager is a route/router manager.
 type Manager struct {
-	serviceManager *udpservice.Manager
-	conf           *runtime.Configuration
+	serviceManager     *udpservice.Manager
+	middlewaresBuilder middlewareBuilder
+	conf               *runtime.Configuration
 }
 
 func (m *Manager) getUDPRouters(ctx context.Context, entryPoints []string) map[string]map[string]*runtime.UDPRouterInfo {
@@ -50,21 +58,27 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 		logger := log.Ctx(rootCtx).With().Str(logs.EntryPointName, entryPointName).Logger()
 		ctx := logger.WithContext(rootCtx)
 
-		if len(routers) > 1 {
-			logger.Warn().Msg("Config has more than one udp router for a given entrypoint.")
+		handler, err := m.buildEntryPointHandler(ctx, routers)
+		if err != nil {
+			logger.Error().Err(err).Send()
+			continue
 		}
 
-		handlers := m.buildEntryPointHandlers(ctx, routers)
-
-		if len(handlers) > 0 {
-			// As UDP support only one router per entrypoint, we only take the first one.
-			entryPointHandlers[entryPointName] = handlers[0]
+		if handler != nil {
+			entryPointHandlers[entryPointName] = handler
 		}
 	}
 	return entryPointHandlers
 }
 
-func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[string]*runtime.UDPRouterInfo) []udp.Handler {
+// buildEntryPointHandler builds the handler routing the sessions of an entry point to the given routers.
+// It returns nil if none of the routers can handle sessions.
+func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string]*runtime.UDPRouterInfo) (udp.Handler, error) {
+	muxer, err := udpmuxer.NewMuxer()
+	if err != nil {
+		return nil, err
+	}
+
 	var rtNames []string
 	for routerName := range configs {
 		rtNames = append(rtNames, routerName)
@@ -74,29 +88,107 @@ func (m *Manager) buildEntryPointHandlers(ctx context.Context, configs map[strin
 		return rtNames[i] > rtNames[j]
 	})
 
-	var handlers []udp.Handler
+	var fallbacks []udp.Handler
 
 	for _, routerName := range rtNames {
 		routerConfig := configs[routerName]
 		logger := log.Ctx(ctx).With().Str(logs.RouterName, routerName).Logger()
 		ctxRouter := logger.WithContext(provider.AddInContext(ctx, routerName))
 
-		if routerConfig.Service == "" {
-			err := errors.New("the service is missing on the udp router")
+		if routerConfig.Priority == 0 {
+			routerConfig.Priority = udpmuxer.GetRulePriority(routerConfig.Rule)
+		}
+
+		handler, err := m.buildUDPHandler(ctxRouter, routerConfig)
+		if err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error().Err(err).Send()
 			continue
 		}
 
-		handler, err := m.serviceManager.BuildUDP(ctxRouter, routerConfig.Service)
-		if err != nil {
+		if routerConfig.Rule == "" {
+			fallbacks = append(fallbacks, handler)
+			continue
+		}
+
+		logger.Debug().Msgf("Adding route for %q", routerConfig.Rule)
+
+		if err := muxer.AddRoute(routerName, routerConfig.Rule, routerConfig.Priority, handler); err != nil {
 			routerConfig.AddError(err, true)
 			logger.Error().Err(err).Send()
 			continue
 		}
+	}
+
+	if len(fallbacks) > 1 {
+		log.Ctx(ctx).Warn().Msg("Config has more than one udp router without rule for a given entrypoint.")
+	}
+
+	var fallback udp.Handler
+	if len(fallbacks) > 0 {
+		// As only one router without rule can handle the sessions which are not matched by the others, we only take the first one.
+		fallback = fallbacks[0]
+	}
+
+	if !muxer.HasRoutes() {
+		return fallback, nil
+	}
+
+	return &router{muxer: muxer, fallback: fallback}, nil
+}
+
+func (m *Manager) buildUDPHandler(ctx context.Context, router *runtime.UDPRouterInfo) (udp.Handler, error) {
+	var qualifiedNames []string
+	for _, name := range router.Middlewares {
+		qualifiedNames = append(qualifiedNames, provider.GetQualifiedName(ctx, name))
+	}
+	router.Middlewares = qualifiedNames
+
+	if router.Service == "" {
+		return nil, errors.New("the service is missing on the udp router")
+	}
+
+	sHandler, err := m.serviceManager.BuildUDP(ctx, router.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	mHandler := m.middlewaresBuilder.BuildChain(ctx, router.Middlewares)
+
+	return udp.NewChain().Extend(*mHandler).Then(sHandler)
+}
+
+// router routes the sessions of an entry point, on their client IP and first datagram.
+type router struct {
+	muxer *udpmuxer.Muxer
+	// fallback handles the sessions which are not matched by the routes of the muxer, if not nil.
+	fallback udp.Handler
+}
+
+// ServeUDP forwards the session to the handler of the first route matching it.
+func (r *router) ServeUDP(conn *udp.Conn) {
+	payload, err := conn.Peek()
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	connData, err := udpmuxer.NewConnData(conn.RemoteAddr(), payload)
+	if err != nil {
+		log.Error().Err(err).Msg("Error while reading session metadata")
+		conn.Close()
+		return
+	}
+
+	if handler := r.muxer.Match(connData); handler != nil {
+		handler.ServeUDP(conn)
+		return
+	}
 
-		handlers = append(handlers, handler)
+	if r.fallback != nil {
+		r.fallback.ServeUDP(conn)
+		return
 	}
 
-	return handlers
+	conn.Close()
 }
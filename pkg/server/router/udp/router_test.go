@@ -2,20 +2,28 @@ package udp
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
-	"github.com/traefik/traefik/v3/pkg/server/service/udp"
+	udpmuxer "github.com/traefik/traefik/v3/pkg/muxer/udp"
+	udpmiddleware "github.com/traefik/traefik/v3/pkg/server/middleware/udp"
+	udpservice "github.com/traefik/traefik/v3/pkg/server/service/udp"
+	"github.com/traefik/traefik/v3/pkg/udp"
 )
 
 func TestRuntimeConfiguration(t *testing.T) {
 	testCases := []struct {
-		desc          string
-		serviceConfig map[string]*runtime.UDPServiceInfo
-		routerConfig  map[string]*runtime.UDPRouterInfo
-		expectedError int
+		desc             string
+		serviceConfig    map[string]*runtime.UDPServiceInfo
+		middlewareConfig map[string]*runtime.UDPMiddlewareInfo
+		routerConfig     map[string]*runtime.UDPRouterInfo
+		expectedError    int
 	}{
 		{
 			desc: "No error",
@@ -103,6 +111,81 @@ func TestRuntimeConfiguration(t *testing.T) {
 			},
 			expectedError: 2,
 		},
+		{
+			desc: "Router with invalid rule",
+			serviceConfig: map[string]*runtime.UDPServiceInfo{
+				"foo-service": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "127.0.0.1:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.UDPRouterInfo{
+				"foo": {
+					UDPRouter: &dynamic.UDPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "PayloadPrefix(`invalid`)",
+					},
+				},
+				"bar": {
+					UDPRouter: &dynamic.UDPRouter{
+						EntryPoints: []string{"web"},
+						Service:     "foo-service",
+						Rule:        "ClientIP(`10.0.0.0/8`)",
+					},
+				},
+			},
+			expectedError: 1,
+		},
+		{
+			desc: "Router with unknown middleware",
+			serviceConfig: map[string]*runtime.UDPServiceInfo{
+				"foo-service": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "127.0.0.1:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			middlewareConfig: map[string]*runtime.UDPMiddlewareInfo{
+				"allowlist": {
+					UDPMiddleware: &dynamic.UDPMiddleware{
+						IPAllowList: &dynamic.UDPIPAllowList{
+							SourceRange: []string{"10.0.0.0/8"},
+						},
+					},
+				},
+			},
+			routerConfig: map[string]*runtime.UDPRouterInfo{
+				"foo": {
+					UDPRouter: &dynamic.UDPRouter{
+						EntryPoints: []string{"web"},
+						Middlewares: []string{"unknown"},
+						Service:     "foo-service",
+					},
+				},
+				"bar": {
+					UDPRouter: &dynamic.UDPRouter{
+						EntryPoints: []string{"web"},
+						Middlewares: []string{"allowlist"},
+						Service:     "foo-service",
+					},
+				},
+			},
+			expectedError: 1,
+		},
 	}
 
 	for _, test := range testCases {
@@ -114,11 +197,13 @@ func TestRuntimeConfiguration(t *testing.T) {
 			entryPoints := []string{"web"}
 
 			conf := &runtime.Configuration{
-				UDPServices: test.serviceConfig,
-				UDPRouters:  test.routerConfig,
+				UDPServices:    test.serviceConfig,
+				UDPMiddlewares: test.middlewareConfig,
+				UDPRouters:     test.routerConfig,
 			}
-			serviceManager := udp.NewManager(conf)
-			routerManager := NewManager(conf, serviceManager)
+			serviceManager := udpservice.NewManager(conf)
+			middlewaresBuilder := udpmiddleware.NewBuilder(conf.UDPMiddlewares)
+			routerManager := NewManager(conf, serviceManager, middlewaresBuilder)
 
 			_ = routerManager.BuildHandlers(context.Background(), entryPoints)
 
@@ -140,3 +225,97 @@ func TestRuntimeConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestRouter_ServeUDP(t *testing.T) {
+	muxer, err := udpmuxer.NewMuxer()
+	require.NoError(t, err)
+
+	err = muxer.AddRoute("quic", "PayloadPrefix(`c?00000001`)", 0, newReplyHandler("quic"))
+	require.NoError(t, err)
+
+	err = muxer.AddRoute("other", "ClientIP(`10.0.0.0/8`)", 0, newReplyHandler("other"))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		fallback udp.Handler
+		payload  []byte
+		expected string
+	}{
+		{
+			desc:     "matching route",
+			payload:  []byte{0xc3, 0x00, 0x00, 0x00, 0x01, 0x08},
+			expected: "quic c30000000108",
+		},
+		{
+			desc:     "fallback",
+			fallback: newReplyHandler("fallback"),
+			payload:  []byte{0x12, 0x34},
+			expected: "fallback 1234",
+		},
+		{
+			desc:    "no fallback",
+			payload: []byte{0x12, 0x34},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			addr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			listener, err := udp.Listen("udp", addr, 3*time.Second)
+			require.NoError(t, err)
+
+			t.Cleanup(func() { _ = listener.Close() })
+
+			router := &router{muxer: muxer, fallback: test.fallback}
+
+			go func() {
+				for {
+					conn, err := listener.Accept()
+					if err != nil {
+						return
+					}
+
+					go router.ServeUDP(conn)
+				}
+			}()
+
+			conn, err := net.Dial("udp", listener.Addr().String())
+			require.NoError(t, err)
+
+			_, err = conn.Write(test.payload)
+			require.NoError(t, err)
+
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(500*time.Millisecond)))
+
+			b := make([]byte, 64)
+			n, err := conn.Read(b)
+			if test.expected == "" {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(b[:n]))
+		})
+	}
+}
+
+// newReplyHandler returns a handler replying to the first datagram of the session with the given name,
+// followed by the datagram in hex.
+func newReplyHandler(name string) udp.Handler {
+	return udp.HandlerFunc(func(conn *udp.Conn) {
+		b := make([]byte, 64)
+		n, err := conn.Read(b)
+		if err != nil {
+			return
+		}
+
+		_, _ = conn.Write([]byte(fmt.Sprintf("%s %x", name, b[:n])))
+	})
+}
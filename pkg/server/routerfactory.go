@@ -4,11 +4,14 @@ import (
 	"context"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/api"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/config/static"
 	"github.com/traefik/traefik/v3/pkg/metrics"
+	"github.com/traefik/traefik/v3/pkg/middlewares/retry"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
 	tcpmiddleware "github.com/traefik/traefik/v3/pkg/server/middleware/tcp"
+	udpmiddleware "github.com/traefik/traefik/v3/pkg/server/middleware/udp"
 	"github.com/traefik/traefik/v3/pkg/server/router"
 	tcprouter "github.com/traefik/traefik/v3/pkg/server/router/tcp"
 	udprouter "github.com/traefik/traefik/v3/pkg/server/router/udp"
@@ -35,6 +38,10 @@ type RouterFactory struct {
 
 	dialerManager *tcp.DialerManager
 
+	// retryBudgets are kept across configuration reloads,
+	// so that the retry middlewares of the old and new configurations share the in-flight counts.
+	retryBudgets *retry.Budgets
+
 	cancelPrevState func()
 }
 
@@ -66,6 +73,7 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 		chainBuilder:    chainBuilder,
 		pluginBuilder:   pluginBuilder,
 		dialerManager:   dialerManager,
+		retryBudgets:    retry.NewBudgets(),
 	}
 }
 
@@ -81,13 +89,18 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 	// HTTP
 	serviceManager := f.managerFactory.Build(rtConf)
 
-	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder)
+	middlewaresBuilder := middleware.NewBuilder(rtConf.Middlewares, serviceManager, f.pluginBuilder, f.retryBudgets)
 
 	routerManager := router.NewManager(rtConf, serviceManager, middlewaresBuilder, f.chainBuilder, f.metricsRegistry, f.tlsManager)
 
 	handlersNonTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, false)
 	handlersTLS := routerManager.BuildHandlers(ctx, f.entryPointsTCP, true)
 
+	f.retryBudgets.Prune(func(serviceName string) bool {
+		_, ok := rtConf.Services[serviceName]
+		return ok
+	})
+
 	serviceManager.LaunchHealthCheck(ctx)
 
 	// TCP
@@ -100,10 +113,34 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 
 	// UDP
 	svcUDPManager := udpsvc.NewManager(rtConf)
-	rtUDPManager := udprouter.NewManager(rtConf, svcUDPManager)
+	middlewaresUDPBuilder := udpmiddleware.NewBuilder(rtConf.UDPMiddlewares)
+
+	rtUDPManager := udprouter.NewManager(rtConf, svcUDPManager, middlewaresUDPBuilder)
 	routersUDP := rtUDPManager.BuildHandlers(ctx, f.entryPointsUDP)
 
+	if routeExplainer := f.managerFactory.RouteExplainer(); routeExplainer != nil {
+		routeExplainer.Update(entryPointMuxers(routerManager, routersTCP))
+	}
+
 	rtConf.PopulateUsedBy()
 
 	return routersTCP, routersUDP
 }
+
+// entryPointMuxers returns the live muxers of the TCP entry points.
+func entryPointMuxers(routerManager *router.Manager, routersTCP map[string]*tcprouter.Router) map[string]api.EntryPointMuxers {
+	httpMuxers := routerManager.GetMuxers(false)
+	httpsMuxers := routerManager.GetMuxers(true)
+
+	muxers := make(map[string]api.EntryPointMuxers, len(routersTCP))
+	for entryPointName, routerTCP := range routersTCP {
+		muxers[entryPointName] = api.EntryPointMuxers{
+			HTTP:   httpMuxers[entryPointName],
+			HTTPS:  httpsMuxers[entryPointName],
+			TCP:    routerTCP.GetTCPMuxer(),
+			TCPTLS: routerTCP.GetTCPTLSMuxer(),
+		}
+	}
+
+	return muxers
+}
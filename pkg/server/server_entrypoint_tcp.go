@@ -27,10 +27,13 @@ import (
 	"github.com/traefik/traefik/v3/pkg/middlewares/contenttype"
 	"github.com/traefik/traefik/v3/pkg/middlewares/forwardedheaders"
 	"github.com/traefik/traefik/v3/pkg/middlewares/requestdecorator"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tlvheaders"
+	"github.com/traefik/traefik/v3/pkg/proxyprotocol"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/traefik/traefik/v3/pkg/server/router"
 	tcprouter "github.com/traefik/traefik/v3/pkg/server/router/tcp"
 	"github.com/traefik/traefik/v3/pkg/tcp"
+	"github.com/traefik/traefik/v3/pkg/tls/fingerprint"
 	"github.com/traefik/traefik/v3/pkg/types"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
@@ -138,6 +141,7 @@ type TCPEntryPoint struct {
 	tracker                *connectionTracker
 	httpServer             *httpServer
 	httpsServer            *httpServer
+	startTLSProtocol       string
 
 	http3Server *http3server
 }
@@ -174,6 +178,15 @@ func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint, hos
 
 	rt.SetHTTPSForwarder(httpsServer.Forwarder)
 
+	var startTLSProtocol string
+	if configuration.StartTLS != nil {
+		startTLSProtocol = configuration.StartTLS.Protocol
+	}
+
+	if err := rt.SetStartTLSProtocol(startTLSProtocol); err != nil {
+		return nil, fmt.Errorf("error setting STARTTLS protocol: %w", err)
+	}
+
 	tcpSwitcher := &tcp.HandlerSwitcher{}
 	tcpSwitcher.Switch(rt)
 
@@ -184,6 +197,7 @@ func NewTCPEntryPoint(ctx context.Context, configuration *static.EntryPoint, hos
 		tracker:                tracker,
 		httpServer:             httpServer,
 		httpsServer:            httpsServer,
+		startTLSProtocol:       startTLSProtocol,
 		http3Server:            h3Server,
 	}, nil
 }
@@ -337,6 +351,10 @@ func (e *TCPEntryPoint) SwitchRouter(rt *tcprouter.Router) {
 
 	e.httpsServer.Switcher.UpdateHandler(httpsHandler)
 
+	if err := rt.SetStartTLSProtocol(e.startTLSProtocol); err != nil {
+		log.Error().Err(err).Msg("Error while setting STARTTLS protocol")
+	}
+
 	e.switcher.Switch(rt)
 
 	if e.http3Server != nil {
@@ -355,6 +373,11 @@ func (c *writeCloserWrapper) CloseWrite() error {
 	return c.writeCloser.CloseWrite()
 }
 
+// NetConn returns the wrapped connection.
+func (c *writeCloserWrapper) NetConn() net.Conn {
+	return c.Conn
+}
+
 // writeCloser returns the given connection, augmented with the WriteCloser
 // implementation, if any was found within the underlying conn.
 func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
@@ -553,6 +576,13 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		return nil, err
 	}
 
+	if configuration.ProxyProtocol != nil && len(configuration.ProxyProtocol.TLVHeaders) > 0 {
+		handler, err = tlvheaders.New(handler, configuration.ProxyProtocol.TLVHeaders)
+		if err != nil {
+			return nil, fmt.Errorf("creating PROXY protocol TLV headers: %w", err)
+		}
+	}
+
 	handler = http.AllowQuerySemicolons(handler)
 
 	handler = contenttype.DisableAutoDetection(handler)
@@ -569,6 +599,15 @@ func createHTTPServer(ctx context.Context, ln net.Listener, configuration *stati
 		ReadTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.ReadTimeout),
 		WriteTimeout: time.Duration(configuration.Transport.RespondingTimeouts.WriteTimeout),
 		IdleTimeout:  time.Duration(configuration.Transport.RespondingTimeouts.IdleTimeout),
+		ConnContext: func(ctx context.Context, conn net.Conn) context.Context {
+			if fingerprints := fingerprint.FromConn(conn); fingerprints != nil {
+				ctx = fingerprint.WithFingerprints(ctx, fingerprints)
+			}
+			if header := proxyprotocol.HeaderFromConn(conn); header != nil {
+				ctx = proxyprotocol.WithHeader(ctx, header)
+			}
+			return ctx
+		},
 	}
 
 	// ConfigureServer configures HTTP/2 with the MaxConcurrentStreams option for the given server.
@@ -616,3 +655,8 @@ func (t *trackedConnection) Close() error {
 	t.tracker.RemoveConnection(t.WriteCloser)
 	return t.WriteCloser.Close()
 }
+
+// NetConn returns the wrapped connection.
+func (t *trackedConnection) NetConn() net.Conn {
+	return t.WriteCloser
+}
@@ -0,0 +1,166 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+)
+
+type tier struct {
+	name       string
+	handler    http.Handler
+	minHealthy int
+	healthy    int
+}
+
+// Tiered is an http.Handler that forwards requests to the first of its ordered tiers
+// having at least its minimum number of healthy servers.
+// When all the tiers are below their minimum, the requests are forwarded to the first tier having a healthy server.
+type Tiered struct {
+	wantsHealthCheck bool
+	info             *runtime.ServiceInfo
+	// updaters is the list of hooks that are run (to update the Tiered
+	// parent(s)), whenever the Tiered status changes.
+	updaters []func(bool)
+
+	mu     sync.RWMutex
+	tiers  []*tier
+	active int // index of the tier receiving the requests, -1 if none.
+}
+
+// NewTiered creates a new Tiered handler, reporting the status of its tiers in the given service info.
+func NewTiered(hc *dynamic.HealthCheck, info *runtime.ServiceInfo) *Tiered {
+	return &Tiered{
+		wantsHealthCheck: hc != nil,
+		info:             info,
+		active:           -1,
+	}
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Tiered changes.
+// Not thread safe.
+func (t *Tiered) RegisterStatusUpdater(fn func(up bool)) error {
+	if !t.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this failover service")
+	}
+
+	t.updaters = append(t.updaters, fn)
+
+	return nil
+}
+
+// AddTier adds a tier with the lowest priority, having the given current number of healthy servers.
+// A minimum number of healthy servers lower than one is considered as one.
+func (t *Tiered) AddTier(name string, handler http.Handler, minHealthy, healthy int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if minHealthy < 1 {
+		minHealthy = 1
+	}
+
+	t.tiers = append(t.tiers, &tier{name: name, handler: handler, minHealthy: minHealthy, healthy: healthy})
+	t.update(context.Background())
+}
+
+// SetTierHealthy sets the number of healthy servers of the tier at the given index.
+func (t *Tiered) SetTierHealthy(ctx context.Context, index, healthy int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.tiers[index].healthy == healthy {
+		return
+	}
+
+	log.Ctx(ctx).Debug().Msgf("Setting number of healthy servers of tier %s to %d", t.tiers[index].name, healthy)
+
+	t.tiers[index].healthy = healthy
+	t.update(ctx)
+}
+
+// update elects the tier receiving the requests, reports the tiers status,
+// and propagates the status of the Tiered if it changed.
+// It must be called with the lock held.
+func (t *Tiered) update(ctx context.Context) {
+	active := -1
+	tiersStatus := make([]runtime.TierStatus, len(t.tiers))
+
+	for i, tr := range t.tiers {
+		tiersStatus[i] = runtime.TierStatus{
+			Service:    tr.name,
+			Status:     runtime.TierStatusDown,
+			Healthy:    tr.healthy,
+			MinHealthy: tr.minHealthy,
+		}
+
+		if tr.healthy < tr.minHealthy {
+			continue
+		}
+
+		if active >= 0 {
+			tiersStatus[i].Status = runtime.TierStatusStandby
+			continue
+		}
+
+		active = i
+		tiersStatus[i].Status = runtime.TierStatusActive
+	}
+
+	// A degraded tier is better than no tier at all.
+	if active < 0 {
+		for i, tr := range t.tiers {
+			if tr.healthy > 0 {
+				active = i
+				tiersStatus[i].Status = runtime.TierStatusActive
+				break
+			}
+		}
+	}
+
+	if t.info != nil {
+		t.info.UpdateTierStatus(tiersStatus)
+	}
+
+	upBefore := t.active >= 0
+	if active != t.active && active >= 0 {
+		log.Ctx(ctx).Debug().Msgf("Forwarding requests to tier %s", t.tiers[active].name)
+	}
+	t.active = active
+
+	upAfter := active >= 0
+	if upBefore == upAfter {
+		return
+	}
+
+	status := "DOWN"
+	if upAfter {
+		status = "UP"
+	}
+	log.Ctx(ctx).Debug().Msgf("Propagating new %s status", status)
+
+	for _, fn := range t.updaters {
+		fn(upAfter)
+	}
+}
+
+func (t *Tiered) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	t.mu.RLock()
+	var handler http.Handler
+	if t.active >= 0 {
+		handler = t.tiers[t.active].handler
+	}
+	t.mu.RUnlock()
+
+	if handler == nil {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	handler.ServeHTTP(w, req)
+}
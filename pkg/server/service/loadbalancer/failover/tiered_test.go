@@ -0,0 +1,100 @@
+package failover
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+)
+
+func serverHandler(name string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("server", name)
+		rw.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestTiered(t *testing.T) {
+	info := &runtime.ServiceInfo{}
+	tiered := NewTiered(&dynamic.HealthCheck{}, info)
+
+	status := true
+	require.NoError(t, tiered.RegisterStatusUpdater(func(up bool) {
+		status = up
+	}))
+
+	tiered.AddTier("region1", serverHandler("region1"), 2, 3)
+	tiered.AddTier("region2", serverHandler("region2"), 2, 2)
+	tiered.AddTier("region3", serverHandler("region3"), 0, 1)
+
+	assert.Equal(t, []runtime.TierStatus{
+		{Service: "region1", Status: runtime.TierStatusActive, Healthy: 3, MinHealthy: 2},
+		{Service: "region2", Status: runtime.TierStatusStandby, Healthy: 2, MinHealthy: 2},
+		{Service: "region3", Status: runtime.TierStatusStandby, Healthy: 1, MinHealthy: 1},
+	}, info.GetTierStatus())
+
+	var sequence []string
+	serve := func() {
+		recorder := httptest.NewRecorder()
+		tiered.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		sequence = append(sequence, recorder.Header().Get("server"))
+	}
+
+	serve()
+
+	// Under its threshold, the first tier spills to the second one.
+	tiered.SetTierHealthy(context.Background(), 0, 1)
+	serve()
+
+	tiered.SetTierHealthy(context.Background(), 1, 1)
+	serve()
+
+	assert.Equal(t, []runtime.TierStatus{
+		{Service: "region1", Status: runtime.TierStatusDown, Healthy: 1, MinHealthy: 2},
+		{Service: "region2", Status: runtime.TierStatusDown, Healthy: 1, MinHealthy: 2},
+		{Service: "region3", Status: runtime.TierStatusActive, Healthy: 1, MinHealthy: 1},
+	}, info.GetTierStatus())
+	assert.True(t, status)
+
+	// All the tiers are under their threshold, the first one with a healthy server receives the requests.
+	tiered.SetTierHealthy(context.Background(), 2, 0)
+	serve()
+	assert.True(t, status)
+
+	assert.Equal(t, []runtime.TierStatus{
+		{Service: "region1", Status: runtime.TierStatusActive, Healthy: 1, MinHealthy: 2},
+		{Service: "region2", Status: runtime.TierStatusDown, Healthy: 1, MinHealthy: 2},
+		{Service: "region3", Status: runtime.TierStatusDown, Healthy: 0, MinHealthy: 1},
+	}, info.GetTierStatus())
+
+	tiered.SetTierHealthy(context.Background(), 0, 0)
+	serve()
+	assert.True(t, status)
+
+	// No tier has a healthy server anymore.
+	tiered.SetTierHealthy(context.Background(), 1, 0)
+	serve()
+	assert.False(t, status)
+
+	// Back over its threshold, the first tier receives the requests again.
+	tiered.SetTierHealthy(context.Background(), 0, 2)
+	serve()
+	assert.True(t, status)
+
+	assert.Equal(t, []string{"region1", "region2", "region3", "region1", "region2", "", "region1"}, sequence)
+}
+
+func TestTieredNoTier(t *testing.T) {
+	tiered := NewTiered(nil, nil)
+
+	recorder := httptest.NewRecorder()
+	tiered.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Error(t, tiered.RegisterStatusUpdater(func(bool) {}))
+}
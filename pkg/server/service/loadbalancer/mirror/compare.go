@@ -0,0 +1,271 @@
+package mirror
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"math/rand"
+	"net"
+	"net/http"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// Reasons of a mismatch between a mirror response and the response of the service.
+const (
+	mismatchStatus = "status"
+	mismatchHeader = "header"
+	mismatchBody   = "body"
+)
+
+// comparison compares the responses of the mirrors with the response of the service.
+type comparison struct {
+	serviceName   string
+	headers       []string
+	body          string
+	maxBodySize   int64
+	logSampleRate float64
+	mismatches    gokitmetrics.Counter
+}
+
+// EnableComparison enables the comparison of the mirrors responses with the response of the service.
+// The given mismatches counter, which can be nil, is incremented for each mismatch,
+// with the service, mirror, and reason labels.
+func (m *Mirroring) EnableComparison(serviceName string, config *dynamic.MirrorCompare, mismatches gokitmetrics.Counter) error {
+	body := config.Body
+	switch body {
+	case "":
+		body = dynamic.MirrorCompareBodyHash
+	case dynamic.MirrorCompareBodyNone, dynamic.MirrorCompareBodyHash, dynamic.MirrorCompareBodyJSON:
+	default:
+		return fmt.Errorf("unsupported body comparison mode %q", config.Body)
+	}
+
+	if config.LogSampleRate < 0 || config.LogSampleRate > 1 {
+		return errors.New("logSampleRate must be between 0 and 1")
+	}
+
+	headers := make([]string, 0, len(config.Headers))
+	for _, header := range config.Headers {
+		headers = append(headers, http.CanonicalHeaderKey(header))
+	}
+
+	m.comparison = &comparison{
+		serviceName:   serviceName,
+		headers:       headers,
+		body:          body,
+		maxBodySize:   config.MaxBodySize,
+		logSampleRate: config.LogSampleRate,
+		mismatches:    mismatches,
+	}
+
+	return nil
+}
+
+func (c *comparison) newResponseWriter(rw http.ResponseWriter) *captureResponseWriter {
+	capture := &capturedResponse{}
+
+	switch c.body {
+	case dynamic.MirrorCompareBodyHash:
+		capture.hash = sha256.New()
+	case dynamic.MirrorCompareBodyJSON:
+		capture.hash = sha256.New()
+		capture.body = &bytes.Buffer{}
+		capture.maxBodySize = c.maxBodySize
+	}
+
+	return &captureResponseWriter{rw: rw, header: make(http.Header), capture: capture}
+}
+
+// compare reports the mismatches between the response of the given mirror and the response of the service.
+func (c *comparison) compare(req *http.Request, mirrorName string, primary, mirror *capturedResponse) {
+	if primary.hijacked || mirror.hijacked {
+		return
+	}
+
+	var reasons []string
+
+	if primary.code != mirror.code {
+		reasons = append(reasons, mismatchStatus)
+	}
+
+	var headers []string
+	for _, header := range c.headers {
+		if primary.header.Get(header) != mirror.header.Get(header) {
+			headers = append(headers, header)
+		}
+	}
+	if len(headers) > 0 {
+		reasons = append(reasons, mismatchHeader)
+	}
+
+	if c.body != dynamic.MirrorCompareBodyNone && !primary.sameBody(mirror) {
+		reasons = append(reasons, mismatchBody)
+	}
+
+	if len(reasons) == 0 {
+		return
+	}
+
+	if c.mismatches != nil {
+		for _, reason := range reasons {
+			c.mismatches.With("service", c.serviceName, "mirror", mirrorName, "reason", reason).Add(1)
+		}
+	}
+
+	if c.logSampleRate <= 0 || rand.Float64() >= c.logSampleRate {
+		return
+	}
+
+	log.Ctx(req.Context()).Warn().
+		Str("service", c.serviceName).
+		Str("mirror", mirrorName).
+		Str("method", req.Method).
+		Str("path", req.URL.Path).
+		Strs("reasons", reasons).
+		Int("statusCode", primary.code).
+		Int("mirrorStatusCode", mirror.code).
+		Strs("headers", headers).
+		Str("bodyHash", primary.sum()).
+		Str("mirrorBodyHash", mirror.sum()).
+		Msg("Mirror response does not match the service response")
+}
+
+// capturedResponse holds what is compared of a response.
+type capturedResponse struct {
+	code     int
+	header   http.Header
+	hijacked bool
+
+	hash hash.Hash
+
+	// body is only kept to compare JSON bodies, up to maxBodySize.
+	body        *bytes.Buffer
+	maxBodySize int64
+	truncated   bool
+}
+
+func (c *capturedResponse) write(data []byte) {
+	if c.hash != nil {
+		_, _ = c.hash.Write(data)
+	}
+
+	if c.body == nil || c.truncated {
+		return
+	}
+
+	if int64(c.body.Len()+len(data)) > c.maxBodySize {
+		c.truncated = true
+		c.body = nil
+		return
+	}
+
+	c.body.Write(data)
+}
+
+func (c *capturedResponse) sum() string {
+	if c.hash == nil {
+		return ""
+	}
+	return hex.EncodeToString(c.hash.Sum(nil))
+}
+
+// sameBody compares the JSON-normalized bodies if possible, and the body hashes otherwise.
+func (c *capturedResponse) sameBody(other *capturedResponse) bool {
+	if c.body != nil && other.body != nil {
+		normalized, err := normalizeJSON(c.body.Bytes())
+		if err == nil {
+			otherNormalized, err := normalizeJSON(other.body.Bytes())
+			if err == nil {
+				return bytes.Equal(normalized, otherNormalized)
+			}
+		}
+	}
+
+	return c.sum() == other.sum()
+}
+
+// normalizeJSON returns the given JSON document without insignificant whitespaces, and with sorted object keys.
+func normalizeJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(document)
+}
+
+// captureResponseWriter captures a response while writing it to the given response writer, if any.
+type captureResponseWriter struct {
+	rw      http.ResponseWriter
+	header  http.Header
+	capture *capturedResponse
+}
+
+func (c *captureResponseWriter) Header() http.Header {
+	if c.rw != nil {
+		return c.rw.Header()
+	}
+	return c.header
+}
+
+func (c *captureResponseWriter) WriteHeader(code int) {
+	// Informational responses are not the final response, they are not compared.
+	if c.capture.code == 0 && (code < 100 || code > 199 || code == http.StatusSwitchingProtocols) {
+		c.capture.code = code
+		c.capture.header = c.Header().Clone()
+	}
+
+	if c.rw != nil {
+		c.rw.WriteHeader(code)
+	}
+}
+
+func (c *captureResponseWriter) Write(data []byte) (int, error) {
+	if c.capture.code == 0 {
+		c.WriteHeader(http.StatusOK)
+	}
+
+	c.capture.write(data)
+
+	if c.rw != nil {
+		return c.rw.Write(data)
+	}
+	return len(data), nil
+}
+
+func (c *captureResponseWriter) Flush() {
+	if flusher, ok := c.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (c *captureResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := c.rw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("%T is not a http.Hijacker", c.rw)
+	}
+
+	c.capture.hijacked = true
+	return hijacker.Hijack()
+}
+
+// finish returns the captured response, once the handler has returned.
+func (c *captureResponseWriter) finish() *capturedResponse {
+	if c.capture.code == 0 {
+		c.capture.code = http.StatusOK
+		c.capture.header = c.Header().Clone()
+	}
+
+	return c.capture
+}
@@ -0,0 +1,175 @@
+package mirror
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/safe"
+)
+
+// reasonsCounter is a metrics.Counter collecting the mismatch reasons.
+type reasonsCounter struct {
+	mu      *sync.Mutex
+	reasons map[string]float64
+
+	labelValues []string
+}
+
+func (c *reasonsCounter) With(labelValues ...string) metrics.Counter {
+	return &reasonsCounter{mu: c.mu, reasons: c.reasons, labelValues: labelValues}
+}
+
+func (c *reasonsCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := 0; i+1 < len(c.labelValues); i += 2 {
+		if c.labelValues[i] == "reason" {
+			c.reasons[c.labelValues[i+1]] += delta
+		}
+	}
+}
+
+type response struct {
+	code   int
+	header map[string]string
+	body   string
+}
+
+func (r response) handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		for k, v := range r.header {
+			rw.Header().Set(k, v)
+		}
+		if r.code != 0 {
+			rw.WriteHeader(r.code)
+		}
+		_, _ = rw.Write([]byte(r.body))
+	})
+}
+
+func TestMirroring_EnableComparison(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.MirrorCompare
+		primary         response
+		mirror          response
+		expectedReasons map[string]float64
+	}{
+		{
+			desc:            "same responses",
+			config:          dynamic.MirrorCompare{Headers: []string{"X-Foo"}},
+			primary:         response{code: http.StatusOK, header: map[string]string{"X-Foo": "bar"}, body: "foo"},
+			mirror:          response{code: http.StatusOK, header: map[string]string{"X-Foo": "bar"}, body: "foo"},
+			expectedReasons: map[string]float64{},
+		},
+		{
+			desc:            "different status codes",
+			primary:         response{code: http.StatusOK},
+			mirror:          response{code: http.StatusInternalServerError},
+			expectedReasons: map[string]float64{mismatchStatus: 1},
+		},
+		{
+			desc:            "implicit status code",
+			primary:         response{body: "foo"},
+			mirror:          response{code: http.StatusOK, body: "foo"},
+			expectedReasons: map[string]float64{},
+		},
+		{
+			desc:            "different compared header",
+			config:          dynamic.MirrorCompare{Headers: []string{"x-foo"}},
+			primary:         response{header: map[string]string{"X-Foo": "bar", "X-Bar": "foo"}},
+			mirror:          response{header: map[string]string{"X-Foo": "baz"}},
+			expectedReasons: map[string]float64{mismatchHeader: 1},
+		},
+		{
+			desc:            "different ignored header",
+			primary:         response{header: map[string]string{"X-Bar": "foo"}},
+			mirror:          response{header: map[string]string{"X-Bar": "bar"}},
+			expectedReasons: map[string]float64{},
+		},
+		{
+			desc:            "different bodies",
+			primary:         response{body: "foo"},
+			mirror:          response{body: "bar"},
+			expectedReasons: map[string]float64{mismatchBody: 1},
+		},
+		{
+			desc:            "different bodies not compared",
+			config:          dynamic.MirrorCompare{Body: dynamic.MirrorCompareBodyNone},
+			primary:         response{body: "foo"},
+			mirror:          response{body: "bar"},
+			expectedReasons: map[string]float64{},
+		},
+		{
+			desc:            "equivalent JSON bodies",
+			config:          dynamic.MirrorCompare{Body: dynamic.MirrorCompareBodyJSON, MaxBodySize: 1024},
+			primary:         response{body: `{"a": 1, "b": [true, null]}`},
+			mirror:          response{body: `{"b":[true,null],"a":1}`},
+			expectedReasons: map[string]float64{},
+		},
+		{
+			desc:            "different JSON bodies",
+			config:          dynamic.MirrorCompare{Body: dynamic.MirrorCompareBodyJSON, MaxBodySize: 1024},
+			primary:         response{body: `{"a": 1}`},
+			mirror:          response{body: `{"a": 2}`},
+			expectedReasons: map[string]float64{mismatchBody: 1},
+		},
+		{
+			desc:            "equivalent JSON bodies larger than the max body size",
+			config:          dynamic.MirrorCompare{Body: dynamic.MirrorCompareBodyJSON, MaxBodySize: 2},
+			primary:         response{body: `{"a": 1}`},
+			mirror:          response{body: `{"a":1}`},
+			expectedReasons: map[string]float64{mismatchBody: 1},
+		},
+		{
+			desc:            "several mismatches",
+			config:          dynamic.MirrorCompare{Headers: []string{"X-Foo"}},
+			primary:         response{code: http.StatusOK, header: map[string]string{"X-Foo": "bar"}, body: "foo"},
+			mirror:          response{code: http.StatusNotFound, body: "bar"},
+			expectedReasons: map[string]float64{mismatchStatus: 1, mismatchHeader: 1, mismatchBody: 1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			pool := safe.NewPool(context.Background())
+
+			mirroring := New(test.primary.handler(), pool, defaultMaxBodySize, nil)
+			err := mirroring.AddMirror("mirror", test.mirror.handler(), 100)
+			require.NoError(t, err)
+
+			counter := &reasonsCounter{mu: &sync.Mutex{}, reasons: map[string]float64{}}
+			test.config.LogSampleRate = 1
+			err = mirroring.EnableComparison("service", &test.config, counter)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			mirroring.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			pool.Stop()
+
+			assert.Equal(t, test.primary.body, recorder.Body.String())
+			assert.Equal(t, test.expectedReasons, counter.reasons)
+		})
+	}
+}
+
+func TestMirroring_EnableComparison_invalidConfig(t *testing.T) {
+	mirroring := New(http.NotFoundHandler(), safe.NewPool(context.Background()), defaultMaxBodySize, nil)
+
+	err := mirroring.EnableComparison("service", &dynamic.MirrorCompare{Body: "foo"}, nil)
+	assert.Error(t, err)
+
+	err = mirroring.EnableComparison("service", &dynamic.MirrorCompare{LogSampleRate: 2}, nil)
+	assert.Error(t, err)
+}
@@ -27,6 +27,7 @@ type Mirroring struct {
 
 	maxBodySize      int64
 	wantsHealthCheck bool
+	comparison       *comparison
 
 	lock  sync.RWMutex
 	total uint64
@@ -52,16 +53,17 @@ func (m *Mirroring) inc() uint64 {
 
 type mirrorHandler struct {
 	http.Handler
+	name    string
 	percent int
 
 	lock  sync.RWMutex
 	count uint64
 }
 
-func (m *Mirroring) getActiveMirrors() []http.Handler {
+func (m *Mirroring) getActiveMirrors() []*mirrorHandler {
 	total := m.inc()
 
-	var mirrors []http.Handler
+	var mirrors []*mirrorHandler
 	for _, handler := range m.mirrorHandlers {
 		handler.lock.Lock()
 		if handler.count*100 < total*uint64(handler.percent) {
@@ -97,7 +99,14 @@ func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	m.handler.ServeHTTP(rw, rr.clone(req.Context()))
+	var primary *capturedResponse
+	if m.comparison != nil {
+		primaryRW := m.comparison.newResponseWriter(rw)
+		m.handler.ServeHTTP(primaryRW, rr.clone(req.Context()))
+		primary = primaryRW.finish()
+	} else {
+		m.handler.ServeHTTP(rw, rr.clone(req.Context()))
+	}
 
 	select {
 	case <-req.Context().Done():
@@ -124,17 +133,24 @@ func (m *Mirroring) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 			// which would trigger a cancellation of the ongoing mirrored requests.
 			// Therefore, we give a new, non-cancellable context  to each of the mirrored calls,
 			// so they can terminate by themselves.
-			handler.ServeHTTP(m.rw, r.WithContext(contextStopPropagation{ctx}))
+			if primary == nil {
+				handler.ServeHTTP(m.rw, r.WithContext(contextStopPropagation{ctx}))
+				continue
+			}
+
+			mirrorRW := m.comparison.newResponseWriter(nil)
+			handler.ServeHTTP(mirrorRW, r.WithContext(contextStopPropagation{ctx}))
+			m.comparison.compare(r, handler.name, primary, mirrorRW.finish())
 		}
 	})
 }
 
 // AddMirror adds an httpHandler to mirror to.
-func (m *Mirroring) AddMirror(handler http.Handler, percent int) error {
+func (m *Mirroring) AddMirror(name string, handler http.Handler, percent int) error {
 	if percent < 0 || percent > 100 {
 		return errors.New("percent must be between 0 and 100")
 	}
-	m.mirrorHandlers = append(m.mirrorHandlers, &mirrorHandler{Handler: handler, percent: percent})
+	m.mirrorHandlers = append(m.mirrorHandlers, &mirrorHandler{Handler: handler, name: name, percent: percent})
 	return nil
 }
 
@@ -0,0 +1,359 @@
+package wrr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
+)
+
+const (
+	// latencyWindowSize is the number of observed latencies used to compute the hedging percentile.
+	latencyWindowSize = 128
+	// minLatencySamples is the number of observed latencies needed before relying on the hedging percentile.
+	minLatencySamples = 20
+)
+
+type hedging struct {
+	delay      time.Duration
+	percentile float64
+	onHedge    func(req *http.Request)
+
+	mu               sync.Mutex
+	latencies        []time.Duration
+	next             int
+	percentileDelay  time.Duration
+	samplesSinceCalc int
+}
+
+// EnableHedging enables the hedging of the requests with a safe method and without body.
+// The given onHedge function, which can be nil, is called for each hedged request sent.
+func (b *Balancer) EnableHedging(config *dynamic.Hedging, onHedge func(req *http.Request)) error {
+	if config.Delay <= 0 && config.Percentile <= 0 {
+		return errors.New("hedging requires a delay or a percentile")
+	}
+
+	if config.Percentile < 0 || config.Percentile >= 100 {
+		return errors.New("hedging percentile must be between 0 and 100")
+	}
+
+	b.hedging = &hedging{
+		delay:      time.Duration(config.Delay),
+		percentile: config.Percentile,
+		onHedge:    onHedge,
+	}
+
+	return nil
+}
+
+// hedgingDelay returns how long to wait for the first attempt before sending the hedged request,
+// or zero if the request should not be hedged, while the latencies needed to compute the percentile are observed.
+func (h *hedging) hedgingDelay() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.percentile > 0 && h.percentileDelay > 0 {
+		return h.percentileDelay
+	}
+
+	return h.delay
+}
+
+// observe records the latency until the response headers of an attempt.
+func (h *hedging) observe(latency time.Duration) {
+	if h.percentile <= 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.latencies) < latencyWindowSize {
+		h.latencies = append(h.latencies, latency)
+	} else {
+		h.latencies[h.next] = latency
+		h.next = (h.next + 1) % latencyWindowSize
+	}
+
+	// Sorting the window on each observation would be wasteful,
+	// so the percentile is only computed once in a while.
+	h.samplesSinceCalc++
+	if len(h.latencies) < minLatencySamples || h.samplesSinceCalc < minLatencySamples/2 && h.percentileDelay > 0 {
+		return
+	}
+	h.samplesSinceCalc = 0
+
+	sorted := make([]time.Duration, len(h.latencies))
+	copy(sorted, h.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	h.percentileDelay = sorted[int(float64(len(sorted)-1)*h.percentile/100)]
+}
+
+// canHedge tells whether the given request can be sent twice.
+func canHedge(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+	default:
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		return false
+	}
+
+	return req.Header.Get("Upgrade") == ""
+}
+
+// nextServerExcept returns the next server which is not the given one.
+func (b *Balancer) nextServerExcept(name string) (*namedHandler, error) {
+	b.mutex.RLock()
+	count := len(b.handlers)
+	b.mutex.RUnlock()
+
+	for i := 0; i < count; i++ {
+		server, err := b.nextServer()
+		if err != nil {
+			return nil, err
+		}
+
+		if server.name != name {
+			return server, nil
+		}
+	}
+
+	return nil, errNoAvailableServer
+}
+
+// serveHedged serves the request with the given server,
+// and sends a hedged request to another server if the response headers are not received before the given delay.
+// The first attempt receiving its response headers wins, and the other one is canceled.
+// A non-positive delay disables the hedged request, the latency of the attempt is only observed.
+func (b *Balancer) serveHedged(rw http.ResponseWriter, req *http.Request, first *namedHandler, delay time.Duration) {
+	race := &hedgeRace{rw: rw, claimed: make(chan struct{})}
+
+	firstAttempt := race.start(req, first, b.hedging)
+
+	var hedge <-chan time.Time
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		hedge = timer.C
+	}
+
+	select {
+	case <-firstAttempt.done:
+		firstAttempt.repanic()
+		return
+	case <-race.claimed:
+		<-firstAttempt.done
+		firstAttempt.repanic()
+		return
+	case <-hedge:
+	}
+
+	// The first attempt may have received its response headers at the same time as the delay elapsed.
+	if race.winner() != nil {
+		<-firstAttempt.done
+		firstAttempt.repanic()
+		return
+	}
+
+	second, err := b.nextServerExcept(first.name)
+	if err != nil {
+		<-firstAttempt.done
+		firstAttempt.repanic()
+		return
+	}
+
+	hedgedReq, mergeLogData := accesslog.WithLogDataCopy(req)
+	secondAttempt := race.start(hedgedReq, second, b.hedging)
+	if secondAttempt == nil {
+		<-firstAttempt.done
+		firstAttempt.repanic()
+		return
+	}
+
+	log.Ctx(req.Context()).Debug().Msgf("Sending hedged request to %s", second.name)
+
+	<-firstAttempt.done
+	<-secondAttempt.done
+
+	if race.winner() == secondAttempt {
+		mergeLogData()
+	}
+	accesslog.SaveHedged(req)
+
+	if b.hedging.onHedge != nil {
+		b.hedging.onHedge(req)
+	}
+
+	race.winner().repanic()
+}
+
+// hedgeRace elects the first attempt receiving its response headers as the one writing the response.
+type hedgeRace struct {
+	rw      http.ResponseWriter
+	claimed chan struct{}
+
+	mu       sync.Mutex
+	attempts []*hedgeAttempt
+	won      *hedgeAttempt
+}
+
+// start serves the request with the given server, in a new goroutine.
+// It returns nil, without serving the request, if the race already has a winner.
+func (r *hedgeRace) start(req *http.Request, server *namedHandler, h *hedging) *hedgeAttempt {
+	ctx, cancel := context.WithCancel(req.Context())
+
+	attempt := &hedgeAttempt{
+		race:   r,
+		header: make(http.Header),
+		start:  time.Now(),
+		hedge:  h,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	r.mu.Lock()
+	if r.won != nil {
+		r.mu.Unlock()
+		cancel()
+		return nil
+	}
+	r.attempts = append(r.attempts, attempt)
+	r.mu.Unlock()
+
+	go func() {
+		defer close(attempt.done)
+		defer cancel()
+		defer func() {
+			// The panic is propagated by the main goroutine if this attempt won the race,
+			// e.g. to let the server abort the response with http.ErrAbortHandler.
+			attempt.panicValue = recover()
+		}()
+
+		server.ServeHTTP(attempt, req.WithContext(ctx))
+
+		// A handler returning without writing anything still completes the response, with its headers.
+		if !attempt.won {
+			attempt.claim()
+		}
+	}()
+
+	return attempt
+}
+
+// claim tells whether the given attempt won the race, electing it if there is no winner yet.
+func (r *hedgeRace) claim(attempt *hedgeAttempt) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.won != nil {
+		return r.won == attempt
+	}
+
+	r.won = attempt
+	close(r.claimed)
+
+	for _, other := range r.attempts {
+		if other != attempt {
+			other.cancel()
+		}
+	}
+
+	return true
+}
+
+func (r *hedgeRace) winner() *hedgeAttempt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.won
+}
+
+// hedgeAttempt is the response writer of an attempt,
+// which only writes to the client response if the attempt won the race.
+type hedgeAttempt struct {
+	race   *hedgeRace
+	header http.Header
+	start  time.Time
+	hedge  *hedging
+	cancel context.CancelFunc
+	done   chan struct{}
+	won    bool
+
+	panicValue interface{}
+}
+
+func (a *hedgeAttempt) Header() http.Header {
+	if a.won {
+		return a.race.rw.Header()
+	}
+	return a.header
+}
+
+func (a *hedgeAttempt) WriteHeader(code int) {
+	if a.won {
+		a.race.rw.WriteHeader(code)
+		return
+	}
+
+	// Informational responses are not final, they cannot decide the race.
+	if code >= 100 && code <= 199 && code != http.StatusSwitchingProtocols {
+		return
+	}
+
+	if !a.claim() {
+		return
+	}
+
+	a.race.rw.WriteHeader(code)
+}
+
+func (a *hedgeAttempt) Write(b []byte) (int, error) {
+	if !a.won && !a.claim() {
+		return len(b), nil
+	}
+	return a.race.rw.Write(b)
+}
+
+func (a *hedgeAttempt) Flush() {
+	if !a.won {
+		return
+	}
+
+	if flusher, ok := a.race.rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// claim tries to make the attempt the race winner, and copies its headers to the client response if it succeeds.
+func (a *hedgeAttempt) claim() bool {
+	if !a.race.claim(a) {
+		return false
+	}
+
+	a.won = true
+	a.hedge.observe(time.Since(a.start))
+
+	headers := a.race.rw.Header()
+	for k, v := range a.header {
+		headers[k] = v
+	}
+
+	return true
+}
+
+func (a *hedgeAttempt) repanic() {
+	if a != nil && a.panicValue != nil {
+		panic(a.panicValue)
+	}
+}
@@ -0,0 +1,221 @@
+package wrr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestBalancer_EnableHedging(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		config      dynamic.Hedging
+		expectError bool
+	}{
+		{
+			desc:        "empty configuration",
+			expectError: true,
+		},
+		{
+			desc:   "delay",
+			config: dynamic.Hedging{Delay: ptypes.Duration(time.Second)},
+		},
+		{
+			desc:   "percentile",
+			config: dynamic.Hedging{Percentile: 95},
+		},
+		{
+			desc:        "percentile too high",
+			config:      dynamic.Hedging{Percentile: 100},
+			expectError: true,
+		},
+		{
+			desc:        "negative percentile",
+			config:      dynamic.Hedging{Delay: ptypes.Duration(time.Second), Percentile: -1},
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := New(nil, false).EnableHedging(&test.config, nil)
+			if test.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBalancer_hedging(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		method         string
+		body           string
+		firstDelay     time.Duration
+		expectedServer string
+		expectedHedges int64
+	}{
+		{
+			desc:           "fast first server",
+			method:         http.MethodGet,
+			expectedServer: "first",
+		},
+		{
+			desc:           "slow first server",
+			method:         http.MethodGet,
+			firstDelay:     time.Second,
+			expectedServer: "second",
+			expectedHedges: 1,
+		},
+		{
+			desc:           "unsafe method",
+			method:         http.MethodPost,
+			firstDelay:     100 * time.Millisecond,
+			expectedServer: "first",
+		},
+		{
+			desc:           "request with body",
+			method:         http.MethodGet,
+			body:           "foo",
+			firstDelay:     100 * time.Millisecond,
+			expectedServer: "first",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := New(nil, false)
+
+			var hedges atomic.Int64
+			err := balancer.EnableHedging(&dynamic.Hedging{Delay: ptypes.Duration(10 * time.Millisecond)}, func(*http.Request) {
+				hedges.Add(1)
+			})
+			require.NoError(t, err)
+
+			balancer.Add("first", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				select {
+				case <-time.After(test.firstDelay):
+				case <-req.Context().Done():
+					return
+				}
+
+				rw.Header().Set("server", "first")
+				rw.WriteHeader(http.StatusOK)
+				_, _ = rw.Write([]byte("first"))
+			}), Int(1))
+
+			balancer.Add("second", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.Header().Set("server", "second")
+				rw.WriteHeader(http.StatusOK)
+				_, _ = rw.Write([]byte("second"))
+			}), Int(1))
+
+			var body io.Reader
+			if test.body != "" {
+				body = strings.NewReader(test.body)
+			}
+			req := httptest.NewRequest(test.method, "/", body)
+
+			recorder := httptest.NewRecorder()
+			balancer.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, test.expectedServer, recorder.Header().Get("server"))
+			assert.Equal(t, test.expectedServer, recorder.Body.String())
+			assert.Equal(t, test.expectedHedges, hedges.Load())
+		})
+	}
+}
+
+func TestBalancer_hedgingPercentile(t *testing.T) {
+	balancer := New(nil, false)
+
+	err := balancer.EnableHedging(&dynamic.Hedging{Percentile: 50}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Duration(0), balancer.hedging.hedgingDelay())
+
+	for i := 1; i <= minLatencySamples; i++ {
+		balancer.hedging.observe(time.Duration(i) * time.Millisecond)
+	}
+
+	delay := balancer.hedging.hedgingDelay()
+	assert.Equal(t, 10*time.Millisecond, delay)
+}
+
+func TestBalancer_hedgingFirstAnswersAtDelay(t *testing.T) {
+	balancer := New(nil, false)
+
+	err := balancer.EnableHedging(&dynamic.Hedging{Delay: ptypes.Duration(time.Millisecond)}, nil)
+	require.NoError(t, err)
+
+	// answered tells whether an attempt of the current request received its response headers.
+	var answered, lateHedges atomic.Int64
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// A hedged request started before the other attempt won is canceled, a late one is not.
+		if answered.Load() == 1 && req.Context().Err() == nil {
+			lateHedges.Add(1)
+		}
+
+		// The response headers are received at about the same time as the delay elapses,
+		// and the response is still being sent afterwards.
+		time.Sleep(time.Millisecond)
+		rw.WriteHeader(http.StatusOK)
+		answered.Store(1)
+		time.Sleep(5 * time.Millisecond)
+	})
+
+	balancer.Add("first", handler, Int(1))
+	balancer.Add("second", handler, Int(1))
+
+	for i := 0; i < 200; i++ {
+		answered.Store(0)
+
+		recorder := httptest.NewRecorder()
+		balancer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+
+	// No hedged request is sent once an attempt received its response headers.
+	assert.Zero(t, lateHedges.Load())
+}
+
+func TestHedgeRace_startAfterWinner(t *testing.T) {
+	race := &hedgeRace{rw: httptest.NewRecorder(), claimed: make(chan struct{})}
+	hedge := &hedging{}
+
+	first := race.start(httptest.NewRequest(http.MethodGet, "/", http.NoBody), &namedHandler{
+		Handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+		}),
+	}, hedge)
+	require.NotNil(t, first)
+	<-first.done
+
+	second := race.start(httptest.NewRequest(http.MethodGet, "/", http.NoBody), &namedHandler{
+		Handler: http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			t.Error("the attempt should not be started")
+		}),
+	}, hedge)
+	assert.Nil(t, second)
+	assert.Same(t, first, race.winner())
+}
@@ -44,6 +44,11 @@ type Balancer struct {
 	// updaters is the list of hooks that are run (to update the Balancer
 	// parent(s)), whenever the Balancer status changes.
 	updaters []func(bool)
+	// healthyCountUpdaters is the list of hooks that are run (to update the Balancer
+	// parent(s)), whenever the number of healthy children of the Balancer changes.
+	healthyCountUpdaters []func(int)
+	// hedging is the hedging configuration of the Balancer, nil if the requests are not hedged.
+	hedging *hedging
 }
 
 // New creates a new load balancer.
@@ -99,7 +104,8 @@ func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
-	upBefore := len(b.status) > 0
+	healthyBefore := len(b.status)
+	upBefore := healthyBefore > 0
 
 	status := "DOWN"
 	if up {
@@ -114,6 +120,12 @@ func (b *Balancer) SetStatus(ctx context.Context, childName string, up bool) {
 		delete(b.status, childName)
 	}
 
+	if healthyBefore != len(b.status) {
+		for _, fn := range b.healthyCountUpdaters {
+			fn(len(b.status))
+		}
+	}
+
 	upAfter := len(b.status) > 0
 	status = "DOWN"
 	if upAfter {
@@ -145,6 +157,21 @@ func (b *Balancer) RegisterStatusUpdater(fn func(up bool)) error {
 	return nil
 }
 
+// HealthyCount returns the number of healthy children of the Balancer.
+func (b *Balancer) HealthyCount() int {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return len(b.status)
+}
+
+// RegisterHealthyCountUpdater adds fn to the list of hooks that are run when the
+// number of healthy children of the Balancer changes.
+// Not thread safe.
+func (b *Balancer) RegisterHealthyCountUpdater(fn func(healthy int)) {
+	b.healthyCountUpdaters = append(b.healthyCountUpdaters, fn)
+}
+
 var errNoAvailableServer = errors.New("no available server")
 
 func (b *Balancer) nextServer() (*namedHandler, error) {
@@ -218,6 +245,12 @@ func (b *Balancer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		http.SetCookie(w, cookie)
 	}
 
+	// Hedging a request would break the stickiness, as the response could come from another server.
+	if b.hedging != nil && b.stickyCookie == nil && canHedge(req) {
+		b.serveHedged(w, req, server, b.hedging.hedgingDelay())
+		return
+	}
+
 	server.ServeHTTP(w, req)
 }
 
@@ -133,6 +133,28 @@ func TestBalancerDownThenUp(t *testing.T) {
 	assert.Equal(t, 1, recorder.save["second"])
 }
 
+func TestBalancerHealthyCount(t *testing.T) {
+	balancer := New(nil, false)
+
+	balancer.Add("first", http.NotFoundHandler(), Int(1))
+	balancer.Add("second", http.NotFoundHandler(), Int(1))
+	assert.Equal(t, 2, balancer.HealthyCount())
+
+	var counts []int
+	balancer.RegisterHealthyCountUpdater(func(healthy int) {
+		counts = append(counts, healthy)
+	})
+
+	ctx := context.WithValue(context.Background(), serviceName, "parent")
+	balancer.SetStatus(ctx, "second", false)
+	balancer.SetStatus(ctx, "second", false)
+	balancer.SetStatus(ctx, "first", false)
+	balancer.SetStatus(ctx, "first", true)
+
+	assert.Equal(t, []int{1, 0, 1}, counts)
+	assert.Equal(t, 1, balancer.HealthyCount())
+}
+
 func TestBalancerPropagate(t *testing.T) {
 	balancer1 := New(nil, true)
 
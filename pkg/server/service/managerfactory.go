@@ -25,6 +25,8 @@ type ManagerFactory struct {
 	pingHandler      http.Handler
 	acmeHTTPHandler  http.Handler
 
+	routeExplainer *api.RouteExplainer
+
 	routinesPool *safe.Pool
 }
 
@@ -38,7 +40,8 @@ func NewManagerFactory(staticConfiguration static.Configuration, routinesPool *s
 	}
 
 	if staticConfiguration.API != nil {
-		apiRouterBuilder := api.NewBuilder(staticConfiguration)
+		factory.routeExplainer = api.NewRouteExplainer()
+		apiRouterBuilder := api.NewBuilder(staticConfiguration, factory.routeExplainer)
 
 		if staticConfiguration.API.Dashboard {
 			factory.dashboardHandler = dashboard.Handler{}
@@ -81,3 +84,9 @@ func (f *ManagerFactory) Build(configuration *runtime.Configuration) *InternalHa
 
 	return NewInternalHandlers(svcManager, apiHandler, f.restHandler, f.metricsHandler, f.pingHandler, f.dashboardHandler, f.acmeHTTPHandler)
 }
+
+// RouteExplainer returns the holder of the live muxers used by the API explain endpoint,
+// or nil when the API is disabled.
+func (f *ManagerFactory) RouteExplainer() *api.RouteExplainer {
+	return f.routeExplainer
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -9,6 +10,7 @@ import (
 	"net/http"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -36,6 +38,11 @@ type SpiffeX509Source interface {
 	x509bundle.Source
 }
 
+// ClientCertificateResolver issues the client certificates presented by the servers transports.
+type ClientCertificateResolver interface {
+	GetClientCertificate(name string) (*tls.Certificate, error)
+}
+
 // NewRoundTripperManager creates a new RoundTripperManager.
 func NewRoundTripperManager(spiffeX509Source SpiffeX509Source) *RoundTripperManager {
 	return &RoundTripperManager{
@@ -50,8 +57,35 @@ type RoundTripperManager struct {
 	rtLock        sync.RWMutex
 	roundTrippers map[string]http.RoundTripper
 	configs       map[string]*dynamic.ServersTransport
+	// files watches the root CAs and client certificates files, nil until the manager runs.
+	files *traefiktls.FileWatcher
 
 	spiffeX509Source SpiffeX509Source
+	certResolvers    map[string]ClientCertificateResolver
+}
+
+// SetClientCertificateResolvers sets the certificates resolvers which can issue the client certificates of the servers transports.
+func (r *RoundTripperManager) SetClientCertificateResolvers(resolvers map[string]ClientCertificateResolver) {
+	r.rtLock.Lock()
+	defer r.rtLock.Unlock()
+
+	r.certResolvers = resolvers
+}
+
+// Run recreates the roundtrippers whose root CAs or client certificates files change, until the given context is done.
+func (r *RoundTripperManager) Run(ctx context.Context) {
+	files, err := traefiktls.NewFileWatcher()
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to watch the servers transports files, they will not be reloaded on change")
+		return
+	}
+
+	r.rtLock.Lock()
+	r.files = files
+	r.files.Watch(r.watchedFiles())
+	r.rtLock.Unlock()
+
+	files.Run(ctx, r.reloadFiles)
 }
 
 // Update updates the roundtrippers configurations.
@@ -71,12 +105,7 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 			continue
 		}
 
-		var err error
-		r.roundTrippers[configName], err = r.createRoundTripper(newConfig)
-		if err != nil {
-			log.Error().Err(err).Msgf("Could not configure HTTP Transport %s, fallback on default transport", configName)
-			r.roundTrippers[configName] = http.DefaultTransport
-		}
+		r.roundTrippers[configName] = r.buildRoundTripper(configName, newConfig)
 	}
 
 	for newConfigName, newConfig := range newConfigs {
@@ -84,15 +113,102 @@ func (r *RoundTripperManager) Update(newConfigs map[string]*dynamic.ServersTrans
 			continue
 		}
 
-		var err error
-		r.roundTrippers[newConfigName], err = r.createRoundTripper(newConfig)
+		r.roundTrippers[newConfigName] = r.buildRoundTripper(newConfigName, newConfig)
+	}
+
+	r.configs = newConfigs
+
+	if r.files != nil {
+		r.files.Watch(r.watchedFiles())
+	}
+}
+
+// buildRoundTripper creates the roundtripper of the given servers transport, falling back on the default transport on error.
+// The roundtrippers using files are reloadable, so that the handlers already holding them use the reloaded files.
+// It must be called with the lock held.
+func (r *RoundTripperManager) buildRoundTripper(name string, cfg *dynamic.ServersTransport) http.RoundTripper {
+	roundTripper, err := r.createRoundTripper(name, cfg)
+	if err != nil {
+		log.Error().Err(err).Msgf("Could not configure HTTP Transport %s, fallback on default transport", name)
+		roundTripper = http.DefaultTransport
+	}
+
+	if cfg == nil || len(traefiktls.ClientFiles(cfg.RootCAs, cfg.Certificates)) == 0 {
+		return roundTripper
+	}
+
+	return newReloadableRoundTripper(roundTripper)
+}
+
+// reloadFiles recreates the roundtrippers using files, and replaces them in place,
+// keeping the previous ones when their files cannot be loaded, as they may be in the middle of a rotation.
+func (r *RoundTripperManager) reloadFiles() {
+	r.rtLock.Lock()
+	defer r.rtLock.Unlock()
+
+	for configName, config := range r.configs {
+		if len(traefiktls.ClientFiles(config.RootCAs, config.Certificates)) == 0 {
+			continue
+		}
+
+		if err := traefiktls.CheckClientFiles(config.RootCAs, config.Certificates); err != nil {
+			log.Warn().Err(err).Msgf("Unable to reload HTTP Transport %s files, keeping the previous ones", configName)
+			continue
+		}
+
+		roundTripper, err := r.createRoundTripper(configName, config)
 		if err != nil {
-			log.Error().Err(err).Msgf("Could not configure HTTP Transport %s, fallback on default transport", newConfigName)
-			r.roundTrippers[newConfigName] = http.DefaultTransport
+			log.Error().Err(err).Msgf("Could not reload HTTP Transport %s", configName)
+			continue
 		}
+
+		log.Debug().Msgf("Reloaded HTTP Transport %s files", configName)
+
+		reloadable, ok := r.roundTrippers[configName].(*reloadableRoundTripper)
+		if !ok {
+			r.roundTrippers[configName] = newReloadableRoundTripper(roundTripper)
+			continue
+		}
+
+		reloadable.set(roundTripper)
 	}
+}
 
-	r.configs = newConfigs
+// reloadableRoundTripper is a roundtripper whose underlying roundtripper can be replaced while it is in use.
+type reloadableRoundTripper struct {
+	current atomic.Pointer[http.RoundTripper]
+}
+
+func newReloadableRoundTripper(roundTripper http.RoundTripper) *reloadableRoundTripper {
+	r := &reloadableRoundTripper{}
+	r.current.Store(&roundTripper)
+
+	return r
+}
+
+func (r *reloadableRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return (*r.current.Load()).RoundTrip(req)
+}
+
+// set replaces the underlying roundtripper, and closes the idle connections of the previous one,
+// which were established with the previous files.
+func (r *reloadableRoundTripper) set(roundTripper http.RoundTripper) {
+	previous := *r.current.Swap(&roundTripper)
+
+	if closer, ok := previous.(interface{ CloseIdleConnections() }); ok && previous != http.DefaultTransport {
+		closer.CloseIdleConnections()
+	}
+}
+
+// watchedFiles returns the root CAs and client certificates files to watch.
+// It must be called with the lock held.
+func (r *RoundTripperManager) watchedFiles() []string {
+	var files []string
+	for _, config := range r.configs {
+		files = append(files, traefiktls.ClientFiles(config.RootCAs, config.Certificates)...)
+	}
+
+	return files
 }
 
 // Get gets a roundtripper by name.
@@ -115,7 +231,7 @@ func (r *RoundTripperManager) Get(name string) (http.RoundTripper, error) {
 // For the settings that can't be configured in Traefik it uses the default http.Transport settings.
 // An exception to this is the MaxIdleConns setting as we only provide the option MaxIdleConnsPerHost in Traefik at this point in time.
 // Setting this value to the default of 100 could lead to confusing behavior and backwards compatibility issues.
-func (r *RoundTripperManager) createRoundTripper(cfg *dynamic.ServersTransport) (http.RoundTripper, error) {
+func (r *RoundTripperManager) createRoundTripper(name string, cfg *dynamic.ServersTransport) (http.RoundTripper, error) {
 	if cfg == nil {
 		return nil, errors.New("no transport configuration given")
 	}
@@ -158,7 +274,7 @@ func (r *RoundTripperManager) createRoundTripper(cfg *dynamic.ServersTransport)
 		transport.TLSClientConfig = tlsconfig.MTLSClientConfig(r.spiffeX509Source, r.spiffeX509Source, spiffeAuthorizer)
 	}
 
-	if cfg.InsecureSkipVerify || len(cfg.RootCAs) > 0 || len(cfg.ServerName) > 0 || len(cfg.Certificates) > 0 || cfg.PeerCertURI != "" {
+	if cfg.InsecureSkipVerify || len(cfg.RootCAs) > 0 || len(cfg.ServerName) > 0 || len(cfg.Certificates) > 0 || cfg.PeerCertURI != "" || cfg.CertResolver != "" {
 		if transport.TLSClientConfig != nil {
 			return nil, errors.New("TLS and SPIFFE configuration cannot be defined at the same time")
 		}
@@ -170,6 +286,15 @@ func (r *RoundTripperManager) createRoundTripper(cfg *dynamic.ServersTransport)
 			Certificates:       cfg.Certificates.GetCertificates(),
 		}
 
+		if cfg.CertResolver != "" {
+			getClientCertificate, err := r.getClientCertificateFunc(name, cfg.CertResolver, cfg.Certificates)
+			if err != nil {
+				return nil, err
+			}
+
+			transport.TLSClientConfig.GetClientCertificate = getClientCertificate
+		}
+
 		if cfg.PeerCertURI != "" {
 			transport.TLSClientConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
 				return traefiktls.VerifyPeerCertificate(cfg.PeerCertURI, transport.TLSClientConfig, rawCerts)
@@ -185,6 +310,22 @@ func (r *RoundTripperManager) createRoundTripper(cfg *dynamic.ServersTransport)
 	return newSmartRoundTripper(transport, cfg.ForwardingTimeouts)
 }
 
+// getClientCertificateFunc returns the function issuing the client certificate of the given servers transport with the given certificates resolver.
+func (r *RoundTripperManager) getClientCertificateFunc(name, certResolver string, certificates traefiktls.Certificates) (func(*tls.CertificateRequestInfo) (*tls.Certificate, error), error) {
+	if len(certificates) > 0 {
+		return nil, errors.New("client certificates and certificates resolver cannot be defined at the same time")
+	}
+
+	resolver, ok := r.certResolvers[certResolver]
+	if !ok {
+		return nil, fmt.Errorf("unknown certificates resolver %q, or it cannot issue client certificates", certResolver)
+	}
+
+	return func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return resolver.GetClientCertificate(name)
+	}, nil
+}
+
 func createRootCACertPool(rootCAs []traefiktls.FileOrContent) *x509.CertPool {
 	if len(rootCAs) == 0 {
 		return nil
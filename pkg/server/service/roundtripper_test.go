@@ -1,16 +1,20 @@
 package service
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"fmt"
 	"math/big"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -22,7 +26,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
 )
 
 func Int32(i int32) *int32 {
@@ -186,6 +192,83 @@ func TestKeepConnectionWhenSameConfiguration(t *testing.T) {
 	assert.EqualValues(t, 2, count)
 }
 
+func TestRoundTripperManager_reloadFiles(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	cert, err := tls.X509KeyPair(LocalhostCert, LocalhostKey)
+	require.NoError(t, err)
+
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	otherCA, _, err := generate.KeyPair("example.com", time.Time{})
+	require.NoError(t, err)
+
+	rootCAFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(rootCAFile, otherCA, 0o600))
+
+	rtManager := NewRoundTripperManager(nil)
+	rtManager.Update(map[string]*dynamic.ServersTransport{
+		"test@file": {
+			ServerName: "example.com",
+			RootCAs:    []traefiktls.FileOrContent{traefiktls.FileOrContent(rootCAFile)},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go rtManager.Run(ctx)
+
+	// The service handler is built once, before the files are reloaded.
+	manager := NewManager(map[string]*runtime.ServiceInfo{
+		"foo@file": {
+			Service: &dynamic.Service{
+				LoadBalancer: &dynamic.ServersLoadBalancer{
+					ServersTransport: "test",
+					Servers:          []dynamic.Server{{URL: srv.URL}},
+				},
+			},
+		},
+	}, nil, nil, rtManager)
+
+	handler, err := manager.BuildHTTP(context.Background(), "foo@file")
+	require.NoError(t, err)
+
+	get := func() error {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://foo", nil))
+
+		if rec.Code != http.StatusOK {
+			return fmt.Errorf("unexpected status code %d", rec.Code)
+		}
+
+		return nil
+	}
+
+	require.Error(t, get())
+
+	// Waits for the watcher to be started.
+	require.Eventually(t, func() bool {
+		rtManager.rtLock.RLock()
+		defer rtManager.rtLock.RUnlock()
+
+		return rtManager.files != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The handler uses the new root CAs when the root CAs file changes.
+	require.NoError(t, os.WriteFile(rootCAFile, LocalhostCert, 0o600))
+	require.Eventually(t, func() bool { return get() == nil }, 5*time.Second, 50*time.Millisecond)
+
+	// The handler keeps the previous root CAs when the root CAs file cannot be loaded.
+	require.NoError(t, os.WriteFile(rootCAFile, []byte("invalid"), 0o600))
+	time.Sleep(2 * time.Second)
+	assert.NoError(t, get())
+}
+
 func TestMTLS(t *testing.T) {
 	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.WriteHeader(http.StatusOK)
@@ -238,6 +321,70 @@ func TestMTLS(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
+type clientCertificateResolverMock struct {
+	cert  tls.Certificate
+	names []string
+}
+
+func (r *clientCertificateResolverMock) GetClientCertificate(name string) (*tls.Certificate, error) {
+	r.names = append(r.names, name)
+	return &r.cert, nil
+}
+
+func TestMTLSCertResolver(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	cert, err := tls.X509KeyPair(LocalhostCert, LocalhostKey)
+	require.NoError(t, err)
+
+	clientPool := x509.NewCertPool()
+	clientPool.AppendCertsFromPEM(mTLSCert)
+
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientPool,
+	}
+	srv.StartTLS()
+
+	clientCert, err := tls.X509KeyPair(mTLSCert, mTLSKey)
+	require.NoError(t, err)
+
+	resolver := &clientCertificateResolverMock{cert: clientCert}
+
+	rtManager := NewRoundTripperManager(nil)
+	rtManager.SetClientCertificateResolvers(map[string]ClientCertificateResolver{"internal": resolver})
+
+	rtManager.Update(map[string]*dynamic.ServersTransport{
+		"test": {
+			ServerName:   "example.com",
+			RootCAs:      []traefiktls.FileOrContent{traefiktls.FileOrContent(LocalhostCert)},
+			CertResolver: "internal",
+		},
+		"unknown": {
+			CertResolver: "unknown",
+		},
+	})
+
+	tr, err := rtManager.Get("test")
+	require.NoError(t, err)
+
+	client := http.Client{Transport: tr}
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"test"}, resolver.names)
+
+	// The transports using an unknown resolver fall back to the default transport.
+	tr, err = rtManager.Get("unknown")
+	require.NoError(t, err)
+	assert.Equal(t, http.DefaultTransport, tr)
+}
+
 func TestSpiffeMTLS(t *testing.T) {
 	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		rw.WriteHeader(http.StatusOK)
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
@@ -21,6 +22,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
 	metricsMiddle "github.com/traefik/traefik/v3/pkg/middlewares/metrics"
+	"github.com/traefik/traefik/v3/pkg/middlewares/timeout"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/traefik/traefik/v3/pkg/server/cookie"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
@@ -116,14 +118,14 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string) (http.H
 		}
 	case conf.Mirroring != nil:
 		var err error
-		lb, err = m.getMirrorServiceHandler(ctx, conf.Mirroring)
+		lb, err = m.getMirrorServiceHandler(ctx, serviceName, conf.Mirroring)
 		if err != nil {
 			conf.AddError(err, true)
 			return nil, err
 		}
 	case conf.Failover != nil:
 		var err error
-		lb, err = m.getFailoverServiceHandler(ctx, serviceName, conf.Failover)
+		lb, err = m.getFailoverServiceHandler(ctx, serviceName, conf)
 		if err != nil {
 			conf.AddError(err, true)
 			return nil, err
@@ -139,7 +141,16 @@ func (m *Manager) BuildHTTP(rootCtx context.Context, serviceName string) (http.H
 	return lb, nil
 }
 
-func (m *Manager) getFailoverServiceHandler(ctx context.Context, serviceName string, config *dynamic.Failover) (http.Handler, error) {
+func (m *Manager) getFailoverServiceHandler(ctx context.Context, serviceName string, info *runtime.ServiceInfo) (http.Handler, error) {
+	config := info.Failover
+	if len(config.Tiers) > 0 {
+		if config.Service != "" || config.Fallback != "" {
+			return nil, errors.New("failover tiers cannot be used with service and fallback")
+		}
+
+		return m.getTieredFailoverServiceHandler(ctx, serviceName, info)
+	}
+
 	f := failover.New(config.HealthCheck)
 
 	serviceHandler, err := m.BuildHTTP(ctx, config.Service)
@@ -186,7 +197,46 @@ func (m *Manager) getFailoverServiceHandler(ctx context.Context, serviceName str
 	return f, nil
 }
 
-func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.Mirroring) (http.Handler, error) {
+func (m *Manager) getTieredFailoverServiceHandler(ctx context.Context, serviceName string, info *runtime.ServiceInfo) (http.Handler, error) {
+	config := info.Failover
+	t := failover.NewTiered(config.HealthCheck, info)
+
+	for i, tierConfig := range config.Tiers {
+		tierHandler, err := m.BuildHTTP(ctx, tierConfig.Service)
+		if err != nil {
+			return nil, err
+		}
+
+		index := i
+		switch updater := tierHandler.(type) {
+		case healthcheck.HealthyCountUpdater:
+			t.AddTier(tierConfig.Service, tierHandler, tierConfig.MinHealthy, updater.HealthyCount())
+			updater.RegisterHealthyCountUpdater(func(healthy int) {
+				t.SetTierHealthy(ctx, index, healthy)
+			})
+
+		case healthcheck.StatusUpdater:
+			// Without the number of healthy servers, an UP service is considered as having enough healthy servers.
+			t.AddTier(tierConfig.Service, tierHandler, 1, 1)
+			if err := updater.RegisterStatusUpdater(func(up bool) {
+				healthy := 0
+				if up {
+					healthy = 1
+				}
+				t.SetTierHealthy(ctx, index, healthy)
+			}); err != nil {
+				return nil, fmt.Errorf("cannot register %v as updater for %v: %w", tierConfig.Service, serviceName, err)
+			}
+
+		default:
+			return nil, fmt.Errorf("child service %v of %v not a healthcheck.StatusUpdater (%T)", tierConfig.Service, serviceName, tierHandler)
+		}
+	}
+
+	return t, nil
+}
+
+func (m *Manager) getMirrorServiceHandler(ctx context.Context, serviceName string, config *dynamic.Mirroring) (http.Handler, error) {
 	serviceHandler, err := m.BuildHTTP(ctx, config.Service)
 	if err != nil {
 		return nil, err
@@ -203,11 +253,24 @@ func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.M
 			return nil, err
 		}
 
-		err = handler.AddMirror(mirrorHandler, mirrorConfig.Percent)
+		err = handler.AddMirror(provider.GetQualifiedName(ctx, mirrorConfig.Name), mirrorHandler, mirrorConfig.Percent)
 		if err != nil {
 			return nil, err
 		}
 	}
+
+	if config.Compare != nil {
+		var mismatches gokitmetrics.Counter
+		if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+			mismatches = m.metricsRegistry.ServiceMirrorMismatchesCounter()
+		}
+
+		err = handler.EnableComparison(serviceName, config.Compare, mismatches)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mirroring comparison configuration: %w", err)
+		}
+	}
+
 	return handler, nil
 }
 
@@ -283,6 +346,21 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 	lb := wrr.New(service.Sticky, service.HealthCheck != nil)
 	healthCheckTargets := make(map[string]*url.URL)
 
+	if service.Hedging != nil {
+		if service.Sticky != nil && service.Sticky.Cookie != nil {
+			logger.Warn().Msg("Hedging is disabled for sticky sessions")
+		}
+
+		err = lb.EnableHedging(service.Hedging, func(*http.Request) {
+			if m.metricsRegistry != nil && m.metricsRegistry.IsSvcEnabled() {
+				m.metricsRegistry.ServiceHedgesCounter().With("service", serviceName).Add(1)
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid hedging configuration: %w", err)
+		}
+	}
+
 	for _, server := range shuffle(service.Servers, m.rand) {
 		hasher := fnv.New64a()
 		_, _ = hasher.Write([]byte(server.URL)) // this will never return an error.
@@ -298,6 +376,7 @@ func (m *Manager) getLoadBalancerServiceHandler(ctx context.Context, serviceName
 			Msg("Creating server")
 
 		proxy := buildSingleHostProxy(target, passHostHeader, time.Duration(flushInterval), roundTripper, m.bufferPool)
+		proxy = timeout.NewServer(proxy, service.Timeouts)
 
 		proxy = accesslog.NewFieldHandler(proxy, accesslog.ServiceURL, target.String(), nil)
 		proxy = accesslog.NewFieldHandler(proxy, accesslog.ServiceAddr, target.Host, nil)
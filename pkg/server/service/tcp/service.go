@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"reflect"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
@@ -43,7 +45,14 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		return nil, fmt.Errorf("the service %q does not exist", serviceQualifiedName)
 	}
 
-	if conf.LoadBalancer != nil && conf.Weighted != nil {
+	value := reflect.ValueOf(*conf.TCPService)
+	var count int
+	for i := 0; i < value.NumField(); i++ {
+		if !value.Field(i).IsNil() {
+			count++
+		}
+	}
+	if count > 1 {
 		err := errors.New("cannot create service: multi-types service not supported, consider declaring two different pieces of service instead")
 		conf.AddError(err, true)
 		return nil, err
@@ -51,7 +60,11 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 
 	switch {
 	case conf.LoadBalancer != nil:
-		loadBalancer := tcp.NewWRRLoadBalancer()
+		loadBalancer, err := newLoadBalancer(conf.LoadBalancer.Strategy)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
 
 		if len(conf.LoadBalancer.ServersTransport) > 0 {
 			conf.LoadBalancer.ServersTransport = provider.GetQualifiedName(ctx, conf.LoadBalancer.ServersTransport)
@@ -78,7 +91,7 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			loadBalancer.AddWeightServer(server.Address, handler, server.Weight)
 			logger.Debug().Msg("Creating TCP server")
 		}
 
@@ -99,6 +112,24 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 
 		return loadBalancer, nil
 
+	case conf.Failover != nil:
+		handler, err := m.getFailoverServiceHandler(ctx, conf.Failover)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		return handler, nil
+
+	case conf.Mirroring != nil:
+		handler, err := m.getMirrorServiceHandler(ctx, conf.Mirroring)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		return handler, nil
+
 	default:
 		err := fmt.Errorf("the service %q does not have any type defined", serviceQualifiedName)
 		conf.AddError(err, true)
@@ -106,6 +137,78 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 	}
 }
 
+func (m *Manager) getFailoverServiceHandler(ctx context.Context, config *dynamic.TCPFailover) (tcp.Handler, error) {
+	serviceHandler, err := m.BuildTCP(ctx, config.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	fallbackHandler, err := m.BuildTCP(ctx, config.Fallback)
+	if err != nil {
+		return nil, err
+	}
+
+	return tcp.NewFailover(serviceHandler, fallbackHandler), nil
+}
+
+func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.TCPMirroring) (tcp.Handler, error) {
+	serviceHandler, err := m.BuildTCP(ctx, config.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := tcp.NewMirroring(serviceHandler)
+	for _, mirrorConfig := range config.Mirrors {
+		mirrorHandler, err := m.BuildTCP(ctx, mirrorConfig.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		err = handler.AddMirror(provider.GetQualifiedName(ctx, mirrorConfig.Name), mirrorHandler, mirrorConfig.Percent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return handler, nil
+}
+
+// balancer is a load-balancer of TCP servers.
+type balancer interface {
+	tcp.Handler
+	// AddWeightServer adds a server with a weight, the key identifying it among the servers.
+	AddWeightServer(key string, serverHandler tcp.Handler, weight *int)
+}
+
+func newLoadBalancer(strategy string) (balancer, error) {
+	switch strategy {
+	case "", dynamic.BalancingStrategyWRR:
+		return keylessBalancer{tcp.NewWRRLoadBalancer()}, nil
+	case dynamic.BalancingStrategyLeastConn:
+		return keylessBalancer{tcp.NewLeastConnLoadBalancer()}, nil
+	case dynamic.BalancingStrategyConsistentHash:
+		return tcp.NewConsistentHashLoadBalancer(), nil
+	default:
+		return nil, fmt.Errorf("unknown load-balancing strategy %q", strategy)
+	}
+}
+
+// keylessBalancer adapts a load-balancer which does not identify its servers to the balancer interface.
+type keylessBalancer struct {
+	keyless interface {
+		tcp.Handler
+		AddWeightServer(serverHandler tcp.Handler, weight *int)
+	}
+}
+
+func (b keylessBalancer) ServeTCP(conn tcp.WriteCloser) {
+	b.keyless.ServeTCP(conn)
+}
+
+func (b keylessBalancer) AddWeightServer(_ string, serverHandler tcp.Handler, weight *int) {
+	b.keyless.AddWeightServer(serverHandler, weight)
+}
+
 func shuffle[T any](values []T, r *rand.Rand) []T {
 	shuffled := make([]T, len(values))
 	copy(shuffled, values)
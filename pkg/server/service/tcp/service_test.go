@@ -234,6 +234,256 @@ func TestManager_BuildTCP(t *testing.T) {
 			providerName:  "provider-1",
 			expectedError: "TCP dialer not found myServersTransport@provider-1",
 		},
+		{
+			desc:        "least connections strategy with weighted servers",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy: dynamic.BalancingStrategyLeastConn,
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+									Weight:  Int(2),
+								},
+								{
+									Address: "192.168.0.13:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "consistent hash strategy with weighted servers",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy: dynamic.BalancingStrategyConsistentHash,
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+									Weight:  Int(2),
+								},
+								{
+									Address: "192.168.0.13:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "unknown strategy",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Strategy: "foo",
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+									Weight:  Int(2),
+								},
+								{
+									Address: "192.168.0.13:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: `unknown load-balancing strategy "foo"`,
+		},
+		{
+			desc:        "failover service",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						Failover: &dynamic.TCPFailover{
+							Service:  "main",
+							Fallback: "fallback",
+						},
+					},
+				},
+				"main@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+				"fallback@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "failover service with unknown fallback",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						Failover: &dynamic.TCPFailover{
+							Service:  "main",
+							Fallback: "fallback",
+						},
+					},
+				},
+				"main@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: `the service "fallback@provider-1" does not exist`,
+		},
+		{
+			desc:        "mirroring service",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						Mirroring: &dynamic.TCPMirroring{
+							Service: "main",
+							Mirrors: []dynamic.TCPMirrorService{
+								{
+									Name:    "mirror",
+									Percent: 10,
+								},
+							},
+						},
+					},
+				},
+				"main@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+				"mirror@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "mirroring service with invalid percent",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						Mirroring: &dynamic.TCPMirroring{
+							Service: "main",
+							Mirrors: []dynamic.TCPMirrorService{
+								{
+									Name:    "mirror",
+									Percent: 101,
+								},
+							},
+						},
+					},
+				},
+				"main@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+				"mirror@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "percent must be between 0 and 100",
+		},
+		{
+			desc:        "multi-types service",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+								},
+							},
+						},
+						Failover: &dynamic.TCPFailover{
+							Service:  "main",
+							Fallback: "fallback",
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "cannot create service: multi-types service not supported, consider declaring two different pieces of service instead",
+		},
 	}
 
 	for _, test := range testCases {
@@ -267,3 +517,5 @@ func TestManager_BuildTCP(t *testing.T) {
 		})
 	}
 }
+
+func Int(v int) *int { return &v }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
@@ -49,7 +50,11 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 
 	switch {
 	case conf.LoadBalancer != nil:
-		loadBalancer := udp.NewWRRLoadBalancer()
+		loadBalancer, err := newLoadBalancer(conf.LoadBalancer.Strategy)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
 
 		for index, server := range shuffle(conf.LoadBalancer.Servers, m.rand) {
 			srvLogger := logger.With().
@@ -67,7 +72,7 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			loadBalancer.AddWeightedServer(server.Address, handler, server.Weight)
 			srvLogger.Debug().Msg("Creating UDP server")
 		}
 
@@ -95,6 +100,42 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 	}
 }
 
+// balancer is a load-balancer of UDP servers.
+type balancer interface {
+	udp.Handler
+	// AddWeightedServer adds a server with a weight, the key identifying it among the servers.
+	AddWeightedServer(key string, serverHandler udp.Handler, weight *int)
+}
+
+func newLoadBalancer(strategy string) (balancer, error) {
+	switch strategy {
+	case "", dynamic.BalancingStrategyWRR:
+		return keylessBalancer{udp.NewWRRLoadBalancer()}, nil
+	case dynamic.BalancingStrategyLeastConn:
+		return keylessBalancer{udp.NewLeastConnLoadBalancer()}, nil
+	case dynamic.BalancingStrategyConsistentHash:
+		return udp.NewConsistentHashLoadBalancer(), nil
+	default:
+		return nil, fmt.Errorf("unknown load-balancing strategy %q", strategy)
+	}
+}
+
+// keylessBalancer adapts a load-balancer which does not identify its servers to the balancer interface.
+type keylessBalancer struct {
+	keyless interface {
+		udp.Handler
+		AddWeightedServer(serverHandler udp.Handler, weight *int)
+	}
+}
+
+func (b keylessBalancer) ServeUDP(conn *udp.Conn) {
+	b.keyless.ServeUDP(conn)
+}
+
+func (b keylessBalancer) AddWeightedServer(_ string, serverHandler udp.Handler, weight *int) {
+	b.keyless.AddWeightedServer(serverHandler, weight)
+}
+
 func shuffle[T any](values []T, r *rand.Rand) []T {
 	shuffled := make([]T, len(values))
 	copy(shuffled, values)
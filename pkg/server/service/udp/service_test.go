@@ -171,6 +171,76 @@ func TestManager_BuildUDP(t *testing.T) {
 			},
 			providerName: "provider-1",
 		},
+		{
+			desc:        "least connections strategy with weighted servers",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName@provider-1": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Strategy: dynamic.BalancingStrategyLeastConn,
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "192.168.0.12:80",
+									Weight:  Int(2),
+								},
+								{
+									Address: "192.168.0.13:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "consistent hash strategy with weighted servers",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName@provider-1": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Strategy: dynamic.BalancingStrategyConsistentHash,
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "192.168.0.12:80",
+									Weight:  Int(2),
+								},
+								{
+									Address: "192.168.0.13:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "unknown strategy",
+			serviceName: "serviceName",
+			configs: map[string]*runtime.UDPServiceInfo{
+				"serviceName@provider-1": {
+					UDPService: &dynamic.UDPService{
+						LoadBalancer: &dynamic.UDPServersLoadBalancer{
+							Strategy: "foo",
+							Servers: []dynamic.UDPServer{
+								{
+									Address: "192.168.0.12:80",
+									Weight:  Int(2),
+								},
+								{
+									Address: "192.168.0.13:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: `unknown load-balancing strategy "foo"`,
+		},
 	}
 
 	for _, test := range testCases {
@@ -199,3 +269,5 @@ func TestManager_BuildUDP(t *testing.T) {
 		})
 	}
 }
+
+func Int(v int) *int { return &v }
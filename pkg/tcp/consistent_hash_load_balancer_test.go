@@ -0,0 +1,96 @@
+package tcp
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsistentHashLoadBalancer(t *testing.T) {
+	newBalancer := func(weights map[string]int) *ConsistentHashLoadBalancer {
+		balancer := NewConsistentHashLoadBalancer()
+		for server, weight := range weights {
+			server, weight := server, weight
+			balancer.AddWeightServer(server, HandlerFunc(func(conn WriteCloser) {
+				_, err := conn.Write([]byte(server))
+				require.NoError(t, err)
+			}), &weight)
+		}
+		return balancer
+	}
+
+	serve := func(balancer *ConsistentHashLoadBalancer, remoteAddr string) string {
+		conn := &addrConn{fakeConn: &fakeConn{writeCall: make(map[string]int)}, remoteAddr: remoteAddr}
+		balancer.ServeTCP(conn)
+
+		require.Len(t, conn.writeCall, 1)
+		for server := range conn.writeCall {
+			return server
+		}
+		return ""
+	}
+
+	balancer := newBalancer(map[string]int{"h1": 1, "h2": 1, "h3": 1})
+
+	assignments := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		ip := fmt.Sprintf("10.0.%d.%d", i/256, i%256)
+		assignments[ip] = serve(balancer, ip+":1234")
+
+		// The connections of a client IP, whatever their port, land on the same server.
+		assert.Equal(t, assignments[ip], serve(balancer, ip+":4321"))
+	}
+
+	counts := make(map[string]int)
+	for _, server := range assignments {
+		counts[server]++
+	}
+	for _, server := range []string{"h1", "h2", "h3"} {
+		assert.InDelta(t, 333, counts[server], 100, server)
+	}
+
+	// Removing a server only moves its own clients.
+	balancer = newBalancer(map[string]int{"h1": 1, "h2": 1})
+	for ip, server := range assignments {
+		if server != "h3" {
+			assert.Equal(t, server, serve(balancer, ip+":1234"))
+		}
+	}
+
+	// The share of a server is proportional to its weight.
+	balancer = newBalancer(map[string]int{"h1": 3, "h2": 1, "h3": 0})
+	counts = make(map[string]int)
+	for ip := range assignments {
+		counts[serve(balancer, ip+":1234")]++
+	}
+	assert.InDelta(t, 750, counts["h1"], 100)
+	assert.InDelta(t, 250, counts["h2"], 100)
+	assert.Zero(t, counts["h3"])
+}
+
+func TestConsistentHashLoadBalancer_noServer(t *testing.T) {
+	balancer := NewConsistentHashLoadBalancer()
+
+	conn := &addrConn{fakeConn: &fakeConn{writeCall: make(map[string]int)}, remoteAddr: "10.0.0.1:1234"}
+	balancer.ServeTCP(conn)
+
+	assert.Equal(t, 1, conn.closeCall)
+}
+
+type addrConn struct {
+	*fakeConn
+
+	remoteAddr string
+}
+
+func (c *addrConn) RemoteAddr() net.Addr {
+	addr, _ := net.ResolveTCPAddr("tcp", c.remoteAddr)
+	return addr
+}
+
+func (c *addrConn) LocalAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 80}
+}
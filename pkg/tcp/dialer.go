@@ -1,12 +1,14 @@
 package tcp
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -34,18 +36,51 @@ func (d tcpDialer) TerminationDelay() time.Duration {
 	return d.terminationDelay
 }
 
+// reloadableDialer is a dialer whose underlying dialer can be replaced while it is in use.
+type reloadableDialer struct {
+	current atomic.Pointer[Dialer]
+}
+
+func newReloadableDialer(dialer Dialer) *reloadableDialer {
+	d := &reloadableDialer{}
+	d.current.Store(&dialer)
+
+	return d
+}
+
+func (d *reloadableDialer) Dial(network, addr string) (net.Conn, error) {
+	return (*d.current.Load()).Dial(network, addr)
+}
+
+func (d *reloadableDialer) TerminationDelay() time.Duration {
+	return (*d.current.Load()).TerminationDelay()
+}
+
+func (d *reloadableDialer) set(dialer Dialer) {
+	d.current.Store(&dialer)
+}
+
 // SpiffeX509Source allows to retrieve a x509 SVID and bundle.
 type SpiffeX509Source interface {
 	x509svid.Source
 	x509bundle.Source
 }
 
+// ClientCertificateResolver issues the client certificates presented by the servers transports.
+type ClientCertificateResolver interface {
+	GetClientCertificate(name string) (*tls.Certificate, error)
+}
+
 // DialerManager handles dialer for the reverse proxy.
 type DialerManager struct {
-	rtLock           sync.RWMutex
-	dialers          map[string]Dialer
-	dialersTLS       map[string]Dialer
+	rtLock     sync.RWMutex
+	dialers    map[string]Dialer
+	dialersTLS map[string]Dialer
+	configs    map[string]*dynamic.TCPServersTransport
+	// files watches the root CAs and client certificates files, nil until the manager runs.
+	files            *traefiktls.FileWatcher
 	spiffeX509Source SpiffeX509Source
+	certResolvers    map[string]ClientCertificateResolver
 }
 
 // NewDialerManager creates a new DialerManager.
@@ -57,6 +92,14 @@ func NewDialerManager(spiffeX509Source SpiffeX509Source) *DialerManager {
 	}
 }
 
+// SetClientCertificateResolvers sets the certificates resolvers which can issue the client certificates of the servers transports.
+func (d *DialerManager) SetClientCertificateResolvers(resolvers map[string]ClientCertificateResolver) {
+	d.rtLock.Lock()
+	defer d.rtLock.Unlock()
+
+	d.certResolvers = resolvers
+}
+
 // Update updates the dialers configurations.
 func (d *DialerManager) Update(configs map[string]*dynamic.TCPServersTransport) {
 	d.rtLock.Lock()
@@ -64,6 +107,7 @@ func (d *DialerManager) Update(configs map[string]*dynamic.TCPServersTransport)
 
 	d.dialers = make(map[string]Dialer)
 	d.dialersTLS = make(map[string]Dialer)
+	d.configs = configs
 	for configName, config := range configs {
 		if err := d.createDialers(configName, config); err != nil {
 			log.Debug().
@@ -72,6 +116,71 @@ func (d *DialerManager) Update(configs map[string]*dynamic.TCPServersTransport)
 				Msg("Create TCP Dialer")
 		}
 	}
+
+	if d.files != nil {
+		d.files.Watch(d.watchedFiles())
+	}
+}
+
+// Run recreates the dialers whose root CAs or client certificates files change, until the given context is done.
+func (d *DialerManager) Run(ctx context.Context) {
+	files, err := traefiktls.NewFileWatcher()
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to watch the TCP servers transports files, they will not be reloaded on change")
+		return
+	}
+
+	d.rtLock.Lock()
+	d.files = files
+	d.files.Watch(d.watchedFiles())
+	d.rtLock.Unlock()
+
+	files.Run(ctx, d.reloadFiles)
+}
+
+// reloadFiles recreates the TLS dialers using files, and replaces them in place,
+// keeping the previous ones when their files cannot be loaded, as they may be in the middle of a rotation.
+func (d *DialerManager) reloadFiles() {
+	d.rtLock.Lock()
+	defer d.rtLock.Unlock()
+
+	for configName, config := range d.configs {
+		if config == nil || config.TLS == nil || len(traefiktls.ClientFiles(config.TLS.RootCAs, config.TLS.Certificates)) == 0 {
+			continue
+		}
+
+		if err := traefiktls.CheckClientFiles(config.TLS.RootCAs, config.TLS.Certificates); err != nil {
+			log.Warn().Str("dialer", configName).Err(err).Msg("Unable to reload TCP Dialer files, keeping the previous ones")
+			continue
+		}
+
+		_, tlsDialer, err := d.buildDialers(configName, config)
+		if err != nil {
+			log.Error().Str("dialer", configName).Err(err).Msg("Could not reload TCP Dialer")
+			continue
+		}
+
+		reloadable, ok := d.dialersTLS[configName].(*reloadableDialer)
+		if !ok {
+			d.dialersTLS[configName] = newReloadableDialer(tlsDialer)
+			continue
+		}
+
+		reloadable.set(tlsDialer)
+	}
+}
+
+// watchedFiles returns the root CAs and client certificates files to watch.
+// It must be called with the lock held.
+func (d *DialerManager) watchedFiles() []string {
+	var files []string
+	for _, config := range d.configs {
+		if config != nil && config.TLS != nil {
+			files = append(files, traefiktls.ClientFiles(config.TLS.RootCAs, config.TLS.Certificates)...)
+		}
+	}
+
+	return files
 }
 
 // Get gets a dialer by name.
@@ -99,9 +208,27 @@ func (d *DialerManager) Get(name string, tls bool) (Dialer, error) {
 }
 
 // createDialers creates the dialers according to the TCPServersTransport configuration.
+// The TLS dialer of a transport using files is reloadable, so that the handlers already holding it use the reloaded files.
 func (d *DialerManager) createDialers(name string, cfg *dynamic.TCPServersTransport) error {
+	dialer, tlsDialer, err := d.buildDialers(name, cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.TLS != nil && len(traefiktls.ClientFiles(cfg.TLS.RootCAs, cfg.TLS.Certificates)) > 0 {
+		tlsDialer = newReloadableDialer(tlsDialer)
+	}
+
+	d.dialers[name] = dialer
+	d.dialersTLS[name] = tlsDialer
+
+	return nil
+}
+
+// buildDialers builds the dialer and the TLS dialer of the given TCPServersTransport configuration.
+func (d *DialerManager) buildDialers(name string, cfg *dynamic.TCPServersTransport) (Dialer, Dialer, error) {
 	if cfg == nil {
-		return errors.New("no transport configuration given")
+		return nil, nil, errors.New("no transport configuration given")
 	}
 
 	dialer := &net.Dialer{
@@ -114,20 +241,20 @@ func (d *DialerManager) createDialers(name string, cfg *dynamic.TCPServersTransp
 	if cfg.TLS != nil {
 		if cfg.TLS.Spiffe != nil {
 			if d.spiffeX509Source == nil {
-				return errors.New("SPIFFE is enabled for this transport, but not configured")
+				return nil, nil, errors.New("SPIFFE is enabled for this transport, but not configured")
 			}
 
 			authorizer, err := buildSpiffeAuthorizer(cfg.TLS.Spiffe)
 			if err != nil {
-				return fmt.Errorf("unable to build SPIFFE authorizer: %w", err)
+				return nil, nil, fmt.Errorf("unable to build SPIFFE authorizer: %w", err)
 			}
 
 			tlsConfig = tlsconfig.MTLSClientConfig(d.spiffeX509Source, d.spiffeX509Source, authorizer)
 		}
 
-		if cfg.TLS.InsecureSkipVerify || len(cfg.TLS.RootCAs) > 0 || len(cfg.TLS.ServerName) > 0 || len(cfg.TLS.Certificates) > 0 || cfg.TLS.PeerCertURI != "" {
+		if cfg.TLS.InsecureSkipVerify || len(cfg.TLS.RootCAs) > 0 || len(cfg.TLS.ServerName) > 0 || len(cfg.TLS.Certificates) > 0 || cfg.TLS.PeerCertURI != "" || cfg.TLS.CertResolver != "" {
 			if tlsConfig != nil {
-				return errors.New("TLS and SPIFFE configuration cannot be defined at the same time")
+				return nil, nil, errors.New("TLS and SPIFFE configuration cannot be defined at the same time")
 			}
 
 			tlsConfig = &tls.Config{
@@ -137,6 +264,15 @@ func (d *DialerManager) createDialers(name string, cfg *dynamic.TCPServersTransp
 				Certificates:       cfg.TLS.Certificates.GetCertificates(),
 			}
 
+			if cfg.TLS.CertResolver != "" {
+				getClientCertificate, err := d.getClientCertificateFunc(name, cfg.TLS.CertResolver, cfg.TLS.Certificates)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				tlsConfig.GetClientCertificate = getClientCertificate
+			}
+
 			if cfg.TLS.PeerCertURI != "" {
 				tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
 					return traefiktls.VerifyPeerCertificate(cfg.TLS.PeerCertURI, tlsConfig, rawCerts)
@@ -150,10 +286,23 @@ func (d *DialerManager) createDialers(name string, cfg *dynamic.TCPServersTransp
 		Config:    tlsConfig,
 	}
 
-	d.dialers[name] = tcpDialer{dialer, time.Duration(cfg.TerminationDelay)}
-	d.dialersTLS[name] = tcpDialer{tlsDialer, time.Duration(cfg.TerminationDelay)}
+	return tcpDialer{dialer, time.Duration(cfg.TerminationDelay)}, tcpDialer{tlsDialer, time.Duration(cfg.TerminationDelay)}, nil
+}
 
-	return nil
+// getClientCertificateFunc returns the function issuing the client certificate of the given servers transport with the given certificates resolver.
+func (d *DialerManager) getClientCertificateFunc(name, certResolver string, certificates traefiktls.Certificates) (func(*tls.CertificateRequestInfo) (*tls.Certificate, error), error) {
+	if len(certificates) > 0 {
+		return nil, errors.New("client certificates and certificates resolver cannot be defined at the same time")
+	}
+
+	resolver, ok := d.certResolvers[certResolver]
+	if !ok {
+		return nil, fmt.Errorf("unknown certificates resolver %q, or it cannot issue client certificates", certResolver)
+	}
+
+	return func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return resolver.GetClientCertificate(name)
+	}, nil
 }
 
 func createRootCACertPool(rootCAs []traefiktls.FileOrContent) *x509.CertPool {
@@ -11,6 +11,8 @@ import (
 	"math/big"
 	"net"
 	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -22,6 +24,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
 )
 
 // LocalhostCert is a PEM-encoded TLS cert
@@ -224,6 +227,66 @@ func TestTLS(t *testing.T) {
 	assert.Equal(t, "PONG", buffer.String())
 }
 
+func TestDialerManager_reloadFiles(t *testing.T) {
+	cert, err := tls.X509KeyPair(LocalhostCert, LocalhostKey)
+	require.NoError(t, err)
+
+	tlsListener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = tlsListener.Close() })
+
+	go func() {
+		for {
+			conn, err := tlsListener.Accept()
+			if err != nil {
+				return
+			}
+
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+
+	otherCA, _, err := generate.KeyPair("example.com", time.Time{})
+	require.NoError(t, err)
+
+	rootCAFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(rootCAFile, otherCA, 0o600))
+
+	dialerManager := NewDialerManager(nil)
+	dialerManager.Update(map[string]*dynamic.TCPServersTransport{
+		"test": {
+			TLS: &dynamic.TLSClientConfig{
+				ServerName: "example.com",
+				RootCAs:    []traefiktls.FileOrContent{traefiktls.FileOrContent(rootCAFile)},
+			},
+		},
+	})
+
+	// The dialer is retrieved once, before the files are reloaded, as the TCP services do when they are built.
+	dialer, err := dialerManager.Get("test", true)
+	require.NoError(t, err)
+
+	_, err = dialer.Dial("tcp", tlsListener.Addr().String())
+	require.Error(t, err)
+
+	// The dialer uses the new root CAs once the root CAs file is reloaded.
+	require.NoError(t, os.WriteFile(rootCAFile, LocalhostCert, 0o600))
+	dialerManager.reloadFiles()
+
+	conn, err := dialer.Dial("tcp", tlsListener.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	// The dialer keeps the previous root CAs when the root CAs file cannot be loaded.
+	require.NoError(t, os.WriteFile(rootCAFile, []byte("invalid"), 0o600))
+	dialerManager.reloadFiles()
+
+	conn, err = dialer.Dial("tcp", tlsListener.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
+
 func TestTLSWithInsecureSkipVerify(t *testing.T) {
 	cert, err := tls.X509KeyPair(LocalhostCert, LocalhostKey)
 	require.NoError(t, err)
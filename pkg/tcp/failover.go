@@ -0,0 +1,92 @@
+package tcp
+
+import (
+	"errors"
+	"net"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Failover is a Handler forwarding the connections to the fallback handler
+// when dialing a server of the main handler fails.
+type Failover struct {
+	handler         Handler
+	fallbackHandler Handler
+}
+
+// NewFailover creates a new Failover.
+func NewFailover(handler, fallbackHandler Handler) *Failover {
+	return &Failover{
+		handler:         handler,
+		fallbackHandler: fallbackHandler,
+	}
+}
+
+// ServeTCP forwards the connection to the main handler, or to the fallback one if dialing the server fails.
+func (f *Failover) ServeTCP(conn WriteCloser) {
+	fConn := &failoverConn{WriteCloser: conn}
+	f.handler.ServeTCP(fConn)
+
+	if fConn.dialErr == nil {
+		return
+	}
+
+	log.Debug().Err(fConn.dialErr).Msg("Forwarding connection to fallback service")
+
+	// The original connection is given to the fallback handler,
+	// so that a dial failure of the fallback also reaches an enclosing Failover.
+	f.fallbackHandler.ServeTCP(conn)
+}
+
+// dialFailureReporter is implemented by the connections which can be handed over to another handler
+// when the Proxy fails to dial its server, the Proxy then not closing them.
+type dialFailureReporter interface {
+	reportDialFailure(err error)
+}
+
+// reportDialFailure reports the dial failure to the first dialFailureReporter found by unwrapping the given connection,
+// as the connection served by a Failover can be wrapped by the handlers between the Failover and the Proxy, such as a Mirroring.
+func reportDialFailure(conn net.Conn, err error) {
+	for conn != nil {
+		if reporter, ok := conn.(dialFailureReporter); ok {
+			reporter.reportDialFailure(err)
+			return
+		}
+
+		wConn, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			return
+		}
+
+		conn = wConn.NetConn()
+	}
+}
+
+// failoverConn is a connection recording the dial failure of the Proxy serving it.
+type failoverConn struct {
+	WriteCloser
+
+	dialErr error
+}
+
+func (c *failoverConn) reportDialFailure(err error) {
+	if err == nil {
+		err = errors.New("dial failure")
+	}
+
+	c.dialErr = err
+}
+
+// Close closes the connection, unless it has to be handed over to the fallback handler.
+func (c *failoverConn) Close() error {
+	if c.dialErr != nil {
+		return nil
+	}
+
+	return c.WriteCloser.Close()
+}
+
+// NetConn returns the underlying connection.
+func (c *failoverConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
@@ -0,0 +1,100 @@
+package tcp
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailover(t *testing.T) {
+	// An address refusing the connections.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	refusingAddr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
+
+	newFailingProxy := func() Handler {
+		proxy, err := NewProxy(refusingAddr, nil, dialer)
+		require.NoError(t, err)
+		return proxy
+	}
+
+	newHandler := func(name string) Handler {
+		return HandlerFunc(func(conn WriteCloser) {
+			_, err := conn.Write([]byte(name))
+			require.NoError(t, err)
+		})
+	}
+
+	newMirroring := func(handler Handler) Handler {
+		mirroring := NewMirroring(handler)
+		require.NoError(t, mirroring.AddMirror("mirror", newHandler("mirror"), 100))
+		return mirroring
+	}
+
+	testCases := []struct {
+		desc          string
+		handler       Handler
+		expectedWrite map[string]int
+	}{
+		{
+			desc:          "main service is up",
+			handler:       NewFailover(newHandler("main"), newHandler("fallback")),
+			expectedWrite: map[string]int{"main": 1},
+		},
+		{
+			desc:          "main service cannot be dialed",
+			handler:       NewFailover(newFailingProxy(), newHandler("fallback")),
+			expectedWrite: map[string]int{"fallback": 1},
+		},
+		{
+			desc:          "main and fallback services of a nested failover cannot be dialed",
+			handler:       NewFailover(NewFailover(newFailingProxy(), newFailingProxy()), newHandler("fallback")),
+			expectedWrite: map[string]int{"fallback": 1},
+		},
+		{
+			desc:          "main service is a mirroring which cannot be dialed",
+			handler:       NewFailover(newMirroring(newFailingProxy()), newHandler("fallback")),
+			expectedWrite: map[string]int{"fallback": 1},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			conn := &addrConn{fakeConn: &fakeConn{writeCall: make(map[string]int)}, remoteAddr: "10.0.0.1:1234"}
+			test.handler.ServeTCP(conn)
+
+			assert.Equal(t, test.expectedWrite, conn.writeCall)
+			// The connection is left to the handlers, which do not close it in this test.
+			assert.Equal(t, 0, conn.closeCall)
+		})
+	}
+}
+
+func TestFailover_fallbackCannotBeDialed(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	refusingAddr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
+
+	main, err := NewProxy(refusingAddr, nil, dialer)
+	require.NoError(t, err)
+	fallback, err := NewProxy(refusingAddr, nil, dialer)
+	require.NoError(t, err)
+
+	conn := &addrConn{fakeConn: &fakeConn{writeCall: make(map[string]int)}, remoteAddr: "10.0.0.1:1234"}
+	NewFailover(main, fallback).ServeTCP(conn)
+
+	// The connection is closed once, by the fallback Proxy.
+	assert.Equal(t, 1, conn.closeCall)
+}
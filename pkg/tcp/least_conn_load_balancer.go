@@ -0,0 +1,98 @@
+package tcp
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+type leastConnServer struct {
+	Handler
+	weight int
+	conns  int
+}
+
+// LeastConnLoadBalancer is a load balancer for TCP services,
+// forwarding each connection to the server having the fewest ongoing connections relatively to its weight.
+type LeastConnLoadBalancer struct {
+	servers []*leastConnServer
+	lock    sync.Mutex
+	// index is the server the next lookup starts from,
+	// rotated on each connection to spread the connections between the servers with the same load.
+	index int
+}
+
+// NewLeastConnLoadBalancer creates a new LeastConnLoadBalancer.
+func NewLeastConnLoadBalancer() *LeastConnLoadBalancer {
+	return &LeastConnLoadBalancer{}
+}
+
+// ServeTCP forwards the connection to the right service.
+func (b *LeastConnLoadBalancer) ServeTCP(conn WriteCloser) {
+	b.lock.Lock()
+	next, err := b.next()
+	if err == nil {
+		next.conns++
+	}
+	b.lock.Unlock()
+
+	if err != nil {
+		log.Error().Err(err).Msg("Error during load balancing")
+		conn.Close()
+		return
+	}
+
+	defer func() {
+		b.lock.Lock()
+		next.conns--
+		b.lock.Unlock()
+	}()
+
+	next.ServeTCP(conn)
+}
+
+// AddServer appends a server to the existing list.
+func (b *LeastConnLoadBalancer) AddServer(serverHandler Handler) {
+	w := 1
+	b.AddWeightServer(serverHandler, &w)
+}
+
+// AddWeightServer appends a server to the existing list with a weight.
+func (b *LeastConnLoadBalancer) AddWeightServer(serverHandler Handler, weight *int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	w := 1
+	if weight != nil {
+		w = *weight
+	}
+	b.servers = append(b.servers, &leastConnServer{Handler: serverHandler, weight: w})
+}
+
+func (b *LeastConnLoadBalancer) next() (*leastConnServer, error) {
+	if len(b.servers) == 0 {
+		return nil, errors.New("no servers in the pool")
+	}
+
+	var best *leastConnServer
+	for i := range b.servers {
+		srv := b.servers[(b.index+i)%len(b.servers)]
+		if srv.weight <= 0 {
+			continue
+		}
+
+		// Compares conns/weight ratios without dividing.
+		if best == nil || srv.conns*best.weight < best.conns*srv.weight {
+			best = srv
+		}
+	}
+
+	if best == nil {
+		return nil, errors.New("all servers have 0 weight")
+	}
+
+	b.index = (b.index + 1) % len(b.servers)
+
+	return best, nil
+}
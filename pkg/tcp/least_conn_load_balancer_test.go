@@ -0,0 +1,102 @@
+package tcp
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeastConnLoadBalancer(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		serving = make(map[string]int)
+	)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	balancer := NewLeastConnLoadBalancer()
+	for _, server := range []struct {
+		name   string
+		weight int
+	}{
+		{name: "h1", weight: 1},
+		{name: "h2", weight: 2},
+	} {
+		server := server
+		balancer.AddWeightServer(HandlerFunc(func(conn WriteCloser) {
+			mu.Lock()
+			serving[server.name]++
+			mu.Unlock()
+
+			started <- struct{}{}
+			<-release
+
+			mu.Lock()
+			serving[server.name]--
+			mu.Unlock()
+		}), &server.weight)
+	}
+
+	var wg sync.WaitGroup
+	serve := func() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			balancer.ServeTCP(&fakeConn{writeCall: make(map[string]int)})
+		}()
+		<-started
+	}
+
+	// The ongoing connections are spread according to the weights.
+	for i := 0; i < 6; i++ {
+		serve()
+	}
+
+	mu.Lock()
+	assert.Equal(t, map[string]int{"h1": 2, "h2": 4}, serving)
+	mu.Unlock()
+
+	close(release)
+	wg.Wait()
+
+	mu.Lock()
+	assert.Equal(t, map[string]int{"h1": 0, "h2": 0}, serving)
+	mu.Unlock()
+}
+
+func TestLeastConnLoadBalancer_noServer(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		weights []int
+	}{
+		{
+			desc: "no servers",
+		},
+		{
+			desc:    "all servers with 0 weight",
+			weights: []int{0, 0},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := NewLeastConnLoadBalancer()
+			for _, weight := range test.weights {
+				weight := weight
+				balancer.AddWeightServer(HandlerFunc(func(conn WriteCloser) {
+					t.Error("server should not be called")
+				}), &weight)
+			}
+
+			conn := &fakeConn{writeCall: make(map[string]int)}
+			balancer.ServeTCP(conn)
+
+			assert.Equal(t, 1, conn.closeCall)
+		})
+	}
+}
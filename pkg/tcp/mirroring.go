@@ -0,0 +1,253 @@
+package tcp
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/safe"
+)
+
+// mirrorBufferSize is the number of reads from the client which can be buffered for a mirror,
+// before the mirror is considered too slow and is cut off.
+const mirrorBufferSize = 64
+
+// Mirroring is a Handler copying the bytes sent by the clients to mirror handlers,
+// the responses of which are discarded.
+type Mirroring struct {
+	handler        Handler
+	mirrorHandlers []*mirrorHandler
+
+	lock  sync.Mutex
+	total uint64
+}
+
+type mirrorHandler struct {
+	Handler
+	name    string
+	percent int
+
+	count uint64
+}
+
+// NewMirroring creates a new Mirroring.
+func NewMirroring(handler Handler) *Mirroring {
+	return &Mirroring{handler: handler}
+}
+
+// AddMirror adds a mirror handler, receiving the given percentage of the connections.
+func (m *Mirroring) AddMirror(name string, handler Handler, percent int) error {
+	if percent < 0 || percent > 100 {
+		return errors.New("percent must be between 0 and 100")
+	}
+
+	m.mirrorHandlers = append(m.mirrorHandlers, &mirrorHandler{Handler: handler, name: name, percent: percent})
+
+	return nil
+}
+
+// ServeTCP forwards the connection to the main handler, and a copy of the client bytes to the active mirrors.
+func (m *Mirroring) ServeTCP(conn WriteCloser) {
+	mirrors := m.getActiveMirrors()
+	if len(mirrors) == 0 {
+		m.handler.ServeTCP(conn)
+		return
+	}
+
+	tConn := &teeConn{WriteCloser: conn}
+	for _, handler := range mirrors {
+		mConn := newMirrorConn(handler.name, conn)
+		tConn.mirrors = append(tConn.mirrors, mConn)
+
+		handler := handler
+		safe.Go(func() {
+			handler.ServeTCP(mConn)
+		})
+	}
+
+	m.handler.ServeTCP(tConn)
+}
+
+func (m *Mirroring) getActiveMirrors() []*mirrorHandler {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.total++
+
+	var mirrors []*mirrorHandler
+	for _, handler := range m.mirrorHandlers {
+		if handler.count*100 < m.total*uint64(handler.percent) {
+			handler.count++
+			mirrors = append(mirrors, handler)
+		}
+	}
+
+	return mirrors
+}
+
+// teeConn is a connection copying the bytes read from the client to the mirror connections.
+type teeConn struct {
+	WriteCloser
+
+	mirrors []*mirrorConn
+}
+
+func (c *teeConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	if n > 0 {
+		for _, mirror := range c.mirrors {
+			mirror.send(p[:n])
+		}
+	}
+
+	if err != nil {
+		c.endMirrors()
+	}
+
+	return n, err
+}
+
+func (c *teeConn) Close() error {
+	c.endMirrors()
+
+	return c.WriteCloser.Close()
+}
+
+// NetConn returns the underlying connection.
+func (c *teeConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+func (c *teeConn) endMirrors() {
+	for _, mirror := range c.mirrors {
+		mirror.end()
+	}
+}
+
+// mirrorConn is the connection served to a mirror handler.
+// It reads the bytes sent by the client to the main handler, and discards the bytes written to it.
+type mirrorConn struct {
+	name       string
+	localAddr  net.Addr
+	remoteAddr net.Addr
+
+	// mu guards the sending of data, so that data is not sent once closed.
+	mu      sync.Mutex
+	ended   bool
+	data    chan []byte
+	pending []byte
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newMirrorConn(name string, conn WriteCloser) *mirrorConn {
+	return &mirrorConn{
+		name:       name,
+		localAddr:  conn.LocalAddr(),
+		remoteAddr: conn.RemoteAddr(),
+		data:       make(chan []byte, mirrorBufferSize),
+		closed:     make(chan struct{}),
+	}
+}
+
+// send queues a copy of the given bytes for the mirror, without ever blocking.
+// A mirror which does not keep up is cut off, not to slow down the main connection.
+func (c *mirrorConn) send(p []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ended {
+		return
+	}
+
+	select {
+	case <-c.closed:
+		return
+	default:
+	}
+
+	chunk := make([]byte, len(p))
+	copy(chunk, p)
+
+	select {
+	case c.data <- chunk:
+	default:
+		log.Debug().Str("mirror", c.name).Msg("Mirror is too slow, closing its connection")
+		c.ended = true
+		close(c.data)
+		_ = c.Close()
+	}
+}
+
+// end signals the mirror that the client has nothing more to send.
+func (c *mirrorConn) end() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ended {
+		return
+	}
+
+	c.ended = true
+	close(c.data)
+}
+
+func (c *mirrorConn) Read(p []byte) (int, error) {
+	if len(c.pending) == 0 {
+		select {
+		case <-c.closed:
+			return 0, net.ErrClosed
+		case chunk, ok := <-c.data:
+			if !ok {
+				return 0, io.EOF
+			}
+			c.pending = chunk
+		}
+	}
+
+	n := copy(p, c.pending)
+	c.pending = c.pending[n:]
+
+	return n, nil
+}
+
+// Write discards the responses of the mirror.
+func (c *mirrorConn) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (c *mirrorConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+
+	return nil
+}
+
+func (c *mirrorConn) CloseWrite() error {
+	return nil
+}
+
+func (c *mirrorConn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+func (c *mirrorConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+func (c *mirrorConn) SetDeadline(time.Time) error {
+	return nil
+}
+
+func (c *mirrorConn) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+func (c *mirrorConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
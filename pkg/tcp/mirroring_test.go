@@ -0,0 +1,154 @@
+package tcp
+
+import (
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirroring(t *testing.T) {
+	mainRead := make(chan string, 1)
+	main := HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+
+		data, err := io.ReadAll(conn)
+		require.NoError(t, err)
+
+		mainRead <- string(data)
+	})
+
+	mirrorRead := make(chan string, 1)
+	mirror := HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+
+		// The responses of the mirror are discarded.
+		_, err := conn.Write([]byte("mirror response"))
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(conn)
+		require.NoError(t, err)
+
+		mirrorRead <- string(data)
+	})
+
+	mirroring := NewMirroring(main)
+	require.NoError(t, mirroring.AddMirror("mirror", mirror, 100))
+
+	server, client := net.Pipe()
+	go mirroring.ServeTCP(pipeConn{server})
+
+	_, err := client.Write([]byte("hello"))
+	require.NoError(t, err)
+	_, err = client.Write([]byte(" world"))
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	assert.Equal(t, "hello world", requireString(t, mainRead))
+	assert.Equal(t, "hello world", requireString(t, mirrorRead))
+}
+
+func TestMirroring_percent(t *testing.T) {
+	var mirrored atomic.Int64
+	served := make(chan struct{}, 10)
+
+	mirroring := NewMirroring(HandlerFunc(func(conn WriteCloser) {}))
+	require.NoError(t, mirroring.AddMirror("mirror", HandlerFunc(func(conn WriteCloser) {
+		mirrored.Add(1)
+		served <- struct{}{}
+	}), 50))
+
+	for i := 0; i < 10; i++ {
+		server, client := net.Pipe()
+		mirroring.ServeTCP(pipeConn{server})
+		require.NoError(t, client.Close())
+	}
+
+	for i := 0; i < 5; i++ {
+		select {
+		case <-served:
+		case <-time.After(time.Second):
+			t.Fatal("Timeout waiting for the mirror")
+		}
+	}
+
+	assert.Equal(t, int64(5), mirrored.Load())
+}
+
+func TestMirroring_AddMirror(t *testing.T) {
+	mirroring := NewMirroring(HandlerFunc(func(conn WriteCloser) {}))
+
+	assert.Error(t, mirroring.AddMirror("mirror", HandlerFunc(func(conn WriteCloser) {}), 101))
+	assert.Error(t, mirroring.AddMirror("mirror", HandlerFunc(func(conn WriteCloser) {}), -1))
+}
+
+func TestMirroring_slowMirror(t *testing.T) {
+	mainRead := make(chan string, 1)
+	main := HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+
+		data, err := io.ReadAll(conn)
+		require.NoError(t, err)
+
+		mainRead <- string(data)
+	})
+
+	release := make(chan struct{})
+	mirrorRead := make(chan error, 1)
+	mirror := HandlerFunc(func(conn WriteCloser) {
+		// The mirror does not read until the main connection is done.
+		<-release
+
+		_, err := io.ReadAll(conn)
+		mirrorRead <- err
+	})
+
+	mirroring := NewMirroring(main)
+	require.NoError(t, mirroring.AddMirror("mirror", mirror, 100))
+
+	server, client := net.Pipe()
+	go mirroring.ServeTCP(pipeConn{server})
+
+	for i := 0; i < 2*mirrorBufferSize; i++ {
+		_, err := client.Write([]byte("a"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, client.Close())
+
+	// The main connection is not slowed down by the mirror.
+	assert.Equal(t, strings.Repeat("a", 2*mirrorBufferSize), requireString(t, mainRead))
+
+	// The mirror has been cut off.
+	close(release)
+	select {
+	case err := <-mirrorRead:
+		assert.ErrorIs(t, err, net.ErrClosed)
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for the mirror")
+	}
+}
+
+func requireString(t *testing.T, c chan string) string {
+	t.Helper()
+
+	select {
+	case s := <-c:
+		return s
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for message")
+		return ""
+	}
+}
+
+type pipeConn struct {
+	net.Conn
+}
+
+func (c pipeConn) CloseWrite() error {
+	return c.Close()
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/pires/go-proxyproto"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/proxyprotocol"
 )
 
 // Proxy forwards a TCP request to a TCP service.
@@ -46,6 +47,8 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	connBackend, err := p.dialBackend()
 	if err != nil {
 		log.Error().Err(err).Msg("Error while dialing backend")
+
+		reportDialFailure(conn, err)
 		return
 	}
 
@@ -55,6 +58,21 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 
 	if p.proxyProtocol != nil && p.proxyProtocol.Version > 0 && p.proxyProtocol.Version < 3 {
 		header := proxyproto.HeaderProxyFromAddrs(byte(p.proxyProtocol.Version), conn.RemoteAddr(), conn.LocalAddr())
+
+		// Only the version 2 of the PROXY protocol supports TLVs.
+		if p.proxyProtocol.Version == 2 {
+			tlvs, err := proxyprotocol.TLVs(conn)
+			if err != nil {
+				log.Error().Err(err).Msg("Error while building TCP proxy protocol TLVs")
+				return
+			}
+
+			if err := header.SetTLVs(tlvs); err != nil {
+				log.Error().Err(err).Msg("Error while setting TCP proxy protocol TLVs")
+				return
+			}
+		}
+
 		if _, err := header.WriteTo(connBackend); err != nil {
 			log.Error().Err(err).Msg("Error while writing TCP proxy protocol headers to backend connection")
 			return
@@ -2,6 +2,7 @@ package tcp
 
 import (
 	"bytes"
+	"crypto/tls"
 	"errors"
 	"io"
 	"net"
@@ -9,9 +10,11 @@ import (
 	"time"
 
 	"github.com/pires/go-proxyproto"
+	"github.com/pires/go-proxyproto/tlvparse"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
 )
 
 func fakeRedis(t *testing.T, listener net.Listener) {
@@ -173,3 +176,128 @@ func TestProxyProtocol(t *testing.T) {
 		})
 	}
 }
+
+func TestProxyProtocol_TLVs(t *testing.T) {
+	backendListener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	headers := make(chan *proxyproto.Header, 1)
+	proxyBackendListener := proxyproto.Listener{
+		Listener: backendListener,
+		ValidateHeader: func(h *proxyproto.Header) error {
+			headers <- h
+			return nil
+		},
+	}
+	defer proxyBackendListener.Close()
+
+	go fakeRedis(t, &proxyBackendListener)
+
+	dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
+
+	proxy, err := NewProxy(proxyBackendListener.Addr().String(), &dynamic.ProxyProtocol{Version: 2}, dialer)
+	require.NoError(t, err)
+
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+
+	proxyListener := &proxyproto.Listener{Listener: listener}
+	defer proxyListener.Close()
+
+	go func() {
+		conn, err := proxyListener.Accept()
+		require.NoError(t, err)
+
+		tlsConn := tls.Server(proxyprotoConn{conn.(*proxyproto.Conn)}, &tls.Config{
+			Certificates: []tls.Certificate{*cert},
+			NextProtos:   []string{"redis"},
+		})
+		proxy.ServeTCP(tlsConn)
+	}()
+
+	conn, err := net.Dial("tcp", proxyListener.Addr().String())
+	require.NoError(t, err)
+
+	header := proxyproto.HeaderProxyFromAddrs(2, conn.LocalAddr(), conn.RemoteAddr())
+	err = header.SetTLVs([]proxyproto.TLV{
+		{Type: 0xE5, Value: []byte("foo")},
+		{Type: proxyproto.PP2_TYPE_AUTHORITY, Value: []byte("upstream.localhost")},
+		{Type: proxyproto.PP2_TYPE_CRC32C, Value: []byte{0, 0, 0, 0}},
+	})
+	require.NoError(t, err)
+
+	_, err = header.WriteTo(conn)
+	require.NoError(t, err)
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         "foo.localhost",
+		NextProtos:         []string{"redis"},
+		InsecureSkipVerify: true,
+	})
+
+	_, err = tlsConn.Write([]byte("ping\n"))
+	require.NoError(t, err)
+
+	err = tlsConn.CloseWrite()
+	require.NoError(t, err)
+
+	var buf []byte
+	buffer := bytes.NewBuffer(buf)
+	_, err = io.Copy(buffer, tlsConn)
+	require.NoError(t, err)
+
+	assert.Equal(t, "PONG", buffer.String())
+
+	var backendHeader *proxyproto.Header
+	select {
+	case backendHeader = <-headers:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for the PROXY protocol header")
+	}
+
+	tlvs, err := backendHeader.TLVs()
+	require.NoError(t, err)
+
+	tlvValues := make(map[proxyproto.PP2Type]string)
+	for _, tlv := range tlvs {
+		tlvValues[tlv.Type] = string(tlv.Value)
+	}
+
+	assert.Equal(t, "foo", tlvValues[0xE5])
+	assert.Equal(t, "foo.localhost", tlvValues[proxyproto.PP2_TYPE_AUTHORITY])
+	assert.Equal(t, "redis", tlvValues[proxyproto.PP2_TYPE_ALPN])
+	assert.NotContains(t, tlvValues, proxyproto.PP2_TYPE_CRC32C)
+
+	var ssl tlvparse.PP2SSL
+	for _, tlv := range tlvs {
+		if tlvparse.IsSSL(tlv) {
+			ssl, err = tlvparse.SSL(tlv)
+			require.NoError(t, err)
+		}
+	}
+
+	assert.True(t, ssl.ClientSSL())
+	assert.False(t, ssl.ClientCertConn())
+	assert.False(t, ssl.Verified())
+
+	version, ok := ssl.SSLVersion()
+	assert.True(t, ok)
+	assert.Equal(t, "TLSv1.3", version)
+}
+
+// proxyprotoConn is a PROXY protocol connection implementing WriteCloser.
+type proxyprotoConn struct {
+	*proxyproto.Conn
+}
+
+func (c proxyprotoConn) CloseWrite() error {
+	tcpConn, ok := c.TCPConn()
+	if !ok {
+		return errors.New("not a TCP connection")
+	}
+
+	return tcpConn.CloseWrite()
+}
@@ -0,0 +1,205 @@
+// Package kvtest provides an in-memory KV store for the tests,
+// standing in for the KV stores shared between Traefik instances.
+package kvtest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/kvtools/valkeyrie/store"
+)
+
+// MemoryStore is an in-memory KV store.
+// It supports the operations used by Traefik, and the other ones return an error.
+type MemoryStore struct {
+	mu       sync.Mutex
+	pairs    map[string]*store.KVPair
+	index    uint64
+	locks    map[string]chan struct{}
+	watchers map[string][]chan []*store.KVPair
+}
+
+// NewMemoryStore creates a new, empty, MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		pairs:    make(map[string]*store.KVPair),
+		locks:    make(map[string]chan struct{}),
+		watchers: make(map[string][]chan []*store.KVPair),
+	}
+}
+
+// Put sets the value of the given key.
+func (m *MemoryStore) Put(_ context.Context, key string, value []byte, _ *store.WriteOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.set(key, value)
+
+	return nil
+}
+
+// Get returns the value of the given key.
+func (m *MemoryStore) Get(_ context.Context, key string, _ *store.ReadOptions) (*store.KVPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pair, ok := m.pairs[key]
+	if !ok {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return pair, nil
+}
+
+// Delete deletes the given key.
+func (m *MemoryStore) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.pairs, key)
+	return nil
+}
+
+// Exists returns whether the given key exists.
+func (m *MemoryStore) Exists(_ context.Context, key string, _ *store.ReadOptions) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.pairs[key]
+	return ok, nil
+}
+
+// Watch is not supported.
+func (m *MemoryStore) Watch(context.Context, string, *store.ReadOptions) (<-chan *store.KVPair, error) {
+	return nil, errors.New("method Watch not supported")
+}
+
+// WatchTree sends the pairs of the given directory, and then sends them again each time a key of the directory is put.
+func (m *MemoryStore) WatchTree(_ context.Context, directory string, _ *store.ReadOptions) (<-chan []*store.KVPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	watcher := make(chan []*store.KVPair, 10)
+	watcher <- m.list(directory)
+	m.watchers[directory] = append(m.watchers[directory], watcher)
+
+	return watcher, nil
+}
+
+// NewLock creates a lock on the given key.
+func (m *MemoryStore) NewLock(_ context.Context, key string, _ *store.LockOptions) (store.Locker, error) {
+	return &memoryLock{store: m, key: key}, nil
+}
+
+// List returns the pairs of the given directory.
+func (m *MemoryStore) List(_ context.Context, directory string, _ *store.ReadOptions) ([]*store.KVPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pairs := m.list(directory)
+	if len(pairs) == 0 {
+		return nil, store.ErrKeyNotFound
+	}
+
+	return pairs, nil
+}
+
+// DeleteTree is not supported.
+func (m *MemoryStore) DeleteTree(context.Context, string) error {
+	return errors.New("method DeleteTree not supported")
+}
+
+// AtomicPut sets the value of the given key, if the key was not modified since the previous pair was read,
+// or if the key does not exist when the previous pair is nil.
+func (m *MemoryStore) AtomicPut(_ context.Context, key string, value []byte, previous *store.KVPair, _ *store.WriteOptions) (bool, *store.KVPair, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.pairs[key]
+	switch {
+	case previous == nil && ok:
+		return false, nil, store.ErrKeyExists
+	case previous != nil && (!ok || current.LastIndex != previous.LastIndex):
+		return false, nil, store.ErrKeyModified
+	}
+
+	return true, m.set(key, value), nil
+}
+
+// AtomicDelete is not supported.
+func (m *MemoryStore) AtomicDelete(context.Context, string, *store.KVPair) (bool, error) {
+	return false, errors.New("method AtomicDelete not supported")
+}
+
+// Close does nothing.
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+// set sets the value of the given key, and notifies the watchers of its directories.
+// It must be called with the lock held.
+func (m *MemoryStore) set(key string, value []byte) *store.KVPair {
+	m.index++
+	pair := &store.KVPair{Key: key, Value: value, LastIndex: m.index}
+	m.pairs[key] = pair
+
+	for prefix, watchers := range m.watchers {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		pairs := m.list(prefix)
+		for _, watcher := range watchers {
+			watcher <- pairs
+		}
+	}
+
+	return pair
+}
+
+// list returns the pairs of the given directory.
+// It must be called with the lock held.
+func (m *MemoryStore) list(directory string) []*store.KVPair {
+	var pairs []*store.KVPair
+	for _, pair := range m.pairs {
+		if strings.HasPrefix(pair.Key, directory+"/") {
+			pairs = append(pairs, pair)
+		}
+	}
+	return pairs
+}
+
+type memoryLock struct {
+	store *MemoryStore
+	key   string
+}
+
+func (l *memoryLock) Lock(ctx context.Context) (<-chan struct{}, error) {
+	for {
+		l.store.mu.Lock()
+		held, ok := l.store.locks[l.key]
+		if !ok {
+			l.store.locks[l.key] = make(chan struct{})
+			l.store.mu.Unlock()
+			return make(chan struct{}), nil
+		}
+		l.store.mu.Unlock()
+
+		select {
+		case <-held:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (l *memoryLock) Unlock(context.Context) error {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+
+	close(l.store.locks[l.key])
+	delete(l.store.locks, l.key)
+	return nil
+}
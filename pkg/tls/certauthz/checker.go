@@ -0,0 +1,224 @@
+package certauthz
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// regexPrefix is the prefix of the rule values which are regular expressions.
+const regexPrefix = "regex:"
+
+// Checker allows to check that client certificates are authorized by a set of rules.
+type Checker struct {
+	allow []rule
+	deny  []rule
+}
+
+// NewChecker builds a new Checker given the rules allowing and denying the client certificates.
+func NewChecker(allow, deny []dynamic.CertificateRule) (*Checker, error) {
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil, errors.New("no allow or deny rules provided")
+	}
+
+	checker := &Checker{}
+
+	for i, config := range allow {
+		r, err := newRule(config)
+		if err != nil {
+			return nil, fmt.Errorf("parsing allow rule %d: %w", i, err)
+		}
+		checker.allow = append(checker.allow, r)
+	}
+
+	for i, config := range deny {
+		r, err := newRule(config)
+		if err != nil {
+			return nil, fmt.Errorf("parsing deny rule %d: %w", i, err)
+		}
+		checker.deny = append(checker.deny, r)
+	}
+
+	return checker, nil
+}
+
+// IsAuthorized checks if the given client certificate is authorized by the rules.
+// A certificate matching a deny rule is refused,
+// and, if there are allow rules, a certificate must match one of them.
+func (c *Checker) IsAuthorized(cert *x509.Certificate) error {
+	if cert == nil {
+		return errors.New("no verified client certificate")
+	}
+
+	values := newCertValues(cert)
+
+	for i, r := range c.deny {
+		if r.match(values) {
+			return fmt.Errorf("certificate %q matched deny rule %d", cert.Subject, i)
+		}
+	}
+
+	if len(c.allow) == 0 {
+		return nil
+	}
+
+	for _, r := range c.allow {
+		if r.match(values) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("certificate %q matched none of the allow rules", cert.Subject)
+}
+
+// certValues are the values of a certificate which can be matched by the rules.
+type certValues struct {
+	commonName         []string
+	organization       []string
+	organizationalUnit []string
+	dnsNames           []string
+	uris               []string
+	emailAddresses     []string
+	fingerprint        []string
+}
+
+func newCertValues(cert *x509.Certificate) certValues {
+	var uris []string
+	for _, uri := range cert.URIs {
+		uris = append(uris, uri.String())
+	}
+
+	sum := sha256.Sum256(cert.Raw)
+
+	return certValues{
+		commonName:         []string{cert.Subject.CommonName},
+		organization:       cert.Subject.Organization,
+		organizationalUnit: cert.Subject.OrganizationalUnit,
+		dnsNames:           cert.DNSNames,
+		uris:               uris,
+		emailAddresses:     cert.EmailAddresses,
+		fingerprint:        []string{hex.EncodeToString(sum[:])},
+	}
+}
+
+// rule is a parsed dynamic.CertificateRule.
+type rule struct {
+	commonName         []matcher
+	organization       []matcher
+	organizationalUnit []matcher
+	dnsNames           []matcher
+	uris               []matcher
+	emailAddresses     []matcher
+	fingerprints       []matcher
+}
+
+func newRule(config dynamic.CertificateRule) (rule, error) {
+	var r rule
+	var err error
+
+	if r.commonName, err = newMatchers(config.CommonName); err != nil {
+		return rule{}, fmt.Errorf("commonName: %w", err)
+	}
+	if r.organization, err = newMatchers(config.Organization); err != nil {
+		return rule{}, fmt.Errorf("organization: %w", err)
+	}
+	if r.organizationalUnit, err = newMatchers(config.OrganizationalUnit); err != nil {
+		return rule{}, fmt.Errorf("organizationalUnit: %w", err)
+	}
+	if r.dnsNames, err = newMatchers(config.DNSNames); err != nil {
+		return rule{}, fmt.Errorf("dnsNames: %w", err)
+	}
+	if r.uris, err = newMatchers(config.URIs); err != nil {
+		return rule{}, fmt.Errorf("uris: %w", err)
+	}
+	if r.emailAddresses, err = newMatchers(config.EmailAddresses); err != nil {
+		return rule{}, fmt.Errorf("emailAddresses: %w", err)
+	}
+
+	for _, fingerprint := range config.Fingerprints {
+		fingerprint = strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+		if _, err := hex.DecodeString(fingerprint); err != nil || len(fingerprint) != 2*sha256.Size {
+			return rule{}, fmt.Errorf("fingerprints: invalid SHA-256 fingerprint %q", fingerprint)
+		}
+		r.fingerprints = append(r.fingerprints, matcher{value: fingerprint})
+	}
+
+	if r.empty() {
+		return rule{}, errors.New("empty rule")
+	}
+
+	return r, nil
+}
+
+func (r rule) empty() bool {
+	return len(r.commonName) == 0 && len(r.organization) == 0 && len(r.organizationalUnit) == 0 &&
+		len(r.dnsNames) == 0 && len(r.uris) == 0 && len(r.emailAddresses) == 0 && len(r.fingerprints) == 0
+}
+
+// match returns whether all the fields of the rule are matched by the given certificate values.
+func (r rule) match(values certValues) bool {
+	return matchField(r.commonName, values.commonName) &&
+		matchField(r.organization, values.organization) &&
+		matchField(r.organizationalUnit, values.organizationalUnit) &&
+		matchField(r.dnsNames, values.dnsNames) &&
+		matchField(r.uris, values.uris) &&
+		matchField(r.emailAddresses, values.emailAddresses) &&
+		matchField(r.fingerprints, values.fingerprint)
+}
+
+// matchField returns whether one of the matchers matches one of the values.
+// A field without matchers is always matched.
+func matchField(matchers []matcher, values []string) bool {
+	if len(matchers) == 0 {
+		return true
+	}
+
+	for _, m := range matchers {
+		for _, value := range values {
+			if m.match(value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matcher matches a value exactly, or against a regular expression.
+type matcher struct {
+	value  string
+	regexp *regexp.Regexp
+}
+
+func newMatchers(values []string) ([]matcher, error) {
+	var matchers []matcher
+	for _, value := range values {
+		expr, ok := strings.CutPrefix(value, regexPrefix)
+		if !ok {
+			matchers = append(matchers, matcher{value: value})
+			continue
+		}
+
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("compiling regex %q: %w", expr, err)
+		}
+		matchers = append(matchers, matcher{regexp: re})
+	}
+
+	return matchers, nil
+}
+
+func (m matcher) match(value string) bool {
+	if m.regexp != nil {
+		return m.regexp.MatchString(value)
+	}
+
+	return m.value == value
+}
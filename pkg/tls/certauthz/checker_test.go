@@ -0,0 +1,234 @@
+package certauthz
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestNewChecker(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		allow       []dynamic.CertificateRule
+		deny        []dynamic.CertificateRule
+		expectedErr bool
+	}{
+		{
+			desc:        "no rules",
+			expectedErr: true,
+		},
+		{
+			desc:  "allow rule",
+			allow: []dynamic.CertificateRule{{CommonName: []string{"foo"}}},
+		},
+		{
+			desc: "deny rule",
+			deny: []dynamic.CertificateRule{{CommonName: []string{"foo"}}},
+		},
+		{
+			desc:        "empty rule",
+			allow:       []dynamic.CertificateRule{{}},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid regex",
+			allow:       []dynamic.CertificateRule{{DNSNames: []string{"regex:("}}},
+			expectedErr: true,
+		},
+		{
+			desc:        "invalid fingerprint",
+			deny:        []dynamic.CertificateRule{{Fingerprints: []string{"foo"}}},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewChecker(test.allow, test.deny)
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChecker_IsAuthorized(t *testing.T) {
+	cert := createCertificate(t)
+
+	sum := sha256.Sum256(cert.Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	var colonFingerprint []string
+	for i := 0; i < len(fingerprint); i += 2 {
+		colonFingerprint = append(colonFingerprint, strings.ToUpper(fingerprint[i:i+2]))
+	}
+
+	testCases := []struct {
+		desc       string
+		allow      []dynamic.CertificateRule
+		deny       []dynamic.CertificateRule
+		cert       *x509.Certificate
+		authorized bool
+	}{
+		{
+			desc:       "no certificate",
+			deny:       []dynamic.CertificateRule{{CommonName: []string{"bar"}}},
+			authorized: false,
+		},
+		{
+			desc:       "common name allowed",
+			allow:      []dynamic.CertificateRule{{CommonName: []string{"bar", "foo"}}},
+			cert:       cert,
+			authorized: true,
+		},
+		{
+			desc:       "common name not allowed",
+			allow:      []dynamic.CertificateRule{{CommonName: []string{"bar"}}},
+			cert:       cert,
+			authorized: false,
+		},
+		{
+			desc:       "organization and organizational unit allowed",
+			allow:      []dynamic.CertificateRule{{Organization: []string{"Acme"}, OrganizationalUnit: []string{"Payments"}}},
+			cert:       cert,
+			authorized: true,
+		},
+		{
+			desc:       "all fields of a rule must match",
+			allow:      []dynamic.CertificateRule{{Organization: []string{"Acme"}, OrganizationalUnit: []string{"Billing"}}},
+			cert:       cert,
+			authorized: false,
+		},
+		{
+			desc: "one of the allow rules must match",
+			allow: []dynamic.CertificateRule{
+				{OrganizationalUnit: []string{"Billing"}},
+				{EmailAddresses: []string{"foo@example.com"}},
+			},
+			cert:       cert,
+			authorized: true,
+		},
+		{
+			desc:       "DNS name regex allowed",
+			allow:      []dynamic.CertificateRule{{DNSNames: []string{`regex:[a-z]+\.example\.com`}}},
+			cert:       cert,
+			authorized: true,
+		},
+		{
+			desc:       "regex is anchored",
+			allow:      []dynamic.CertificateRule{{DNSNames: []string{`regex:example\.com`}}},
+			cert:       cert,
+			authorized: false,
+		},
+		{
+			desc:       "SPIFFE ID allowed",
+			allow:      []dynamic.CertificateRule{{URIs: []string{"spiffe://example.org/ns/default/sa/foo"}}},
+			cert:       cert,
+			authorized: true,
+		},
+		{
+			desc:       "SPIFFE ID regex allowed",
+			allow:      []dynamic.CertificateRule{{URIs: []string{"regex:spiffe://example.org/ns/default/.*"}}},
+			cert:       cert,
+			authorized: true,
+		},
+		{
+			desc:       "fingerprint allowed",
+			allow:      []dynamic.CertificateRule{{Fingerprints: []string{fingerprint}}},
+			cert:       cert,
+			authorized: true,
+		},
+		{
+			desc:       "fingerprint with colons allowed",
+			allow:      []dynamic.CertificateRule{{Fingerprints: []string{strings.Join(colonFingerprint, ":")}}},
+			cert:       cert,
+			authorized: true,
+		},
+		{
+			desc:       "denied",
+			deny:       []dynamic.CertificateRule{{CommonName: []string{"foo"}}},
+			cert:       cert,
+			authorized: false,
+		},
+		{
+			desc:       "not denied",
+			deny:       []dynamic.CertificateRule{{CommonName: []string{"bar"}}},
+			cert:       cert,
+			authorized: true,
+		},
+		{
+			desc:       "deny takes precedence",
+			allow:      []dynamic.CertificateRule{{CommonName: []string{"foo"}}},
+			deny:       []dynamic.CertificateRule{{Fingerprints: []string{fingerprint}}},
+			cert:       cert,
+			authorized: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			checker, err := NewChecker(test.allow, test.deny)
+			require.NoError(t, err)
+
+			err = checker.IsAuthorized(test.cert)
+			if test.authorized {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func createCertificate(t *testing.T) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	spiffeID, err := url.Parse("spiffe://example.org/ns/default/sa/foo")
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject: pkix.Name{
+			CommonName:         "foo",
+			Organization:       []string{"Acme"},
+			OrganizationalUnit: []string{"Payments"},
+		},
+		DNSNames:       []string{"foo.example.com"},
+		URIs:           []*url.URL{spiffeID},
+		EmailAddresses: []string{"foo@example.com"},
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
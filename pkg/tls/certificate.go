@@ -73,6 +73,42 @@ func (c Certificates) GetCertificates() []tls.Certificate {
 	return certs
 }
 
+// ClientFiles returns the file paths among the given root CAs and client certificates.
+func ClientFiles(rootCAs []FileOrContent, certificates Certificates) []string {
+	var files []string
+	addFiles := func(contents ...FileOrContent) {
+		for _, content := range contents {
+			if content.IsPath() {
+				files = append(files, content.String())
+			}
+		}
+	}
+
+	addFiles(rootCAs...)
+	for _, certificate := range certificates {
+		addFiles(certificate.CertFile, certificate.KeyFile)
+	}
+
+	return files
+}
+
+// CheckClientFiles checks that the given root CAs and client certificates can be loaded.
+func CheckClientFiles(rootCAs []FileOrContent, certificates Certificates) error {
+	if len(rootCAs) > 0 {
+		if _, err := BuildCertPool(rootCAs); err != nil {
+			return err
+		}
+	}
+
+	for _, certificate := range certificates {
+		if _, err := certificate.GetCertificate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // FileOrContent hold a file path or content.
 type FileOrContent string
 
@@ -116,6 +152,13 @@ func (c *Certificate) AppendCertificate(certs map[string]map[string]*tls.Certifi
 		return fmt.Errorf("unable to generate TLS certificate : %w", err)
 	}
 
+	appendCertificate(certs, storeName, &tlsCert)
+
+	return nil
+}
+
+// appendCertificate appends a loaded certificate to a certificates map keyed by store name, and by certificate domains.
+func appendCertificate(certs map[string]map[string]*tls.Certificate, storeName string, tlsCert *tls.Certificate) {
 	parsedCert, _ := x509.ParseCertificate(tlsCert.Certificate[0])
 
 	var SANs []string
@@ -156,10 +199,8 @@ func (c *Certificate) AppendCertificate(certs map[string]map[string]*tls.Certifi
 		log.Debug().Msgf("Skipping addition of certificate for domain(s) %q, to TLS Store %s, as it already exists for this store.", certKey, storeName)
 	} else {
 		log.Debug().Msgf("Adding certificate for domain(s) %s", certKey)
-		certs[storeName][certKey] = &tlsCert
+		certs[storeName][certKey] = tlsCert
 	}
-
-	return err
 }
 
 // GetCertificate returns a tls.Certificate matching the configured CertFile and KeyFile.
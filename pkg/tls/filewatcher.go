@@ -0,0 +1,131 @@
+package tls
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/fsnotify.v1"
+)
+
+// fileChangeDelay is the delay during which the file events are coalesced,
+// as a rotation usually writes several files, in several steps.
+const fileChangeDelay = 500 * time.Millisecond
+
+// FileWatcher notifies the changes of the content of a set of files.
+// The parent directories of the files are watched, rather than the files themselves,
+// to detect the files replaced by a rename or by a symbolic link update, as done by Kubernetes for the mounted secrets.
+type FileWatcher struct {
+	watcher *fsnotify.Watcher
+
+	mu     sync.Mutex
+	dirs   map[string]struct{}
+	hashes map[string][sha256.Size]byte
+}
+
+// NewFileWatcher creates a new FileWatcher.
+func NewFileWatcher() (*FileWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating file watcher: %w", err)
+	}
+
+	return &FileWatcher{
+		watcher: watcher,
+		dirs:    make(map[string]struct{}),
+		hashes:  make(map[string][sha256.Size]byte),
+	}, nil
+}
+
+// Watch sets the files to watch, replacing the previous ones.
+func (w *FileWatcher) Watch(files []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	dirs := make(map[string]struct{})
+	hashes := make(map[string][sha256.Size]byte)
+	for _, file := range files {
+		hashes[file] = hashFile(file)
+
+		dirs[filepath.Dir(file)] = struct{}{}
+		if target, err := filepath.EvalSymlinks(file); err == nil {
+			dirs[filepath.Dir(target)] = struct{}{}
+		}
+	}
+
+	for dir := range w.dirs {
+		if _, ok := dirs[dir]; !ok {
+			_ = w.watcher.Remove(dir)
+		}
+	}
+
+	for dir := range dirs {
+		if _, ok := w.dirs[dir]; ok {
+			continue
+		}
+
+		if err := w.watcher.Add(dir); err != nil {
+			log.Error().Err(err).Msgf("Unable to watch the directory %s", dir)
+			delete(dirs, dir)
+		}
+	}
+
+	w.dirs = dirs
+	w.hashes = hashes
+}
+
+// Run calls onChange each time the content of a watched file changes, until the given context is done.
+func (w *FileWatcher) Run(ctx context.Context, onChange func()) {
+	defer func() { _ = w.watcher.Close() }()
+
+	timer := time.NewTimer(fileChangeDelay)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-w.watcher.Events:
+			timer.Reset(fileChangeDelay)
+		case err := <-w.watcher.Errors:
+			log.Error().Err(err).Msg("File watcher error")
+		case <-timer.C:
+			if w.changed() {
+				onChange()
+			}
+		}
+	}
+}
+
+// changed returns whether the content of one of the watched files changed since the last call.
+func (w *FileWatcher) changed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var changed bool
+	for file, hash := range w.hashes {
+		newHash := hashFile(file)
+		if newHash != hash {
+			w.hashes[file] = newHash
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// hashFile returns the hash of the content of the given file, or a zero hash if the file cannot be read.
+func hashFile(file string) [sha256.Size]byte {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return [sha256.Size]byte{}
+	}
+
+	return sha256.Sum256(content)
+}
@@ -0,0 +1,70 @@
+package tls
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileWatcher(t *testing.T) {
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, "tls.crt")
+	require.NoError(t, os.WriteFile(file, []byte("foo"), 0o600))
+
+	// Mimics the Kubernetes secrets volumes, where the files are symbolic links updated on change.
+	dataDir := filepath.Join(dir, "..data-1")
+	require.NoError(t, os.Mkdir(dataDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "tls.key"), []byte("foo"), 0o600))
+	require.NoError(t, os.Symlink(dataDir, filepath.Join(dir, "..data")))
+
+	link := filepath.Join(dir, "tls.key")
+	require.NoError(t, os.Symlink(filepath.Join("..data", "tls.key"), link))
+
+	watcher, err := NewFileWatcher()
+	require.NoError(t, err)
+
+	watcher.Watch([]string{file, link})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	changes := make(chan struct{}, 10)
+	go watcher.Run(ctx, func() { changes <- struct{}{} })
+
+	expectChange := func(t *testing.T, expected bool) {
+		t.Helper()
+
+		select {
+		case <-changes:
+			require.True(t, expected, "unexpected change notification")
+		case <-time.After(3 * fileChangeDelay):
+			require.False(t, expected, "missing change notification")
+		}
+	}
+
+	// Writing the same content is not a change.
+	require.NoError(t, os.WriteFile(file, []byte("foo"), 0o600))
+	expectChange(t, false)
+
+	require.NoError(t, os.WriteFile(file, []byte("bar"), 0o600))
+	expectChange(t, true)
+
+	// Replacing the file by a rename.
+	tmp := filepath.Join(dir, "tls.crt.tmp")
+	require.NoError(t, os.WriteFile(tmp, []byte("baz"), 0o600))
+	require.NoError(t, os.Rename(tmp, file))
+	expectChange(t, true)
+
+	// Updating the symbolic link.
+	newDataDir := filepath.Join(dir, "..data-2")
+	require.NoError(t, os.Mkdir(newDataDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(newDataDir, "tls.key"), []byte("bar"), 0o600))
+	require.NoError(t, os.Symlink(newDataDir, filepath.Join(dir, "..data_tmp")))
+	require.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+	expectChange(t, true)
+}
@@ -0,0 +1,405 @@
+package fingerprint
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // JA3 fingerprints are MD5 hashes by definition.
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/cryptobyte"
+)
+
+const (
+	recordTypeHandshake      = 0x16
+	handshakeTypeClientHello = 0x01
+)
+
+const (
+	extensionServerName          uint16 = 0
+	extensionSupportedGroups     uint16 = 10
+	extensionPointFormats        uint16 = 11
+	extensionSignatureAlgorithms uint16 = 13
+	extensionALPN                uint16 = 16
+	extensionSupportedVersions   uint16 = 43
+)
+
+// Fingerprints are the fingerprints of a TLS ClientHello.
+type Fingerprints struct {
+	// JA3 is the MD5 hash of the JA3 fingerprint, in hexadecimal.
+	JA3 string
+	// JA4 is the JA4 fingerprint.
+	JA4 string
+}
+
+// Match returns whether the given fingerprint is the JA3 or the JA4 fingerprint.
+func (f *Fingerprints) Match(fingerprint string) bool {
+	if f == nil {
+		return false
+	}
+
+	return strings.EqualFold(fingerprint, f.JA3) || strings.EqualFold(fingerprint, f.JA4)
+}
+
+// clientHello holds the ClientHello fields used by the fingerprints.
+type clientHello struct {
+	version             uint16
+	ciphers             []uint16
+	extensions          []uint16
+	supportedGroups     []uint16
+	pointFormats        []uint8
+	signatureAlgorithms []uint16
+	supportedVersions   []uint16
+	alpnProtocols       []string
+	serverName          bool
+}
+
+// Compute computes the fingerprints of the ClientHello contained in the given TLS record.
+func Compute(record []byte) (*Fingerprints, error) {
+	hello, err := parseClientHello(record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Fingerprints{
+		JA3: hashJA3(ja3(hello)),
+		JA4: ja4(hello),
+	}, nil
+}
+
+// parseClientHello parses the ClientHello contained in the given TLS record.
+func parseClientHello(record []byte) (*clientHello, error) {
+	var (
+		input       = cryptobyte.String(record)
+		recordType  uint8
+		fragment    cryptobyte.String
+		messageType uint8
+		message     cryptobyte.String
+	)
+
+	if !input.ReadUint8(&recordType) || recordType != recordTypeHandshake {
+		return nil, errors.New("not a TLS handshake record")
+	}
+
+	if !input.Skip(2) || !input.ReadUint16LengthPrefixed(&fragment) {
+		return nil, errors.New("truncated TLS record")
+	}
+
+	if !fragment.ReadUint8(&messageType) || messageType != handshakeTypeClientHello {
+		return nil, errors.New("not a ClientHello message")
+	}
+
+	if !fragment.ReadUint24LengthPrefixed(&message) {
+		return nil, errors.New("truncated ClientHello message")
+	}
+
+	hello := &clientHello{}
+
+	var sessionID, ciphers, compressionMethods cryptobyte.String
+	if !message.ReadUint16(&hello.version) ||
+		!message.Skip(32) ||
+		!message.ReadUint8LengthPrefixed(&sessionID) ||
+		!message.ReadUint16LengthPrefixed(&ciphers) ||
+		!message.ReadUint8LengthPrefixed(&compressionMethods) {
+		return nil, errors.New("malformed ClientHello message")
+	}
+
+	for !ciphers.Empty() {
+		var cipher uint16
+		if !ciphers.ReadUint16(&cipher) {
+			return nil, errors.New("malformed cipher suites")
+		}
+		hello.ciphers = append(hello.ciphers, cipher)
+	}
+
+	// The extensions are optional.
+	if message.Empty() {
+		return hello, nil
+	}
+
+	var extensions cryptobyte.String
+	if !message.ReadUint16LengthPrefixed(&extensions) {
+		return nil, errors.New("malformed extensions")
+	}
+
+	for !extensions.Empty() {
+		var extension uint16
+		var data cryptobyte.String
+		if !extensions.ReadUint16(&extension) || !extensions.ReadUint16LengthPrefixed(&data) {
+			return nil, errors.New("malformed extension")
+		}
+
+		hello.extensions = append(hello.extensions, extension)
+
+		if err := hello.parseExtension(extension, data); err != nil {
+			return nil, fmt.Errorf("parsing extension %d: %w", extension, err)
+		}
+	}
+
+	return hello, nil
+}
+
+// parseExtension parses the data of the extensions used by the fingerprints.
+func (h *clientHello) parseExtension(extension uint16, data cryptobyte.String) error {
+	switch extension {
+	case extensionServerName:
+		h.serverName = true
+
+	case extensionSupportedGroups:
+		return readUint16List(&data, true, &h.supportedGroups)
+
+	case extensionPointFormats:
+		var formats cryptobyte.String
+		if !data.ReadUint8LengthPrefixed(&formats) {
+			return errors.New("malformed point formats")
+		}
+		h.pointFormats = formats
+
+	case extensionSignatureAlgorithms:
+		return readUint16List(&data, true, &h.signatureAlgorithms)
+
+	case extensionSupportedVersions:
+		return readUint16List(&data, false, &h.supportedVersions)
+
+	case extensionALPN:
+		var protocols cryptobyte.String
+		if !data.ReadUint16LengthPrefixed(&protocols) {
+			return errors.New("malformed ALPN protocols")
+		}
+
+		for !protocols.Empty() {
+			var protocol cryptobyte.String
+			if !protocols.ReadUint8LengthPrefixed(&protocol) {
+				return errors.New("malformed ALPN protocol")
+			}
+			h.alpnProtocols = append(h.alpnProtocols, string(protocol))
+		}
+	}
+
+	return nil
+}
+
+// readUint16List reads a list of uint16 values, prefixed by its length on two bytes, or on one byte if not long.
+func readUint16List(data *cryptobyte.String, long bool, values *[]uint16) error {
+	var list cryptobyte.String
+	if long && !data.ReadUint16LengthPrefixed(&list) || !long && !data.ReadUint8LengthPrefixed(&list) {
+		return errors.New("malformed list")
+	}
+
+	for !list.Empty() {
+		var value uint16
+		if !list.ReadUint16(&value) {
+			return errors.New("malformed list value")
+		}
+		*values = append(*values, value)
+	}
+
+	return nil
+}
+
+// ja3 returns the JA3 fingerprint string, before hashing:
+// the version, ciphers, extensions, supported groups and point formats, as decimal values, without the GREASE ones.
+func ja3(hello *clientHello) string {
+	pointFormats := make([]uint16, 0, len(hello.pointFormats))
+	for _, format := range hello.pointFormats {
+		pointFormats = append(pointFormats, uint16(format))
+	}
+
+	return strings.Join([]string{
+		strconv.Itoa(int(hello.version)),
+		joinDecimal(hello.ciphers),
+		joinDecimal(hello.extensions),
+		joinDecimal(hello.supportedGroups),
+		joinDecimal(pointFormats),
+	}, ",")
+}
+
+func hashJA3(fingerprint string) string {
+	hash := md5.Sum([]byte(fingerprint)) //nolint:gosec // JA3 fingerprints are MD5 hashes by definition.
+	return hex.EncodeToString(hash[:])
+}
+
+// ja4 returns the JA4 fingerprint, made of:
+// the protocol, version, SNI presence, counts of ciphers and extensions and ALPN protocol;
+// the truncated hash of the sorted ciphers;
+// the truncated hash of the sorted extensions (without the SNI and ALPN ones) and of the signature algorithms.
+func ja4(hello *clientHello) string {
+	ciphers := withoutGREASE(hello.ciphers)
+	extensions := withoutGREASE(hello.extensions)
+
+	version := hello.version
+	for _, supportedVersion := range withoutGREASE(hello.supportedVersions) {
+		if supportedVersion > version {
+			version = supportedVersion
+		}
+	}
+
+	sni := "i"
+	if hello.serverName {
+		sni = "d"
+	}
+
+	var alpnProtocol string
+	if len(hello.alpnProtocols) > 0 {
+		alpnProtocol = hello.alpnProtocols[0]
+	}
+
+	prefix := fmt.Sprintf("t%s%s%02d%02d%s", ja4Version(version), sni, min99(len(ciphers)), min99(len(extensions)), ja4ALPN(alpnProtocol))
+
+	var hashedExtensions []uint16
+	for _, extension := range extensions {
+		if extension != extensionServerName && extension != extensionALPN {
+			hashedExtensions = append(hashedExtensions, extension)
+		}
+	}
+
+	return ja4Hashes(prefix, ciphers, hashedExtensions, withoutGREASE(hello.signatureAlgorithms))
+}
+
+// ja4Hashes returns the JA4 fingerprint from its prefix, and the ciphers, extensions and signature algorithms to hash.
+func ja4Hashes(prefix string, ciphers, extensions, signatureAlgorithms []uint16) string {
+	cipherHash := "000000000000"
+	if len(ciphers) > 0 {
+		cipherHash = truncatedHash(joinHex(sorted(ciphers)))
+	}
+
+	extensionHash := "000000000000"
+	if len(extensions) > 0 {
+		extensionsString := joinHex(sorted(extensions))
+		if len(signatureAlgorithms) > 0 {
+			extensionsString += "_" + joinHex(signatureAlgorithms)
+		}
+		extensionHash = truncatedHash(extensionsString)
+	}
+
+	return prefix + "_" + cipherHash + "_" + extensionHash
+}
+
+func ja4Version(version uint16) string {
+	switch version {
+	case tls.VersionTLS13:
+		return "13"
+	case tls.VersionTLS12:
+		return "12"
+	case tls.VersionTLS11:
+		return "11"
+	case tls.VersionTLS10:
+		return "10"
+	case tls.VersionSSL30: //nolint:staticcheck // SSLv3 is fingerprinted, not supported.
+		return "s3"
+	default:
+		return "00"
+	}
+}
+
+// ja4ALPN returns the first and last characters of the given ALPN protocol,
+// or of its hexadecimal representation if they are not alphanumeric.
+func ja4ALPN(protocol string) string {
+	if protocol == "" {
+		return "00"
+	}
+
+	first, last := protocol[0], protocol[len(protocol)-1]
+	if isAlphanumeric(first) && isAlphanumeric(last) {
+		return string([]byte{first, last})
+	}
+
+	encoded := hex.EncodeToString([]byte(protocol))
+	return string([]byte{encoded[0], encoded[len(encoded)-1]})
+}
+
+func isAlphanumeric(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// isGREASE returns whether the given value is a GREASE value (RFC 8701).
+func isGREASE(value uint16) bool {
+	return value&0x0f0f == 0x0a0a && value>>8 == value&0xff
+}
+
+func withoutGREASE(values []uint16) []uint16 {
+	var filtered []uint16
+	for _, value := range values {
+		if !isGREASE(value) {
+			filtered = append(filtered, value)
+		}
+	}
+
+	return filtered
+}
+
+func sorted(values []uint16) []uint16 {
+	sortedValues := append([]uint16(nil), values...)
+	sort.Slice(sortedValues, func(i, j int) bool { return sortedValues[i] < sortedValues[j] })
+
+	return sortedValues
+}
+
+func joinDecimal(values []uint16) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range withoutGREASE(values) {
+		parts = append(parts, strconv.Itoa(int(value)))
+	}
+
+	return strings.Join(parts, "-")
+}
+
+func joinHex(values []uint16) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, fmt.Sprintf("%04x", value))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func truncatedHash(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])[:12]
+}
+
+func min99(count int) int {
+	if count > 99 {
+		return 99
+	}
+
+	return count
+}
+
+type contextKey struct{}
+
+// WithFingerprints returns a copy of the given context, holding the given fingerprints.
+func WithFingerprints(ctx context.Context, fingerprints *Fingerprints) context.Context {
+	return context.WithValue(ctx, contextKey{}, fingerprints)
+}
+
+// FromContext returns the fingerprints held by the given context, or nil.
+func FromContext(ctx context.Context) *Fingerprints {
+	fingerprints, _ := ctx.Value(contextKey{}).(*Fingerprints)
+	return fingerprints
+}
+
+// Conn is a connection providing the fingerprints of its ClientHello.
+type Conn interface {
+	ClientHelloFingerprints() *Fingerprints
+}
+
+// FromConn returns the fingerprints of the ClientHello of the given connection, or nil.
+// The TLS connections are unwrapped to find the fingerprints of their underlying connection.
+func FromConn(conn net.Conn) *Fingerprints {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		conn = tlsConn.NetConn()
+	}
+
+	if fingerprintsConn, ok := conn.(Conn); ok {
+		return fingerprintsConn.ClientHelloFingerprints()
+	}
+
+	return nil
+}
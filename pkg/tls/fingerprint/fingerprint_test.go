@@ -0,0 +1,145 @@
+package fingerprint
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_hashJA3(t *testing.T) {
+	// Example from https://github.com/salesforce/ja3.
+	assert.Equal(t, "ada70206e40642a3e4461f35503241d5", hashJA3("769,47-53-5-10-49161-49162-49171-49172-50-56-19-4,0-10-11,23-24-25,0"))
+}
+
+func Test_ja4Hashes(t *testing.T) {
+	// Example from https://github.com/FoxIO-LLC/ja4.
+	ciphers := []uint16{0x1301, 0x1302, 0x1303, 0xc02b, 0xc02f, 0xc02c, 0xc030, 0xcca9, 0xcca8, 0xc013, 0xc014, 0x009c, 0x009d, 0x002f, 0x0035}
+	extensions := []uint16{0x001b, 0x0005, 0x000a, 0x000b, 0x000d, 0x0012, 0x0015, 0x0017, 0x0023, 0x002b, 0x002d, 0x0033, 0x4469, 0xff01}
+	signatureAlgorithms := []uint16{0x0403, 0x0804, 0x0401, 0x0503, 0x0805, 0x0501, 0x0806, 0x0601}
+
+	assert.Equal(t, "t13d1516h2_8daaf6152771_e5627efa2ab1", ja4Hashes("t13d1516h2", ciphers, extensions, signatureAlgorithms))
+	assert.Equal(t, "t13i0000i0_000000000000_000000000000", ja4Hashes("t13i0000i0", nil, nil, nil))
+}
+
+func Test_ja4ALPN(t *testing.T) {
+	testCases := []struct {
+		protocol string
+		expected string
+	}{
+		{protocol: "", expected: "00"},
+		{protocol: "h2", expected: "h2"},
+		{protocol: "http/1.1", expected: "h1"},
+		{protocol: "a", expected: "aa"},
+		{protocol: "\xab\xcd", expected: "ad"},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, ja4ALPN(test.protocol), test.protocol)
+	}
+}
+
+func Test_isGREASE(t *testing.T) {
+	assert.True(t, isGREASE(0x0a0a))
+	assert.True(t, isGREASE(0xfafa))
+	assert.False(t, isGREASE(0x0a1a))
+	assert.False(t, isGREASE(0x1301))
+}
+
+func TestCompute(t *testing.T) {
+	record := clientHelloRecord(t, &tls.Config{
+		ServerName:   "example.com",
+		NextProtos:   []string{"h2", "http/1.1"},
+		MinVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+	})
+
+	hello, err := parseClientHello(record)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint16(tls.VersionTLS12), hello.version)
+	assert.True(t, hello.serverName)
+	assert.Equal(t, []string{"h2", "http/1.1"}, hello.alpnProtocols)
+	assert.Contains(t, hello.supportedVersions, uint16(tls.VersionTLS13))
+	assert.NotEmpty(t, hello.signatureAlgorithms)
+
+	fingerprints, err := Compute(record)
+	require.NoError(t, err)
+
+	assert.Len(t, fingerprints.JA3, 32)
+	assert.True(t, strings.HasPrefix(fingerprints.JA4, "t13d"), fingerprints.JA4)
+	assert.Equal(t, "h2", fingerprints.JA4[8:10])
+
+	assert.True(t, fingerprints.Match(fingerprints.JA3))
+	assert.True(t, fingerprints.Match(strings.ToUpper(fingerprints.JA4)))
+	assert.False(t, fingerprints.Match("t13d1516h2_8daaf6152771_e5627efa2ab1"))
+
+	// The fingerprints only depend on the ClientHello.
+	other, err := Compute(clientHelloRecord(t, &tls.Config{
+		ServerName:   "example.org",
+		NextProtos:   []string{"h2", "http/1.1"},
+		MinVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, fingerprints, other)
+
+	_, err = Compute([]byte("GET / HTTP/1.1\r\n"))
+	assert.Error(t, err)
+
+	_, err = Compute(record[:len(record)-10])
+	assert.Error(t, err)
+}
+
+func TestFromConn(t *testing.T) {
+	fingerprints := &Fingerprints{JA3: "foo", JA4: "bar"}
+
+	conn := fingerprintsConn{fingerprints: fingerprints}
+	assert.Same(t, fingerprints, FromConn(conn))
+	assert.Same(t, fingerprints, FromConn(tls.Server(conn, &tls.Config{})))
+	assert.Nil(t, FromConn(&net.TCPConn{}))
+
+	ctx := WithFingerprints(context.Background(), fingerprints)
+	assert.Same(t, fingerprints, FromContext(ctx))
+	assert.Nil(t, FromContext(context.Background()))
+}
+
+type fingerprintsConn struct {
+	net.Conn
+
+	fingerprints *Fingerprints
+}
+
+func (c fingerprintsConn) ClientHelloFingerprints() *Fingerprints {
+	return c.fingerprints
+}
+
+// clientHelloRecord returns the TLS record of the ClientHello sent by a client with the given configuration.
+func clientHelloRecord(t *testing.T, config *tls.Config) []byte {
+	t.Helper()
+
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() { _ = serverConn.Close() })
+
+	go func() {
+		_ = tls.Client(clientConn, config).Handshake()
+		_ = clientConn.Close()
+	}()
+
+	header := make([]byte, 5)
+	_, err := io.ReadFull(serverConn, header)
+	require.NoError(t, err)
+
+	record := make([]byte, 5+int(header[3])<<8|int(header[4]))
+	copy(record, header)
+
+	_, err = io.ReadFull(serverConn, record[5:])
+	require.NoError(t, err)
+
+	return record
+}
@@ -0,0 +1,391 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	// defaultOCSPRefreshInterval is the refresh interval of the responses without NextUpdate.
+	defaultOCSPRefreshInterval = time.Hour
+	// ocspRetryInterval is the delay before retrying to fetch a response after a failure.
+	ocspRetryInterval = time.Minute
+	// maxOCSPResponseSize is the maximum size of a response from an OCSP responder.
+	maxOCSPResponseSize = 1024 * 1024
+)
+
+// OCSPConfig contains the OCSP configuration.
+type OCSPConfig struct {
+	ResponderOverrides map[string]string `description:"Defines a map of OCSP responders to replace for querying OCSP servers." json:"responderOverrides,omitempty" toml:"responderOverrides,omitempty" yaml:"responderOverrides,omitempty"`
+	Storage            string            `description:"Defines the file where the OCSP responses are persisted." json:"storage,omitempty" toml:"storage,omitempty" yaml:"storage,omitempty" export:"true"`
+}
+
+// ocspStaple is the OCSP response of a certificate.
+type ocspStaple struct {
+	leaf   *x509.Certificate
+	issuer *x509.Certificate
+
+	raw        []byte
+	nextUpdate time.Time
+	// refreshAt is the time after which the response must be fetched again.
+	refreshAt time.Time
+}
+
+// ocspStapler fetches and refreshes the OCSP responses of the certificates,
+// in order to staple them in the TLS handshakes.
+type ocspStapler struct {
+	client             *http.Client
+	responderOverrides map[string]string
+	storage            string
+
+	trigger chan struct{}
+
+	mu sync.RWMutex
+	// staples are the tracked OCSP staples, by certificate fingerprint.
+	staples map[string]*ocspStaple
+	// certs are the fingerprints of the tracked certificates.
+	certs map[*tls.Certificate]string
+	// persisted are the responses loaded from the storage, by certificate fingerprint,
+	// which are not tracked yet.
+	persisted map[string][]byte
+}
+
+func newOCSPStapler(config OCSPConfig) *ocspStapler {
+	s := &ocspStapler{
+		client:             &http.Client{Timeout: 10 * time.Second},
+		responderOverrides: config.ResponderOverrides,
+		storage:            config.Storage,
+		trigger:            make(chan struct{}, 1),
+		staples:            make(map[string]*ocspStaple),
+		certs:              make(map[*tls.Certificate]string),
+		persisted:          make(map[string][]byte),
+	}
+
+	if s.storage != "" {
+		if err := s.load(); err != nil {
+			log.Error().Err(err).Str("storage", s.storage).Msg("Unable to load OCSP responses")
+		}
+	}
+
+	return s
+}
+
+// Update replaces the tracked certificates.
+// The certificates without issuer in their chain, or without OCSP responder, are ignored.
+func (s *ocspStapler) Update(certs []*tls.Certificate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	staples := make(map[string]*ocspStaple)
+	s.certs = make(map[*tls.Certificate]string)
+
+	for _, cert := range certs {
+		if cert == nil || len(cert.Certificate) < 2 {
+			continue
+		}
+
+		fingerprint := certFingerprint(cert.Certificate[0])
+		if staple, ok := staples[fingerprint]; ok {
+			if staple != nil {
+				s.certs[cert] = fingerprint
+			}
+			continue
+		}
+
+		if staple, ok := s.staples[fingerprint]; ok {
+			staples[fingerprint] = staple
+			s.certs[cert] = fingerprint
+			continue
+		}
+
+		staple, err := s.newStaple(cert)
+		if err != nil {
+			log.Debug().Err(err).Msg("OCSP stapling disabled for certificate")
+			// Marks the certificate as seen.
+			staples[fingerprint] = nil
+			continue
+		}
+
+		staples[fingerprint] = staple
+		s.certs[cert] = fingerprint
+	}
+
+	for fingerprint, staple := range staples {
+		if staple == nil {
+			delete(staples, fingerprint)
+		}
+	}
+
+	// Keeps the responses of the removed certificates, in case they come back.
+	for fingerprint, staple := range s.staples {
+		if _, ok := staples[fingerprint]; !ok && staple.raw != nil {
+			s.persisted[fingerprint] = staple.raw
+		}
+	}
+	s.staples = staples
+
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// newStaple returns the staple of the given certificate, initialized with its persisted response, if any.
+// It must be called with the lock held.
+func (s *ocspStapler) newStaple(cert *tls.Certificate) (*ocspStaple, error) {
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("parsing leaf certificate: %w", err)
+	}
+
+	if len(leaf.OCSPServer) == 0 {
+		return nil, fmt.Errorf("no OCSP server for certificate %s", leaf.Subject)
+	}
+
+	issuer, err := x509.ParseCertificate(cert.Certificate[1])
+	if err != nil {
+		return nil, fmt.Errorf("parsing issuer certificate: %w", err)
+	}
+
+	staple := &ocspStaple{leaf: leaf, issuer: issuer}
+
+	fingerprint := certFingerprint(cert.Certificate[0])
+	if raw, ok := s.persisted[fingerprint]; ok {
+		delete(s.persisted, fingerprint)
+
+		resp, err := ocsp.ParseResponseForCert(raw, leaf, issuer)
+		if err == nil && resp.NextUpdate.After(time.Now()) {
+			staple.setResponse(raw, resp)
+		}
+	}
+
+	return staple, nil
+}
+
+// GetStaple returns the OCSP response to staple for the given certificate, if any.
+func (s *ocspStapler) GetStaple(cert *tls.Certificate) []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fingerprint, ok := s.certs[cert]
+	if !ok {
+		return nil
+	}
+
+	staple := s.staples[fingerprint]
+	if staple.raw == nil || !staple.nextUpdate.IsZero() && staple.nextUpdate.Before(time.Now()) {
+		return nil
+	}
+
+	return staple.raw
+}
+
+// Run refreshes the OCSP responses until the given context is done.
+func (s *ocspStapler) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-s.trigger:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		}
+
+		timer.Reset(time.Until(s.refresh(ctx)))
+	}
+}
+
+// refresh fetches the due OCSP responses, and returns the time of the next refresh.
+func (s *ocspStapler) refresh(ctx context.Context) time.Time {
+	s.mu.RLock()
+	var due []*ocspStaple
+	for _, staple := range s.staples {
+		if !staple.refreshAt.After(time.Now()) {
+			due = append(due, staple)
+		}
+	}
+	s.mu.RUnlock()
+
+	var updated bool
+	for _, staple := range due {
+		raw, resp, err := queryOCSP(ctx, s.client, s.responderOverrides, staple.leaf, staple.issuer)
+
+		s.mu.Lock()
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("Unable to fetch OCSP response for certificate %s", staple.leaf.Subject)
+			staple.refreshAt = time.Now().Add(ocspRetryInterval)
+		} else {
+			if resp.Status == ocsp.Revoked {
+				log.Ctx(ctx).Error().Msgf("Certificate %s has been revoked", staple.leaf.Subject)
+			}
+
+			staple.setResponse(raw, resp)
+			updated = true
+		}
+		s.mu.Unlock()
+	}
+
+	if updated && s.storage != "" {
+		if err := s.save(); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("storage", s.storage).Msg("Unable to persist OCSP responses")
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	next := time.Now().Add(defaultOCSPRefreshInterval)
+	for _, staple := range s.staples {
+		if staple.refreshAt.Before(next) {
+			next = staple.refreshAt
+		}
+	}
+
+	return next
+}
+
+// queryOCSP queries the OCSP responders of the given certificate, until one of them answers with a known status.
+func queryOCSP(ctx context.Context, client *http.Client, responderOverrides map[string]string, leaf, issuer *x509.Certificate) ([]byte, *ocsp.Response, error) {
+	body, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating OCSP request: %w", err)
+	}
+
+	var errs []error
+	for _, responder := range leaf.OCSPServer {
+		if override, ok := responderOverrides[responder]; ok {
+			responder = override
+		}
+
+		raw, resp, err := queryOCSPResponder(ctx, client, responder, body, leaf, issuer)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", responder, err))
+			continue
+		}
+
+		return raw, resp, nil
+	}
+
+	return nil, nil, errors.Join(errs...)
+}
+
+func queryOCSPResponder(ctx context.Context, client *http.Client, responder string, body []byte, leaf, issuer *x509.Certificate) ([]byte, *ocsp.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responder, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/ocsp-request")
+	req.Header.Set("Accept", "application/ocsp-response")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(res.Body, maxOCSPResponseSize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := ocsp.ParseResponseForCert(raw, leaf, issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing OCSP response: %w", err)
+	}
+
+	if resp.Status == ocsp.Unknown {
+		return nil, nil, errors.New("unknown certificate status")
+	}
+
+	return raw, resp, nil
+}
+
+// setResponse sets the OCSP response of the staple, and schedules its refresh halfway through its validity period.
+func (o *ocspStaple) setResponse(raw []byte, resp *ocsp.Response) {
+	o.raw = raw
+	o.nextUpdate = resp.NextUpdate
+
+	if resp.NextUpdate.IsZero() {
+		o.refreshAt = time.Now().Add(defaultOCSPRefreshInterval)
+		return
+	}
+
+	o.refreshAt = resp.ThisUpdate.Add(resp.NextUpdate.Sub(resp.ThisUpdate) / 2)
+}
+
+// load reads the persisted OCSP responses.
+func (s *ocspStapler) load() error {
+	data, err := os.ReadFile(s.storage)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, &s.persisted)
+}
+
+// save persists the valid OCSP responses, including the loaded ones which are not tracked (yet),
+// and forgets the expired ones.
+func (s *ocspStapler) save() error {
+	s.mu.Lock()
+	responses := make(map[string][]byte)
+	for fingerprint, raw := range s.persisted {
+		resp, err := ocsp.ParseResponse(raw, nil)
+		if err != nil || !resp.NextUpdate.After(time.Now()) {
+			delete(s.persisted, fingerprint)
+			continue
+		}
+		responses[fingerprint] = raw
+	}
+	for fingerprint, staple := range s.staples {
+		if staple.raw != nil {
+			responses[fingerprint] = staple.raw
+		}
+	}
+	s.mu.Unlock()
+
+	data, err := json.MarshalIndent(responses, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.storage, data, 0o600)
+}
+
+func certFingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,281 @@
+package tls
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// ocspResponder is a local OCSP responder, answering for the certificates issued by its CA.
+type ocspResponder struct {
+	*httptest.Server
+
+	ca     *x509.Certificate
+	caKey  crypto.Signer
+	caPEM  []byte
+	status int
+	// validity is the duration between ThisUpdate and NextUpdate of the responses.
+	validity time.Duration
+	// revoked are the serial numbers of the revoked certificates, answered regardless of the status.
+	revoked  sync.Map
+	requests atomic.Int64
+	failing  atomic.Bool
+}
+
+func newOCSPResponder(t *testing.T, status int, validity time.Duration) *ocspResponder {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, caKey.Public(), caKey)
+	require.NoError(t, err)
+
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	responder := &ocspResponder{
+		ca:       ca,
+		caKey:    caKey,
+		caPEM:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		status:   status,
+		validity: validity,
+	}
+
+	responder.Server = httptest.NewServer(http.HandlerFunc(responder.ServeHTTP))
+	t.Cleanup(responder.Close)
+
+	return responder
+}
+
+func (r *ocspResponder) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	r.requests.Add(1)
+
+	if r.failing.Load() {
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	ocspReq, err := ocsp.ParseRequest(body)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	status := r.status
+	if _, ok := r.revoked.Load(ocspReq.SerialNumber.String()); ok {
+		status = ocsp.Revoked
+	}
+
+	now := time.Now().Truncate(time.Second)
+	resp, err := ocsp.CreateResponse(r.ca, r.ca, ocsp.Response{
+		Status:       status,
+		SerialNumber: ocspReq.SerialNumber,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(r.validity),
+		RevokedAt:    now,
+	}, r.caKey)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/ocsp-response")
+	_, _ = rw.Write(resp)
+}
+
+// issue returns a certificate, with its chain, issued by the CA of the responder, for the given OCSP server.
+func (r *ocspResponder) issue(t *testing.T, domain, ocspServer string) *CertAndStores {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		OCSPServer:   []string{ocspServer},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, r.ca, key.Public(), r.caKey)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), r.caPEM...)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return &CertAndStores{
+		Certificate: Certificate{
+			CertFile: FileOrContent(certPEM),
+			KeyFile:  FileOrContent(keyPEM),
+		},
+	}
+}
+
+func getCertificate(t *testing.T, manager *Manager, serverName string) *tls.Certificate {
+	t.Helper()
+
+	config, err := manager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+	require.NoError(t, err)
+
+	cert, err := config.GetCertificate(&tls.ClientHelloInfo{ServerName: serverName})
+	require.NoError(t, err)
+	require.NotNil(t, cert)
+
+	return cert
+}
+
+func TestManager_OCSPStapling(t *testing.T) {
+	responder := newOCSPResponder(t, ocsp.Good, time.Hour)
+	storage := filepath.Join(t.TempDir(), "ocsp.json")
+
+	manager := NewManager(&OCSPConfig{
+		ResponderOverrides: map[string]string{"http://ocsp.example.com": responder.URL},
+		Storage:            storage,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go manager.Run(ctx)
+
+	manager.UpdateConfigs(context.Background(), nil, map[string]Options{DefaultTLSConfigName: {}}, []*CertAndStores{
+		responder.issue(t, "foo.localhost", "http://ocsp.example.com"),
+	})
+
+	assert.Eventually(t, func() bool {
+		return getCertificate(t, manager, "foo.localhost").OCSPStaple != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cert := getCertificate(t, manager, "foo.localhost")
+	resp, err := ocsp.ParseResponse(cert.OCSPStaple, responder.ca)
+	require.NoError(t, err)
+	assert.Equal(t, ocsp.Good, resp.Status)
+
+	// The default generated certificate has no OCSP responder.
+	assert.Nil(t, getCertificate(t, manager, "bar.localhost").OCSPStaple)
+
+	// The certificates of the store are not modified.
+	store := manager.GetStore(DefaultTLSStoreName)
+	for _, storeCert := range store.DynamicCerts.Get().(map[string]*tls.Certificate) {
+		assert.Nil(t, storeCert.OCSPStaple)
+	}
+
+	// A restarted manager staples the persisted response, without querying the responder.
+	responder.failing.Store(true)
+	requests := responder.requests.Load()
+
+	restarted := NewManager(&OCSPConfig{
+		ResponderOverrides: map[string]string{"http://ocsp.example.com": responder.URL},
+		Storage:            storage,
+	})
+	restarted.UpdateConfigs(context.Background(), nil, map[string]Options{DefaultTLSConfigName: {}}, manager.certs)
+
+	assert.Equal(t, cert.OCSPStaple, getCertificate(t, restarted, "foo.localhost").OCSPStaple)
+	assert.Equal(t, requests, responder.requests.Load())
+}
+
+func TestManager_OCSPStapling_refresh(t *testing.T) {
+	responder := newOCSPResponder(t, ocsp.Good, 2*time.Second)
+
+	manager := NewManager(&OCSPConfig{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go manager.Run(ctx)
+
+	manager.UpdateConfigs(context.Background(), nil, map[string]Options{DefaultTLSConfigName: {}}, []*CertAndStores{
+		responder.issue(t, "foo.localhost", responder.URL),
+	})
+
+	// The response is refreshed halfway through its validity period.
+	assert.Eventually(t, func() bool {
+		return responder.requests.Load() >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.NotNil(t, getCertificate(t, manager, "foo.localhost").OCSPStaple)
+}
+
+func TestManager_OCSPStapling_disabled(t *testing.T) {
+	responder := newOCSPResponder(t, ocsp.Good, time.Hour)
+
+	manager := NewManager(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go manager.Run(ctx)
+
+	manager.UpdateConfigs(context.Background(), nil, map[string]Options{DefaultTLSConfigName: {}}, []*CertAndStores{
+		responder.issue(t, "foo.localhost", responder.URL),
+	})
+
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Nil(t, getCertificate(t, manager, "foo.localhost").OCSPStaple)
+	assert.Zero(t, responder.requests.Load())
+}
+
+func TestManager_OCSPStapling_unreachableResponder(t *testing.T) {
+	responder := newOCSPResponder(t, ocsp.Good, time.Hour)
+	responder.failing.Store(true)
+
+	manager := NewManager(&OCSPConfig{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go manager.Run(ctx)
+
+	manager.UpdateConfigs(context.Background(), nil, map[string]Options{DefaultTLSConfigName: {}}, []*CertAndStores{
+		responder.issue(t, "foo.localhost", responder.URL),
+	})
+
+	assert.Eventually(t, func() bool {
+		return responder.requests.Load() >= 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Nil(t, getCertificate(t, manager, "foo.localhost").OCSPStaple)
+}
@@ -0,0 +1,432 @@
+package tls
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/safe"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	defaultCRLRefreshInterval = time.Hour
+	// maxCRLSize is the maximum size of a CRL downloaded from a URL.
+	maxCRLSize = 10 * 1024 * 1024
+	// ocspCheckTimeout is the maximum duration of an OCSP check during a handshake.
+	ocspCheckTimeout = 5 * time.Second
+	// maxOCSPCacheSize is the maximum number of cached OCSP statuses.
+	maxOCSPCacheSize = 10000
+)
+
+// Client certificate rejection reasons.
+const (
+	rejectReasonCRLRevoked    = "crl_revoked"
+	rejectReasonOCSPRevoked   = "ocsp_revoked"
+	rejectReasonStatusUnknown = "status_unknown"
+)
+
+// crl is a loaded certificate revocation list.
+type crl struct {
+	list    *x509.RevocationList
+	revoked map[string]struct{}
+
+	mu sync.Mutex
+	// verified caches the result of the signature verification, by issuer fingerprint.
+	verified map[string]bool
+}
+
+func newCRL(list *x509.RevocationList) *crl {
+	revoked := make(map[string]struct{}, len(list.RevokedCertificates))
+	for _, cert := range list.RevokedCertificates {
+		revoked[cert.SerialNumber.String()] = struct{}{}
+	}
+
+	return &crl{list: list, revoked: revoked, verified: make(map[string]bool)}
+}
+
+// issuedBy returns whether the CRL has been issued, and signed, by the given issuer.
+func (c *crl) issuedBy(issuer *x509.Certificate) bool {
+	if !bytes.Equal(c.list.RawIssuer, issuer.RawSubject) {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fingerprint := certFingerprint(issuer.Raw)
+	if verified, ok := c.verified[fingerprint]; ok {
+		return verified
+	}
+
+	verified := c.list.CheckSignatureFrom(issuer) == nil
+	c.verified[fingerprint] = verified
+
+	return verified
+}
+
+type ocspStatus struct {
+	status    int
+	expiresAt time.Time
+}
+
+// ocspQuery is an in-flight OCSP query, whose result is shared by the concurrent handshakes of the same certificate.
+type ocspQuery struct {
+	done   chan struct{}
+	status int
+	err    error
+}
+
+// revocationChecker checks the revocation status of the client certificates, against CRLs and OCSP responders.
+type revocationChecker struct {
+	optionsName string
+	config      ClientAuthRevocation
+	client      *http.Client
+	rejects     gokitmetrics.Counter
+
+	loading  atomic.Bool
+	crlsMu   sync.RWMutex
+	crls     map[string][]*crl // by source
+	loadedAt time.Time
+
+	ocspCacheMu  sync.Mutex
+	ocspCache    map[string]ocspStatus // by certificate fingerprint
+	ocspInflight map[string]*ocspQuery // by certificate fingerprint
+}
+
+func newRevocationChecker(optionsName string, config ClientAuthRevocation, rejects gokitmetrics.Counter) *revocationChecker {
+	c := &revocationChecker{
+		optionsName:  optionsName,
+		config:       config,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		rejects:      rejects,
+		crls:         make(map[string][]*crl),
+		ocspCache:    make(map[string]ocspStatus),
+		ocspInflight: make(map[string]*ocspQuery),
+	}
+
+	// The CRLs are loaded before the checker is used,
+	// so that the client certificates are not rejected as unknown, with the HardFail policy, in the meantime.
+	if c.hasCRLs() {
+		c.loading.Store(true)
+		c.loadCRLs()
+	}
+
+	return c
+}
+
+func (c *revocationChecker) hasCRLs() bool {
+	return len(c.config.CRLFiles) > 0 || len(c.config.CRLURLs) > 0
+}
+
+// loadCRLs (re)loads the CRLs from all the sources.
+// The CRLs of a source which cannot be loaded are kept until the next reload.
+func (c *revocationChecker) loadCRLs() {
+	defer c.loading.Store(false)
+
+	logger := log.With().Str(logs.TLSOptionsName, c.optionsName).Logger()
+
+	crls := make(map[string][]*crl)
+
+	for _, file := range c.config.CRLFiles {
+		source := "file"
+		if file.IsPath() {
+			source = file.String()
+		}
+
+		data, err := file.Read()
+		if err == nil {
+			crls[source], err = parseCRLs(data)
+		}
+		if err != nil {
+			logger.Error().Err(err).Str("source", source).Msg("Unable to load CRL")
+			crls[source] = c.getCRLs(source)
+		}
+	}
+
+	for _, url := range c.config.CRLURLs {
+		data, err := c.download(url)
+		if err == nil {
+			crls[url], err = parseCRLs(data)
+		}
+		if err != nil {
+			logger.Error().Err(err).Str("source", url).Msg("Unable to load CRL")
+			crls[url] = c.getCRLs(url)
+		}
+	}
+
+	c.crlsMu.Lock()
+	c.crls = crls
+	c.loadedAt = time.Now()
+	c.crlsMu.Unlock()
+
+	logger.Debug().Msg("CRLs loaded")
+}
+
+func (c *revocationChecker) getCRLs(source string) []*crl {
+	c.crlsMu.RLock()
+	defer c.crlsMu.RUnlock()
+
+	return c.crls[source]
+}
+
+func (c *revocationChecker) download(url string) ([]byte, error) {
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxCRLSize))
+}
+
+// parseCRLs parses the given PEM encoded CRLs, or DER encoded CRL.
+func parseCRLs(data []byte) ([]*crl, error) {
+	if !bytes.Contains(data, []byte("-----BEGIN")) {
+		list, err := x509.ParseRevocationList(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing CRL: %w", err)
+		}
+		return []*crl{newCRL(list)}, nil
+	}
+
+	var crls []*crl
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "X509 CRL" {
+			continue
+		}
+
+		list, err := x509.ParseRevocationList(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing CRL: %w", err)
+		}
+		crls = append(crls, newCRL(list))
+	}
+
+	if len(crls) == 0 {
+		return nil, errors.New("no CRL found")
+	}
+
+	return crls, nil
+}
+
+// refreshCRLs reloads the CRLs in the background, if the refresh interval has elapsed.
+func (c *revocationChecker) refreshCRLs() {
+	interval := time.Duration(c.config.CRLRefreshInterval)
+	if interval <= 0 {
+		interval = defaultCRLRefreshInterval
+	}
+
+	c.crlsMu.RLock()
+	due := time.Since(c.loadedAt) > interval
+	c.crlsMu.RUnlock()
+
+	if due && c.loading.CompareAndSwap(false, true) {
+		safe.Go(c.loadCRLs)
+	}
+}
+
+// VerifyPeerCertificate rejects the revoked client certificates,
+// and, with the HardFail policy, the ones whose revocation status cannot be determined.
+// Only the revocation status of the client certificate itself is checked, not the one of its intermediate CAs.
+// It is meant to be used as the tls.Config VerifyPeerCertificate callback.
+func (c *revocationChecker) VerifyPeerCertificate(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+	// The client certificate has not been verified, or not been sent.
+	if len(verifiedChains) == 0 || len(verifiedChains[0]) < 2 {
+		return nil
+	}
+
+	leaf, issuer := verifiedChains[0][0], verifiedChains[0][1]
+
+	reason, err := c.check(leaf, issuer)
+	if reason == "" {
+		return nil
+	}
+
+	logger := log.With().Str(logs.TLSOptionsName, c.optionsName).
+		Str("subject", leaf.Subject.String()).
+		Str("serial", leaf.SerialNumber.String()).
+		Str("reason", reason).
+		Logger()
+
+	if reason == rejectReasonStatusUnknown && c.config.FailurePolicy != RevocationHardFail {
+		logger.Debug().Err(err).Msg("Accepting client certificate with unknown revocation status")
+		return nil
+	}
+
+	logger.Warn().Err(err).Msg("Rejecting client certificate")
+
+	if c.rejects != nil {
+		c.rejects.With("tls_options", c.optionsName, "reason", reason).Add(1)
+	}
+
+	if err != nil {
+		return fmt.Errorf("client certificate rejected (%s): %w", reason, err)
+	}
+	return fmt.Errorf("client certificate rejected (%s)", reason)
+}
+
+// check returns the reason to reject the given certificate, if any.
+// The returned error explains why the revocation status is unknown.
+func (c *revocationChecker) check(leaf, issuer *x509.Certificate) (string, error) {
+	var known bool
+	var errs []error
+
+	if c.hasCRLs() {
+		revoked, err := c.checkCRLs(leaf, issuer)
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+		case revoked:
+			return rejectReasonCRLRevoked, nil
+		default:
+			known = true
+		}
+	}
+
+	if c.config.OCSP {
+		revoked, err := c.checkOCSP(leaf, issuer)
+		switch {
+		case err != nil:
+			errs = append(errs, err)
+		case revoked:
+			return rejectReasonOCSPRevoked, nil
+		default:
+			known = true
+		}
+	}
+
+	if known {
+		return "", nil
+	}
+
+	if len(errs) == 0 {
+		errs = append(errs, errors.New("no revocation check configured"))
+	}
+
+	return rejectReasonStatusUnknown, errors.Join(errs...)
+}
+
+func (c *revocationChecker) checkCRLs(leaf, issuer *x509.Certificate) (bool, error) {
+	c.refreshCRLs()
+
+	c.crlsMu.RLock()
+	defer c.crlsMu.RUnlock()
+
+	var found bool
+	for _, crls := range c.crls {
+		for _, cr := range crls {
+			if !cr.issuedBy(issuer) {
+				continue
+			}
+
+			if !cr.list.NextUpdate.IsZero() && cr.list.NextUpdate.Before(time.Now()) {
+				continue
+			}
+
+			if _, ok := cr.revoked[leaf.SerialNumber.String()]; ok {
+				return true, nil
+			}
+			found = true
+		}
+	}
+
+	if !found {
+		return false, fmt.Errorf("no valid CRL for issuer %s", issuer.Subject)
+	}
+
+	return false, nil
+}
+
+func (c *revocationChecker) checkOCSP(leaf, issuer *x509.Certificate) (bool, error) {
+	if len(leaf.OCSPServer) == 0 {
+		return false, errors.New("no OCSP server for certificate")
+	}
+
+	fingerprint := certFingerprint(leaf.Raw)
+
+	c.ocspCacheMu.Lock()
+	cached, ok := c.ocspCache[fingerprint]
+	if ok && cached.expiresAt.Before(time.Now()) {
+		delete(c.ocspCache, fingerprint)
+		ok = false
+	}
+
+	if ok {
+		c.ocspCacheMu.Unlock()
+		return cached.status == ocsp.Revoked, nil
+	}
+
+	// Another handshake is already querying the OCSP responder for this certificate.
+	if query, inflight := c.ocspInflight[fingerprint]; inflight {
+		c.ocspCacheMu.Unlock()
+
+		<-query.done
+		return query.status == ocsp.Revoked, query.err
+	}
+
+	query := &ocspQuery{done: make(chan struct{})}
+	c.ocspInflight[fingerprint] = query
+	c.ocspCacheMu.Unlock()
+
+	defer func() {
+		c.ocspCacheMu.Lock()
+		delete(c.ocspInflight, fingerprint)
+		c.ocspCacheMu.Unlock()
+
+		close(query.done)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), ocspCheckTimeout)
+	defer cancel()
+
+	_, resp, err := queryOCSP(ctx, c.client, nil, leaf, issuer)
+	if err != nil {
+		query.err = err
+		return false, err
+	}
+
+	query.status = resp.Status
+
+	expiresAt := resp.NextUpdate
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(defaultOCSPRefreshInterval)
+	}
+
+	c.ocspCacheMu.Lock()
+	if len(c.ocspCache) >= maxOCSPCacheSize {
+		for fp, status := range c.ocspCache {
+			if status.expiresAt.Before(time.Now()) {
+				delete(c.ocspCache, fp)
+			}
+		}
+		if len(c.ocspCache) >= maxOCSPCacheSize {
+			c.ocspCache = make(map[string]ocspStatus)
+		}
+	}
+	c.ocspCache[fingerprint] = ocspStatus{status: resp.Status, expiresAt: expiresAt}
+	c.ocspCacheMu.Unlock()
+
+	return resp.Status == ocsp.Revoked, nil
+}
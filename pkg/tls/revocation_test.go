@@ -0,0 +1,338 @@
+package tls
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"golang.org/x/crypto/ocsp"
+)
+
+// rejectsCounter counts the rejections by reason.
+type rejectsCounter struct {
+	mu      *sync.Mutex
+	counts  map[string]float64
+	reasons []string
+}
+
+func newRejectsCounter() *rejectsCounter {
+	return &rejectsCounter{mu: &sync.Mutex{}, counts: make(map[string]float64)}
+}
+
+func (c *rejectsCounter) With(labelValues ...string) gokitmetrics.Counter {
+	return &rejectsCounter{mu: c.mu, counts: c.counts, reasons: labelValues}
+}
+
+func (c *rejectsCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := 0; i+1 < len(c.reasons); i += 2 {
+		if c.reasons[i] == "reason" {
+			c.counts[c.reasons[i+1]] += delta
+		}
+	}
+}
+
+func (c *rejectsCounter) get(reason string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.counts[reason]
+}
+
+// createCRL returns a PEM encoded CRL of the responder CA, revoking the given certificates.
+func (r *ocspResponder) createCRL(t *testing.T, nextUpdate time.Time, revoked ...*x509.Certificate) []byte {
+	t.Helper()
+
+	var entries []x509.RevocationListEntry
+	for _, cert := range revoked {
+		entries = append(entries, x509.RevocationListEntry{SerialNumber: cert.SerialNumber, RevocationTime: time.Now()})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(time.Now().UnixNano()),
+		ThisUpdate:                time.Now().Add(-time.Minute),
+		NextUpdate:                nextUpdate,
+		RevokedCertificateEntries: entries,
+	}, r.ca, r.caKey)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+}
+
+// clientChain issues a client certificate and returns its verified chain.
+func (r *ocspResponder) clientChain(t *testing.T, ocspServer string) []*x509.Certificate {
+	t.Helper()
+
+	cert, err := r.issue(t, "client", ocspServer).GetCertificate()
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+
+	return []*x509.Certificate{leaf, r.ca}
+}
+
+func TestRevocationChecker(t *testing.T) {
+	responder := newOCSPResponder(t, ocsp.Good, time.Hour)
+	otherCA := newOCSPResponder(t, ocsp.Good, time.Hour)
+
+	goodChain := responder.clientChain(t, responder.URL)
+	revokedChain := responder.clientChain(t, responder.URL)
+	responder.revoked.Store(revokedChain[0].SerialNumber.String(), struct{}{})
+
+	crl := responder.createCRL(t, time.Now().Add(time.Hour), revokedChain[0])
+	expiredCRL := responder.createCRL(t, time.Now().Add(-time.Second), revokedChain[0])
+	otherCRL := otherCA.createCRL(t, time.Now().Add(time.Hour), revokedChain[0])
+
+	testCases := []struct {
+		desc           string
+		config         ClientAuthRevocation
+		failingOCSP    bool
+		chain          []*x509.Certificate
+		expectedReason string
+	}{
+		{
+			desc:   "CRL, good certificate",
+			config: ClientAuthRevocation{CRLFiles: []FileOrContent{FileOrContent(crl)}},
+			chain:  goodChain,
+		},
+		{
+			desc:   "CRL, good certificate, hard fail",
+			config: ClientAuthRevocation{CRLFiles: []FileOrContent{FileOrContent(crl)}, FailurePolicy: RevocationHardFail},
+			chain:  goodChain,
+		},
+		{
+			desc:           "CRL, revoked certificate",
+			config:         ClientAuthRevocation{CRLFiles: []FileOrContent{FileOrContent(crl)}},
+			chain:          revokedChain,
+			expectedReason: rejectReasonCRLRevoked,
+		},
+		{
+			desc:   "CRL of another issuer, soft fail",
+			config: ClientAuthRevocation{CRLFiles: []FileOrContent{FileOrContent(otherCRL)}},
+			chain:  revokedChain,
+		},
+		{
+			desc:           "CRL of another issuer, hard fail",
+			config:         ClientAuthRevocation{CRLFiles: []FileOrContent{FileOrContent(otherCRL)}, FailurePolicy: RevocationHardFail},
+			chain:          revokedChain,
+			expectedReason: rejectReasonStatusUnknown,
+		},
+		{
+			desc:           "expired CRL, hard fail",
+			config:         ClientAuthRevocation{CRLFiles: []FileOrContent{FileOrContent(expiredCRL)}, FailurePolicy: RevocationHardFail},
+			chain:          goodChain,
+			expectedReason: rejectReasonStatusUnknown,
+		},
+		{
+			desc:   "OCSP, good certificate",
+			config: ClientAuthRevocation{OCSP: true, FailurePolicy: RevocationHardFail},
+			chain:  goodChain,
+		},
+		{
+			desc:           "OCSP, revoked certificate",
+			config:         ClientAuthRevocation{OCSP: true},
+			chain:          revokedChain,
+			expectedReason: rejectReasonOCSPRevoked,
+		},
+		{
+			desc:        "OCSP unavailable, soft fail",
+			config:      ClientAuthRevocation{OCSP: true},
+			failingOCSP: true,
+			chain:       goodChain,
+		},
+		{
+			desc:           "OCSP unavailable, hard fail",
+			config:         ClientAuthRevocation{OCSP: true, FailurePolicy: RevocationHardFail},
+			failingOCSP:    true,
+			chain:          goodChain,
+			expectedReason: rejectReasonStatusUnknown,
+		},
+		{
+			desc:        "OCSP unavailable, good certificate in CRL, hard fail",
+			config:      ClientAuthRevocation{CRLFiles: []FileOrContent{FileOrContent(crl)}, OCSP: true, FailurePolicy: RevocationHardFail},
+			failingOCSP: true,
+			chain:       goodChain,
+		},
+		{
+			desc:           "CRL of another issuer, revoked certificate by OCSP",
+			config:         ClientAuthRevocation{CRLFiles: []FileOrContent{FileOrContent(otherCRL)}, OCSP: true},
+			chain:          revokedChain,
+			expectedReason: rejectReasonOCSPRevoked,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			responder.failing.Store(test.failingOCSP)
+
+			rejects := newRejectsCounter()
+			checker := newRevocationChecker("foo", test.config, rejects)
+
+			err := checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{test.chain})
+			if test.expectedReason == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectedReason)
+			assert.Equal(t, float64(1), rejects.get(test.expectedReason))
+		})
+	}
+}
+
+func TestRevocationChecker_OCSPCache(t *testing.T) {
+	responder := newOCSPResponder(t, ocsp.Good, time.Hour)
+	chain := responder.clientChain(t, responder.URL)
+
+	checker := newRevocationChecker("foo", ClientAuthRevocation{OCSP: true}, nil)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{chain}))
+	}
+
+	assert.Equal(t, int64(1), responder.requests.Load())
+}
+
+func TestRevocationChecker_OCSPConcurrentQueries(t *testing.T) {
+	responder := newOCSPResponder(t, ocsp.Good, time.Hour)
+	chain := responder.clientChain(t, responder.URL)
+
+	checker := newRevocationChecker("foo", ClientAuthRevocation{OCSP: true, FailurePolicy: RevocationHardFail}, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{chain}))
+		}()
+	}
+	wg.Wait()
+
+	// The concurrent handshakes either share the in-flight query, or hit the cache.
+	assert.Equal(t, int64(1), responder.requests.Load())
+}
+
+func TestRevocationChecker_CRLURLReload(t *testing.T) {
+	responder := newOCSPResponder(t, ocsp.Good, time.Hour)
+	chain := responder.clientChain(t, responder.URL)
+
+	var crl atomic.Value
+	crl.Store(responder.createCRL(t, time.Now().Add(time.Hour)))
+
+	crlServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write(crl.Load().([]byte))
+	}))
+	t.Cleanup(crlServer.Close)
+
+	checker := newRevocationChecker("foo", ClientAuthRevocation{
+		CRLURLs:            []string{crlServer.URL},
+		CRLRefreshInterval: ptypes.Duration(50 * time.Millisecond),
+	}, nil)
+
+	require.NoError(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{chain}))
+
+	// The certificate is revoked by the next version of the CRL.
+	crl.Store(responder.createCRL(t, time.Now().Add(time.Hour), chain[0]))
+
+	assert.Eventually(t, func() bool {
+		return checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{chain}) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestManager_ClientCertRevocation(t *testing.T) {
+	responder := newOCSPResponder(t, ocsp.Good, time.Hour)
+
+	goodCert, err := responder.issue(t, "good", responder.URL).GetCertificate()
+	require.NoError(t, err)
+	revokedCert, err := responder.issue(t, "revoked", responder.URL).GetCertificate()
+	require.NoError(t, err)
+
+	revokedLeaf, err := x509.ParseCertificate(revokedCert.Certificate[0])
+	require.NoError(t, err)
+
+	rejects := newRejectsCounter()
+
+	manager := NewManager(nil)
+	manager.SetClientCertRejectsCounter(rejects)
+	manager.UpdateConfigs(context.Background(), nil, map[string]Options{
+		DefaultTLSConfigName: {
+			ClientAuth: ClientAuth{
+				CAFiles:        []FileOrContent{FileOrContent(responder.caPEM)},
+				ClientAuthType: "RequireAndVerifyClientCert",
+				Revocation: &ClientAuthRevocation{
+					CRLFiles: []FileOrContent{FileOrContent(responder.createCRL(t, time.Now().Add(time.Hour), revokedLeaf))},
+				},
+			},
+		},
+	}, nil)
+
+	serverConfig, err := manager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+	require.NoError(t, err)
+
+	handshake := func(clientCert tls.Certificate) error {
+		clientConn, serverConn := net.Pipe()
+		defer func() { _ = clientConn.Close() }()
+
+		serverErr := make(chan error, 1)
+		go func() {
+			defer func() { _ = serverConn.Close() }()
+			serverErr <- tls.Server(serverConn, serverConfig).Handshake()
+		}()
+
+		client := tls.Client(clientConn, &tls.Config{
+			InsecureSkipVerify: true,
+			Certificates:       []tls.Certificate{clientCert},
+			MaxVersion:         tls.VersionTLS12,
+		})
+		_ = client.Handshake()
+
+		return <-serverErr
+	}
+
+	require.NoError(t, handshake(goodCert))
+
+	err = handshake(revokedCert)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), rejectReasonCRLRevoked)
+	assert.Equal(t, float64(1), rejects.get(rejectReasonCRLRevoked))
+}
+
+func TestBuildTLSConfig_revocation(t *testing.T) {
+	responder := newOCSPResponder(t, ocsp.Good, time.Hour)
+
+	_, err := buildTLSConfig(Options{ClientAuth: ClientAuth{
+		Revocation: &ClientAuthRevocation{OCSP: true},
+	}})
+	assert.Error(t, err)
+
+	_, err = buildTLSConfig(Options{ClientAuth: ClientAuth{
+		CAFiles:    []FileOrContent{FileOrContent(responder.caPEM)},
+		Revocation: &ClientAuthRevocation{OCSP: true, FailurePolicy: "foo"},
+	}})
+	assert.Error(t, err)
+
+	_, err = buildTLSConfig(Options{ClientAuth: ClientAuth{
+		CAFiles:    []FileOrContent{FileOrContent(responder.caPEM)},
+		Revocation: &ClientAuthRevocation{OCSP: true, FailurePolicy: RevocationHardFail},
+	}})
+	assert.NoError(t, err)
+}
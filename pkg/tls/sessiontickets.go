@@ -0,0 +1,324 @@
+package tls
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kvtools/valkeyrie/store"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// sessionTicketKeysSyncInterval is the interval between two checks of the rotation of the generated keys,
+	// and between two synchronizations of the shared keys.
+	sessionTicketKeysSyncInterval = time.Minute
+	// sessionTicketKeysSyncTimeout is the timeout of a synchronization of the shared keys.
+	sessionTicketKeysSyncTimeout = 10 * time.Second
+	// maxSessionTicketKeysRotationAttempts is the maximum number of attempts to store a rotated key,
+	// which fails when another instance rotates it at the same time.
+	maxSessionTicketKeysRotationAttempts = 3
+)
+
+// sessionTicketKey is a session ticket key, as stored in the KV store.
+type sessionTicketKey struct {
+	Key       []byte    `json:"key"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SessionTicketsStore is the KV store sharing the session ticket keys between the Traefik instances.
+// The keys of a TLS options are stored under the <RootKey>/sessiontickets/<TLS options name> key.
+type SessionTicketsStore struct {
+	Client  store.Store
+	RootKey string
+}
+
+// sessionTicketKeys provides the session ticket keys of a TLS options,
+// and sets them on the TLS configurations built with these options.
+type sessionTicketKeys struct {
+	name   string
+	config SessionTickets
+	// store is the KV store sharing the keys, nil if the keys are not shared.
+	store *SessionTicketsStore
+
+	mu   sync.Mutex
+	keys []sessionTicketKey
+	// configs are the TLS configurations using the keys, which are updated on rotation.
+	configs []*tls.Config
+}
+
+func newSessionTicketKeys(ctx context.Context, name string, config SessionTickets, kvStore *SessionTicketsStore) (*sessionTicketKeys, error) {
+	k := &sessionTicketKeys{name: name, config: config}
+
+	switch {
+	case len(config.KeyFiles) > 0:
+		if err := k.loadFiles(); err != nil {
+			return nil, err
+		}
+
+	case config.Shared:
+		if kvStore == nil {
+			return nil, errors.New("shared session ticket keys require the session tickets KV storage of the static configuration")
+		}
+		k.store = kvStore
+
+		// The instance generates its own keys until the shared ones are synchronized.
+		k.rotate(time.Now())
+
+		if err := k.sync(ctx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("tlsOptions", name).Msg("Unable to synchronize the shared session ticket keys")
+		}
+
+	default:
+		k.rotate(time.Now())
+	}
+
+	return k, nil
+}
+
+// apply sets the current keys on the given TLS configuration, and keeps it to update its keys on rotation.
+func (k *sessionTicketKeys) apply(tlsConfig *tls.Config) {
+	k.replace(nil, tlsConfig)
+}
+
+// replace sets the current keys on the given TLS configuration,
+// and keeps it to update its keys on rotation instead of the previous one, which can be nil.
+func (k *sessionTicketKeys) replace(previous, tlsConfig *tls.Config) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	replaced := false
+	for i, config := range k.configs {
+		if previous != nil && config == previous {
+			k.configs[i] = tlsConfig
+			replaced = true
+			break
+		}
+	}
+
+	if !replaced {
+		k.configs = append(k.configs, tlsConfig)
+	}
+
+	if ticketKeys := k.ticketKeys(time.Now()); len(ticketKeys) > 0 {
+		tlsConfig.SetSessionTicketKeys(ticketKeys)
+	}
+}
+
+// resetConfigs forgets the TLS configurations using the keys, before they are rebuilt by a configuration update.
+func (k *sessionTicketKeys) resetConfigs() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.configs = nil
+}
+
+// refresh rotates the generated keys when needed, or synchronizes the shared ones.
+func (k *sessionTicketKeys) refresh(ctx context.Context) error {
+	switch {
+	case len(k.config.KeyFiles) > 0:
+		return nil
+	case k.store != nil:
+		return k.sync(ctx)
+	default:
+		k.rotate(time.Now())
+		return nil
+	}
+}
+
+// loadFiles loads the keys from the key files, keeping the previous ones on error.
+func (k *sessionTicketKeys) loadFiles() error {
+	keys := make([]sessionTicketKey, 0, len(k.config.KeyFiles))
+	for _, keyFile := range k.config.KeyFiles {
+		content, err := keyFile.Read()
+		if err != nil {
+			return fmt.Errorf("reading session ticket key: %w", err)
+		}
+
+		key, err := parseSessionTicketKey(content)
+		if err != nil {
+			if keyFile.IsPath() {
+				return fmt.Errorf("invalid session ticket key in %s: %w", keyFile, err)
+			}
+			return fmt.Errorf("invalid session ticket key: %w", err)
+		}
+
+		keys = append(keys, sessionTicketKey{Key: key[:]})
+	}
+
+	k.setKeys(keys)
+
+	return nil
+}
+
+// rotate generates a new key when the current one is older than the rotation interval.
+func (k *sessionTicketKeys) rotate(now time.Time) {
+	k.mu.Lock()
+	keys := k.keys
+	k.mu.Unlock()
+
+	if !k.rotationNeeded(keys, now) {
+		return
+	}
+
+	rotated, err := k.rotated(keys, now)
+	if err != nil {
+		log.Error().Err(err).Str("tlsOptions", k.name).Msg("Unable to rotate the session ticket keys")
+		return
+	}
+
+	k.setKeys(rotated)
+}
+
+// sync synchronizes the keys with the KV store, rotating them when the current one is older than the rotation interval.
+// The instances concurrently rotating the keys rely on an atomic update of the store, which only one of them wins.
+func (k *sessionTicketKeys) sync(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, sessionTicketKeysSyncTimeout)
+	defer cancel()
+
+	key := path.Join(k.store.RootKey, "sessiontickets", k.name)
+
+	for i := 0; i < maxSessionTicketKeysRotationAttempts; i++ {
+		pair, err := k.store.Client.Get(ctx, key, nil)
+		if err != nil && !errors.Is(err, store.ErrKeyNotFound) {
+			return fmt.Errorf("getting session ticket keys: %w", err)
+		}
+
+		var keys []sessionTicketKey
+		if pair != nil {
+			if err := json.Unmarshal(pair.Value, &keys); err != nil {
+				return fmt.Errorf("decoding session ticket keys: %w", err)
+			}
+
+			for _, key := range keys {
+				if len(key.Key) != 32 {
+					return fmt.Errorf("invalid session ticket key length: %d", len(key.Key))
+				}
+			}
+		}
+
+		now := time.Now()
+		if !k.rotationNeeded(keys, now) {
+			k.setKeys(keys)
+			return nil
+		}
+
+		rotated, err := k.rotated(keys, now)
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(rotated)
+		if err != nil {
+			return fmt.Errorf("encoding session ticket keys: %w", err)
+		}
+
+		_, _, err = k.store.Client.AtomicPut(ctx, key, data, pair, nil)
+		if errors.Is(err, store.ErrKeyModified) || errors.Is(err, store.ErrKeyExists) {
+			// Another instance rotated the keys in the meantime.
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("storing session ticket keys: %w", err)
+		}
+
+		k.setKeys(rotated)
+		return nil
+	}
+
+	return errors.New("unable to store the rotated session ticket keys, as they are concurrently modified")
+}
+
+func (k *sessionTicketKeys) rotationNeeded(keys []sessionTicketKey, now time.Time) bool {
+	return len(keys) == 0 || now.Sub(keys[0].CreatedAt) >= time.Duration(k.config.RotationInterval)
+}
+
+// rotated returns the given keys, prepended with a new key, and without the keys exceeding the number of previous keys.
+func (k *sessionTicketKeys) rotated(keys []sessionTicketKey, now time.Time) ([]sessionTicketKey, error) {
+	newKey := sessionTicketKey{Key: make([]byte, 32), CreatedAt: now}
+	if _, err := rand.Read(newKey.Key); err != nil {
+		return nil, fmt.Errorf("generating session ticket key: %w", err)
+	}
+
+	rotated := append([]sessionTicketKey{newKey}, keys...)
+	if len(rotated) > k.config.PreviousKeys+1 {
+		rotated = rotated[:k.config.PreviousKeys+1]
+	}
+
+	return rotated, nil
+}
+
+// setKeys sets the keys, and updates the TLS configurations using them.
+func (k *sessionTicketKeys) setKeys(keys []sessionTicketKey) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys = keys
+
+	ticketKeys := k.ticketKeys(time.Now())
+	if len(ticketKeys) == 0 {
+		return
+	}
+
+	for _, tlsConfig := range k.configs {
+		tlsConfig.SetSessionTicketKeys(ticketKeys)
+	}
+}
+
+// ticketKeys returns the keys in the order expected by the TLS configuration, the first one encrypting the new tickets.
+// A shared key starts encrypting the tickets once all the instances had the time to synchronize it,
+// so that they all can decrypt them.
+// It must be called with the lock held.
+func (k *sessionTicketKeys) ticketKeys(now time.Time) [][32]byte {
+	if len(k.keys) == 0 {
+		return nil
+	}
+
+	current := 0
+	if k.store != nil {
+		for current < len(k.keys)-1 && now.Sub(k.keys[current].CreatedAt) < sessionTicketKeysSyncInterval {
+			current++
+		}
+	}
+
+	ticketKeys := [][32]byte{toTicketKey(k.keys[current].Key)}
+	for i, key := range k.keys {
+		if i != current {
+			ticketKeys = append(ticketKeys, toTicketKey(key.Key))
+		}
+	}
+
+	return ticketKeys
+}
+
+func toTicketKey(key []byte) [32]byte {
+	var ticketKey [32]byte
+	copy(ticketKey[:], key)
+	return ticketKey
+}
+
+// parseSessionTicketKey parses a base64 encoded session ticket key.
+func parseSessionTicketKey(content []byte) ([32]byte, error) {
+	var key [32]byte
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return key, err
+	}
+
+	if len(decoded) != len(key) {
+		return key, fmt.Errorf("the key must be %d bytes long, got %d bytes", len(key), len(decoded))
+	}
+
+	copy(key[:], decoded)
+
+	return key, nil
+}
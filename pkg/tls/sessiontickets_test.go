@@ -0,0 +1,275 @@
+package tls
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/testhelpers/kvtest"
+)
+
+// handshakesCounter counts the handshakes by resumption.
+type handshakesCounter struct {
+	mu      *sync.Mutex
+	counts  map[string]float64
+	resumed string
+}
+
+func newHandshakesCounter() *handshakesCounter {
+	return &handshakesCounter{mu: &sync.Mutex{}, counts: make(map[string]float64)}
+}
+
+func (c *handshakesCounter) With(labelValues ...string) gokitmetrics.Counter {
+	counter := &handshakesCounter{mu: c.mu, counts: c.counts}
+	for i := 0; i+1 < len(labelValues); i += 2 {
+		if labelValues[i] == "resumed" {
+			counter.resumed = labelValues[i+1]
+		}
+	}
+
+	return counter
+}
+
+func (c *handshakesCounter) Add(delta float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counts[c.resumed] += delta
+}
+
+func (c *handshakesCounter) get(resumed string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.counts[resumed]
+}
+
+func TestSessionTicketKeys_rotate(t *testing.T) {
+	config := SessionTickets{RotationInterval: ptypes.Duration(time.Hour), PreviousKeys: 2}
+
+	keys, err := newSessionTicketKeys(context.Background(), "foo", config, nil)
+	require.NoError(t, err)
+	require.Len(t, keys.keys, 1)
+
+	tlsConfig := &tls.Config{}
+	keys.apply(tlsConfig)
+
+	now := time.Now()
+
+	keys.rotate(now.Add(time.Minute))
+	assert.Len(t, keys.keys, 1)
+
+	first := keys.keys[0]
+	for i := 1; i <= 3; i++ {
+		keys.rotate(now.Add(time.Duration(i) * time.Hour))
+	}
+
+	// The current key and the 2 previous ones are kept.
+	require.Len(t, keys.keys, 3)
+	assert.NotContains(t, keys.keys, first)
+	assert.Equal(t, now.Add(3*time.Hour), keys.keys[0].CreatedAt)
+
+	keys.resetConfigs()
+	assert.Empty(t, keys.configs)
+}
+
+func TestSessionTicketKeys_replace(t *testing.T) {
+	config := SessionTickets{RotationInterval: ptypes.Duration(time.Hour)}
+
+	keys, err := newSessionTicketKeys(context.Background(), "foo", config, nil)
+	require.NoError(t, err)
+
+	tlsConfig := &tls.Config{}
+	keys.apply(tlsConfig)
+
+	first := &tls.Config{}
+	keys.replace(nil, first)
+	assert.Equal(t, []*tls.Config{tlsConfig, first}, keys.configs)
+
+	// The reloaded clones replace each other, instead of piling up.
+	second := &tls.Config{}
+	keys.replace(first, second)
+	assert.Equal(t, []*tls.Config{tlsConfig, second}, keys.configs)
+
+	third := &tls.Config{}
+	keys.replace(second, third)
+	require.Len(t, keys.configs, 2)
+	assert.Same(t, third, keys.configs[1])
+}
+
+func TestSessionTicketKeys_loadFiles(t *testing.T) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "ticket.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600))
+
+	config := SessionTickets{KeyFiles: []FileOrContent{FileOrContent(keyFile)}}
+
+	keys, err := newSessionTicketKeys(context.Background(), "foo", config, nil)
+	require.NoError(t, err)
+	require.Len(t, keys.keys, 1)
+	assert.Equal(t, key, keys.keys[0].Key)
+
+	// The previous keys are kept when the files are invalid.
+	require.NoError(t, os.WriteFile(keyFile, []byte("invalid"), 0o600))
+	assert.Error(t, keys.loadFiles())
+	assert.Equal(t, key, keys.keys[0].Key)
+
+	require.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key[:16])), 0o600))
+	assert.ErrorContains(t, keys.loadFiles(), "must be 32 bytes long")
+
+	_, err = newSessionTicketKeys(context.Background(), "foo", SessionTickets{KeyFiles: []FileOrContent{"invalid"}}, nil)
+	assert.Error(t, err)
+}
+
+func TestSessionTicketKeys_sync(t *testing.T) {
+	kvStore := &SessionTicketsStore{Client: kvtest.NewMemoryStore(), RootKey: "traefik"}
+	config := SessionTickets{Shared: true, RotationInterval: ptypes.Duration(time.Hour), PreviousKeys: 1}
+
+	_, err := newSessionTicketKeys(context.Background(), "foo", config, nil)
+	require.Error(t, err)
+
+	keysA, err := newSessionTicketKeys(context.Background(), "foo", config, kvStore)
+	require.NoError(t, err)
+
+	keysB, err := newSessionTicketKeys(context.Background(), "foo", config, kvStore)
+	require.NoError(t, err)
+
+	// The second instance uses the keys generated by the first one.
+	require.Len(t, keysB.keys, 1)
+	assert.Equal(t, rawKeys(keysA.keys), rawKeys(keysB.keys))
+
+	// A rotated key is only used to encrypt the tickets once all the instances synchronized it.
+	previous := keysA.keys[0]
+	keysA.keys[0].CreatedAt = time.Now().Add(-2 * time.Hour)
+	require.NoError(t, kvStore.Client.Put(context.Background(), "traefik/sessiontickets/foo", mustMarshalKeys(t, keysA.keys), nil))
+
+	require.NoError(t, keysA.sync(context.Background()))
+	require.Len(t, keysA.keys, 2)
+	assert.Equal(t, previous.Key, keysA.keys[1].Key)
+
+	ticketKeys := keysA.ticketKeys(time.Now())
+	require.Len(t, ticketKeys, 2)
+	assert.Equal(t, toTicketKey(previous.Key), ticketKeys[0])
+
+	ticketKeys = keysA.ticketKeys(time.Now().Add(sessionTicketKeysSyncInterval))
+	assert.Equal(t, toTicketKey(keysA.keys[0].Key), ticketKeys[0])
+
+	require.NoError(t, keysB.refresh(context.Background()))
+	assert.Equal(t, rawKeys(keysA.keys), rawKeys(keysB.keys))
+}
+
+func TestManager_sessionTickets(t *testing.T) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	configs := map[string]Options{
+		"default": {
+			MinVersion: "VersionTLS13",
+			SessionTickets: &SessionTickets{
+				KeyFiles: []FileOrContent{FileOrContent(base64.StdEncoding.EncodeToString(key))},
+			},
+		},
+	}
+	certs := []*CertAndStores{{Certificate: Certificate{CertFile: localhostCert, KeyFile: localhostKey}}}
+
+	handshakes := newHandshakesCounter()
+
+	newServerConfig := func(configs map[string]Options) *tls.Config {
+		t.Helper()
+
+		manager := NewManager(nil)
+		manager.SetHandshakesCounter(handshakes)
+		manager.UpdateConfigs(context.Background(), nil, configs, certs)
+
+		serverConfig, err := manager.Get("default", "default")
+		require.NoError(t, err)
+
+		return serverConfig
+	}
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AppendCertsFromPEM([]byte(localhostCert))
+
+	clientConfig := &tls.Config{
+		ServerName:         "example.com",
+		RootCAs:            rootCAs,
+		ClientSessionCache: tls.NewLRUClientSessionCache(1),
+	}
+
+	// The sessions established with an instance are resumed with another instance sharing the keys.
+	assert.False(t, handshake(t, newServerConfig(configs), clientConfig).DidResume)
+	assert.True(t, handshake(t, newServerConfig(configs), clientConfig).DidResume)
+
+	assert.Equal(t, float64(1), handshakes.get("false"))
+	assert.Equal(t, float64(1), handshakes.get("true"))
+
+	// The sessions are not resumed with an instance using other keys.
+	assert.False(t, handshake(t, newServerConfig(map[string]Options{"default": {MinVersion: "VersionTLS13"}}), clientConfig).DidResume)
+
+	// The sessions are not resumed when the session tickets are disabled.
+	disabled := map[string]Options{"default": {MinVersion: "VersionTLS13", SessionTickets: &SessionTickets{Disabled: true}}}
+	assert.False(t, handshake(t, newServerConfig(disabled), clientConfig).DidResume)
+	assert.False(t, handshake(t, newServerConfig(disabled), clientConfig).DidResume)
+}
+
+// handshake performs a TLS handshake between the given configurations,
+// and waits for the session ticket sent by the server after the handshake.
+func handshake(t *testing.T, serverConfig, clientConfig *tls.Config) tls.ConnectionState {
+	t.Helper()
+
+	serverConn, clientConn := net.Pipe()
+
+	go func() {
+		server := tls.Server(serverConn, serverConfig)
+		if server.Handshake() == nil {
+			_, _ = server.Write([]byte("x"))
+		}
+		_ = server.Close()
+	}()
+
+	client := tls.Client(clientConn, clientConfig)
+	defer func() { _ = clientConn.Close() }()
+
+	require.NoError(t, client.Handshake())
+
+	_, err := io.ReadFull(client, make([]byte, 1))
+	require.NoError(t, err)
+
+	return client.ConnectionState()
+}
+
+func mustMarshalKeys(t *testing.T, keys []sessionTicketKey) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(keys)
+	require.NoError(t, err)
+
+	return data
+}
+
+func rawKeys(keys []sessionTicketKey) [][]byte {
+	var raw [][]byte
+	for _, key := range keys {
+		raw = append(raw, key.Key)
+	}
+
+	return raw
+}
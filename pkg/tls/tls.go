@@ -1,9 +1,22 @@
 package tls
 
-import "github.com/traefik/traefik/v3/pkg/types"
+import (
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/types"
+)
 
 const certificateHeader = "-----BEGIN CERTIFICATE-----\n"
 
+// Revocation failure policies.
+const (
+	// RevocationSoftFail accepts the client certificates whose revocation status cannot be determined.
+	RevocationSoftFail = "SoftFail"
+	// RevocationHardFail rejects the client certificates whose revocation status cannot be determined.
+	RevocationHardFail = "HardFail"
+)
+
 // +k8s:deepcopy-gen=true
 
 // ClientAuth defines the parameters of the client authentication part of the TLS connection, if any.
@@ -12,6 +25,57 @@ type ClientAuth struct {
 	// ClientAuthType defines the client authentication type to apply.
 	// The available values are: "NoClientCert", "RequestClientCert", "VerifyClientCertIfGiven" and "RequireAndVerifyClientCert".
 	ClientAuthType string `json:"clientAuthType,omitempty" toml:"clientAuthType,omitempty" yaml:"clientAuthType,omitempty" export:"true"`
+	// Revocation defines the revocation checks of the client certificates.
+	Revocation *ClientAuthRevocation `json:"revocation,omitempty" toml:"revocation,omitempty" yaml:"revocation,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ClientAuthRevocation defines the revocation checks of the client certificates.
+type ClientAuthRevocation struct {
+	// CRLFiles are the CRLs (file paths or contents, PEM or DER encoded) of the client certificates issuers.
+	CRLFiles []FileOrContent `json:"crlFiles,omitempty" toml:"crlFiles,omitempty" yaml:"crlFiles,omitempty"`
+	// CRLURLs are the URLs from which the CRLs of the client certificates issuers are downloaded.
+	CRLURLs []string `json:"crlURLs,omitempty" toml:"crlURLs,omitempty" yaml:"crlURLs,omitempty"`
+	// CRLRefreshInterval defines how often the CRLs are reloaded.
+	CRLRefreshInterval ptypes.Duration `json:"crlRefreshInterval,omitempty" toml:"crlRefreshInterval,omitempty" yaml:"crlRefreshInterval,omitempty" export:"true"`
+	// OCSP enables the revocation checks against the OCSP responders of the client certificates.
+	OCSP bool `json:"ocsp,omitempty" toml:"ocsp,omitempty" yaml:"ocsp,omitempty" export:"true"`
+	// FailurePolicy defines whether a client certificate is accepted when its revocation status cannot be determined.
+	// The available values are: "SoftFail" (default) and "HardFail".
+	FailurePolicy string `json:"failurePolicy,omitempty" toml:"failurePolicy,omitempty" yaml:"failurePolicy,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values for a ClientAuthRevocation struct.
+func (r *ClientAuthRevocation) SetDefaults() {
+	r.CRLRefreshInterval = ptypes.Duration(time.Hour)
+	r.FailurePolicy = RevocationSoftFail
+}
+
+// +k8s:deepcopy-gen=true
+
+// SessionTickets defines the keys of the session tickets, used to resume the TLS sessions.
+// By default, each Traefik instance generates and rotates its own keys,
+// so a session can only be resumed with the instance which established it.
+type SessionTickets struct {
+	// Disabled disables the session tickets.
+	Disabled bool `json:"disabled,omitempty" toml:"disabled,omitempty" yaml:"disabled,omitempty" export:"true"`
+	// KeyFiles are the session ticket keys (file paths or contents), each one being 32 random bytes, base64 encoded.
+	// The first key encrypts the new tickets, and all of them decrypt the tickets.
+	KeyFiles []FileOrContent `json:"keyFiles,omitempty" toml:"keyFiles,omitempty" yaml:"keyFiles,omitempty" loggable:"false"`
+	// Shared shares the keys generated by Traefik between the instances, through the session tickets KV storage of the static configuration.
+	Shared bool `json:"shared,omitempty" toml:"shared,omitempty" yaml:"shared,omitempty" export:"true"`
+	// RotationInterval defines how often the keys generated by Traefik are rotated.
+	RotationInterval ptypes.Duration `json:"rotationInterval,omitempty" toml:"rotationInterval,omitempty" yaml:"rotationInterval,omitempty" export:"true"`
+	// PreviousKeys defines how many previous keys generated by Traefik are still accepted to decrypt the tickets.
+	PreviousKeys int `json:"previousKeys,omitempty" toml:"previousKeys,omitempty" yaml:"previousKeys,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values for a SessionTickets struct.
+// The tickets are accepted during a week, as with the keys generated by default.
+func (s *SessionTickets) SetDefaults() {
+	s.RotationInterval = ptypes.Duration(24 * time.Hour)
+	s.PreviousKeys = 6
 }
 
 // +k8s:deepcopy-gen=true
@@ -25,6 +89,8 @@ type Options struct {
 	ClientAuth       ClientAuth `json:"clientAuth,omitempty" toml:"clientAuth,omitempty" yaml:"clientAuth,omitempty"`
 	SniStrict        bool       `json:"sniStrict,omitempty" toml:"sniStrict,omitempty" yaml:"sniStrict,omitempty" export:"true"`
 	ALPNProtocols    []string   `json:"alpnProtocols,omitempty" toml:"alpnProtocols,omitempty" yaml:"alpnProtocols,omitempty" export:"true"`
+	// SessionTickets defines the keys of the session tickets, used to resume the TLS sessions.
+	SessionTickets *SessionTickets `json:"sessionTickets,omitempty" toml:"sessionTickets,omitempty" yaml:"sessionTickets,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 }
 
 // SetDefaults sets the default values for an Options struct.
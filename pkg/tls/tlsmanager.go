@@ -6,13 +6,19 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
+	"reflect"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/traefik/traefik/v3/pkg/tls/generate"
 	"github.com/traefik/traefik/v3/pkg/types"
 )
@@ -49,16 +55,102 @@ type Manager struct {
 	stores       map[string]*CertificateStore
 	configs      map[string]Options
 	certs        []*CertAndStores
+	// ocsp staples the OCSP responses of the certificates, nil if OCSP stapling is disabled.
+	ocsp *ocspStapler
+	// revocationCheckers check the revocation status of the client certificates, by TLS options name.
+	revocationCheckers map[string]*revocationChecker
+	clientCertRejects  gokitmetrics.Counter
+	// files watches the certificate, key and CA files, nil until the manager runs.
+	files *FileWatcher
+	// loadedCertificates are the last successfully loaded certificates, kept while their files are being rotated.
+	loadedCertificates map[Certificate]*tls.Certificate
+	// clientCAs are the client CAs pools loaded from files, by TLS options name.
+	clientCAs map[string]*x509.CertPool
+	// sessionTickets are the session ticket keys, by TLS options name.
+	sessionTickets      map[string]*sessionTicketKeys
+	sessionTicketsStore *SessionTicketsStore
+	handshakes          gokitmetrics.Counter
+	// onDemand obtains the certificates of the server names without certificate, nil if disabled.
+	onDemand OnDemandCertificates
+}
+
+// OnDemandCertificates obtains the certificates of the server names without certificate during the TLS handshakes.
+type OnDemandCertificates interface {
+	// ObtainCertificate returns the certificate of the given server name, obtaining it if needed.
+	ObtainCertificate(ctx context.Context, serverName string) (*tls.Certificate, error)
 }
 
 // NewManager creates a new Manager.
-func NewManager() *Manager {
-	return &Manager{
+// OCSP stapling is enabled when the given OCSP configuration is not nil.
+func NewManager(ocspConfig *OCSPConfig) *Manager {
+	manager := &Manager{
 		stores: map[string]*CertificateStore{},
 		configs: map[string]Options{
 			"default": DefaultTLSOptions,
 		},
 	}
+
+	if ocspConfig != nil {
+		manager.ocsp = newOCSPStapler(*ocspConfig)
+	}
+
+	return manager
+}
+
+// SetClientCertRejectsCounter sets the counter of the client certificates rejected by the revocation checks.
+func (m *Manager) SetClientCertRejectsCounter(counter gokitmetrics.Counter) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.clientCertRejects = counter
+}
+
+// SetSessionTicketsStore sets the KV store sharing the session ticket keys between the Traefik instances.
+func (m *Manager) SetSessionTicketsStore(sessionTicketsStore *SessionTicketsStore) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.sessionTicketsStore = sessionTicketsStore
+}
+
+// SetHandshakesCounter sets the counter of the completed TLS handshakes.
+func (m *Manager) SetHandshakesCounter(counter gokitmetrics.Counter) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.handshakes = counter
+}
+
+// SetOnDemandCertificates sets the provider of the certificates obtained during the TLS handshakes,
+// for the server names without certificate in the default store.
+func (m *Manager) SetOnDemandCertificates(onDemand OnDemandCertificates) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.onDemand = onDemand
+}
+
+// Run reloads the certificates and the client CAs when their files change,
+// refreshes the OCSP responses of the certificates, and rotates the session ticket keys, until the given context is done.
+func (m *Manager) Run(ctx context.Context) {
+	if m.ocsp != nil {
+		safe.Go(func() { m.ocsp.Run(ctx) })
+	}
+
+	safe.Go(func() { m.refreshSessionTickets(ctx) })
+
+	files, err := NewFileWatcher()
+	if err != nil {
+		log.Error().Err(err).Msg("Unable to watch the TLS certificates files, they will not be reloaded on change")
+		return
+	}
+
+	m.lock.Lock()
+	m.files = files
+	m.files.Watch(m.watchedFiles())
+	m.lock.Unlock()
+
+	files.Run(ctx, m.reloadFiles)
 }
 
 // UpdateConfigs updates the TLS* configuration options.
@@ -83,8 +175,46 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 		m.storesConfig[tlsalpn01.ACMETLS1Protocol] = Store{}
 	}
 
+	m.buildStores(ctx)
+	m.buildClientCAs(ctx)
+
+	if m.files != nil {
+		m.files.Watch(m.watchedFiles())
+	}
+
+	m.updateRevocationCheckers()
+	m.updateSessionTickets(ctx)
+}
+
+// reloadFiles rebuilds the certificate stores and the client CAs pools from the changed files.
+func (m *Manager) reloadFiles() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	log.Info().Msg("Reloading the TLS certificates and client CAs from their changed files")
+
+	ctx := context.Background()
+	m.buildStores(ctx)
+	m.buildClientCAs(ctx)
+
+	for name, keys := range m.sessionTickets {
+		if len(keys.config.KeyFiles) == 0 {
+			continue
+		}
+
+		if err := keys.loadFiles(); err != nil {
+			log.Warn().Err(err).Str("tlsOptions", name).Msg("Unable to reload session ticket keys, keeping the previous ones")
+		}
+	}
+}
+
+// buildStores builds the certificate stores from the configuration.
+// It must be called with the lock held.
+func (m *Manager) buildStores(ctx context.Context) {
+	loadedCertificates := make(map[Certificate]*tls.Certificate)
+
 	storesCertificates := make(map[string]map[string]*tls.Certificate)
-	for _, conf := range certs {
+	for _, conf := range m.certs {
 		if len(conf.Stores) == 0 {
 			log.Ctx(ctx).Debug().MsgFunc(func() string {
 				return fmt.Sprintf("No store is defined to add the certificate %s, it will be added to the default store",
@@ -100,10 +230,13 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 				m.storesConfig[store] = Store{}
 			}
 
-			err := conf.Certificate.AppendCertificate(storesCertificates, store)
+			tlsCert, err := m.loadCertificate(conf.Certificate, loadedCertificates)
 			if err != nil {
 				logger.Error().Err(err).Msgf("Unable to append certificate %s to store", conf.Certificate.GetTruncatedCertificateName())
+				continue
 			}
+
+			appendCertificate(storesCertificates, store, tlsCert)
 		}
 	}
 
@@ -125,13 +258,246 @@ func (m *Manager) UpdateConfigs(ctx context.Context, stores map[string]Store, co
 		logger := log.Ctx(ctx).With().Str(logs.TLSStoreName, storeName).Logger()
 		ctxStore := logger.WithContext(ctx)
 
-		certificate, err := getDefaultCertificate(ctxStore, storeConfig, st)
+		certificate, err := m.getDefaultCertificate(ctxStore, storeConfig, st, loadedCertificates)
 		if err != nil {
 			logger.Error().Err(err).Msg("Error while creating certificate store")
 		}
 
 		st.DefaultCertificate = certificate
 	}
+
+	if m.ocsp != nil {
+		m.ocsp.Update(m.allCertificates())
+	}
+
+	m.loadedCertificates = loadedCertificates
+}
+
+// loadCertificate loads the given certificate, and adds it to the loaded certificates.
+// The previously loaded certificate is used when it cannot be loaded, as its files may be in the middle of a rotation.
+// It must be called with the lock held.
+func (m *Manager) loadCertificate(cert Certificate, loaded map[Certificate]*tls.Certificate) (*tls.Certificate, error) {
+	if tlsCert, ok := loaded[cert]; ok {
+		return tlsCert, nil
+	}
+
+	tlsCert, err := cert.GetCertificate()
+	if err != nil {
+		previous, ok := m.loadedCertificates[cert]
+		if !ok {
+			return nil, err
+		}
+
+		log.Warn().Err(err).Msgf("Unable to reload certificate %s, keeping the previous one", cert.GetTruncatedCertificateName())
+		loaded[cert] = previous
+		return previous, nil
+	}
+
+	loaded[cert] = &tlsCert
+	return &tlsCert, nil
+}
+
+// buildClientCAs loads the client CAs pools of the TLS options whose CA files are file paths,
+// keeping the previous pool of a TLS options when its files cannot be loaded.
+// It must be called with the lock held.
+func (m *Manager) buildClientCAs(ctx context.Context) {
+	clientCAs := make(map[string]*x509.CertPool)
+	for name, config := range m.configs {
+		if !hasPath(config.ClientAuth.CAFiles) {
+			continue
+		}
+
+		previous, hasPrevious := m.clientCAs[name]
+
+		pool, err := BuildCertPool(config.ClientAuth.CAFiles)
+		if err != nil {
+			if hasPrevious {
+				log.Ctx(ctx).Warn().Err(err).Str("tlsOptions", name).Msg("Unable to reload client CAs, keeping the previous ones")
+				clientCAs[name] = previous
+			}
+			continue
+		}
+
+		// Keeps the previous pool when it did not change, to avoid rebuilding the TLS configurations using it.
+		if hasPrevious && previous.Equal(pool) {
+			pool = previous
+		}
+
+		clientCAs[name] = pool
+	}
+
+	m.clientCAs = clientCAs
+}
+
+// watchedFiles returns the certificate, key and CA files to watch.
+// It must be called with the lock held.
+func (m *Manager) watchedFiles() []string {
+	var files []string
+	addFiles := func(contents ...FileOrContent) {
+		for _, content := range contents {
+			if content.IsPath() {
+				files = append(files, content.String())
+			}
+		}
+	}
+
+	for _, cert := range m.certs {
+		addFiles(cert.CertFile, cert.KeyFile)
+	}
+
+	for _, store := range m.storesConfig {
+		if store.DefaultCertificate != nil {
+			addFiles(store.DefaultCertificate.CertFile, store.DefaultCertificate.KeyFile)
+		}
+	}
+
+	for _, config := range m.configs {
+		addFiles(config.ClientAuth.CAFiles...)
+
+		if config.SessionTickets != nil {
+			addFiles(config.SessionTickets.KeyFiles...)
+		}
+	}
+
+	return files
+}
+
+// getConfigForClient returns a function providing the TLS configuration with the current client CAs of the given TLS options,
+// when they have been reloaded since the given TLS configuration was built.
+func (m *Manager) getConfigForClient(configName string, tlsConfig *tls.Config) func(*tls.ClientHelloInfo) (*tls.Config, error) {
+	var mu sync.Mutex
+	var reloaded *tls.Config
+
+	return func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		m.lock.RLock()
+		clientCAs, ok := m.clientCAs[configName]
+		m.lock.RUnlock()
+
+		if !ok || clientCAs == tlsConfig.ClientCAs {
+			return nil, nil
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if reloaded == nil || reloaded.ClientCAs != clientCAs {
+			previous := reloaded
+
+			reloaded = tlsConfig.Clone()
+			reloaded.GetConfigForClient = nil
+			reloaded.ClientCAs = clientCAs
+
+			// The clone has its own session ticket keys, which also have to be rotated,
+			// and replaces the clone of the previous client CAs, which is not used anymore.
+			m.lock.RLock()
+			if keys, ok := m.sessionTickets[configName]; ok {
+				keys.replace(previous, reloaded)
+			}
+			m.lock.RUnlock()
+		}
+
+		return reloaded, nil
+	}
+}
+
+// updateRevocationCheckers creates the revocation checkers of the TLS options,
+// keeping the existing ones, with their loaded CRLs, when their configuration did not change.
+// It must be called with the lock held.
+func (m *Manager) updateRevocationCheckers() {
+	checkers := make(map[string]*revocationChecker)
+	for name, config := range m.configs {
+		if config.ClientAuth.Revocation == nil {
+			continue
+		}
+
+		if checker, ok := m.revocationCheckers[name]; ok && reflect.DeepEqual(checker.config, *config.ClientAuth.Revocation) {
+			checkers[name] = checker
+			continue
+		}
+
+		checkers[name] = newRevocationChecker(name, *config.ClientAuth.Revocation, m.clientCertRejects)
+	}
+
+	m.revocationCheckers = checkers
+}
+
+// updateSessionTickets creates the session ticket keys of the TLS options,
+// keeping the existing ones when their configuration did not change.
+// The TLS configurations using the kept keys are forgotten, as they are rebuilt after a configuration update.
+// It must be called with the lock held.
+func (m *Manager) updateSessionTickets(ctx context.Context) {
+	sessionTickets := make(map[string]*sessionTicketKeys)
+	for name, config := range m.configs {
+		if config.SessionTickets == nil || config.SessionTickets.Disabled {
+			continue
+		}
+
+		if keys, ok := m.sessionTickets[name]; ok && reflect.DeepEqual(keys.config, *config.SessionTickets) {
+			keys.resetConfigs()
+			sessionTickets[name] = keys
+			continue
+		}
+
+		keys, err := newSessionTicketKeys(ctx, name, *config.SessionTickets, m.sessionTicketsStore)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("tlsOptions", name).Msg("Unable to load session ticket keys")
+			continue
+		}
+
+		sessionTickets[name] = keys
+	}
+
+	m.sessionTickets = sessionTickets
+}
+
+// refreshSessionTickets periodically rotates the generated session ticket keys, and synchronizes the shared ones,
+// until the given context is done.
+func (m *Manager) refreshSessionTickets(ctx context.Context) {
+	ticker := time.NewTicker(sessionTicketKeysSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.lock.RLock()
+			sessionTickets := make(map[string]*sessionTicketKeys, len(m.sessionTickets))
+			for name, keys := range m.sessionTickets {
+				sessionTickets[name] = keys
+			}
+			m.lock.RUnlock()
+
+			for name, keys := range sessionTickets {
+				if err := keys.refresh(ctx); err != nil {
+					log.Ctx(ctx).Error().Err(err).Str("tlsOptions", name).Msg("Unable to refresh the session ticket keys")
+				}
+			}
+		}
+	}
+}
+
+// allCertificates returns the certificates of all the stores, except the ACME TLS store.
+// It must be called with the lock held.
+func (m *Manager) allCertificates() []*tls.Certificate {
+	var certs []*tls.Certificate
+	for storeName, st := range m.stores {
+		if storeName == tlsalpn01.ACMETLS1Protocol {
+			continue
+		}
+
+		if st.DynamicCerts != nil && st.DynamicCerts.Get() != nil {
+			for _, cert := range st.DynamicCerts.Get().(map[string]*tls.Certificate) {
+				certs = append(certs, cert)
+			}
+		}
+
+		if st.DefaultCertificate != nil {
+			certs = append(certs, st.DefaultCertificate)
+		}
+	}
+
+	return certs
 }
 
 // sanitizeDomains sanitizes the domain definition Main and SANS,
@@ -170,18 +536,46 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 		return nil, fmt.Errorf("building TLS config: %w", err)
 	}
 
-	store := m.getStore(storeName)
-	if store == nil {
+	if checker, ok := m.revocationCheckers[configName]; ok {
+		tlsConfig.VerifyPeerCertificate = checker.VerifyPeerCertificate
+	}
+
+	if clientCAs, ok := m.clientCAs[configName]; ok {
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.GetConfigForClient = m.getConfigForClient(configName, tlsConfig)
+	}
+
+	if config.SessionTickets != nil && !config.SessionTickets.Disabled {
+		keys, ok := m.sessionTickets[configName]
+		if !ok {
+			return nil, fmt.Errorf("no session ticket keys for TLS options: %s", configName)
+		}
+
+		keys.apply(tlsConfig)
+	}
+
+	if m.handshakes != nil {
+		handshakes := m.handshakes
+		tlsConfig.VerifyConnection = func(state tls.ConnectionState) error {
+			handshakes.With("tls_options", configName, "resumed", strconv.FormatBool(state.DidResume)).Add(1)
+			return nil
+		}
+	}
+
+	if m.getStore(storeName) == nil {
 		err = fmt.Errorf("TLS store %s not found", storeName)
 	}
-	acmeTLSStore := m.getStore(tlsalpn01.ACMETLS1Protocol)
-	if acmeTLSStore == nil && err == nil {
+	if m.getStore(tlsalpn01.ACMETLS1Protocol) == nil && err == nil {
 		err = fmt.Errorf("ACME TLS store %s not found", tlsalpn01.ACMETLS1Protocol)
 	}
 
-	tlsConfig.GetCertificate = func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	getCertificate := func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
 		domainToCheck := types.CanonicalDomain(clientHello.ServerName)
 
+		// The stores are looked up for each handshake, as they are rebuilt when the certificate files change.
+		store := m.GetStore(storeName)
+		acmeTLSStore := m.GetStore(tlsalpn01.ACMETLS1Protocol)
+
 		if isACMETLS(clientHello) {
 			certificate := acmeTLSStore.GetBestCertificate(clientHello)
 			if certificate == nil {
@@ -204,6 +598,10 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 			return bestCertificate, nil
 		}
 
+		if certificate := m.obtainOnDemand(clientHello, storeName); certificate != nil {
+			return certificate, nil
+		}
+
 		if sniStrict {
 			log.Debug().Msgf("TLS: strict SNI enabled - No certificate found for domain: %q, closing connection", domainToCheck)
 			// Same comment as above, as in the isACMETLS case.
@@ -221,9 +619,57 @@ func (m *Manager) Get(storeName, configName string) (*tls.Config, error) {
 		return store.DefaultCertificate, nil
 	}
 
+	tlsConfig.GetCertificate = func(clientHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		certificate, err := getCertificate(clientHello)
+		if certificate == nil || m.ocsp == nil {
+			return certificate, err
+		}
+
+		staple := m.ocsp.GetStaple(certificate)
+		if staple == nil {
+			return certificate, err
+		}
+
+		// The certificates are shared between the handshakes, so the OCSP response is stapled to a copy.
+		stapled := *certificate
+		stapled.OCSPStaple = staple
+		return &stapled, err
+	}
+
 	return tlsConfig, err
 }
 
+// obtainOnDemand returns the certificate obtained on demand for the server name of the given ClientHello,
+// or nil if the certificates are not obtained on demand, or the certificate cannot be obtained.
+func (m *Manager) obtainOnDemand(clientHello *tls.ClientHelloInfo, storeName string) *tls.Certificate {
+	m.lock.RLock()
+	onDemand := m.onDemand
+	m.lock.RUnlock()
+
+	// The certificates obtained on demand are added to the default store.
+	if onDemand == nil || storeName != DefaultTLSStoreName {
+		return nil
+	}
+
+	serverName := types.CanonicalDomain(clientHello.ServerName)
+	if serverName == "" || net.ParseIP(serverName) != nil {
+		return nil
+	}
+
+	ctx := clientHello.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	certificate, err := onDemand.ObtainCertificate(ctx, serverName)
+	if err != nil {
+		log.Debug().Err(err).Msgf("TLS: no certificate obtained on demand for domain: %q", serverName)
+		return nil
+	}
+
+	return certificate
+}
+
 // GetServerCertificates returns all certificates from the default store,
 // as well as the user-defined default certificate (if it exists).
 func (m *Manager) GetServerCertificates() []*x509.Certificate {
@@ -281,9 +727,9 @@ func (m *Manager) GetStore(storeName string) *CertificateStore {
 	return m.getStore(storeName)
 }
 
-func getDefaultCertificate(ctx context.Context, tlsStore Store, st *CertificateStore) (*tls.Certificate, error) {
+func (m *Manager) getDefaultCertificate(ctx context.Context, tlsStore Store, st *CertificateStore, loaded map[Certificate]*tls.Certificate) (*tls.Certificate, error) {
 	if tlsStore.DefaultCertificate != nil {
-		cert, err := buildDefaultCertificate(tlsStore.DefaultCertificate)
+		cert, err := m.loadCertificate(*tlsStore.DefaultCertificate, loaded)
 		if err != nil {
 			return nil, err
 		}
@@ -321,19 +767,9 @@ func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 	}
 
 	if len(tlsOption.ClientAuth.CAFiles) > 0 {
-		pool := x509.NewCertPool()
-		for _, caFile := range tlsOption.ClientAuth.CAFiles {
-			data, err := caFile.Read()
-			if err != nil {
-				return nil, err
-			}
-			ok := pool.AppendCertsFromPEM(data)
-			if !ok {
-				if caFile.IsPath() {
-					return nil, fmt.Errorf("invalid certificate(s) in %s", caFile)
-				}
-				return nil, errors.New("invalid certificate(s) content")
-			}
+		pool, err := BuildCertPool(tlsOption.ClientAuth.CAFiles)
+		if err != nil {
+			return nil, err
 		}
 		conf.ClientCAs = pool
 		conf.ClientAuth = tls.RequireAndVerifyClientCert
@@ -362,6 +798,32 @@ func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 		}
 	}
 
+	if revocation := tlsOption.ClientAuth.Revocation; revocation != nil {
+		if conf.ClientCAs == nil {
+			return nil, errors.New("client certificates revocation checks require CAFiles")
+		}
+
+		switch revocation.FailurePolicy {
+		case "", RevocationSoftFail, RevocationHardFail:
+		default:
+			return nil, fmt.Errorf("unknown revocation failure policy %q", revocation.FailurePolicy)
+		}
+	}
+
+	if sessionTickets := tlsOption.SessionTickets; sessionTickets != nil {
+		conf.SessionTicketsDisabled = sessionTickets.Disabled
+
+		switch {
+		case sessionTickets.Disabled:
+		case len(sessionTickets.KeyFiles) > 0 && sessionTickets.Shared:
+			return nil, errors.New("session ticket key files and shared keys cannot be defined at the same time")
+		case len(sessionTickets.KeyFiles) == 0 && sessionTickets.RotationInterval <= 0:
+			return nil, errors.New("the session ticket keys rotation interval must be positive")
+		case sessionTickets.PreviousKeys < 0:
+			return nil, errors.New("the number of previous session ticket keys must not be negative")
+		}
+	}
+
 	// Set the minimum TLS version if set in the config
 	if minConst, exists := MinVersion[tlsOption.MinVersion]; exists {
 		conf.MinVersion = minConst
@@ -403,22 +865,35 @@ func buildTLSConfig(tlsOption Options) (*tls.Config, error) {
 	return conf, nil
 }
 
-func buildDefaultCertificate(defaultCertificate *Certificate) (*tls.Certificate, error) {
-	certFile, err := defaultCertificate.CertFile.Read()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get cert file content: %w", err)
+// BuildCertPool builds a certificates pool from the given CA files or contents.
+func BuildCertPool(caFiles []FileOrContent) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, caFile := range caFiles {
+		data, err := caFile.Read()
+		if err != nil {
+			return nil, err
+		}
+		ok := pool.AppendCertsFromPEM(data)
+		if !ok {
+			if caFile.IsPath() {
+				return nil, fmt.Errorf("invalid certificate(s) in %s", caFile)
+			}
+			return nil, errors.New("invalid certificate(s) content")
+		}
 	}
 
-	keyFile, err := defaultCertificate.KeyFile.Read()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get key file content: %w", err)
-	}
+	return pool, nil
+}
 
-	cert, err := tls.X509KeyPair(certFile, keyFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load X509 key pair: %w", err)
+// hasPath returns whether one of the given files or contents is a file path.
+func hasPath(contents []FileOrContent) bool {
+	for _, content := range contents {
+		if content.IsPath() {
+			return true
+		}
 	}
-	return &cert, nil
+
+	return false
 }
 
 func isACMETLS(clientHello *tls.ClientHelloInfo) bool {
@@ -5,10 +5,15 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
 )
 
 // LocalhostCert is a PEM-encoded TLS cert with SAN IPs
@@ -76,7 +81,7 @@ func TestTLSInStore(t *testing.T) {
 		},
 	}}
 
-	tlsManager := NewManager()
+	tlsManager := NewManager(nil)
 	tlsManager.UpdateConfigs(context.Background(), nil, nil, dynamicConfigs)
 
 	certs := tlsManager.GetStore("default").DynamicCerts.Get().(map[string]*tls.Certificate)
@@ -93,7 +98,7 @@ func TestTLSInvalidStore(t *testing.T) {
 		},
 	}}
 
-	tlsManager := NewManager()
+	tlsManager := NewManager(nil)
 	tlsManager.UpdateConfigs(context.Background(),
 		map[string]Store{
 			"default": {
@@ -157,7 +162,7 @@ func TestManager_Get(t *testing.T) {
 		},
 	}
 
-	tlsManager := NewManager()
+	tlsManager := NewManager(nil)
 	tlsManager.UpdateConfigs(context.Background(), nil, tlsConfigs, dynamicConfigs)
 
 	for _, test := range testCases {
@@ -297,7 +302,7 @@ func TestClientAuth(t *testing.T) {
 		},
 	}
 
-	tlsManager := NewManager()
+	tlsManager := NewManager(nil)
 	tlsManager.UpdateConfigs(context.Background(), nil, tlsConfigs, nil)
 
 	for _, test := range testCases {
@@ -326,7 +331,7 @@ func TestClientAuth(t *testing.T) {
 }
 
 func TestManager_Get_DefaultValues(t *testing.T) {
-	tlsManager := NewManager()
+	tlsManager := NewManager(nil)
 
 	// Ensures we won't break things for Traefik users when updating Go
 	config, _ := tlsManager.Get("default", "default")
@@ -352,3 +357,135 @@ func TestManager_Get_DefaultValues(t *testing.T) {
 		tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
 	})
 }
+
+func TestManager_reloadFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	writeKeyPair := func(t *testing.T, domain string) []byte {
+		t.Helper()
+
+		certPEM, keyPEM, err := generate.KeyPair(domain, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+		require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+
+		return certPEM
+	}
+
+	caPEM := writeKeyPair(t, "foo.localhost")
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0o600))
+
+	tlsManager := NewManager(nil)
+	tlsManager.UpdateConfigs(context.Background(), nil,
+		map[string]Options{
+			"default": {ClientAuth: ClientAuth{CAFiles: []FileOrContent{FileOrContent(caFile)}}},
+		},
+		[]*CertAndStores{{
+			Certificate: Certificate{CertFile: FileOrContent(certFile), KeyFile: FileOrContent(keyFile)},
+		}},
+	)
+
+	tlsConfig, err := tlsManager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go tlsManager.Run(ctx)
+
+	hasDomain := func(domain string) func() bool {
+		return func() bool {
+			return tlsManager.GetStore(DefaultTLSStoreName).GetBestCertificate(&tls.ClientHelloInfo{ServerName: domain}) != nil
+		}
+	}
+
+	require.True(t, hasDomain("foo.localhost")())
+
+	// Waits for the watcher to be started.
+	require.Eventually(t, func() bool {
+		tlsManager.lock.RLock()
+		defer tlsManager.lock.RUnlock()
+
+		return tlsManager.files != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The certificate is reloaded on change, without updating the configuration.
+	caPEM = writeKeyPair(t, "bar.localhost")
+	require.Eventually(t, hasDomain("bar.localhost"), 5*time.Second, 50*time.Millisecond)
+
+	cert, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "bar.localhost"})
+	require.NoError(t, err)
+	assert.Equal(t, tlsManager.GetStore(DefaultTLSStoreName).GetBestCertificate(&tls.ClientHelloInfo{ServerName: "bar.localhost"}), cert)
+
+	// The previous certificate is kept when the new one cannot be loaded.
+	require.NoError(t, os.WriteFile(certFile, []byte("invalid"), 0o600))
+	time.Sleep(3 * fileChangeDelay)
+	assert.True(t, hasDomain("bar.localhost")())
+
+	// The client CAs are reloaded on change, for the existing TLS configurations.
+	conf, err := tlsConfig.GetConfigForClient(&tls.ClientHelloInfo{})
+	require.NoError(t, err)
+	assert.Nil(t, conf)
+
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0o600))
+	require.Eventually(t, func() bool {
+		conf, err = tlsConfig.GetConfigForClient(&tls.ClientHelloInfo{})
+		return err == nil && conf != nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	expectedCAs, err := BuildCertPool([]FileOrContent{FileOrContent(caPEM)})
+	require.NoError(t, err)
+	assert.True(t, expectedCAs.Equal(conf.ClientCAs))
+	assert.False(t, expectedCAs.Equal(tlsConfig.ClientCAs))
+}
+
+type onDemandCertificatesFunc func(ctx context.Context, serverName string) (*tls.Certificate, error)
+
+func (f onDemandCertificatesFunc) ObtainCertificate(ctx context.Context, serverName string) (*tls.Certificate, error) {
+	return f(ctx, serverName)
+}
+
+func TestManager_Get_onDemand(t *testing.T) {
+	onDemandCert := &tls.Certificate{}
+
+	var asked []string
+	tlsManager := NewManager(nil)
+	tlsManager.SetOnDemandCertificates(onDemandCertificatesFunc(func(_ context.Context, serverName string) (*tls.Certificate, error) {
+		asked = append(asked, serverName)
+		if serverName == "denied.com" {
+			return nil, errors.New("not allowed")
+		}
+		return onDemandCert, nil
+	}))
+	tlsManager.UpdateConfigs(context.Background(), nil, map[string]Options{"default": DefaultTLSOptions}, []*CertAndStores{{
+		Certificate: Certificate{CertFile: localhostCert, KeyFile: localhostKey},
+	}})
+
+	tlsConfig, err := tlsManager.Get(DefaultTLSStoreName, DefaultTLSConfigName)
+	require.NoError(t, err)
+
+	// The existing certificates are served without obtaining them.
+	cert, err := tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "example.com"})
+	require.NoError(t, err)
+	assert.NotSame(t, onDemandCert, cert)
+
+	cert, err = tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "Customer.com"})
+	require.NoError(t, err)
+	assert.Same(t, onDemandCert, cert)
+
+	// The default certificate is served when the certificate cannot be obtained.
+	cert, err = tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "denied.com"})
+	require.NoError(t, err)
+	assert.Same(t, tlsManager.GetStore(DefaultTLSStoreName).DefaultCertificate, cert)
+
+	// The certificates are not obtained for IP addresses.
+	_, err = tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "10.0.0.1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"customer.com", "denied.com"}, asked)
+}
@@ -63,6 +63,11 @@ func (in *ClientAuth) DeepCopyInto(out *ClientAuth) {
 		*out = make([]FileOrContent, len(*in))
 		copy(*out, *in)
 	}
+	if in.Revocation != nil {
+		in, out := &in.Revocation, &out.Revocation
+		*out = new(ClientAuthRevocation)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -76,6 +81,32 @@ func (in *ClientAuth) DeepCopy() *ClientAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientAuthRevocation) DeepCopyInto(out *ClientAuthRevocation) {
+	*out = *in
+	if in.CRLFiles != nil {
+		in, out := &in.CRLFiles, &out.CRLFiles
+		*out = make([]FileOrContent, len(*in))
+		copy(*out, *in)
+	}
+	if in.CRLURLs != nil {
+		in, out := &in.CRLURLs, &out.CRLURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientAuthRevocation.
+func (in *ClientAuthRevocation) DeepCopy() *ClientAuthRevocation {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientAuthRevocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GeneratedCert) DeepCopyInto(out *GeneratedCert) {
 	*out = *in
@@ -116,6 +147,11 @@ func (in *Options) DeepCopyInto(out *Options) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.SessionTickets != nil {
+		in, out := &in.SessionTickets, &out.SessionTickets
+		*out = new(SessionTickets)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -129,6 +165,27 @@ func (in *Options) DeepCopy() *Options {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionTickets) DeepCopyInto(out *SessionTickets) {
+	*out = *in
+	if in.KeyFiles != nil {
+		in, out := &in.KeyFiles, &out.KeyFiles
+		*out = make([]FileOrContent, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionTickets.
+func (in *SessionTickets) DeepCopy() *SessionTickets {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionTickets)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Store) DeepCopyInto(out *Store) {
 	*out = *in
@@ -0,0 +1,62 @@
+package udp
+
+import (
+	"errors"
+)
+
+// Constructor A constructor for a piece of UDP middleware.
+type Constructor func(Handler) (Handler, error)
+
+// Chain is a chain for UDP handlers.
+// Chain acts as a list of udp.Handler constructors.
+// Chain is effectively immutable:
+// once created, it will always hold
+// the same set of constructors in the same order.
+type Chain struct {
+	constructors []Constructor
+}
+
+// NewChain creates a new UDP chain,
+// memorizing the given list of UDP middleware constructors.
+// New serves no other function,
+// constructors are only called upon a call to Then().
+func NewChain(constructors ...Constructor) Chain {
+	return Chain{constructors: constructors}
+}
+
+// Then adds an handler at the end of the chain.
+func (c Chain) Then(h Handler) (Handler, error) {
+	if h == nil {
+		return nil, errors.New("cannot add a nil handler to the chain")
+	}
+
+	for i := range c.constructors {
+		handler, err := c.constructors[len(c.constructors)-1-i](h)
+		if err != nil {
+			return nil, err
+		}
+		h = handler
+	}
+
+	return h, nil
+}
+
+// Append extends a chain, adding the specified constructors
+// as the last ones in the session flow.
+//
+// Append returns a new chain, leaving the original one untouched.
+func (c Chain) Append(constructors ...Constructor) Chain {
+	newCons := make([]Constructor, 0, len(c.constructors)+len(constructors))
+	newCons = append(newCons, c.constructors...)
+	newCons = append(newCons, constructors...)
+
+	return Chain{newCons}
+}
+
+// Extend extends a chain by adding the specified chain
+// as the last one in the session flow.
+//
+// Extend returns a new chain, leaving the original one untouched.
+func (c Chain) Extend(chain Chain) Chain {
+	return c.Append(chain.constructors...)
+}
@@ -202,6 +202,12 @@ type Conn struct {
 	muActivity   sync.RWMutex
 	lastActivity time.Time // the last time the session saw either read or write activity
 
+	muRead sync.Mutex
+	peeked []byte // the next datagram, when it has been peeked but not read yet.
+
+	muFilters sync.RWMutex
+	filters   []func(datagram []byte) bool // to drop the datagrams for which one of them returns false
+
 	timeout  time.Duration // for timeouts
 	doneOnce sync.Once
 	doneCh   chan struct{}
@@ -255,7 +261,78 @@ func (c *Conn) readLoop() {
 // Read reads up to len(p) bytes into p from the connection.
 // Each call corresponds to at most one datagram.
 // If p is smaller than the datagram, the extra bytes will be discarded.
+// The datagrams dropped by the filters of the connection are skipped.
 func (c *Conn) Read(p []byte) (int, error) {
+	c.muRead.Lock()
+	defer c.muRead.Unlock()
+
+	for {
+		var n int
+		if c.peeked != nil {
+			n = copy(p, c.peeked)
+			c.peeked = nil
+		} else {
+			var err error
+			n, err = c.read(p)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		if c.keep(p[:n]) {
+			return n, nil
+		}
+	}
+}
+
+// Peek returns the next datagram sent by the client, without consuming it:
+// it is returned again by the next Read, unless it is dropped by the filters of the connection.
+func (c *Conn) Peek() ([]byte, error) {
+	c.muRead.Lock()
+	defer c.muRead.Unlock()
+
+	if c.peeked == nil {
+		buf := make([]byte, maxDatagramSize)
+		n, err := c.read(buf)
+		if err != nil {
+			return nil, err
+		}
+		c.peeked = buf[:n]
+	}
+
+	return c.peeked, nil
+}
+
+// AddFilter adds a filter to the datagrams sent by the client:
+// the datagrams for which the filter returns false are dropped, and never returned by Read.
+func (c *Conn) AddFilter(filter func(datagram []byte) bool) {
+	c.muFilters.Lock()
+	defer c.muFilters.Unlock()
+
+	c.filters = append(c.filters, filter)
+}
+
+// keep reports whether the given datagram passes all the filters of the connection.
+func (c *Conn) keep(datagram []byte) bool {
+	c.muFilters.RLock()
+	defer c.muFilters.RUnlock()
+
+	for _, filter := range c.filters {
+		if !filter(datagram) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RemoteAddr returns the address of the client of the connection.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.rAddr
+}
+
+// read reads the next datagram received from the client into p.
+func (c *Conn) read(p []byte) (int, error) {
 	select {
 	case c.readCh <- p:
 		n := <-c.sizeCh
@@ -1,6 +1,7 @@
 package udp
 
 import (
+	"bytes"
 	"crypto/rand"
 	"errors"
 	"io"
@@ -374,6 +375,65 @@ func TestReadLoopMaxDataSize(t *testing.T) {
 	}
 }
 
+func TestConn_PeekAndFilter(t *testing.T) {
+	addr, err := net.ResolveUDPAddr("udp", ":0")
+	require.NoError(t, err)
+
+	ln, err := Listen("udp", addr, 3*time.Second)
+	require.NoError(t, err)
+	defer func() {
+		err := ln.Close()
+		require.NoError(t, err)
+	}()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		peeked, err := conn.Peek()
+		if err != nil {
+			return
+		}
+
+		// Peeking again returns the same datagram.
+		peekedAgain, err := conn.Peek()
+		if err != nil || !bytes.Equal(peeked, peekedAgain) {
+			return
+		}
+
+		conn.AddFilter(func(datagram []byte) bool {
+			return !bytes.HasPrefix(datagram, []byte("drop"))
+		})
+
+		b := make([]byte, 2048)
+		n, err := conn.Read(b)
+		if err != nil {
+			return
+		}
+
+		_, _ = conn.Write(append(append(peeked, ' '), b[:n]...))
+	}()
+
+	udpConn, err := net.Dial("udp", ln.Addr().String())
+	require.NoError(t, err)
+
+	_, err = udpConn.Write([]byte("drop1"))
+	require.NoError(t, err)
+	_, err = udpConn.Write([]byte("drop2"))
+	require.NoError(t, err)
+	_, err = udpConn.Write([]byte("keep"))
+	require.NoError(t, err)
+
+	require.NoError(t, udpConn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	b := make([]byte, 2048)
+	n, err := udpConn.Read(b)
+	require.NoError(t, err)
+	assert.Equal(t, "drop1 keep", string(b[:n]))
+}
+
 // requireEcho tests that the conn session is live and functional,
 // by writing data through it, and expecting the same data as a response when reading on it.
 // It fatals if the read blocks longer than timeout,
@@ -0,0 +1,113 @@
+package udp
+
+import (
+	"errors"
+	"hash/fnv"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// hashReplicas is the number of points a server of weight 1 has on the hash ring.
+const hashReplicas = 100
+
+type hashPoint struct {
+	hash    uint64
+	handler Handler
+}
+
+// ConsistentHashLoadBalancer is a load balancer for UDP services,
+// forwarding the sessions of a client IP to the same server with a consistent hash ring.
+// Adding or removing a server only moves the clients of the ring part it gets or leaves.
+type ConsistentHashLoadBalancer struct {
+	ring []hashPoint // sorted by hash.
+	lock sync.RWMutex
+}
+
+// NewConsistentHashLoadBalancer creates a new ConsistentHashLoadBalancer.
+func NewConsistentHashLoadBalancer() *ConsistentHashLoadBalancer {
+	return &ConsistentHashLoadBalancer{}
+}
+
+// ServeUDP forwards the connection to the right service.
+func (b *ConsistentHashLoadBalancer) ServeUDP(conn *Conn) {
+	ip, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		ip = conn.RemoteAddr().String()
+	}
+
+	b.lock.RLock()
+	next, err := b.next(ip)
+	b.lock.RUnlock()
+
+	if err != nil {
+		log.Error().Err(err).Msg("Error during load balancing")
+		conn.Close()
+		return
+	}
+
+	next.ServeUDP(conn)
+}
+
+// AddServer appends a handler to the ring.
+// The key identifies the server, and must not change across configuration reloads to keep the clients on the same server.
+func (b *ConsistentHashLoadBalancer) AddServer(key string, serverHandler Handler) {
+	w := 1
+	b.AddWeightedServer(key, serverHandler, &w)
+}
+
+// AddWeightedServer appends a handler to the ring with a weight,
+// the share of the ring it gets being proportional to its weight.
+// The key identifies the server, and must not change across configuration reloads to keep the clients on the same server.
+func (b *ConsistentHashLoadBalancer) AddWeightedServer(key string, serverHandler Handler, weight *int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	w := 1
+	if weight != nil {
+		w = *weight
+	}
+
+	for i := 0; i < w*hashReplicas; i++ {
+		b.ring = append(b.ring, hashPoint{hash: hashKey(key + "#" + strconv.Itoa(i)), handler: serverHandler})
+	}
+
+	sort.Slice(b.ring, func(i, j int) bool {
+		return b.ring[i].hash < b.ring[j].hash
+	})
+}
+
+func (b *ConsistentHashLoadBalancer) next(ip string) (Handler, error) {
+	if len(b.ring) == 0 {
+		return nil, errors.New("no servers in the pool")
+	}
+
+	h := hashKey(ip)
+	i := sort.Search(len(b.ring), func(i int) bool {
+		return b.ring[i].hash >= h
+	})
+	if i == len(b.ring) {
+		i = 0
+	}
+
+	return b.ring[i].handler, nil
+}
+
+// hashKey hashes the given key with FNV-1a,
+// followed by the splitmix64 finalizer to spread similar keys over the whole ring.
+func hashKey(key string) uint64 {
+	hasher := fnv.New64a()
+	_, _ = hasher.Write([]byte(key))
+	h := hasher.Sum64()
+
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+
+	return h
+}
@@ -0,0 +1,129 @@
+package udp
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsistentHashLoadBalancer(t *testing.T) {
+	// servers are the servers which served the sessions.
+	servers := make(map[*Conn]string)
+
+	newBalancer := func(weights map[string]int) *ConsistentHashLoadBalancer {
+		balancer := NewConsistentHashLoadBalancer()
+		for server, weight := range weights {
+			server, weight := server, weight
+			balancer.AddWeightedServer(server, HandlerFunc(func(conn *Conn) {
+				servers[conn] = server
+			}), &weight)
+		}
+		return balancer
+	}
+
+	serve := func(balancer *ConsistentHashLoadBalancer, remoteAddr string) string {
+		conn := newTestConn(t, remoteAddr)
+		balancer.ServeUDP(conn)
+
+		require.Contains(t, servers, conn)
+		return servers[conn]
+	}
+
+	balancer := newBalancer(map[string]int{"h1": 1, "h2": 1, "h3": 1})
+
+	assignments := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		ip := fmt.Sprintf("10.0.%d.%d", i/256, i%256)
+		assignments[ip] = serve(balancer, ip+":1234")
+
+		// The sessions of a client IP, whatever their port, land on the same server.
+		assert.Equal(t, assignments[ip], serve(balancer, ip+":4321"))
+	}
+
+	counts := make(map[string]int)
+	for _, server := range assignments {
+		counts[server]++
+	}
+	for _, server := range []string{"h1", "h2", "h3"} {
+		assert.InDelta(t, 333, counts[server], 100, server)
+	}
+
+	// Removing a server only moves its own clients.
+	balancer = newBalancer(map[string]int{"h1": 1, "h2": 1})
+	for ip, server := range assignments {
+		if server != "h3" {
+			assert.Equal(t, server, serve(balancer, ip+":1234"))
+		}
+	}
+
+	// The share of a server is proportional to its weight, and a server with 0 weight gets no sessions.
+	balancer = newBalancer(map[string]int{"h1": 3, "h2": 1, "h3": 0})
+	counts = make(map[string]int)
+	for ip := range assignments {
+		counts[serve(balancer, ip+":1234")]++
+	}
+	assert.InDelta(t, 750, counts["h1"], 100)
+	assert.InDelta(t, 250, counts["h2"], 100)
+	assert.Zero(t, counts["h3"])
+}
+
+func TestConsistentHashLoadBalancer_noServer(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		weights []int
+	}{
+		{
+			desc: "no servers",
+		},
+		{
+			desc:    "all servers with 0 weight",
+			weights: []int{0, 0},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := NewConsistentHashLoadBalancer()
+			for i, weight := range test.weights {
+				weight := weight
+				balancer.AddWeightedServer(fmt.Sprintf("h%d", i), HandlerFunc(func(conn *Conn) {
+					t.Error("server should not be called")
+				}), &weight)
+			}
+
+			conn := newTestConn(t, "10.0.0.1:1234")
+			balancer.ServeUDP(conn)
+
+			assert.True(t, isClosed(conn))
+		})
+	}
+}
+
+// newTestConn creates a session of the given remote address, which is not bound to a socket.
+func newTestConn(t *testing.T, remoteAddr string) *Conn {
+	t.Helper()
+
+	addr, err := net.ResolveUDPAddr("udp", remoteAddr)
+	require.NoError(t, err)
+
+	listener := &Listener{conns: make(map[string]*Conn)}
+	conn := &Conn{listener: listener, rAddr: addr, doneCh: make(chan struct{})}
+	listener.conns[addr.String()] = conn
+
+	return conn
+}
+
+func isClosed(conn *Conn) bool {
+	select {
+	case <-conn.doneCh:
+		return true
+	default:
+		return false
+	}
+}
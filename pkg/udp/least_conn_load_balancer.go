@@ -0,0 +1,98 @@
+package udp
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+type leastConnServer struct {
+	Handler
+	weight   int
+	sessions int
+}
+
+// LeastConnLoadBalancer is a load balancer for UDP services,
+// forwarding each session to the server having the fewest ongoing sessions relatively to its weight.
+type LeastConnLoadBalancer struct {
+	servers []*leastConnServer
+	lock    sync.Mutex
+	// index is the server the next lookup starts from,
+	// rotated on each session to spread the sessions between the servers with the same load.
+	index int
+}
+
+// NewLeastConnLoadBalancer creates a new LeastConnLoadBalancer.
+func NewLeastConnLoadBalancer() *LeastConnLoadBalancer {
+	return &LeastConnLoadBalancer{}
+}
+
+// ServeUDP forwards the connection to the right service.
+func (b *LeastConnLoadBalancer) ServeUDP(conn *Conn) {
+	b.lock.Lock()
+	next, err := b.next()
+	if err == nil {
+		next.sessions++
+	}
+	b.lock.Unlock()
+
+	if err != nil {
+		log.Error().Err(err).Msg("Error during load balancing")
+		conn.Close()
+		return
+	}
+
+	defer func() {
+		b.lock.Lock()
+		next.sessions--
+		b.lock.Unlock()
+	}()
+
+	next.ServeUDP(conn)
+}
+
+// AddServer appends a handler to the existing list.
+func (b *LeastConnLoadBalancer) AddServer(serverHandler Handler) {
+	w := 1
+	b.AddWeightedServer(serverHandler, &w)
+}
+
+// AddWeightedServer appends a handler to the existing list with a weight.
+func (b *LeastConnLoadBalancer) AddWeightedServer(serverHandler Handler, weight *int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	w := 1
+	if weight != nil {
+		w = *weight
+	}
+	b.servers = append(b.servers, &leastConnServer{Handler: serverHandler, weight: w})
+}
+
+func (b *LeastConnLoadBalancer) next() (*leastConnServer, error) {
+	if len(b.servers) == 0 {
+		return nil, errors.New("no servers in the pool")
+	}
+
+	var best *leastConnServer
+	for i := range b.servers {
+		srv := b.servers[(b.index+i)%len(b.servers)]
+		if srv.weight <= 0 {
+			continue
+		}
+
+		// Compares sessions/weight ratios without dividing.
+		if best == nil || srv.sessions*best.weight < best.sessions*srv.weight {
+			best = srv
+		}
+	}
+
+	if best == nil {
+		return nil, errors.New("all servers have 0 weight")
+	}
+
+	b.index = (b.index + 1) % len(b.servers)
+
+	return best, nil
+}
@@ -0,0 +1,126 @@
+package udp
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeastConnLoadBalancer(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		serving = make(map[string]int)
+	)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	balancer := NewLeastConnLoadBalancer()
+	for _, server := range []struct {
+		name   string
+		weight int
+	}{
+		{name: "h1", weight: 1},
+		{name: "h2", weight: 2},
+		{name: "h3", weight: 0},
+	} {
+		server := server
+		balancer.AddWeightedServer(HandlerFunc(func(conn *Conn) {
+			mu.Lock()
+			serving[server.name]++
+			mu.Unlock()
+
+			started <- struct{}{}
+			<-release
+
+			mu.Lock()
+			serving[server.name]--
+			mu.Unlock()
+		}), &server.weight)
+	}
+
+	sessions := func() []int {
+		balancer.lock.Lock()
+		defer balancer.lock.Unlock()
+
+		var sessions []int
+		for _, server := range balancer.servers {
+			sessions = append(sessions, server.sessions)
+		}
+		return sessions
+	}
+
+	var wg sync.WaitGroup
+	serve := func(n int) {
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				balancer.ServeUDP(newTestConn(t, "10.0.0.1:1234"))
+			}()
+			<-started
+		}
+	}
+
+	// The ongoing sessions are spread according to the weights, the server with 0 weight getting none.
+	serve(6)
+
+	mu.Lock()
+	assert.Equal(t, map[string]int{"h1": 2, "h2": 4}, serving)
+	mu.Unlock()
+	assert.Equal(t, []int{2, 4, 0}, sessions())
+
+	// The sessions are not counted anymore once released.
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, []int{0, 0, 0}, sessions())
+
+	// The new sessions are spread again, as if the released ones never existed.
+	release = make(chan struct{})
+	serve(3)
+
+	mu.Lock()
+	assert.Equal(t, map[string]int{"h1": 1, "h2": 2}, serving)
+	mu.Unlock()
+	assert.Equal(t, []int{1, 2, 0}, sessions())
+
+	close(release)
+	wg.Wait()
+}
+
+func TestLeastConnLoadBalancer_noServer(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		weights []int
+	}{
+		{
+			desc: "no servers",
+		},
+		{
+			desc:    "all servers with 0 weight",
+			weights: []int{0, 0},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := NewLeastConnLoadBalancer()
+			for _, weight := range test.weights {
+				weight := weight
+				balancer.AddWeightedServer(HandlerFunc(func(conn *Conn) {
+					t.Error("server should not be called")
+				}), &weight)
+			}
+
+			conn := newTestConn(t, "10.0.0.1:1234")
+			balancer.ServeUDP(conn)
+
+			assert.True(t, isClosed(conn))
+		})
+	}
+}